@@ -0,0 +1,141 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"taskify/config"
+)
+
+func TestAuthRoutes(t *testing.T) {
+	s := newTestServer(t)
+	existing := s.register("alice")
+
+	tests := []struct {
+		name   string
+		path   string
+		body   interface{}
+		status int
+	}{
+		{"register succeeds", "/api/auth/register", gin.H{"name": "Bob", "email": "bob@example.com", "password": "secret123"}, http.StatusCreated},
+		{"register rejects duplicate email", "/api/auth/register", gin.H{"name": "Alice", "email": existing.Email, "password": "secret123"}, http.StatusConflict},
+		{"register rejects short password", "/api/auth/register", gin.H{"name": "Carol", "email": "carol@example.com", "password": "123"}, http.StatusBadRequest},
+		{"register rejects invalid email", "/api/auth/register", gin.H{"name": "Carol", "email": "not-an-email", "password": "secret123"}, http.StatusBadRequest},
+		{"register rejects malformed JSON", "/api/auth/register", `{"name":`, http.StatusBadRequest},
+		{"login succeeds", "/api/auth/login", gin.H{"email": existing.Email, "password": "secret123"}, http.StatusOK},
+		{"login rejects wrong password", "/api/auth/login", gin.H{"email": existing.Email, "password": "wrong-password"}, http.StatusUnauthorized},
+		{"login rejects unknown email", "/api/auth/login", gin.H{"email": "nobody@example.com", "password": "secret123"}, http.StatusUnauthorized},
+		{"login requires password", "/api/auth/login", gin.H{"email": existing.Email}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.do(http.MethodPost, tt.path, "", tt.body)
+			expectStatus(t, rec, tt.status)
+			if tt.status == http.StatusOK && str(decode(t, rec), "token") == "" {
+				t.Fatalf("expected a token in %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareRejectsMissingOrInvalidTokens(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name          string
+		authorization string
+	}{
+		{"missing header", ""},
+		{"wrong scheme", "Basic abc"},
+		{"garbage token", "Bearer not-a-jwt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var headers []header
+			if tt.authorization != "" {
+				headers = append(headers, header{"Authorization", tt.authorization})
+			}
+			expectStatus(t, s.do(http.MethodGet, "/api/projects", "", nil, headers...), http.StatusUnauthorized)
+		})
+	}
+}
+
+func TestLogoutRevokesToken(t *testing.T) {
+	s := newTestServer(t)
+	user := s.register("alice")
+	other := s.login(user.Email, "secret123")
+
+	expectStatus(t, s.do(http.MethodPost, "/api/auth/logout", "", nil), http.StatusUnauthorized)
+	expectStatus(t, s.do(http.MethodPost, "/api/auth/logout", user.Token, nil), http.StatusOK)
+
+	// Token yang sudah logout ditolak, tetapi sesi lain milik user yang sama tetap berlaku
+	expectStatus(t, s.do(http.MethodGet, "/api/projects", user.Token, nil), http.StatusUnauthorized)
+	expectStatus(t, s.do(http.MethodPost, "/api/auth/logout", user.Token, nil), http.StatusUnauthorized)
+	expectStatus(t, s.do(http.MethodGet, "/api/projects", other, nil), http.StatusOK)
+}
+
+func TestLoginIgnoresIdempotencyKey(t *testing.T) {
+	s := newTestServer(t)
+	user := s.register("erin")
+	key := header{"Idempotency-Key", "login-once"}
+	body := gin.H{"email": user.Email, "password": "secret123"}
+
+	first := s.do(http.MethodPost, "/api/auth/login", "", body, key)
+	expectStatus(t, first, http.StatusOK)
+	expectStatus(t, s.do(http.MethodPost, "/api/auth/logout", str(decode(t, first), "token"), nil), http.StatusOK)
+
+	// Retry dengan key yang sama harus mendapat token baru, bukan token yang sudah dicabut
+	retry := s.do(http.MethodPost, "/api/auth/login", "", body, key)
+	expectStatus(t, retry, http.StatusOK)
+	if retry.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatal("login responses must not be replayed")
+	}
+	expectStatus(t, s.do(http.MethodGet, "/api/projects", str(decode(t, retry), "token"), nil), http.StatusOK)
+}
+
+func TestAuthRateLimit(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) { cfg.Auth.RateLimit = 2 })
+
+	body := gin.H{"email": "nobody@example.com", "password": "secret123"}
+	expectStatus(t, s.do(http.MethodPost, "/api/auth/login", "", body), http.StatusUnauthorized)
+	expectStatus(t, s.do(http.MethodPost, "/api/auth/login", "", body), http.StatusUnauthorized)
+
+	rec := s.do(http.MethodPost, "/api/auth/login", "", body)
+	expectStatus(t, rec, http.StatusTooManyRequests)
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatal("expected a Retry-After header")
+	}
+}
+
+func TestRegisterIdempotencyKey(t *testing.T) {
+	s := newTestServer(t)
+	key := header{"Idempotency-Key", "register-once"}
+	body := gin.H{"name": "Dave", "email": "dave@example.com", "password": "secret123"}
+
+	first := s.do(http.MethodPost, "/api/auth/register", "", body, key)
+	expectStatus(t, first, http.StatusCreated)
+
+	// Retry dengan key dan body yang sama memutar ulang response pertama, bukan 409 email terpakai
+	replay := s.do(http.MethodPost, "/api/auth/register", "", body, key)
+	expectStatus(t, replay, http.StatusCreated)
+	if replay.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatal("expected Idempotent-Replayed header on the retry")
+	}
+	if str(decode(t, replay), "user_id") != str(decode(t, first), "user_id") {
+		t.Fatal("replayed response should carry the original user_id")
+	}
+
+	// Tanpa login, key di-scope per client: client lain dengan key yang sama tidak mendapat response Dave
+	rec := s.do(http.MethodPost, "/api/auth/register", "", body, key, header{"User-Agent", "another-client/1.0"})
+	expectStatus(t, rec, http.StatusConflict)
+	if rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatal("idempotency keys must not be shared between anonymous clients")
+	}
+
+	body["email"] = "someone-else@example.com"
+	expectStatus(t, s.do(http.MethodPost, "/api/auth/register", "", body, key), http.StatusUnprocessableEntity)
+}
@@ -0,0 +1,34 @@
+// Package cache menyediakan penyimpanan key-value bersama (cache, counter, flag) dengan TTL.
+// Implementasi Redis dipakai jika REDIS_ADDR diset sehingga state terbagi antar instance;
+// jika tidak, implementasi in-memory dipakai (hanya berlaku untuk satu proses).
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrMiss dikembalikan oleh Get jika key tidak ada atau sudah kedaluwarsa.
+var ErrMiss = errors.New("cache: key not found")
+
+// Store adalah kontrak yang dipenuhi oleh semua backend cache.
+type Store interface {
+	// Get mengambil nilai key, atau ErrMiss jika tidak ada.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set menyimpan nilai dengan TTL; ttl <= 0 berarti tanpa kedaluwarsa.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// SetNX menyimpan nilai hanya jika key belum ada dan melaporkan apakah penyimpanan terjadi.
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	// CompareAndSwap mengganti nilai key dengan value dan TTL baru hanya jika nilainya saat ini sama persis
+	// dengan old, secara atomik, dan melaporkan apakah penggantian terjadi.
+	CompareAndSwap(ctx context.Context, key string, old, value []byte, ttl time.Duration) (bool, error)
+	// CompareAndDelete menghapus key hanya jika nilainya saat ini sama persis dengan old, secara atomik.
+	CompareAndDelete(ctx context.Context, key string, old []byte) (bool, error)
+	// Delete menghapus key; key yang tidak ada diabaikan.
+	Delete(ctx context.Context, keys ...string) error
+	// Incr menaikkan counter dan mengembalikan nilai barunya. TTL diset saat counter pertama dibuat.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// Ping memeriksa apakah backend bisa dihubungi.
+	Ping(ctx context.Context) error
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore adalah Store yang disimpan di Redis sehingga bisa dipakai bersama oleh beberapa instance.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore membuat RedisStore dari client yang sudah dikonfigurasi.
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (r *RedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrMiss
+	}
+	return value, err
+}
+
+func (r *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, nonNegative(ttl)).Err()
+}
+
+func (r *RedisStore) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, nonNegative(ttl)).Result()
+}
+
+// compareAndSwapScript mengganti nilai dan TTL key hanya jika nilainya masih ARGV[1].
+var compareAndSwapScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+if tonumber(ARGV[3]) > 0 then
+	redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+else
+	redis.call("SET", KEYS[1], ARGV[2])
+end
+return 1
+`)
+
+func (r *RedisStore) CompareAndSwap(ctx context.Context, key string, old, value []byte, ttl time.Duration) (bool, error) {
+	swapped, err := compareAndSwapScript.Run(ctx, r.client, []string{key}, old, value, nonNegative(ttl).Milliseconds()).Int()
+	return swapped == 1, err
+}
+
+// compareAndDeleteScript menghapus key hanya jika nilainya masih ARGV[1].
+var compareAndDeleteScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+return redis.call("DEL", KEYS[1])
+`)
+
+func (r *RedisStore) CompareAndDelete(ctx context.Context, key string, old []byte) (bool, error) {
+	deleted, err := compareAndDeleteScript.Run(ctx, r.client, []string{key}, old).Int()
+	return deleted == 1, err
+}
+
+func (r *RedisStore) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.client.Del(ctx, keys...).Err()
+}
+
+// incrScript menaikkan counter dan memasang TTL secara atomik saat counter baru dibuat.
+var incrScript = redis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+if n == 1 and tonumber(ARGV[1]) > 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return n
+`)
+
+func (r *RedisStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return incrScript.Run(ctx, r.client, []string{key}, nonNegative(ttl).Milliseconds()).Int64()
+}
+
+func (r *RedisStore) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+// nonNegative mengubah ttl <= 0 menjadi 0, yang berarti "tanpa kedaluwarsa" bagi go-redis.
+func nonNegative(ttl time.Duration) time.Duration {
+	if ttl < 0 {
+		return 0
+	}
+	return ttl
+}
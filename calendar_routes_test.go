@@ -0,0 +1,184 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"taskify/models"
+)
+
+// createCalendarFeed membuat URL feed di path (/api/me/calendar-feed atau feed proyek) dan mengembalikan
+// path feed-nya, siap dipakai dengan s.do tanpa token.
+func (s *testServer) createCalendarFeed(user testUser, path string) string {
+	s.t.Helper()
+
+	rec := s.do(http.MethodPost, path, user.Token, nil)
+	expectStatus(s.t, rec, http.StatusCreated)
+	feedURL, err := url.Parse(str(decode(s.t, rec), "url"))
+	if err != nil || !strings.HasPrefix(feedURL.Path, "/api/calendar/cal_") || !strings.HasSuffix(feedURL.Path, ".ics") {
+		s.t.Fatalf("unexpected feed URL %v", feedURL)
+	}
+	return feedURL.Path
+}
+
+// fetchCalendar mengambil feed tanpa Authorization dan mengembalikan isinya dengan baris yang sudah
+// di-unfold, setelah memastikan format barisnya sesuai RFC 5545.
+func (s *testServer) fetchCalendar(path string) string {
+	s.t.Helper()
+
+	rec := s.do(http.MethodGet, path, "", nil)
+	expectStatus(s.t, rec, http.StatusOK)
+	if contentType := rec.Header().Get("Content-Type"); contentType != "text/calendar; charset=utf-8" {
+		s.t.Fatalf("unexpected content type %q", contentType)
+	}
+	raw := rec.Body.String()
+	if !strings.HasSuffix(raw, "END:VCALENDAR\r\n") {
+		s.t.Fatalf("calendar must end with END:VCALENDAR and CRLF, got %q", raw)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(raw, "\r\n"), "\r\n") {
+		if len(line) > 75 || strings.Contains(line, "\n") {
+			s.t.Fatalf("line is not folded correctly: %q", line)
+		}
+	}
+	return strings.ReplaceAll(raw, "\r\n ", "")
+}
+
+// calendarEntry mengembalikan komponen VEVENT/VTODO dengan UID tugas taskID.
+func calendarEntry(t *testing.T, calendar, taskID string) string {
+	t.Helper()
+
+	start := strings.Index(calendar, "UID:"+taskID+"@taskify\r\n")
+	if start < 0 {
+		t.Fatalf("task %s not in calendar:\n%s", taskID, calendar)
+	}
+	end := strings.Index(calendar[start:], "\r\nEND:")
+	return calendar[start : start+end]
+}
+
+func TestCalendarFeedLifecycle(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Roadmap")
+	taskID := s.createTask(owner, projectID, "Release", gin.H{"deadline": "2030-03-01"})
+
+	expectStatus(t, s.do(http.MethodGet, "/api/me/calendar-feed", owner.Token, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodPost, "/api/me/calendar-feed", "", nil), http.StatusUnauthorized)
+
+	path := s.createCalendarFeed(owner, "/api/me/calendar-feed")
+	rec := s.do(http.MethodGet, "/api/me/calendar-feed", owner.Token, nil)
+	expectStatus(t, rec, http.StatusOK)
+	if body := decode(t, rec); lookup(body, "feed.created_at") == nil || lookup(body, "url") != nil {
+		t.Fatalf("expected feed info without the URL, got %v", body)
+	}
+
+	calendar := s.fetchCalendar(path)
+	entry := calendarEntry(t, calendar, taskID)
+	for _, want := range []string{"DTSTART;VALUE=DATE:20300301", "DTEND;VALUE=DATE:20300302", "SUMMARY:Release", "CATEGORIES:Roadmap"} {
+		if !strings.Contains(entry, want) {
+			t.Fatalf("expected %q in entry:\n%s", want, entry)
+		}
+	}
+	if !strings.Contains(calendar, "BEGIN:VEVENT") || !strings.Contains(calendar, "PRODID:-//Taskify//Taskify API//EN") {
+		t.Fatalf("unexpected calendar:\n%s", calendar)
+	}
+	if body := decode(t, s.do(http.MethodGet, "/api/me/calendar-feed", owner.Token, nil)); lookup(body, "feed.last_accessed_at") == nil {
+		t.Fatalf("expected last_accessed_at after a fetch, got %v", body)
+	}
+	expectStatus(t, s.do(http.MethodGet, path+"?component=vjournal", "", nil), http.StatusBadRequest)
+
+	// Membuat ulang URL mencabut URL lama
+	regenerated := s.createCalendarFeed(owner, "/api/me/calendar-feed")
+	if regenerated == path {
+		t.Fatal("expected a new feed URL")
+	}
+	expectStatus(t, s.do(http.MethodGet, path, "", nil), http.StatusNotFound)
+	s.fetchCalendar(regenerated)
+
+	expectStatus(t, s.do(http.MethodDelete, "/api/me/calendar-feed", owner.Token, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodGet, regenerated, "", nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodDelete, "/api/me/calendar-feed", owner.Token, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, "/api/calendar/cal_unknown.ics", "", nil), http.StatusNotFound)
+}
+
+func TestCalendarFeedContent(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	other := s.register("other")
+	work := s.createProject(owner, "Work")
+	home := s.createProject(owner, "Home")
+	archived := s.createProject(owner, "Old")
+
+	planID := s.createTask(owner, work, "Plan, review; ship", gin.H{
+		"deadline":    "2030-03-01",
+		"description": "Line one\nLine two with a long explanation that needs folding because it is longer than seventy-five octets é",
+	})
+	doneID := s.createTask(owner, work, "Done already", gin.H{"deadline": "2030-02-01", "status": "done"})
+	busyID := s.createTask(owner, home, "Painting", gin.H{"deadline": "2030-04-01", "status": "in_progress"})
+	s.createTask(owner, home, "No deadline", nil)
+	hiddenID := s.createTask(owner, archived, "Archived task", gin.H{"deadline": "2030-05-01"})
+	expectStatus(t, s.do(http.MethodPost, "/api/projects/detail/"+archived+"/archive", owner.Token, nil), http.StatusOK)
+	foreignID := s.createTask(other, s.createProject(other, "Theirs"), "Not mine", gin.H{"deadline": "2030-03-01"})
+
+	path := s.createCalendarFeed(owner, "/api/me/calendar-feed")
+	events := s.fetchCalendar(path)
+	if strings.Count(events, "BEGIN:VEVENT") != 3 || strings.Contains(events, hiddenID) || strings.Contains(events, foreignID) {
+		t.Fatalf("expected the 3 tasks with deadlines in active projects:\n%s", events)
+	}
+	plan := calendarEntry(t, events, planID)
+	if !strings.Contains(plan, `SUMMARY:Plan\, review\; ship`) || !strings.Contains(plan, `DESCRIPTION:Line one\nLine two`) ||
+		!strings.Contains(plan, "seventy-five octets é") {
+		t.Fatalf("expected escaped text:\n%s", plan)
+	}
+	if entry := calendarEntry(t, events, doneID); !strings.Contains(entry, "SUMMARY:✓ Done already") {
+		t.Fatalf("expected done tasks to be marked:\n%s", entry)
+	}
+
+	// UID tetap sama di setiap pengambilan, dan status dipetakan pada VTODO
+	todos := s.fetchCalendar(path + "?component=vtodo")
+	if strings.Count(todos, "BEGIN:VTODO") != 3 || strings.Contains(todos, "BEGIN:VEVENT") {
+		t.Fatalf("expected VTODO entries:\n%s", todos)
+	}
+	for taskID, want := range map[string]string{planID: "STATUS:NEEDS-ACTION", doneID: "STATUS:COMPLETED", busyID: "STATUS:IN-PROCESS"} {
+		if entry := calendarEntry(t, todos, taskID); !strings.Contains(entry, want) || !strings.Contains(entry, "DUE;VALUE=DATE:") {
+			t.Fatalf("expected %s in:\n%s", want, entry)
+		}
+	}
+}
+
+func TestProjectCalendarFeed(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	stranger := s.register("stranger")
+	projectID := s.createProject(owner, "Garden")
+	otherProject := s.createProject(owner, "Kitchen")
+	taskID := s.createTask(owner, projectID, "Plant tomatoes", gin.H{"deadline": "2030-04-15"})
+	s.createTask(owner, otherProject, "Fix sink", gin.H{"deadline": "2030-04-16"})
+	feedPath := "/api/projects/" + projectID + "/calendar-feed"
+
+	expectStatus(t, s.do(http.MethodPost, feedPath, stranger.Token, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodPost, "/api/projects/not-a-uuid/calendar-feed", owner.Token, nil), http.StatusBadRequest)
+
+	path := s.createCalendarFeed(owner, feedPath)
+	calendar := s.fetchCalendar(path)
+	calendarEntry(t, calendar, taskID)
+	if strings.Count(calendar, "BEGIN:VEVENT") != 1 || !strings.Contains(calendar, "X-WR-CALNAME:Taskify: Garden") {
+		t.Fatalf("expected only the project's task:\n%s", calendar)
+	}
+
+	// Feed proyek terpisah dari feed semua proyek
+	expectStatus(t, s.do(http.MethodGet, "/api/me/calendar-feed", owner.Token, nil), http.StatusNotFound)
+
+	// Menghapus proyek ikut menghapus feed-nya
+	expectStatus(t, s.do(http.MethodDelete, "/api/projects/detail/"+projectID, owner.Token, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodGet, path, "", nil), http.StatusNotFound)
+	s.dispatch()
+	var feeds int64
+	s.db.Model(&models.CalendarFeed{}).Where("project_id = ?", projectID).Count(&feeds)
+	if feeds != 0 {
+		t.Fatalf("expected the project's feed to be deleted, got %d", feeds)
+	}
+}
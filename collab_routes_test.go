@@ -0,0 +1,245 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"taskify/config"
+	"taskify/models"
+	"taskify/usecase"
+)
+
+// wsClient adalah satu koneksi ke channel kolaborasi proyek.
+type wsClient struct {
+	t    *testing.T
+	conn *websocket.Conn
+	ID   string // connection_id dari pesan welcome
+}
+
+// openSocket membuka GET /api/projects/:project_id/ws lewat server HTTP sungguhan dengan token di
+// subprotocol, seperti yang dilakukan browser.
+func (s *testServer) openSocket(user testUser, projectID string) *wsClient {
+	s.t.Helper()
+
+	server := httptest.NewServer(s.router)
+	s.t.Cleanup(server.Close)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/projects/" + projectID + "/ws"
+	dialer := websocket.Dialer{Subprotocols: []string{"bearer", user.Token}}
+	conn, resp, err := dialer.Dial(url, nil)
+	if err != nil {
+		s.t.Fatalf("open collaboration socket: %v", err)
+	}
+	s.t.Cleanup(func() { conn.Close() })
+	if resp.Header.Get("Sec-WebSocket-Protocol") != "bearer" {
+		s.t.Fatalf("expected the bearer subprotocol to be selected, got %q", resp.Header.Get("Sec-WebSocket-Protocol"))
+	}
+
+	client := &wsClient{t: s.t, conn: conn}
+	client.ID = str(client.next("welcome"), "connection_id")
+	return client
+}
+
+// next mengembalikan pesan berikutnya berjenis kind (pesan lain dilewati) atau menghentikan test setelah 2 detik.
+func (wc *wsClient) next(kind string) map[string]interface{} {
+	wc.t.Helper()
+
+	wc.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var message map[string]interface{}
+		if err := wc.conn.ReadJSON(&message); err != nil {
+			wc.t.Fatalf("waiting for %s: %v", kind, err)
+		}
+		if message["type"] == kind {
+			return message
+		}
+	}
+}
+
+// presence menunggu daftar kehadiran yang memenuhi match.
+func (wc *wsClient) presence(match func(members []interface{}) bool) []interface{} {
+	wc.t.Helper()
+
+	for {
+		members, _ := wc.next("presence")["members"].([]interface{})
+		if match(members) {
+			return members
+		}
+	}
+}
+
+func (wc *wsClient) send(message gin.H) {
+	wc.t.Helper()
+	if err := wc.conn.WriteJSON(message); err != nil {
+		wc.t.Fatalf("send %v: %v", message, err)
+	}
+}
+
+// expectClose memastikan server menutup koneksi dengan kode code.
+func (wc *wsClient) expectClose(code int) {
+	wc.t.Helper()
+
+	wc.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		_, _, err := wc.conn.ReadMessage()
+		if err == nil {
+			continue
+		}
+		var closeErr *websocket.CloseError
+		if !errors.As(err, &closeErr) || closeErr.Code != code {
+			wc.t.Fatalf("expected close code %d, got %v", code, err)
+		}
+		return
+	}
+}
+
+// member mencari kehadiran connectionID di members.
+func member(members []interface{}, connectionID string) map[string]interface{} {
+	for _, m := range members {
+		if presence, _ := m.(map[string]interface{}); presence["connection_id"] == connectionID {
+			return presence
+		}
+	}
+	return nil
+}
+
+func TestCollabSocketAuthorization(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	stranger := s.register("stranger")
+	projectID := s.createProject(owner, "Private")
+	path := "/api/projects/" + projectID + "/ws"
+
+	expectStatus(t, s.do(http.MethodGet, path, "", nil), http.StatusUnauthorized)
+	expectStatus(t, s.do(http.MethodGet, path, stranger.Token, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, "/api/projects/not-a-uuid/ws", owner.Token, nil), http.StatusBadRequest)
+
+	// Token tidak valid di subprotocol ditolak sebelum upgrade
+	server := httptest.NewServer(s.router)
+	defer server.Close()
+	dialer := websocket.Dialer{Subprotocols: []string{"bearer", "not-a-token"}}
+	_, resp, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+path, nil)
+	if err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for an invalid token, got %v", err)
+	}
+}
+
+func TestCollabPresenceLocksAndEvents(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Together")
+	taskID := s.createTask(owner, projectID, "Shared", nil)
+	s.dispatch()
+
+	first := s.openSocket(owner, projectID)
+	second := s.openSocket(owner, projectID)
+
+	// Kehadiran: koneksi kedua melihat koneksi pertama membuka tugas
+	first.send(gin.H{"type": "view", "task_id": taskID})
+	members := second.presence(func(members []interface{}) bool {
+		presence := member(members, first.ID)
+		return presence != nil && presence["viewing_task_id"] == taskID
+	})
+	if len(members) != 2 {
+		t.Fatalf("expected both connections in presence, got %v", members)
+	}
+
+	// Soft lock: hanya satu koneksi yang bisa mengedit tugas yang sama
+	first.send(gin.H{"type": "edit_start", "task_id": taskID})
+	if lock := first.next("lock_granted"); str(lock, "lock.connection_id") != first.ID {
+		t.Fatalf("unexpected lock: %v", lock)
+	}
+	second.presence(func(members []interface{}) bool {
+		presence := member(members, first.ID)
+		return presence != nil && presence["editing_task_id"] == taskID
+	})
+	second.send(gin.H{"type": "edit_start", "task_id": taskID})
+	if denied := second.next("lock_denied"); str(denied, "lock.connection_id") != first.ID {
+		t.Fatalf("expected the lock holder in lock_denied, got %v", denied)
+	}
+
+	// Perubahan tugas disiarkan ke semua koneksi
+	rec := s.do(http.MethodPatch, "/api/projects/"+projectID+"/tasks/"+taskID, owner.Token, gin.H{"status": "in_progress"})
+	expectStatus(t, rec, http.StatusOK)
+	s.dispatch()
+	if event := second.next("event"); str(event, "event.type") != models.EventTaskUpdated || str(event, "event.data.id") != taskID {
+		t.Fatalf("unexpected event: %v", event)
+	}
+
+	// Koneksi yang pergi melepas lock-nya dan hilang dari daftar kehadiran
+	first.conn.Close()
+	second.presence(func(members []interface{}) bool { return member(members, first.ID) == nil })
+	second.send(gin.H{"type": "edit_start", "task_id": taskID})
+	second.next("lock_granted")
+
+	second.send(gin.H{"type": "edit_start", "task_id": "00000000-0000-0000-0000-000000000000"})
+	if reply := second.next("error"); !strings.Contains(str(reply, "error"), "Task not found") {
+		t.Fatalf("unexpected error reply: %v", reply)
+	}
+}
+
+func TestCollabLockExpires(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) { cfg.Collab.LockTTL = 50 * time.Millisecond })
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Idle")
+	taskID := s.createTask(owner, projectID, "Draft", nil)
+
+	first := s.openSocket(owner, projectID)
+	second := s.openSocket(owner, projectID)
+
+	first.send(gin.H{"type": "edit_start", "task_id": taskID})
+	first.next("lock_granted")
+
+	// Lock yang tidak diperpanjang berakhir sendiri
+	time.Sleep(100 * time.Millisecond)
+	second.send(gin.H{"type": "edit_start", "task_id": taskID})
+	second.next("lock_granted")
+}
+
+func TestCollabSocketRechecksAuthorization(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) { cfg.Collab.AuthRecheckInterval = 20 * time.Millisecond })
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Guarded")
+
+	// Token yang dicabut (logout) memutus koneksi yang sedang terbuka
+	client := s.openSocket(owner, projectID)
+	expectStatus(t, s.do(http.MethodPost, "/api/auth/logout", owner.Token, nil), http.StatusOK)
+	client.expectClose(usecase.CloseTokenInvalid)
+
+	// Anggota yang dikeluarkan diputus dengan 4403
+	other := s.register("other")
+	shared := s.createProject(other, "Shared")
+	guest := s.register("guest")
+	s.addMember(other, shared, guest)
+	client = s.openSocket(guest, shared)
+	expectStatus(t, s.do(http.MethodDelete, "/api/projects/"+shared+"/members/"+guest.ID, other.Token, nil), http.StatusOK)
+	client.expectClose(usecase.CloseAccessRevoked)
+
+	// Proyek yang dihapus mengakhiri koneksi setelah event-nya terkirim, baik lewat worker outbox maupun
+	// lewat pengecekan ulang yang lebih dulu menemukan proyeknya hilang
+	for _, relay := range []bool{true, false} {
+		doomed := s.createProject(other, "Doomed")
+		client = s.openSocket(other, doomed)
+		expectStatus(t, s.do(http.MethodDelete, "/api/projects/detail/"+doomed, other.Token, nil), http.StatusOK)
+		if relay {
+			s.dispatch()
+		}
+		event := client.next("event")
+		if str(event, "event.type") != models.EventProjectDeleted || str(event, "event.project_id") != doomed {
+			t.Fatalf("expected project.deleted for %s, got %v", doomed, event)
+		}
+		client.expectClose(websocket.CloseNormalClosure)
+	}
+
+	// Shutdown memutus koneksi dengan going away
+	client = s.openSocket(other, s.createProject(other, "Survivor"))
+	s.app.closeStreams()
+	client.expectClose(websocket.CloseGoingAway)
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"taskify/cache"
+)
+
+// CacheConfig berisi REDIS_ADDR, REDIS_PASSWORD, dan REDIS_DB. RedisAddr kosong berarti cache in-memory.
+type CacheConfig struct {
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+}
+
+// ConnectCache memakai Redis jika cfg.RedisAddr diset, atau cache in-memory jika tidak.
+func ConnectCache(ctx context.Context, cfg CacheConfig) (cache.Store, error) {
+	if cfg.RedisAddr == "" {
+		slog.Info("using in-memory cache (REDIS_ADDR not set)")
+		return cache.NewMemoryStore(), nil
+	}
+
+	client, err := connectRedis(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("connected to Redis", "addr", cfg.RedisAddr)
+	return cache.NewRedisStore(client), nil
+}
+
+// connectRedis membuat client Redis dari cfg dan memastikan servernya bisa dihubungi.
+func connectRedis(ctx context.Context, cfg CacheConfig) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("connect to Redis at %s: %w", cfg.RedisAddr, err)
+	}
+	return client, nil
+}
@@ -0,0 +1,15 @@
+package config
+
+import "time"
+
+// CollabConfig berisi pengaturan channel kolaborasi WebSocket: WS_PING_INTERVAL (jeda ping ke client; client
+// yang tidak membalas dalam dua kali jeda ini diputus), COLLAB_LOCK_TTL (umur soft lock "sedang mengedit"
+// sebelum harus diperpanjang), COLLAB_PRESENCE_TTL (umur kehadiran koneksi yang berhenti memberi kabar,
+// misalnya karena instance-nya mati), dan WS_AUTH_RECHECK_INTERVAL (seberapa sering token dan akses proyek
+// setiap koneksi diperiksa ulang).
+type CollabConfig struct {
+	PingInterval        time.Duration
+	LockTTL             time.Duration
+	PresenceTTL         time.Duration
+	AuthRecheckInterval time.Duration
+}
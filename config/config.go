@@ -0,0 +1,390 @@
+// Package config memuat seluruh konfigurasi aplikasi sekali saat start ke dalam satu struct Config yang
+// bertipe dan sudah divalidasi, lalu membuka koneksi database dan cache berdasarkan konfigurasi tersebut.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"taskify/logging"
+)
+
+// minJWTSecretLength adalah panjang minimum JWT_SECRET; HS256 butuh kunci minimal 256 bit.
+const minJWTSecretLength = 32
+
+// weakJWTSecrets adalah nilai contoh dan placeholder yang tidak boleh dipakai sebagai JWT_SECRET.
+var weakJWTSecrets = map[string]bool{
+	"rahasia":                           true,
+	"hash1234":                          true,
+	"secret":                            true,
+	"changeme":                          true,
+	"change-me-to-a-long-random-string": true,
+	"your-256-bit-secret":               true,
+	"your_jwt_secret":                   true,
+}
+
+// Config adalah konfigurasi lengkap aplikasi. Nilainya berasal dari Load dan diteruskan secara eksplisit
+// ke komponen yang membutuhkannya.
+type Config struct {
+	Server   ServerConfig
+	Database DatabaseConfig
+	Cache    CacheConfig
+	Auth     AuthConfig
+	API      APIConfig
+	Log      LogConfig
+	Tracing  TracingConfig
+	Webhook  WebhookConfig
+	Stream   StreamConfig
+	Collab   CollabConfig
+	Mail     MailConfig
+	Reminder ReminderConfig
+	Import   ImportConfig
+}
+
+// AuthConfig berisi pengaturan token JWT dan pembatasan endpoint autentikasi.
+type AuthConfig struct {
+	JWTSecret string
+	TokenTTL  time.Duration
+	RateLimit int // Request per menit per IP ke /api/auth, 0 menonaktifkan
+}
+
+// APIConfig berisi pengaturan perilaku endpoint API.
+type APIConfig struct {
+	RequireIfMatch    bool          // PUT/PATCH/DELETE tanpa If-Match ditolak dengan 428
+	IdempotencyTTL    time.Duration // Lama response Idempotency-Key disimpan
+	BulkMaxOperations int           // Batas operasi per bulk request
+	ImportMaxRows     int           // Batas tugas per import proyek
+	ImportMaxBytes    int           // Batas ukuran file import
+}
+
+// LogConfig berisi level dan format log.
+type LogConfig struct {
+	Level  string
+	Format string
+}
+
+// TracingConfig berisi exporter dan nama service untuk OpenTelemetry.
+type TracingConfig struct {
+	Exporter    string
+	ServiceName string
+}
+
+// Default mengembalikan konfigurasi bawaan. JWTSecret sengaja kosong karena wajib diisi.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Addr:              ":8080",
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			ShutdownTimeout:   20 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Driver:  "mysql",
+			SSLMode: "disable",
+		},
+		Auth: AuthConfig{
+			TokenTTL:  24 * time.Hour,
+			RateLimit: 20,
+		},
+		API: APIConfig{
+			IdempotencyTTL:    24 * time.Hour,
+			BulkMaxOperations: 100,
+			ImportMaxRows:     5000,
+			ImportMaxBytes:    10 << 20,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "taskify",
+		},
+		Webhook: WebhookConfig{
+			Timeout:          10 * time.Second,
+			MaxAttempts:      8,
+			BackoffBase:      30 * time.Second,
+			BackoffMax:       6 * time.Hour,
+			PollInterval:     2 * time.Second,
+			RelayMaxAttempts: 5,
+		},
+		Stream: StreamConfig{
+			HeartbeatInterval: 15 * time.Second,
+			LogSize:           1000,
+			LogTTL:            24 * time.Hour,
+		},
+		Collab: CollabConfig{
+			PingInterval:        30 * time.Second,
+			LockTTL:             30 * time.Second,
+			PresenceTTL:         45 * time.Second,
+			AuthRecheckInterval: time.Minute,
+		},
+		Mail: MailConfig{
+			Backend: "log",
+			From:    "Taskify <no-reply@taskify.local>",
+			Dir:     "tmp/mail",
+		},
+		Reminder: ReminderConfig{
+			Enabled:      true,
+			PollInterval: time.Minute,
+		},
+		Import: ImportConfig{
+			Enabled:      true,
+			PollInterval: 2 * time.Second,
+			Retention:    7 * 24 * time.Hour,
+		},
+	}
+}
+
+// Load membaca konfigurasi dari environment, dari file YAML/TOML di CONFIG_FILE (opsional), dan dari
+// file secret *_FILE, lalu memvalidasinya. Semua masalah dilaporkan sekaligus dalam satu error.
+func Load() (Config, error) {
+	src, err := newSource()
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg := Default()
+	cfg.Server.Addr = ":" + src.str("PORT", strings.TrimPrefix(cfg.Server.Addr, ":"))
+	cfg.Server.ReadTimeout = src.duration("HTTP_READ_TIMEOUT", cfg.Server.ReadTimeout)
+	cfg.Server.ReadHeaderTimeout = src.duration("HTTP_READ_HEADER_TIMEOUT", cfg.Server.ReadHeaderTimeout)
+	cfg.Server.WriteTimeout = src.duration("HTTP_WRITE_TIMEOUT", cfg.Server.WriteTimeout)
+	cfg.Server.IdleTimeout = src.duration("HTTP_IDLE_TIMEOUT", cfg.Server.IdleTimeout)
+	cfg.Server.ShutdownTimeout = src.duration("SHUTDOWN_TIMEOUT", cfg.Server.ShutdownTimeout)
+
+	cfg.Database = readDatabase(src, cfg.Database)
+
+	cfg.Cache.RedisAddr = src.str("REDIS_ADDR", cfg.Cache.RedisAddr)
+	cfg.Cache.RedisPassword = src.str("REDIS_PASSWORD", cfg.Cache.RedisPassword)
+	cfg.Cache.RedisDB = src.integer("REDIS_DB", cfg.Cache.RedisDB)
+
+	cfg.Auth.JWTSecret = src.str("JWT_SECRET", cfg.Auth.JWTSecret)
+	cfg.Auth.TokenTTL = src.duration("JWT_TTL", cfg.Auth.TokenTTL)
+	cfg.Auth.RateLimit = src.integer("AUTH_RATE_LIMIT", cfg.Auth.RateLimit)
+
+	cfg.API.RequireIfMatch = src.boolean("REQUIRE_IF_MATCH", cfg.API.RequireIfMatch)
+	cfg.API.IdempotencyTTL = src.duration("IDEMPOTENCY_TTL", cfg.API.IdempotencyTTL)
+	cfg.API.BulkMaxOperations = src.integer("BULK_MAX_OPERATIONS", cfg.API.BulkMaxOperations)
+	cfg.API.ImportMaxRows = src.integer("IMPORT_MAX_ROWS", cfg.API.ImportMaxRows)
+	cfg.API.ImportMaxBytes = src.integer("IMPORT_MAX_BYTES", cfg.API.ImportMaxBytes)
+
+	cfg.Log.Level = strings.ToLower(src.str("LOG_LEVEL", cfg.Log.Level))
+	cfg.Log.Format = strings.ToLower(src.str("LOG_FORMAT", cfg.Log.Format))
+
+	cfg.Tracing.Exporter = strings.ToLower(src.str("OTEL_TRACES_EXPORTER", cfg.Tracing.Exporter))
+	cfg.Tracing.ServiceName = src.str("OTEL_SERVICE_NAME", cfg.Tracing.ServiceName)
+
+	cfg.Webhook.Timeout = src.duration("WEBHOOK_TIMEOUT", cfg.Webhook.Timeout)
+	cfg.Webhook.MaxAttempts = src.integer("WEBHOOK_MAX_ATTEMPTS", cfg.Webhook.MaxAttempts)
+	cfg.Webhook.BackoffBase = src.duration("WEBHOOK_BACKOFF_BASE", cfg.Webhook.BackoffBase)
+	cfg.Webhook.BackoffMax = src.duration("WEBHOOK_BACKOFF_MAX", cfg.Webhook.BackoffMax)
+	cfg.Webhook.PollInterval = src.duration("WEBHOOK_POLL_INTERVAL", cfg.Webhook.PollInterval)
+	cfg.Webhook.RelayMaxAttempts = src.integer("WEBHOOK_RELAY_MAX_ATTEMPTS", cfg.Webhook.RelayMaxAttempts)
+	cfg.Webhook.AllowPrivateTargets = src.boolean("WEBHOOK_ALLOW_PRIVATE_TARGETS", cfg.Webhook.AllowPrivateTargets)
+
+	cfg.Stream.HeartbeatInterval = src.duration("SSE_HEARTBEAT_INTERVAL", cfg.Stream.HeartbeatInterval)
+	cfg.Stream.LogSize = src.integer("EVENT_LOG_SIZE", cfg.Stream.LogSize)
+	cfg.Stream.LogTTL = src.duration("EVENT_LOG_TTL", cfg.Stream.LogTTL)
+
+	cfg.Collab.PingInterval = src.duration("WS_PING_INTERVAL", cfg.Collab.PingInterval)
+	cfg.Collab.LockTTL = src.duration("COLLAB_LOCK_TTL", cfg.Collab.LockTTL)
+	cfg.Collab.PresenceTTL = src.duration("COLLAB_PRESENCE_TTL", cfg.Collab.PresenceTTL)
+	cfg.Collab.AuthRecheckInterval = src.duration("WS_AUTH_RECHECK_INTERVAL", cfg.Collab.AuthRecheckInterval)
+
+	cfg.Mail.Backend = strings.ToLower(src.str("MAIL_BACKEND", cfg.Mail.Backend))
+	cfg.Mail.From = src.str("MAIL_FROM", cfg.Mail.From)
+	cfg.Mail.Dir = src.str("MAIL_DIR", cfg.Mail.Dir)
+	cfg.Mail.SMTPAddr = src.str("SMTP_ADDR", cfg.Mail.SMTPAddr)
+	cfg.Mail.SMTPUsername = src.str("SMTP_USERNAME", cfg.Mail.SMTPUsername)
+	cfg.Mail.SMTPPassword = src.str("SMTP_PASSWORD", cfg.Mail.SMTPPassword)
+
+	cfg.Reminder.Enabled = src.boolean("REMINDERS_ENABLED", cfg.Reminder.Enabled)
+	cfg.Reminder.PollInterval = src.duration("REMINDER_POLL_INTERVAL", cfg.Reminder.PollInterval)
+
+	cfg.Import.Enabled = src.boolean("IMPORT_JOBS_ENABLED", cfg.Import.Enabled)
+	cfg.Import.PollInterval = src.duration("IMPORT_POLL_INTERVAL", cfg.Import.PollInterval)
+	cfg.Import.Retention = src.duration("IMPORT_JOB_RETENTION", cfg.Import.Retention)
+
+	problems := src.problems
+	if err := cfg.Validate(); err != nil {
+		var invalid *ValidationError
+		if errors.As(err, &invalid) {
+			problems = append(problems, invalid.Problems...)
+		}
+	}
+	if len(problems) > 0 {
+		return Config{}, &ValidationError{Problems: problems}
+	}
+	return cfg, nil
+}
+
+// ValidationError mengumpulkan semua nilai konfigurasi yang tidak valid, satu baris per masalah.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Validate memeriksa aturan yang tidak bisa dijamin oleh tipe data, misalnya panjang JWT_SECRET
+// dan nilai enum. Dipanggil oleh Load; test yang menyusun Config sendiri boleh memanggilnya juga.
+func (c Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	port := strings.TrimPrefix(c.Server.Addr, ":")
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		add("PORT must be a number between 1 and 65535, got %q", port)
+	}
+	for _, timeout := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"HTTP_READ_TIMEOUT", c.Server.ReadTimeout},
+		{"HTTP_READ_HEADER_TIMEOUT", c.Server.ReadHeaderTimeout},
+		{"HTTP_WRITE_TIMEOUT", c.Server.WriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", c.Server.IdleTimeout},
+	} {
+		if timeout.value < 0 {
+			add("%s must not be negative", timeout.name)
+		}
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		add("SHUTDOWN_TIMEOUT must be positive")
+	}
+
+	problems = append(problems, c.Database.problems()...)
+
+	if c.Cache.RedisDB < 0 {
+		add("REDIS_DB must not be negative")
+	}
+
+	switch secret := c.Auth.JWTSecret; {
+	case secret == "":
+		add("JWT_SECRET is required (set JWT_SECRET or JWT_SECRET_FILE)")
+	case weakJWTSecrets[strings.ToLower(secret)]:
+		add("JWT_SECRET is a well-known example value; generate a random one, e.g. `openssl rand -hex 32`")
+	case len(secret) < minJWTSecretLength:
+		add("JWT_SECRET must be at least %d characters, got %d", minJWTSecretLength, len(secret))
+	}
+	if c.Auth.TokenTTL <= 0 {
+		add("JWT_TTL must be positive")
+	}
+	if c.Auth.RateLimit < 0 {
+		add("AUTH_RATE_LIMIT must not be negative (0 disables the limit)")
+	}
+
+	if c.API.IdempotencyTTL <= 0 {
+		add("IDEMPOTENCY_TTL must be positive")
+	}
+	if c.API.BulkMaxOperations <= 0 {
+		add("BULK_MAX_OPERATIONS must be positive")
+	}
+	if c.API.ImportMaxRows <= 0 {
+		add("IMPORT_MAX_ROWS must be positive")
+	}
+	if c.API.ImportMaxBytes <= 0 {
+		add("IMPORT_MAX_BYTES must be positive")
+	}
+
+	if _, err := logging.ParseLevel(c.Log.Level); err != nil {
+		add("LOG_LEVEL must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	if c.Log.Format != "json" && c.Log.Format != "text" {
+		add("LOG_FORMAT must be json or text, got %q", c.Log.Format)
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		add("OTEL_TRACES_EXPORTER must be none, stdout or otlp, got %q", c.Tracing.Exporter)
+	}
+
+	for _, setting := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"WEBHOOK_TIMEOUT", c.Webhook.Timeout},
+		{"WEBHOOK_BACKOFF_BASE", c.Webhook.BackoffBase},
+		{"WEBHOOK_POLL_INTERVAL", c.Webhook.PollInterval},
+	} {
+		if setting.value <= 0 {
+			add("%s must be positive", setting.name)
+		}
+	}
+	if c.Webhook.BackoffMax < c.Webhook.BackoffBase {
+		add("WEBHOOK_BACKOFF_MAX must not be shorter than WEBHOOK_BACKOFF_BASE")
+	}
+	if c.Webhook.MaxAttempts <= 0 {
+		add("WEBHOOK_MAX_ATTEMPTS must be positive")
+	}
+	if c.Webhook.RelayMaxAttempts <= 0 {
+		add("WEBHOOK_RELAY_MAX_ATTEMPTS must be positive")
+	}
+
+	if c.Stream.HeartbeatInterval <= 0 {
+		add("SSE_HEARTBEAT_INTERVAL must be positive")
+	}
+	if c.Stream.LogSize <= 0 {
+		add("EVENT_LOG_SIZE must be positive")
+	}
+	if c.Stream.LogTTL <= 0 {
+		add("EVENT_LOG_TTL must be positive")
+	}
+
+	if c.Collab.PingInterval <= 0 {
+		add("WS_PING_INTERVAL must be positive")
+	}
+	if c.Collab.LockTTL <= 0 {
+		add("COLLAB_LOCK_TTL must be positive")
+	}
+	if c.Collab.PresenceTTL <= 0 {
+		add("COLLAB_PRESENCE_TTL must be positive")
+	}
+	if c.Collab.AuthRecheckInterval <= 0 {
+		add("WS_AUTH_RECHECK_INTERVAL must be positive")
+	}
+
+	switch c.Mail.Backend {
+	case "smtp":
+		if _, _, err := net.SplitHostPort(c.Mail.SMTPAddr); err != nil {
+			add("SMTP_ADDR must be host:port for MAIL_BACKEND smtp, got %q", c.Mail.SMTPAddr)
+		}
+	case "file":
+		if c.Mail.Dir == "" {
+			add("MAIL_DIR is required for MAIL_BACKEND file")
+		}
+	case "log":
+	default:
+		add("MAIL_BACKEND must be smtp, file or log, got %q", c.Mail.Backend)
+	}
+	if _, err := mail.ParseAddress(c.Mail.From); err != nil {
+		add("MAIL_FROM must be an email address, got %q", c.Mail.From)
+	}
+
+	if c.Reminder.PollInterval <= 0 {
+		add("REMINDER_POLL_INTERVAL must be positive")
+	}
+
+	if c.Import.PollInterval <= 0 {
+		add("IMPORT_POLL_INTERVAL must be positive")
+	}
+	if c.Import.Retention <= 0 {
+		add("IMPORT_JOB_RETENTION must be positive")
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
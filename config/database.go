@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"taskify/logging"
+)
+
+// DatabaseConfig berisi variabel DB_*. Driver adalah mysql, postgres, atau sqlite (default mysql);
+// untuk sqlite, Name adalah path file database.
+type DatabaseConfig struct {
+	Driver   string
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Name     string
+	SSLMode  string // Hanya untuk postgres, default disable
+}
+
+// LoadDatabase membaca dan memvalidasi hanya variabel DB_*, dari sumber yang sama dengan Load. Dipakai
+// subcommand migrate, yang tidak butuh JWT_SECRET, Redis, maupun pengaturan server lainnya.
+func LoadDatabase() (DatabaseConfig, error) {
+	src, err := newSource()
+	if err != nil {
+		return DatabaseConfig{}, err
+	}
+
+	cfg := readDatabase(src, Default().Database)
+	if problems := append(src.problems, cfg.problems()...); len(problems) > 0 {
+		return DatabaseConfig{}, &ValidationError{Problems: problems}
+	}
+	return cfg, nil
+}
+
+// readDatabase mengisi variabel DB_* dari src di atas nilai bawaan cfg.
+func readDatabase(src *source, cfg DatabaseConfig) DatabaseConfig {
+	cfg.Driver = strings.ToLower(src.str("DB_DRIVER", cfg.Driver))
+	cfg.Host = src.str("DB_HOST", cfg.Host)
+	cfg.Port = src.str("DB_PORT", cfg.Port)
+	cfg.User = src.str("DB_USER", cfg.User)
+	cfg.Password = src.str("DB_PASSWORD", cfg.Password)
+	cfg.Name = src.str("DB_NAME", cfg.Name)
+	cfg.SSLMode = src.str("DB_SSLMODE", cfg.SSLMode)
+	return cfg
+}
+
+// problems mengembalikan masalah pada variabel DB_*, satu baris per masalah.
+func (c DatabaseConfig) problems() []string {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	switch c.Driver {
+	case "mysql", "postgres":
+		if c.Host == "" {
+			add("DB_HOST is required for DB_DRIVER %s", c.Driver)
+		}
+		if c.Name == "" {
+			add("DB_NAME is required for DB_DRIVER %s", c.Driver)
+		}
+		if c.Port != "" {
+			if _, err := strconv.Atoi(c.Port); err != nil {
+				add("DB_PORT must be a number, got %q", c.Port)
+			}
+		}
+	case "sqlite":
+	default:
+		add("DB_DRIVER must be mysql, postgres or sqlite, got %q", c.Driver)
+	}
+	return problems
+}
+
+// ConnectDatabase membuka koneksi database sesuai cfg.Driver.
+func ConnectDatabase(cfg DatabaseConfig) (*gorm.DB, error) {
+	dialector, err := openDialector(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// TranslateError membuat error unique constraint muncul sebagai gorm.ErrDuplicatedKey
+	db, err := gorm.Open(dialector, &gorm.Config{TranslateError: true, Logger: logging.NewGormLogger()})
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s database: %w", cfg.Driver, err)
+	}
+
+	if cfg.Driver == "sqlite" {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, fmt.Errorf("access database pool: %w", err)
+		}
+		// SQLite hanya mengizinkan satu penulis; satu koneksi juga menjaga database :memory: tetap sama
+		sqlDB.SetMaxOpenConns(1)
+	}
+
+	slog.Info("connected to database", "driver", cfg.Driver)
+	return db, nil
+}
+
+// openDialector membangun DSN dari DatabaseConfig untuk driver yang dipilih.
+func openDialector(cfg DatabaseConfig) (gorm.Dialector, error) {
+	switch cfg.Driver {
+	case "mysql":
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name)
+		return mysql.Open(dsn), nil
+
+	case "postgres":
+		port := cfg.Port
+		if port == "" {
+			port = "5432"
+		}
+		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s TimeZone=UTC", cfg.Host, port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode)
+		return postgres.Open(dsn), nil
+
+	case "sqlite":
+		// ":memory:" membuat database sementara di memori
+		name := cfg.Name
+		if name == "" {
+			name = "taskify.db"
+		}
+		dsn := "file:" + name + "?_foreign_keys=on&_busy_timeout=5000"
+		if name == ":memory:" {
+			dsn = "file::memory:?cache=shared&_foreign_keys=on"
+		}
+		return sqlite.Open(dsn), nil
+	}
+
+	return nil, fmt.Errorf("unsupported DB_DRIVER %q (expected mysql, postgres or sqlite)", cfg.Driver)
+}
@@ -0,0 +1,12 @@
+package config
+
+import "time"
+
+// ImportConfig berisi pengaturan worker import latar belakang: IMPORT_JOBS_ENABLED (false mematikan worker
+// di instance ini; job tetap bisa dibuat dan diproses instance lain), IMPORT_POLL_INTERVAL (seberapa sering
+// job baru diperiksa), dan IMPORT_JOB_RETENTION (lama job yang sudah selesai disimpan).
+type ImportConfig struct {
+	Enabled      bool
+	PollInterval time.Duration
+	Retention    time.Duration
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+
+	"taskify/mail"
+)
+
+// MailConfig berisi MAIL_BACKEND (smtp, file, atau log), MAIL_FROM (alamat pengirim), MAIL_DIR (direktori
+// tujuan backend file), serta SMTP_ADDR, SMTP_USERNAME, dan SMTP_PASSWORD untuk backend smtp.
+type MailConfig struct {
+	Backend      string
+	From         string
+	Dir          string
+	SMTPAddr     string
+	SMTPUsername string
+	SMTPPassword string
+}
+
+// ConnectMailer membuat backend email sesuai cfg.Backend.
+func ConnectMailer(cfg MailConfig) (mail.Mailer, error) {
+	switch cfg.Backend {
+	case "smtp":
+		slog.Info("sending email via SMTP", "addr", cfg.SMTPAddr)
+		return mail.NewSMTPMailer(cfg.SMTPAddr, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From), nil
+	case "file":
+		slog.Info("writing email to files instead of sending it", "dir", cfg.Dir)
+		return mail.NewFileMailer(cfg.Dir, cfg.From)
+	case "log":
+		return mail.NewLogMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown MAIL_BACKEND %q", cfg.Backend)
+	}
+}
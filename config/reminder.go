@@ -0,0 +1,11 @@
+package config
+
+import "time"
+
+// ReminderConfig berisi pengaturan scheduler pengingat deadline: REMINDERS_ENABLED (false mematikan
+// scheduler di instance ini) dan REMINDER_POLL_INTERVAL (seberapa sering deadline diperiksa; pengingat
+// terkirim paling lambat satu interval setelah jadwalnya).
+type ReminderConfig struct {
+	Enabled      bool
+	PollInterval time.Duration
+}
@@ -0,0 +1,15 @@
+package config
+
+import "time"
+
+// ServerConfig berisi pengaturan http.Server dan batas waktu graceful shutdown. Addr berasal dari PORT
+// (default 8080); timeout dari HTTP_READ_TIMEOUT, HTTP_READ_HEADER_TIMEOUT, HTTP_WRITE_TIMEOUT,
+// HTTP_IDLE_TIMEOUT, dan SHUTDOWN_TIMEOUT dalam format durasi Go (misal 15s, 1m).
+type ServerConfig struct {
+	Addr              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+}
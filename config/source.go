@@ -0,0 +1,140 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// source mencari nilai konfigurasi berdasarkan nama variabel env. Urutan prioritas: environment,
+// lalu file CONFIG_FILE; di masing-masing lapisan NAMA_FILE berisi path file yang isinya dipakai
+// sebagai nilai NAMA (untuk secret Docker/Kubernetes). Nilai yang tidak bisa di-parse dikumpulkan
+// di problems supaya semuanya dilaporkan sekaligus.
+type source struct {
+	file     map[string]string
+	problems []string
+}
+
+// newSource membaca file konfigurasi dari CONFIG_FILE jika diset. Formatnya ditentukan dari ekstensi
+// (.yaml, .yml, atau .toml) dan key-nya sama dengan nama variabel env, misalnya DB_DRIVER: postgres.
+func newSource() (*source, error) {
+	src := &source{file: map[string]string{}}
+
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		return src, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read CONFIG_FILE: %w", err)
+	}
+
+	values := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(raw, &values)
+	case ".toml":
+		err = toml.Unmarshal(raw, &values)
+	default:
+		return nil, fmt.Errorf("CONFIG_FILE %q must have a .yaml, .yml or .toml extension", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse CONFIG_FILE %q: %w", path, err)
+	}
+
+	for key, value := range values {
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			return nil, fmt.Errorf("CONFIG_FILE %q: %s must be a single value", path, key)
+		}
+		src.file[strings.ToUpper(key)] = fmt.Sprint(value)
+	}
+	return src, nil
+}
+
+// lookup mengembalikan nilai mentah untuk name beserta penanda apakah nilainya diset. Nilai kosong (misalnya
+// `JWT_SECRET=` yang tersisa di file .env) dianggap tidak diset, sehingga tidak bentrok dengan NAMA_FILE dan
+// tidak menutupi nilai dari lapisan berikutnya.
+func (s *source) lookup(name string) (string, bool) {
+	layers := []func(string) (string, bool){
+		os.LookupEnv,
+		func(key string) (string, bool) {
+			value, ok := s.file[key]
+			return value, ok
+		},
+	}
+	for _, get := range layers {
+		value, hasValue := get(name)
+		path, hasFile := get(name + "_FILE")
+		hasValue, hasFile = hasValue && value != "", hasFile && path != ""
+		switch {
+		case hasValue && hasFile:
+			s.problems = append(s.problems, fmt.Sprintf("%s and %s_FILE are both set; use only one", name, name))
+			return value, true
+		case hasValue:
+			return value, true
+		case hasFile:
+			content, err := os.ReadFile(path)
+			if err != nil {
+				s.problems = append(s.problems, fmt.Sprintf("%s_FILE: %v", name, err))
+				return "", false
+			}
+			// Editor dan `echo` biasanya menambahkan newline di akhir file secret
+			return strings.TrimRight(string(content), "\r\n"), true
+		}
+	}
+	return "", false
+}
+
+func (s *source) str(name, fallback string) string {
+	if value, ok := s.lookup(name); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+func (s *source) integer(name string, fallback int) int {
+	raw := s.str(name, "")
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		s.problems = append(s.problems, fmt.Sprintf("%s must be an integer, got %q", name, raw))
+		return fallback
+	}
+	return value
+}
+
+func (s *source) boolean(name string, fallback bool) bool {
+	raw := s.str(name, "")
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		s.problems = append(s.problems, fmt.Sprintf("%s must be true or false, got %q", name, raw))
+		return fallback
+	}
+	return value
+}
+
+// duration membaca durasi dalam format Go, misalnya 15s, 1m, atau 24h.
+func (s *source) duration(name string, fallback time.Duration) time.Duration {
+	raw := s.str(name, "")
+	if raw == "" {
+		return fallback
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		s.problems = append(s.problems, fmt.Sprintf("%s must be a duration such as 15s or 1m, got %q", name, raw))
+		return fallback
+	}
+	return value
+}
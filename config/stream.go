@@ -0,0 +1,33 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"taskify/realtime"
+)
+
+// StreamConfig berisi pengaturan stream event real-time: SSE_HEARTBEAT_INTERVAL (jeda komentar heartbeat
+// supaya proxy tidak menutup koneksi yang diam), EVENT_LOG_SIZE (jumlah event terakhir per proyek yang bisa
+// diputar ulang dengan Last-Event-ID), dan EVENT_LOG_TTL (umur log proyek yang tidak punya event baru).
+type StreamConfig struct {
+	HeartbeatInterval time.Duration
+	LogSize           int
+	LogTTL            time.Duration
+}
+
+// ConnectBroker memakai Redis sebagai backend pub/sub jika cacheCfg.RedisAddr diset, sehingga event sampai ke
+// client di semua instance, atau backend in-memory jika tidak.
+func ConnectBroker(ctx context.Context, cacheCfg CacheConfig, cfg StreamConfig) (realtime.Broker, error) {
+	if cacheCfg.RedisAddr == "" {
+		slog.Info("using in-memory realtime broker (REDIS_ADDR not set)")
+		return realtime.NewMemoryBroker(cfg.LogSize), nil
+	}
+
+	client, err := connectRedis(ctx, cacheCfg)
+	if err != nil {
+		return nil, err
+	}
+	return realtime.NewRedisBroker(client, cfg.LogSize, cfg.LogTTL), nil
+}
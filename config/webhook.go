@@ -0,0 +1,20 @@
+package config
+
+import "time"
+
+// WebhookConfig berisi pengaturan dispatcher webhook: WEBHOOK_TIMEOUT (batas waktu satu request ke
+// penerima), WEBHOOK_MAX_ATTEMPTS (jumlah percobaan sebelum pengiriman menjadi dead), WEBHOOK_BACKOFF_BASE
+// dan WEBHOOK_BACKOFF_MAX (jeda retry eksponensial), serta WEBHOOK_POLL_INTERVAL (seberapa sering outbox
+// dan antrean pengiriman diperiksa). WEBHOOK_RELAY_MAX_ATTEMPTS adalah jumlah percobaan membagikan satu event
+// outbox sebelum event itu disisihkan sebagai dead. WEBHOOK_ALLOW_PRIVATE_TARGETS=true mengizinkan webhook ke alamat
+// loopback, privat, dan link-local; bawaannya ditolak supaya webhook tidak bisa dipakai untuk menjangkau
+// jaringan internal server.
+type WebhookConfig struct {
+	Timeout             time.Duration
+	MaxAttempts         int
+	BackoffBase         time.Duration
+	BackoffMax          time.Duration
+	PollInterval        time.Duration
+	RelayMaxAttempts    int
+	AllowPrivateTargets bool
+}
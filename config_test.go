@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"taskify/config"
+)
+
+const validTestSecret = "0123456789abcdef0123456789abcdef"
+
+// writeFile membuat file sementara berisi content dan mengembalikan path-nya.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestConfigLoadFromEnvironment(t *testing.T) {
+	t.Setenv("DB_DRIVER", "SQLite")
+	t.Setenv("JWT_SECRET", validTestSecret)
+	t.Setenv("PORT", "9090")
+	t.Setenv("HTTP_WRITE_TIMEOUT", "45s")
+	t.Setenv("AUTH_RATE_LIMIT", "5")
+	t.Setenv("REQUIRE_IF_MATCH", "true")
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE_TARGETS", "true")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Database.Driver != "sqlite" || cfg.Server.Addr != ":9090" || cfg.Server.WriteTimeout != 45*time.Second {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+	if cfg.Auth.RateLimit != 5 || !cfg.API.RequireIfMatch || cfg.API.BulkMaxOperations != 100 || !cfg.Webhook.AllowPrivateTargets {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+}
+
+func TestConfigLoadFromFile(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": "DB_DRIVER: sqlite\nDB_NAME: from-file.db\nAUTH_RATE_LIMIT: 7\nIDEMPOTENCY_TTL: 1h\nREQUIRE_IF_MATCH: true\n",
+		"config.toml": "DB_DRIVER = \"sqlite\"\nDB_NAME = \"from-file.db\"\nAUTH_RATE_LIMIT = 7\nIDEMPOTENCY_TTL = \"1h\"\nREQUIRE_IF_MATCH = true\n",
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("CONFIG_FILE", writeFile(t, name, content))
+			t.Setenv("JWT_SECRET", validTestSecret)
+			t.Setenv("AUTH_RATE_LIMIT", "3") // Env menang atas file
+
+			cfg, err := config.Load()
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if cfg.Database.Name != "from-file.db" || cfg.API.IdempotencyTTL != time.Hour || !cfg.API.RequireIfMatch {
+				t.Fatalf("file values not applied: %+v", cfg)
+			}
+			if cfg.Auth.RateLimit != 3 {
+				t.Fatalf("expected env to override the file, got rate limit %d", cfg.Auth.RateLimit)
+			}
+		})
+	}
+}
+
+func TestConfigSecretFiles(t *testing.T) {
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("JWT_SECRET_FILE", writeFile(t, "jwt", validTestSecret+"\n"))
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Auth.JWTSecret != validTestSecret {
+		t.Fatalf("expected secret from file without trailing newline, got %q", cfg.Auth.JWTSecret)
+	}
+
+	// Baris kosong seperti `JWT_SECRET=` di file .env tidak dianggap bentrok dengan JWT_SECRET_FILE
+	t.Setenv("JWT_SECRET", "")
+	if cfg, err := config.Load(); err != nil || cfg.Auth.JWTSecret != validTestSecret {
+		t.Fatalf("expected an empty JWT_SECRET to be ignored, got %q, %v", cfg.Auth.JWTSecret, err)
+	}
+
+	t.Setenv("JWT_SECRET", validTestSecret)
+	if _, err := config.Load(); err == nil || !strings.Contains(err.Error(), "JWT_SECRET and JWT_SECRET_FILE are both set") {
+		t.Fatalf("expected an ambiguity error, got %v", err)
+	}
+}
+
+func TestConfigLoadDatabaseOnly(t *testing.T) {
+	// Subcommand migrate tidak butuh JWT_SECRET maupun pengaturan lain yang tidak valid
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("PORT", "not-a-port")
+	t.Setenv("DB_DRIVER", "SQLite")
+	t.Setenv("DB_NAME", "migrate.db")
+
+	cfg, err := config.LoadDatabase()
+	if err != nil {
+		t.Fatalf("load database config: %v", err)
+	}
+	if cfg.Driver != "sqlite" || cfg.Name != "migrate.db" {
+		t.Fatalf("unexpected database config: %+v", cfg)
+	}
+	if _, err := config.Load(); err == nil {
+		t.Fatal("expected the full config to stay invalid")
+	}
+
+	t.Setenv("DB_DRIVER", "oracle")
+	if _, err := config.LoadDatabase(); err == nil || !strings.Contains(err.Error(), "DB_DRIVER must be mysql, postgres or sqlite") {
+		t.Fatalf("expected the database config to be validated, got %v", err)
+	}
+}
+
+func TestConfigValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want []string
+	}{
+		{"missing secret", map[string]string{}, []string{"JWT_SECRET is required"}},
+		{"short secret", map[string]string{"JWT_SECRET": "too-short"}, []string{"at least 32 characters"}},
+		{"example secret", map[string]string{"JWT_SECRET": "rahasia"}, []string{"well-known example value"}},
+		{"unreadable secret file", map[string]string{"JWT_SECRET_FILE": "/does/not/exist"}, []string{"JWT_SECRET_FILE:", "JWT_SECRET is required"}},
+		{
+			"every problem reported at once",
+			map[string]string{
+				"JWT_SECRET":           validTestSecret,
+				"DB_DRIVER":            "oracle",
+				"PORT":                 "http",
+				"HTTP_READ_TIMEOUT":    "soon",
+				"BULK_MAX_OPERATIONS":  "0",
+				"LOG_FORMAT":           "xml",
+				"OTEL_TRACES_EXPORTER": "zipkin",
+			},
+			[]string{"DB_DRIVER must be", "PORT must be", "HTTP_READ_TIMEOUT must be a duration", "BULK_MAX_OPERATIONS must be positive", "LOG_FORMAT must be", "OTEL_TRACES_EXPORTER must be"},
+		},
+		{
+			"webhook retries",
+			map[string]string{"JWT_SECRET": validTestSecret, "WEBHOOK_MAX_ATTEMPTS": "0", "WEBHOOK_BACKOFF_MAX": "1s", "WEBHOOK_TIMEOUT": "0s"},
+			[]string{"WEBHOOK_TIMEOUT must be positive", "WEBHOOK_BACKOFF_MAX must not be shorter", "WEBHOOK_MAX_ATTEMPTS must be positive"},
+		},
+		{
+			"event stream",
+			map[string]string{"JWT_SECRET": validTestSecret, "EVENT_LOG_SIZE": "0", "SSE_HEARTBEAT_INTERVAL": "-1s"},
+			[]string{"EVENT_LOG_SIZE must be positive", "SSE_HEARTBEAT_INTERVAL must be positive"},
+		},
+		{
+			"collaboration",
+			map[string]string{"JWT_SECRET": validTestSecret, "COLLAB_LOCK_TTL": "0s", "WS_PING_INTERVAL": "-5s"},
+			[]string{"WS_PING_INTERVAL must be positive", "COLLAB_LOCK_TTL must be positive"},
+		},
+		{
+			"mail and reminders",
+			map[string]string{"JWT_SECRET": validTestSecret, "MAIL_BACKEND": "smtp", "MAIL_FROM": "taskify", "REMINDER_POLL_INTERVAL": "0s"},
+			[]string{"SMTP_ADDR must be host:port", "MAIL_FROM must be an email address", "REMINDER_POLL_INTERVAL must be positive"},
+		},
+		{
+			"import limits",
+			map[string]string{"JWT_SECRET": validTestSecret, "IMPORT_MAX_ROWS": "0", "IMPORT_MAX_BYTES": "-1", "IMPORT_POLL_INTERVAL": "0s"},
+			[]string{"IMPORT_MAX_ROWS must be positive", "IMPORT_MAX_BYTES must be positive", "IMPORT_POLL_INTERVAL must be positive"},
+		},
+		{"network database needs a host", map[string]string{"JWT_SECRET": validTestSecret, "DB_DRIVER": "postgres"}, []string{"DB_HOST is required", "DB_NAME is required"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DB_DRIVER", "sqlite")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			_, err := config.Load()
+			var invalid *config.ValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("expected a ValidationError, got %v", err)
+			}
+			if len(invalid.Problems) != len(tt.want) {
+				t.Fatalf("expected %d problems, got %v", len(tt.want), invalid.Problems)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Fatalf("expected %q in:\n%v", want, err)
+				}
+			}
+		})
+	}
+}
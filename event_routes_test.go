@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"taskify/config"
+	"taskify/models"
+	"taskify/realtime"
+	"taskify/service"
+)
+
+// sseEvent adalah satu blok Server-Sent Events; Comment terisi untuk baris komentar (heartbeat).
+type sseEvent struct {
+	ID      string
+	Event   string
+	Data    string
+	Comment string
+}
+
+// sseStream membaca blok SSE dari satu koneksi stream di goroutine terpisah.
+type sseStream struct {
+	t      *testing.T
+	resp   *http.Response
+	events chan sseEvent
+}
+
+// openStream membuka GET /api/projects/:project_id/events lewat server HTTP sungguhan, karena
+// httptest.ResponseRecorder tidak bisa dibaca sambil handler masih menulis.
+func (s *testServer) openStream(user testUser, projectID, lastEventID string) *sseStream {
+	s.t.Helper()
+
+	server := httptest.NewServer(s.router)
+	s.t.Cleanup(server.Close)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/projects/"+projectID+"/events", nil)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+user.Token)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		s.t.Fatalf("open event stream: %v", err)
+	}
+	s.t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		s.t.Fatalf("expected an event stream, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	stream := &sseStream{t: s.t, resp: resp, events: make(chan sseEvent, 100)}
+	go func() {
+		defer close(stream.events)
+		scanner := bufio.NewScanner(resp.Body)
+		var event sseEvent
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				if event != (sseEvent{}) {
+					stream.events <- event
+				}
+				event = sseEvent{}
+			case strings.HasPrefix(line, ":"):
+				event.Comment = strings.TrimSpace(line[1:])
+			case strings.HasPrefix(line, "id: "):
+				event.ID = line[len("id: "):]
+			case strings.HasPrefix(line, "event: "):
+				event.Event = line[len("event: "):]
+			case strings.HasPrefix(line, "data: "):
+				event.Data = line[len("data: "):]
+			}
+		}
+	}()
+	return stream
+}
+
+// next mengembalikan event berikutnya (melewati heartbeat dan retry) atau menghentikan test setelah 2 detik.
+func (st *sseStream) next() sseEvent {
+	st.t.Helper()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event, ok := <-st.events:
+			if !ok {
+				st.t.Fatal("event stream closed unexpectedly")
+			}
+			if event.Event == "" {
+				continue
+			}
+			return event
+		case <-timeout:
+			st.t.Fatal("timed out waiting for an event")
+		}
+	}
+}
+
+// expectClosed memastikan server menutup stream.
+func (st *sseStream) expectClosed() {
+	st.t.Helper()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case _, ok := <-st.events:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			st.t.Fatal("expected the server to close the stream")
+		}
+	}
+}
+
+func TestEventStreamAuthorization(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	stranger := s.register("stranger")
+	projectID := s.createProject(owner, "Private")
+	path := "/api/projects/" + projectID + "/events"
+
+	expectStatus(t, s.do(http.MethodGet, path, "", nil), http.StatusUnauthorized)
+	expectStatus(t, s.do(http.MethodGet, path, stranger.Token, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, "/api/projects/not-a-uuid/events", owner.Token, nil), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodGet, path, owner.Token, nil, header{"Last-Event-ID", "abc"}), http.StatusBadRequest)
+}
+
+func TestEventStreamDeliversAndResumes(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Live")
+
+	stream := s.openStream(owner, projectID, "")
+	taskID := s.createTask(owner, projectID, "Watch me", nil)
+	rec := s.do(http.MethodPatch, "/api/projects/"+projectID+"/tasks/"+taskID, owner.Token, gin.H{"status": "in_progress"})
+	expectStatus(t, rec, http.StatusOK)
+	s.dispatch()
+
+	want := []struct{ id, event string }{
+		{"1", models.EventTaskCreated},
+		{"2", models.EventTaskUpdated},
+		{"3", models.EventTaskStatusChanged},
+	}
+	for _, w := range want {
+		event := stream.next()
+		if event.ID != w.id || event.Event != w.event {
+			t.Fatalf("expected %s %s, got %+v", w.id, w.event, event)
+		}
+		if !strings.Contains(event.Data, `"type":"`+w.event+`"`) || !strings.Contains(event.Data, taskID) {
+			t.Fatalf("unexpected data: %s", event.Data)
+		}
+	}
+
+	// Event yang terjadi saat client terputus diputar ulang sesudah Last-Event-ID
+	s.createTask(owner, projectID, "While away", nil)
+	s.dispatch()
+	resumed := s.openStream(owner, projectID, "3")
+	if event := resumed.next(); event.ID != "4" || event.Event != models.EventTaskCreated || !strings.Contains(event.Data, "While away") {
+		t.Fatalf("expected the missed task.created to be replayed, got %+v", event)
+	}
+
+	// Stream berakhir setelah proyeknya dihapus
+	expectStatus(t, s.do(http.MethodDelete, "/api/projects/detail/"+projectID, owner.Token, nil), http.StatusOK)
+	s.dispatch()
+	if event := resumed.next(); event.Event != models.EventProjectDeleted {
+		t.Fatalf("expected project.deleted, got %+v", event)
+	}
+	resumed.expectClosed()
+}
+
+func TestEventStreamResetsWhenLogNoLongerCoversLastEventID(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) { cfg.Stream.LogSize = 2 })
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Busy")
+
+	for _, title := range []string{"One", "Two", "Three", "Four"} {
+		s.createTask(owner, projectID, title, nil)
+	}
+	s.dispatch()
+
+	stream := s.openStream(owner, projectID, "1")
+	if event := stream.next(); event.Event != service.EventReset || event.ID != "" {
+		t.Fatalf("expected a reset event, got %+v", event)
+	}
+
+	// Sesudah reset, event baru tetap mengalir
+	s.createTask(owner, projectID, "Five", nil)
+	s.dispatch()
+	if event := stream.next(); event.ID != "5" || !strings.Contains(event.Data, "Five") {
+		t.Fatalf("expected the next live event, got %+v", event)
+	}
+}
+
+func TestEventStreamHeartbeatAndShutdown(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) { cfg.Stream.HeartbeatInterval = 20 * time.Millisecond })
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Quiet")
+
+	stream := s.openStream(owner, projectID, "")
+	timeout := time.After(2 * time.Second)
+	for heartbeat := false; !heartbeat; {
+		select {
+		case event := <-stream.events:
+			heartbeat = event.Comment == "heartbeat"
+		case <-timeout:
+			t.Fatal("expected a heartbeat comment")
+		}
+	}
+
+	// Shutdown memutus stream yang terbuka supaya tidak menahan server
+	s.app.closeStreams()
+	stream.expectClosed()
+}
+
+// stubBroker adalah Broker yang pesannya dikirim langsung oleh test lewat messages, dan channel-nya tidak
+// ditutup saat langganan dibatalkan, sehingga fanOut masih bisa menerima pesan setelah Hub.Close.
+type stubBroker struct{ messages chan realtime.Message }
+
+func (b stubBroker) Publish(context.Context, string, []byte) (realtime.Message, error) {
+	return realtime.Message{}, nil
+}
+
+func (b stubBroker) Since(context.Context, string, uint64) ([]realtime.Message, bool, error) {
+	return nil, true, nil
+}
+
+func (b stubBroker) Subscribe(context.Context, string) (<-chan realtime.Message, error) {
+	return b.messages, nil
+}
+
+func TestHubCloseWhileFanOutDelivers(t *testing.T) {
+	broker := stubBroker{messages: make(chan realtime.Message)}
+	hub := realtime.NewHub(broker, 4)
+	sub, err := hub.Subscribe(context.Background(), "project:1")
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	hub.Close()
+	if _, ok := <-sub.C; ok || sub.Err() != realtime.ErrClosed {
+		t.Fatalf("expected the subscription to end with ErrClosed, got %v", sub.Err())
+	}
+
+	// Channel tanpa buffer: pesan kedua baru diterima setelah fanOut selesai meneruskan pesan pertama, yang
+	// dulu panic karena mengirim ke channel pelanggan yang sudah ditutup Close
+	broker.messages <- realtime.Message{ID: 1}
+	broker.messages <- realtime.Message{ID: 2}
+	close(broker.messages)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestHealthRoutes(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		want   map[string]string
+	}{
+		{"liveness", "/healthz", http.StatusOK, map[string]string{"status": "ok"}},
+		{"readiness", "/readyz", http.StatusOK, map[string]string{"status": "ready", "checks.database": "ok", "checks.cache": "ok"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.do(http.MethodGet, tt.path, "", nil)
+			expectStatus(t, rec, tt.status)
+			body := decode(t, rec)
+			for path, want := range tt.want {
+				if got := str(body, path); got != want {
+					t.Fatalf("%s: expected %q, got %q", path, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestReadinessFailsWhenDatabaseIsDown(t *testing.T) {
+	s := newTestServer(t)
+
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.Close()
+
+	rec := s.do(http.MethodGet, "/readyz", "", nil)
+	expectStatus(t, rec, http.StatusServiceUnavailable)
+	if got := str(decode(t, rec), "checks.database"); got != "unavailable" {
+		t.Fatalf("expected database to be unavailable, got %q", got)
+	}
+
+	// Liveness tidak bergantung pada database
+	expectStatus(t, s.do(http.MethodGet, "/healthz", "", nil), http.StatusOK)
+}
@@ -0,0 +1,102 @@
+// Package ical menulis kalender dalam format iCalendar (RFC 5545): baris diakhiri CRLF, baris panjang dilipat
+// pada 75 oktet, dan nilai teks di-escape.
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets adalah panjang maksimum satu baris sebelum dilipat (RFC 5545 bagian 3.1).
+const maxLineOctets = 75
+
+// Property adalah satu baris content line. Params ditulis apa adanya, misalnya "VALUE=DATE"; Value harus
+// sudah di-escape dengan Text jika bertipe TEXT.
+type Property struct {
+	Name   string
+	Params []string
+	Value  string
+}
+
+// Component adalah satu komponen kalender, misalnya VEVENT atau VTODO.
+type Component struct {
+	Name       string
+	Properties []Property
+}
+
+// Add menambahkan properti ke komponen.
+func (c *Component) Add(name, value string, params ...string) {
+	c.Properties = append(c.Properties, Property{Name: name, Params: params, Value: value})
+}
+
+// Calendar adalah satu objek VCALENDAR.
+type Calendar struct {
+	ProductID  string // PRODID, misalnya "-//Taskify//Taskify API//EN"
+	Name       string // Nama yang ditampilkan aplikasi kalender (X-WR-CALNAME); kosong berarti tidak ditulis
+	Components []Component
+}
+
+// Encode mengembalikan kalender dalam format iCalendar.
+func (c Calendar) Encode() []byte {
+	var buf bytes.Buffer
+	writeLine(&buf, Property{Name: "BEGIN", Value: "VCALENDAR"})
+	writeLine(&buf, Property{Name: "VERSION", Value: "2.0"})
+	writeLine(&buf, Property{Name: "PRODID", Value: c.ProductID})
+	writeLine(&buf, Property{Name: "CALSCALE", Value: "GREGORIAN"})
+	writeLine(&buf, Property{Name: "METHOD", Value: "PUBLISH"})
+	if c.Name != "" {
+		writeLine(&buf, Property{Name: "X-WR-CALNAME", Value: Text(c.Name)})
+	}
+	for _, component := range c.Components {
+		writeLine(&buf, Property{Name: "BEGIN", Value: component.Name})
+		for _, property := range component.Properties {
+			writeLine(&buf, property)
+		}
+		writeLine(&buf, Property{Name: "END", Value: component.Name})
+	}
+	writeLine(&buf, Property{Name: "END", Value: "VCALENDAR"})
+	return buf.Bytes()
+}
+
+// writeLine menulis property sebagai content line, dilipat menjadi beberapa baris jika lebih dari 75 oktet.
+// Lipatan tidak pernah memotong karakter UTF-8.
+func writeLine(buf *bytes.Buffer, property Property) {
+	line := property.Name
+	for _, param := range property.Params {
+		line += ";" + param
+	}
+	line += ":" + property.Value
+
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1 // Spasi di awal baris lanjutan ikut dihitung
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// Text meng-escape nilai bertipe TEXT.
+func Text(value string) string {
+	return textEscaper.Replace(value)
+}
+
+// Date memformat t sebagai DATE (YYYYMMDD) menurut tanggal kalendernya di UTC.
+func Date(t time.Time) string {
+	return t.UTC().Format("20060102")
+}
+
+// DateTime memformat t sebagai DATE-TIME UTC (YYYYMMDDTHHMMSSZ).
+func DateTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/models"
+)
+
+const trelloBoard = `{
+  "name": "Website Relaunch",
+  "desc": "Marketing site",
+  "lists": [
+    {"id": "l1", "name": "To Do", "closed": false},
+    {"id": "l2", "name": "Doing", "closed": false},
+    {"id": "l3", "name": "Ideas", "closed": false},
+    {"id": "l4", "name": "Old", "closed": true}
+  ],
+  "cards": [
+    {"id": "c1", "name": "Write copy", "desc": "Homepage copy", "idList": "l1", "due": "2030-04-01T09:00:00.000Z",
+     "shortUrl": "https://trello.com/c/abc", "labels": [{"name": "content", "color": "green"}, {"name": "", "color": "red"}],
+     "idChecklists": ["k1"]},
+    {"id": "c2", "name": "Build pages", "desc": "", "idList": "l2", "due": null},
+    {"id": "c3", "name": "Launch party", "desc": "", "idList": "l3", "due": "2030-05-01T12:00:00.000Z", "dueComplete": true},
+    {"id": "c4", "name": "Dark mode", "desc": "", "idList": "l3"},
+    {"id": "c5", "name": "Archived card", "desc": "", "idList": "l1", "closed": true},
+    {"id": "c6", "name": "Card in archived list", "desc": "", "idList": "l4"}
+  ],
+  "checklists": [
+    {"id": "k1", "idCard": "c1", "name": "Sections", "pos": 1, "checkItems": [
+      {"name": "Footer", "state": "incomplete", "pos": 2},
+      {"name": "Hero", "state": "complete", "pos": 1}
+    ]}
+  ]
+}`
+
+const jiraExport = "Summary,Issue key,Issue id,Parent id,Issue Type,Status,Status Category,Due Date,Labels,Labels,Description,Project name\n" +
+	"Login page,APP-1,10001,,Story,Awaiting QA,In Progress,15/Mar/30 12:00 AM,auth,frontend,\"Users sign in\nwith email\",Mobile App\n" +
+	"Add form,APP-2,10002,10001,Sub-task,Done,Done,,,,,Mobile App\n" +
+	"Add validation,APP-3,10003,10001,Sub-task,To Do,To Do,,,,,Mobile App\n" +
+	"Crash on start,APP-4,10004,,Bug,Open,To Do,2030-03-20,,,,Mobile App\n"
+
+// processImports menjalankan satu putaran worker import seperti Run.
+func (s *testServer) processImports() {
+	s.t.Helper()
+	if _, err := s.app.imports.ProcessDue(context.Background(), time.Now()); err != nil {
+		s.t.Fatalf("process import jobs: %v", err)
+	}
+}
+
+// importJob mengambil import job lewat API.
+func (s *testServer) importJob(user testUser, jobID string) map[string]interface{} {
+	s.t.Helper()
+	rec := s.do(http.MethodGet, "/api/imports/"+jobID, user.Token, nil)
+	expectStatus(s.t, rec, http.StatusOK)
+	return decode(s.t, rec)
+}
+
+// importedProject mengambil proyek hasil import langsung dari database.
+func (s *testServer) importedProject(projectID string) models.Project {
+	s.t.Helper()
+	var project models.Project
+	if err := s.db.First(&project, "id = ?", projectID).Error; err != nil {
+		s.t.Fatalf("find imported project: %v", err)
+	}
+	return project
+}
+
+func TestImportTrelloJob(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	stranger := s.register("stranger")
+
+	rec := s.upload("/api/imports?format=trello&status_map=Ideas:in_progress", owner.Token, "board.json", trelloBoard)
+	expectStatus(t, rec, http.StatusAccepted)
+	body := decode(t, rec)
+	jobID := str(body, "job.id")
+	if rec.Header().Get("Location") != "/api/imports/"+jobID || str(body, "job.status") != models.ImportQueued ||
+		str(body, "job.format") != "trello" || str(body, "job.filename") != "board.json" || str(body, "job.options.status_map.Ideas") != "in_progress" {
+		t.Fatalf("unexpected queued job: %v %v", rec.Header(), body)
+	}
+	expectStatus(t, s.do(http.MethodGet, "/api/imports/"+jobID, stranger.Token, nil), http.StatusNotFound)
+
+	s.processImports()
+
+	body = s.importJob(owner, jobID)
+	if str(body, "job.status") != models.ImportCompleted || str(body, "job.project_id") != jobID ||
+		lookup(body, "job.processed") != float64(4) || lookup(body, "job.total") != float64(4) ||
+		lookup(body, "job.report.imported") != float64(4) || str(body, "job.report.warnings.0") != "Skipped 2 archived cards" {
+		t.Fatalf("unexpected completed job: %v", body)
+	}
+
+	if project := s.importedProject(jobID); project.Name != "Website Relaunch" || project.Description != "Marketing site" {
+		t.Fatalf("unexpected project: %+v", project)
+	}
+
+	tasks := s.projectTasks(owner, jobID)
+	if len(tasks) != 4 {
+		t.Fatalf("expected 4 tasks, got %v", tasks)
+	}
+	copyTask := tasks["Write copy"]
+	wantDescription := "Homepage copy\n\nLabels: content, red\n\nChecklist: Sections\n- [x] Hero\n- [ ] Footer\n\nTrello: https://trello.com/c/abc"
+	if copyTask["status"] != "todo" || copyTask["description"] != wantDescription || !strings.HasPrefix(copyTask["deadline"].(string), "2030-04-01") {
+		t.Fatalf("unexpected card task: %v", copyTask)
+	}
+	if tasks["Build pages"]["status"] != "in_progress" || tasks["Launch party"]["status"] != "done" || tasks["Dark mode"]["status"] != "in_progress" {
+		t.Fatalf("unexpected statuses: %v", tasks)
+	}
+
+	rec = s.do(http.MethodGet, "/api/imports", owner.Token, nil)
+	expectStatus(t, rec, http.StatusOK)
+	if body := decode(t, rec); count(body, "jobs") != 1 || str(body, "jobs.0.id") != jobID || lookup(body, "jobs.0.report") != nil {
+		t.Fatalf("unexpected job list: %v", body)
+	}
+	rec = s.do(http.MethodGet, "/api/imports", stranger.Token, nil)
+	expectStatus(t, rec, http.StatusOK)
+	if body := decode(t, rec); count(body, "jobs") != 0 {
+		t.Fatalf("stranger should not see jobs: %v", body)
+	}
+}
+
+func TestImportJira(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+
+	rec := s.do(http.MethodPost, "/api/imports?format=jira", owner.Token, jiraExport, csvHeader)
+	expectStatus(t, rec, http.StatusAccepted)
+	jobID := str(decode(t, rec), "job.id")
+	s.processImports()
+
+	body := s.importJob(owner, jobID)
+	if str(body, "job.status") != models.ImportCompleted || lookup(body, "job.report.rows") != float64(2) ||
+		str(body, "job.report.warnings.0") != "Added 2 sub-tasks as checklist items of their parent issue" ||
+		str(body, "job.report.results.0.warnings.0") != `Unknown status "Awaiting QA" was mapped to in_progress from "In Progress"` {
+		t.Fatalf("unexpected Jira job: %v", body)
+	}
+
+	tasks := s.projectTasks(owner, jobID)
+	login := tasks["Login page"]
+	wantDescription := "Users sign in\nwith email\n\nLabels: auth, frontend\n\nChecklist: Sub-tasks\n- [x] APP-2 Add form\n- [ ] APP-3 Add validation\n\nJira: APP-1"
+	if len(tasks) != 2 || login["status"] != "in_progress" || login["description"] != wantDescription ||
+		!strings.HasPrefix(login["deadline"].(string), "2030-03-15") {
+		t.Fatalf("unexpected Jira tasks: %v", tasks)
+	}
+	if crash := tasks["Crash on start"]; crash["status"] != "todo" || !strings.HasPrefix(crash["deadline"].(string), "2030-03-20") {
+		t.Fatalf("unexpected bug task: %v", crash)
+	}
+	if project := s.importedProject(jobID); project.Name != "Mobile App" {
+		t.Fatalf("expected project name from the export, got %q", project.Name)
+	}
+
+	// Import langsung juga menerima format Trello dan Jira
+	rec = s.do(http.MethodPost, "/api/projects/import?format=jira&name=Direct", owner.Token, jiraExport, csvHeader)
+	expectStatus(t, rec, http.StatusCreated)
+	if body := decode(t, rec); str(body, "project.name") != "Direct" || lookup(body, "report.imported") != float64(2) {
+		t.Fatalf("unexpected direct Jira import: %v", body)
+	}
+	rec = s.upload("/api/projects/import?format=trello", owner.Token, "board.json", trelloBoard)
+	expectStatus(t, rec, http.StatusCreated)
+	if body := decode(t, rec); str(body, "project.name") != "Website Relaunch" || lookup(body, "report.imported") != float64(4) {
+		t.Fatalf("unexpected direct Trello import: %v", body)
+	}
+}
+
+func TestImportJobFailures(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+
+	expectStatus(t, s.do(http.MethodPost, "/api/imports?format=asana", owner.Token, trelloBoard), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/api/imports?format=trello&mode=all", owner.Token, trelloBoard), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodGet, "/api/imports/"+uuid.NewString(), owner.Token, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, "/api/imports/not-a-uuid", owner.Token, nil), http.StatusBadRequest)
+
+	queue := func(path, content string) string {
+		t.Helper()
+		rec := s.do(http.MethodPost, path, owner.Token, content)
+		expectStatus(t, rec, http.StatusAccepted)
+		return str(decode(t, rec), "job.id")
+	}
+	notTrello := queue("/api/imports?format=trello", `{"name": "Not a board"}`)
+	badDate := strings.Replace(jiraExport, "2030-03-20", "next week", 1)
+	atomic := queue("/api/imports?format=jira", badDate)
+	bestEffort := queue("/api/imports?format=jira&mode=best_effort", badDate)
+	dryRun := queue("/api/imports?format=trello&dry_run=true", trelloBoard)
+	s.processImports()
+
+	body := s.importJob(owner, notTrello)
+	if str(body, "job.status") != models.ImportFailed || str(body, "job.error") != "File is not a Trello board export; expected lists and cards" {
+		t.Fatalf("unexpected invalid file job: %v", body)
+	}
+	body = s.importJob(owner, atomic)
+	if str(body, "job.status") != models.ImportFailed || str(body, "job.error") != "Import failed; no changes were applied" ||
+		lookup(body, "job.report.aborted") != true || str(body, "job.report.results.1.errors.0") != `deadline "next week" must use the YYYY-MM-DD format` ||
+		lookup(body, "job.project_id") != nil {
+		t.Fatalf("unexpected aborted job: %v", body)
+	}
+	body = s.importJob(owner, bestEffort)
+	if str(body, "job.status") != models.ImportCompleted || lookup(body, "job.report.imported") != float64(1) || lookup(body, "job.processed") != float64(1) {
+		t.Fatalf("unexpected best effort job: %v", body)
+	}
+	body = s.importJob(owner, dryRun)
+	if str(body, "job.status") != models.ImportCompleted || lookup(body, "job.report.dry_run") != true || lookup(body, "job.project_id") != nil {
+		t.Fatalf("unexpected dry run job: %v", body)
+	}
+	if projects := s.countProjects(owner); projects != 1 {
+		t.Fatalf("expected only the best effort import to create a project, got %d", projects)
+	}
+
+	// File job yang sudah selesai tidak disimpan lagi
+	var job models.ImportJob
+	s.db.Select("data").First(&job, "id = ?", bestEffort)
+	if len(job.Data) != 0 {
+		t.Fatalf("expected the uploaded file to be discarded, got %d bytes", len(job.Data))
+	}
+}
+
+func TestImportJobRetry(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+
+	queue := func() string {
+		t.Helper()
+		rec := s.upload("/api/imports?format=trello", owner.Token, "board.json", trelloBoard)
+		expectStatus(t, rec, http.StatusAccepted)
+		return str(decode(t, rec), "job.id")
+	}
+	abandon := func(jobID string, attempts int) {
+		t.Helper()
+		expired := time.Now().Add(-time.Minute).UTC()
+		s.db.Model(&models.ImportJob{}).Where("id = ?", jobID).
+			Updates(map[string]interface{}{"status": models.ImportRunning, "lease_until": expired, "attempts": attempts})
+	}
+
+	// Instance sebelumnya mati setelah menyimpan sebagian tugas: sisanya dibuang dan import diulang
+	interrupted := queue()
+	abandon(interrupted, 1)
+	projectID := uuid.MustParse(interrupted)
+	s.db.Create(&models.Project{ID: projectID, Name: "Partial", CreatedByID: uuid.MustParse(owner.ID), Version: 1})
+	s.db.Omit("Project").Create(&models.Task{ProjectID: projectID, Title: "Leftover", Status: models.Todo, Version: 1})
+	// Proyek setengah jadi sudah terlihat oleh pemiliknya (dan masuk cache akses) selama import berjalan
+	expectStatus(t, s.do(http.MethodGet, "/api/projects/detail/"+interrupted, owner.Token, nil), http.StatusOK)
+	s.createWebhook(owner, interrupted, "https://hooks.example.com/partial", "*")
+
+	// Job yang lease-nya belum habis masih dipegang worker lain
+	running := queue()
+	s.db.Model(&models.ImportJob{}).Where("id = ?", running).
+		Updates(map[string]interface{}{"status": models.ImportRunning, "lease_until": time.Now().Add(time.Minute).UTC(), "attempts": 1})
+
+	exhausted := queue()
+	abandon(exhausted, 3)
+	s.processImports()
+
+	body := s.importJob(owner, interrupted)
+	if str(body, "job.status") != models.ImportCompleted || lookup(body, "job.attempts") != float64(2) {
+		t.Fatalf("unexpected retried job: %v", body)
+	}
+	if tasks := s.projectTasks(owner, interrupted); len(tasks) != 4 || tasks["Leftover"] != nil {
+		t.Fatalf("expected the partial import to be replaced, got %v", tasks)
+	}
+	if name := str(decode(t, s.do(http.MethodGet, "/api/projects/detail/"+interrupted, owner.Token, nil)), "project.name"); name == "Partial" {
+		t.Fatal("the discarded project must not be served from the access cache")
+	}
+	var deleted int64
+	s.db.Model(&models.OutboxEvent{}).Where("type = ? AND project_id = ?", models.EventProjectDeleted, projectID).Count(&deleted)
+	if deleted != 1 {
+		t.Fatalf("expected a project.deleted event for the discarded project, got %d", deleted)
+	}
+	s.dispatch()
+	if webhooks := count(decode(t, s.do(http.MethodGet, "/api/projects/"+interrupted+"/webhooks", owner.Token, nil)), "webhooks"); webhooks != 0 {
+		t.Fatalf("webhooks of the discarded project should be removed, got %d", webhooks)
+	}
+	if body := s.importJob(owner, running); str(body, "job.status") != models.ImportRunning {
+		t.Fatalf("job with an active lease should not be taken over: %v", body)
+	}
+	body = s.importJob(owner, exhausted)
+	if str(body, "job.status") != models.ImportFailed || str(body, "job.error") != "Import was interrupted too many times; upload the file again" {
+		t.Fatalf("unexpected exhausted job: %v", body)
+	}
+	if projects := s.countProjects(owner); projects != 1 {
+		t.Fatalf("expected a single imported project, got %d", projects)
+	}
+}
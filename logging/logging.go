@@ -0,0 +1,125 @@
+// Package logging menyiapkan logger log/slog aplikasi: format JSON (atau text), level yang bisa diatur,
+// redaksi atribut sensitif, dan logger per request yang dibawa lewat context.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Redacted menggantikan nilai atribut sensitif di log.
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys adalah nama atribut (case-insensitive, di group mana pun) yang nilainya tidak pernah dicetak.
+var sensitiveKeys = map[string]bool{
+	"authorization": true,
+	"cookie":        true,
+	"set-cookie":    true,
+	"password":      true,
+	"token":         true,
+	"jwt_secret":    true,
+	"secret":        true,
+}
+
+// Setup memasang logger default dengan level (LOG_LEVEL: debug, info, warn, error) dan format
+// (LOG_FORMAT: json atau text). Package log standar ikut diarahkan ke logger ini.
+func Setup(levelName, format string) error {
+	level, err := ParseLevel(levelName)
+	if err != nil {
+		return err
+	}
+	if format != "json" && format != "text" {
+		return fmt.Errorf("unsupported LOG_FORMAT %q (expected json or text)", format)
+	}
+
+	slog.SetDefault(slog.New(NewHandler(os.Stdout, level, format)))
+	return nil
+}
+
+// ParseLevel mengubah nama level menjadi slog.Level; string kosong berarti info.
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unsupported LOG_LEVEL %q (expected debug, info, warn or error)", name)
+}
+
+// NewHandler membuat handler slog dengan redaksi atribut sensitif. format "text" menghasilkan key=value,
+// selain itu JSON.
+func NewHandler(w io.Writer, level slog.Leveler, format string) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redact}
+	if format == "text" {
+		return slog.NewTextHandler(w, opts)
+	}
+	return slog.NewJSONHandler(w, opts)
+}
+
+func redact(_ []string, attr slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(attr.Key)] {
+		return slog.String(attr.Key, Redacted)
+	}
+	return attr
+}
+
+// RedactPath mengganti segmen path yang berisi parameter rute sensitif (misalnya :token pada feed kalender)
+// dengan Redacted, supaya path request aman dicatat di log dan span.
+func RedactPath(path string, params gin.Params) string {
+	var secrets []string
+	for _, param := range params {
+		if sensitiveKeys[strings.ToLower(param.Key)] && param.Value != "" {
+			secrets = append(secrets, param.Value)
+		}
+	}
+	if len(secrets) == 0 {
+		return path
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		for _, secret := range secrets {
+			if segment == secret {
+				segments[i] = Redacted
+			}
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Fatal mencatat pesan di level error lalu menghentikan proses, pengganti log.Fatalf saat start.
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+type loggerKey struct{}
+
+// WithLogger menyimpan logger di context, biasanya logger per request yang sudah berisi request_id.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext mengambil logger dari context, atau logger default jika tidak ada.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// With menambahkan atribut ke logger di context dan mengembalikan context baru.
+func With(ctx context.Context, args ...any) context.Context {
+	return WithLogger(ctx, FromContext(ctx).With(args...))
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"taskify/logging"
+)
+
+// captureLogs mengarahkan logger default ke buffer JSON di level debug selama test berjalan.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(logging.NewHandler(&buffer, slog.LevelDebug, "json")))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buffer
+}
+
+// logEntries mem-parse setiap baris log JSON dengan pesan msg.
+func logEntries(t *testing.T, buffer *bytes.Buffer, msg string) []map[string]interface{} {
+	t.Helper()
+
+	var entries []map[string]interface{}
+	scanner := bufio.NewScanner(bytes.NewReader(buffer.Bytes()))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("log line is not JSON: %s", scanner.Text())
+		}
+		if entry["msg"] == msg {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func TestRequestIDs(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{"accepts a valid incoming id", "req-123.abc:7", true},
+		{"generates an id when missing", "", false},
+		{"replaces an id with unsafe characters", "bad id\nwith newline", false},
+		{"replaces an overlong id", strings.Repeat("a", 129), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var headers []header
+			if tt.incoming != "" {
+				headers = append(headers, header{"X-Request-ID", tt.incoming})
+			}
+			rec := s.do(http.MethodGet, "/api/projects", "", nil, headers...)
+			expectStatus(t, rec, http.StatusUnauthorized)
+
+			id := rec.Header().Get("X-Request-ID")
+			if tt.keep && id != tt.incoming {
+				t.Fatalf("expected incoming id %q to be echoed, got %q", tt.incoming, id)
+			}
+			if !tt.keep {
+				if _, err := uuid.Parse(id); err != nil {
+					t.Fatalf("expected a generated UUID, got %q", id)
+				}
+			}
+			if got := str(decode(t, rec), "request_id"); got != id {
+				t.Fatalf("error body request_id %q does not match header %q", got, id)
+			}
+		})
+	}
+
+	t.Run("successful bodies are untouched", func(t *testing.T) {
+		rec := s.do(http.MethodGet, "/healthz", "", nil)
+		expectStatus(t, rec, http.StatusOK)
+		if _, ok := decode(t, rec)["request_id"]; ok {
+			t.Fatal("request_id should only be added to error bodies")
+		}
+	})
+}
+
+func TestAccessLogCarriesRequestContextAndRedactsSecrets(t *testing.T) {
+	logs := captureLogs(t)
+	s := newTestServer(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Logged")
+
+	rec := s.do(http.MethodGet, "/api/projects/detail/"+projectID, owner.Token, nil, header{"X-Request-ID", "trace-me"})
+	expectStatus(t, rec, http.StatusOK)
+
+	var entry map[string]interface{}
+	for _, candidate := range logEntries(t, logs, "request completed") {
+		if candidate["request_id"] == "trace-me" {
+			entry = candidate
+		}
+	}
+	if entry == nil {
+		t.Fatalf("no access log for the request in:\n%s", logs.String())
+	}
+
+	want := map[string]interface{}{
+		"user_id": owner.ID,
+		"route":   "/api/projects/detail/:id",
+		"method":  "GET",
+		"status":  float64(200),
+		"path":    "/api/projects/detail/" + projectID,
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Fatalf("%s: expected %v, got %v", key, value, entry[key])
+		}
+	}
+	if str(entry, "headers.authorization") != logging.Redacted {
+		t.Fatalf("authorization header was not redacted: %v", entry["headers"])
+	}
+
+	// Token dan password dari register/login tidak pernah muncul di log
+	for _, secret := range []string{owner.Token, "secret123"} {
+		if strings.Contains(logs.String(), secret) {
+			t.Fatalf("log output contains secret %q", secret)
+		}
+	}
+}
+
+func TestRedactsSensitiveAttributes(t *testing.T) {
+	logs := captureLogs(t)
+	slog.Info("user input", "password", "hunter2", slog.Group("request", "Authorization", "Bearer abc"))
+
+	output := logs.String()
+	if strings.Contains(output, "hunter2") || strings.Contains(output, "Bearer abc") {
+		t.Fatalf("sensitive values leaked: %s", output)
+	}
+}
+
+func TestRecoveryLogsPanicsWithRequestID(t *testing.T) {
+	logs := captureLogs(t)
+	s := newTestServer(t)
+	s.router.GET("/panic", func(c *gin.Context) { panic("boom") })
+
+	rec := s.do(http.MethodGet, "/panic", "", nil, header{"X-Request-ID", "panic-1"})
+	expectStatus(t, rec, http.StatusInternalServerError)
+	if str(decode(t, rec), "request_id") != "panic-1" {
+		t.Fatalf("expected request_id in the 500 body, got %s", rec.Body.String())
+	}
+
+	entries := logEntries(t, logs, "panic recovered")
+	if len(entries) != 1 || entries[0]["request_id"] != "panic-1" || entries[0]["panic"] != "boom" {
+		t.Fatalf("unexpected panic log: %v", entries)
+	}
+}
+
+func TestAccessLogRedactsCalendarFeedToken(t *testing.T) {
+	logs := captureLogs(t)
+	s := newTestServer(t)
+	owner := s.register("owner")
+	path := s.createCalendarFeed(owner, "/api/me/calendar-feed")
+	token := strings.TrimPrefix(path, "/api/calendar/")
+
+	expectStatus(t, s.do(http.MethodGet, path, "", nil, header{"X-Request-ID", "fetch-feed"}), http.StatusOK)
+
+	var entry map[string]interface{}
+	for _, candidate := range logEntries(t, logs, "request completed") {
+		if candidate["request_id"] == "fetch-feed" {
+			entry = candidate
+		}
+	}
+	if entry == nil {
+		t.Fatalf("no access log for the feed request in:\n%s", logs.String())
+	}
+	if entry["route"] != "/api/calendar/:token" || entry["path"] != "/api/calendar/"+logging.Redacted {
+		t.Fatalf("expected a redacted path, got route %v and path %v", entry["route"], entry["path"])
+	}
+	if strings.Contains(logs.String(), token) {
+		t.Fatalf("log output contains the calendar token %q", token)
+	}
+}
@@ -0,0 +1,33 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FileMailer menulis setiap email sebagai file .eml di sebuah direktori alih-alih mengirimnya, supaya email
+// bisa diperiksa saat development dan di test.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer membuat FileMailer yang menulis ke dir (dibuat jika belum ada) dengan pengirim from.
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create mail directory %s: %w", dir, err)
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+func (m *FileMailer) Send(_ context.Context, message Message) error {
+	now := time.Now()
+	// Nama file diawali waktu kirim supaya urutan abjad sama dengan urutan pengiriman
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000"), uuid.NewString()[:8])
+	return os.WriteFile(filepath.Join(m.dir, name), render(m.from, message, now), 0o644)
+}
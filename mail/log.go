@@ -0,0 +1,23 @@
+package mail
+
+import (
+	"context"
+	"log/slog"
+
+	"taskify/logging"
+)
+
+// LogMailer tidak mengirim email, hanya mencatat penerima dan subjeknya ke log (isi email di level debug).
+type LogMailer struct{}
+
+// NewLogMailer membuat LogMailer.
+func NewLogMailer() LogMailer {
+	return LogMailer{}
+}
+
+func (LogMailer) Send(ctx context.Context, message Message) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("email not sent (MAIL_BACKEND=log)", "to", message.To, "subject", message.Subject)
+	logger.Debug("email body", slog.String("to", message.To), slog.String("body", message.Body))
+	return nil
+}
@@ -0,0 +1,50 @@
+// Package mail mengirim email keluar. Backend SMTP dipakai di produksi; backend log dan file tidak benar-benar
+// mengirim apa pun dan berguna untuk development serta test.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message adalah satu email teks biasa untuk satu penerima.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer adalah kontrak yang dipenuhi oleh semua backend email.
+type Mailer interface {
+	// Send mengirim message. Error berarti message mungkin belum terkirim dan boleh dicoba lagi.
+	Send(ctx context.Context, message Message) error
+}
+
+// render menyusun message sebagai email RFC 5322 lengkap dengan header-nya.
+func render(from string, message Message, now time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", message.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", uuid.NewString(), domainOf(from))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(message.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return buf.Bytes()
+}
+
+func domainOf(address string) string {
+	address = strings.TrimSuffix(address, ">")
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		return address[i+1:]
+	}
+	return "localhost"
+}
@@ -0,0 +1,45 @@
+package mail
+
+import (
+	"context"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"time"
+)
+
+// SMTPMailer mengirim email lewat server SMTP. STARTTLS dipakai otomatis jika server mendukungnya; auth PLAIN
+// dipakai jika username diset.
+type SMTPMailer struct {
+	addr     string
+	from     string
+	envelope string // Alamat pengirim tanpa nama tampilan, untuk perintah MAIL FROM
+	auth     smtp.Auth
+}
+
+// NewSMTPMailer membuat SMTPMailer untuk server di addr (host:port).
+func NewSMTPMailer(addr, username, password, from string) *SMTPMailer {
+	m := &SMTPMailer{addr: addr, from: from, envelope: from}
+	if address, err := netmail.ParseAddress(from); err == nil {
+		m.envelope = address.Address
+	}
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, message Message) error {
+	// net/smtp tidak menerima context; kirim di goroutine supaya pemanggil tidak tertahan setelah ctx berakhir
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(m.addr, m.auth, m.envelope, []string{message.To}, render(m.from, message, time.Now()))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,11 +1,18 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"taskify/config"
-	"taskify/routes"
+	"taskify/logging"
+	"taskify/migrations"
+	"taskify/tracing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -16,23 +23,99 @@ func init() {
 }
 
 func main() {
-	router := gin.Default()
-	config.ConnectDatabase()
+	// Subcommand `taskify migrate ...` hanya butuh database, jadi dijalankan sebelum konfigurasi lainnya
+	// (JWT_SECRET, Redis, dan seterusnya) dibaca, lalu keluar tanpa menyalakan server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		migrateMain(os.Args[2:])
+		return
+	}
+
+	// Semua konfigurasi dibaca dan divalidasi sekali di sini; nilai yang salah menghentikan start
+	cfg, err := config.Load()
+	if err != nil {
+		fatalConfig(err)
+	}
+	if err := logging.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		logging.Fatal("invalid logging configuration", "error", err)
+	}
+	// Mode debug Gin mencetak daftar rute sebagai teks biasa; aktifkan lagi dengan GIN_MODE=debug
+	if os.Getenv("GIN_MODE") == "" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	db, err := config.ConnectDatabase(cfg.Database)
+	if err != nil {
+		logging.Fatal("failed to connect to database", "error", err)
+	}
+
+	// Jangan melayani request jika skema database tidak cocok dengan versi binary ini
+	if err := migrations.NewRunner(db).Check(); err != nil {
+		logging.Fatal("refusing to start", "error", err)
+	}
+
+	store, err := config.ConnectCache(context.Background(), cfg.Cache)
+	if err != nil {
+		logging.Fatal("failed to connect to cache", "error", err)
+	}
+
+	broker, err := config.ConnectBroker(context.Background(), cfg.Cache, cfg.Stream)
+	if err != nil {
+		logging.Fatal("failed to connect to realtime broker", "error", err)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		logging.Fatal("failed to set up tracing", "error", err)
+	}
+
+	application, err := newApp(cfg, db, store, broker)
+	if err != nil {
+		logging.Fatal("failed to set up application", "error", err)
+	}
+
+	server := newHTTPServer(cfg.Server, application.router)
+	server.RegisterOnShutdown(application.closeStreams)
+	workers := newBackgroundWorkers()
+	application.startWorkers(workers)
 
-	api := router.Group("/api")
-	{
-		routes.AuthRoutes(api)
-		routes.ProjectRoutes(api)
-		routes.TaskRoutes(api)
+	listener, err := net.Listen("tcp", cfg.Server.Addr)
+	if err != nil {
+		logging.Fatal("failed to start server", "addr", cfg.Server.Addr, "error", err)
 	}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// SIGINT/SIGTERM memulai graceful shutdown; sinyal kedua menghentikan proses seketika
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	slog.Info("server running", "addr", cfg.Server.Addr)
+	if err := serve(ctx, server, listener, workers, cfg.Server.ShutdownTimeout); err != nil {
+		logging.Fatal("server stopped with error", "error", err)
+	}
+
+	// Kirim span yang masih tertahan di batcher sebelum proses berakhir
+	flushCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
 	}
 
-	log.Printf("Server running on port %s", port)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+	slog.Info("server stopped")
+}
+
+// fatalConfig melaporkan error config.Load atau config.LoadDatabase, satu log per masalah, lalu keluar.
+func fatalConfig(err error) {
+	var invalid *config.ValidationError
+	if errors.As(err, &invalid) {
+		for _, problem := range invalid.Problems {
+			slog.Error("invalid configuration", "problem", problem)
+		}
+		logging.Fatal("refusing to start: fix the configuration problems above")
 	}
+	logging.Fatal("failed to load configuration", "error", err)
 }
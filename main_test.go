@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"taskify/cache"
+	"taskify/config"
+	"taskify/migrations"
+	"taskify/realtime"
+)
+
+// testServer membungkus router asli dari newApp di atas database SQLite in-memory milik satu test.
+type testServer struct {
+	t      *testing.T
+	db     *gorm.DB
+	app    *app
+	router *gin.Engine
+}
+
+// testUser adalah user yang sudah register dan login.
+type testUser struct {
+	ID    string
+	Email string
+	Token string
+}
+
+// header adalah pasangan nama/nilai header tambahan untuk request test.
+type header struct {
+	Name  string
+	Value string
+}
+
+// testConfig adalah konfigurasi bawaan dengan secret test dan tanpa rate limit, karena banyak test
+// login berkali-kali dari IP yang sama. Webhook ke alamat internal diizinkan karena penerima webhook palsu
+// berjalan di 127.0.0.1.
+func testConfig() config.Config {
+	cfg := config.Default()
+	cfg.Database.Driver = "sqlite"
+	cfg.Auth.JWTSecret = "integration-test-secret-0123456789abcdef"
+	cfg.Auth.RateLimit = 0
+	cfg.Webhook.AllowPrivateTargets = true
+	return cfg
+}
+
+// newTestServer membuat database terisolasi (skema dibangun dengan migrasi yang sama seperti produksi),
+// cache in-memory baru, dan router lengkap. configure mengubah testConfig, misalnya untuk menyalakan
+// rate limit.
+func newTestServer(t *testing.T, configure ...func(cfg *config.Config)) *testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := testConfig()
+	for _, fn := range configure {
+		fn(&cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("test config: %v", err)
+	}
+
+	db := openTestDatabase(t)
+	if _, err := migrations.NewRunner(db).Up(); err != nil {
+		t.Fatalf("migrate test database: %v", err)
+	}
+
+	application, err := newApp(cfg, db, cache.NewMemoryStore(), realtime.NewMemoryBroker(cfg.Stream.LogSize))
+	if err != nil {
+		t.Fatalf("set up app: %v", err)
+	}
+	return &testServer{t: t, db: db, app: application, router: application.router}
+}
+
+// openTestDatabase membuka database SQLite in-memory kosong yang hanya dipakai t.
+func openTestDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	// Nama database unik per test agar cache=shared tidak membagi data antar test
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared&_foreign_keys=on", uuid.NewString())
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		TranslateError: true, // Sama seperti config.ConnectDatabase
+		Logger:         logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+// do mengirim request ke router. body berupa string dikirim apa adanya, selain itu di-encode sebagai JSON.
+func (s *testServer) do(method, path, token string, body interface{}, headers ...header) *httptest.ResponseRecorder {
+	s.t.Helper()
+
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reader = strings.NewReader(b)
+	default:
+		encoded, err := json.Marshal(b)
+		if err != nil {
+			s.t.Fatalf("encode request body: %v", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for _, h := range headers {
+		req.Header.Set(h.Name, h.Value)
+	}
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// register membuat user baru lalu login, dan mengembalikan token-nya.
+func (s *testServer) register(name string) testUser {
+	s.t.Helper()
+
+	email := fmt.Sprintf("%s-%s@example.com", name, uuid.NewString()[:8])
+	rec := s.do(http.MethodPost, "/api/auth/register", "", gin.H{"name": name, "email": email, "password": "secret123"})
+	expectStatus(s.t, rec, http.StatusCreated)
+
+	return testUser{ID: str(decode(s.t, rec), "user_id"), Email: email, Token: s.login(email, "secret123")}
+}
+
+// login mengembalikan token JWT untuk email dan password yang diberikan.
+func (s *testServer) login(email, password string) string {
+	s.t.Helper()
+
+	rec := s.do(http.MethodPost, "/api/auth/login", "", gin.H{"email": email, "password": password})
+	expectStatus(s.t, rec, http.StatusOK)
+	return str(decode(s.t, rec), "token")
+}
+
+// createProject membuat proyek milik user dan mengembalikan ID-nya.
+func (s *testServer) createProject(user testUser, name string) string {
+	s.t.Helper()
+
+	rec := s.do(http.MethodPost, "/api/projects", user.Token, gin.H{"name": name, "description": name + " description", "created_by": user.ID})
+	expectStatus(s.t, rec, http.StatusCreated)
+	return str(decode(s.t, rec), "project.id")
+}
+
+// createTask membuat tugas di proyek dan mengembalikan ID-nya.
+func (s *testServer) createTask(user testUser, projectID, title string, extra gin.H) string {
+	s.t.Helper()
+
+	body := gin.H{"title": title, "status": "todo"}
+	for k, v := range extra {
+		body[k] = v
+	}
+	rec := s.do(http.MethodPost, "/api/projects/"+projectID+"/tasks", user.Token, body)
+	expectStatus(s.t, rec, http.StatusCreated)
+	return str(decode(s.t, rec), "task.id")
+}
+
+// expectStatus menghentikan test jika status response tidak sesuai.
+func expectStatus(t *testing.T, rec *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if rec.Code != want {
+		t.Fatalf("expected status %d, got %d: %s", want, rec.Code, rec.Body.String())
+	}
+}
+
+// decode mem-parse body response JSON.
+func decode(t *testing.T, rec *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+	}
+	return body
+}
+
+// lookup mengambil nilai bersarang dengan path bertitik, misalnya "project.id" atau "results.0.status".
+func lookup(body interface{}, path string) interface{} {
+	current := body
+	for _, part := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			current = node[part]
+		case []interface{}:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil
+			}
+			current = node[index]
+		default:
+			return nil
+		}
+	}
+	return current
+}
+
+// str mengambil nilai string di path; string kosong jika tidak ada.
+func str(body interface{}, path string) string {
+	value, _ := lookup(body, path).(string)
+	return value
+}
+
+// count mengambil panjang array di path.
+func count(body interface{}, path string) int {
+	items, _ := lookup(body, path).([]interface{})
+	return len(items)
+}
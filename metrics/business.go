@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"taskify/models"
+	"taskify/repository"
+)
+
+// scrapeTimeout membatasi query gauge bisnis supaya scrape yang lambat tidak menumpuk di database.
+const scrapeTimeout = 5 * time.Second
+
+var tasksByStatusDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "tasks"),
+	"Number of tasks by status.",
+	[]string{"status"}, nil,
+)
+
+// taskStatusCollector menghitung tugas per status setiap kali /metrics di-scrape.
+type taskStatusCollector struct {
+	tasks repository.TaskRepository
+}
+
+// NewTaskStatusCollector membuat gauge taskify_tasks{status} yang dibaca langsung dari repository.
+func NewTaskStatusCollector(tasks repository.TaskRepository) prometheus.Collector {
+	return taskStatusCollector{tasks: tasks}
+}
+
+func (c taskStatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- tasksByStatusDesc
+}
+
+func (c taskStatusCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+
+	counts, err := c.tasks.CountByStatus(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(tasksByStatusDesc, err)
+		return
+	}
+
+	// Status tanpa tugas tetap dilaporkan sebagai 0 supaya series tidak hilang dari grafik
+	for _, status := range []models.TaskStatus{models.Todo, models.InProgress, models.Done} {
+		ch <- prometheus.MustNewConstMetric(tasksByStatusDesc, prometheus.GaugeValue, float64(counts[status]), string(status))
+	}
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute dipakai untuk request yang tidak cocok dengan rute mana pun (404), supaya path acak
+// dari scanner tidak membuat label baru.
+const unmatchedRoute = "unmatched"
+
+// Middleware mencatat jumlah dan latensi request. Label route memakai template Gin (c.FullPath(),
+// misalnya /api/projects/:project_id/tasks), bukan path mentah, agar kardinalitas tetap kecil.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		HTTPRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		HTTPRequestDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
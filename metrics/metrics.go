@@ -0,0 +1,75 @@
+// Package metrics mendefinisikan metrik Prometheus Taskify: HTTP, query GORM, pool koneksi database,
+// login, dan gauge bisnis. Vektor metrik bersifat global supaya bisa dicatat dari package mana pun,
+// sedangkan registry dibuat per router sehingga collector milik satu database tidak bertabrakan.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "taskify"
+
+var (
+	// HTTPRequests menghitung request per method, template rute (FullPath), dan status.
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration mencatat latensi request per method, template rute, dan status.
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// DBQueryDuration mencatat durasi query GORM per operasi dan tabel.
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "GORM query duration by operation and table.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	// DBQueryErrors menghitung query GORM yang gagal (record not found tidak dihitung).
+	DBQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "Failed GORM queries by operation and table, excluding record not found.",
+	}, []string{"operation", "table"})
+
+	// LoginAttempts menghitung percobaan login per hasil: success, invalid_credentials, atau error.
+	LoginAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_attempts_total",
+		Help:      "Login attempts by result.",
+	}, []string{"result"})
+)
+
+// NewRegistry membuat registry berisi metrik global, metrik runtime Go dan proses, serta collector tambahan
+// (pool koneksi database, gauge bisnis) milik satu instance aplikasi.
+func NewRegistry(extra ...prometheus.Collector) *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		DBQueryDuration,
+		DBQueryErrors,
+		LoginAttempts,
+	)
+	registry.MustRegister(extra...)
+	return registry
+}
+
+// Handler menyajikan isi registry dalam format exposition Prometheus.
+func Handler(registry *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
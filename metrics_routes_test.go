@@ -0,0 +1,53 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMetricsRoute(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Observed")
+	s.createTask(owner, projectID, "First", nil)
+	s.createTask(owner, projectID, "Second", gin.H{"status": "done"})
+	expectStatus(t, s.do(http.MethodGet, "/api/projects/detail/"+projectID, owner.Token, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodPost, "/api/auth/login", "", gin.H{"email": owner.Email, "password": "wrong-password"}), http.StatusUnauthorized)
+	expectStatus(t, s.do(http.MethodGet, "/no/such/path/"+projectID, "", nil), http.StatusNotFound)
+
+	rec := s.do(http.MethodGet, "/metrics", "", nil)
+	expectStatus(t, rec, http.StatusOK)
+	body := rec.Body.String()
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"request counter uses route template", `taskify_http_requests_total{method="GET",route="/api/projects/detail/:id",status="200"}`},
+		{"unmatched routes share one label", `taskify_http_requests_total{method="GET",route="unmatched",status="404"}`},
+		{"latency histogram", `taskify_http_request_duration_seconds_bucket{method="POST",route="/api/projects/:project_id/tasks",status="201"`},
+		{"gorm query duration", `taskify_db_query_duration_seconds_count{operation="create",table="tasks"}`},
+		{"login success", `taskify_login_attempts_total{result="success"}`},
+		{"login failure", `taskify_login_attempts_total{result="invalid_credentials"}`},
+		{"connection pool stats", `go_sql_max_open_connections{db_name="sqlite"} 1`},
+		{"tasks by status", `taskify_tasks{status="todo"} 1`},
+		{"empty status is still reported", `taskify_tasks{status="in_progress"} 0`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(body, tt.want) {
+				t.Fatalf("expected /metrics to contain %s", tt.want)
+			}
+		})
+	}
+
+	t.Run("raw ids never become labels", func(t *testing.T) {
+		if strings.Contains(body, projectID) {
+			t.Fatal("project id leaked into metric labels")
+		}
+	})
+}
@@ -1,16 +1,18 @@
 package middlewares
 
 import (
-	"fmt"
 	"net/http"
 	"strings"
 
+	"taskify/logging"
+	"taskify/tracing"
 	"taskify/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware memvalidasi Bearer token dengan tokens dan menolak token yang sudah dicabut.
+func AuthMiddleware(tokens *utils.TokenManager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -27,17 +29,33 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		tokenString := parts[1]
-		claims, err := utils.ValidateToken(tokenString)
+		claims, err := tokens.Validate(tokenString)
 		if err != nil {
-			fmt.Println("Token validation error:", err) // Tambahkan ini
+			logging.FromContext(c.Request.Context()).Debug("token validation failed", "error", err)
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			c.Abort()
 			return
 		}
-		fmt.Println("UserID from token in middleware:", claims.UserID) // Tambahkan ini
-		c.Set("userID", claims.UserID)
+
+		// Token yang sudah dicabut (logout) ditolak walaupun belum kedaluwarsa
+		revoked, err := tokens.IsRevoked(c.Request.Context(), claims.ID)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to verify token revocation status"})
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+			c.Abort()
+			return
+		}
 
 		c.Set("userID", claims.UserID)
+		c.Set("tokenClaims", claims)
+
+		// Log dan span request berikutnya membawa user_id
+		tracing.SetUser(c.Request.Context(), claims.UserID.String())
+		c.Request = c.Request.WithContext(logging.With(c.Request.Context(), "user_id", claims.UserID.String()))
 		c.Next()
 	}
 }
@@ -0,0 +1,163 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"taskify/cache"
+	"taskify/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// idempotencyRecord adalah hasil request POST dengan header Idempotency-Key yang disimpan di cache,
+// supaya retry dengan key yang sama mendapatkan response yang sama tanpa membuat data ganda.
+type idempotencyRecord struct {
+	Fingerprint  string `json:"fingerprint"` // SHA-256 dari method, path dan body
+	Completed    bool   `json:"completed"`
+	StatusCode   int    `json:"status_code,omitempty"`
+	ContentType  string `json:"content_type,omitempty"`
+	ResponseBody []byte `json:"response_body,omitempty"`
+}
+
+// idempotencyRecorder meneruskan response ke client sambil menyalin body-nya agar bisa disimpan.
+type idempotencyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *idempotencyRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *idempotencyRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware membuat request POST dengan header Idempotency-Key aman untuk di-retry.
+// Response pertama disimpan di store selama ttl (IDEMPOTENCY_TTL) dan diputar ulang untuk retry dengan key dan
+// body yang sama; key yang dipakai ulang dengan body berbeda ditolak dengan 422. Jangan pasang middleware ini
+// pada endpoint yang response-nya berisi rahasia (token, secret), karena response disimpan apa adanya.
+func IdempotencyMiddleware(store cache.Store, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > 255 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key must be at most 255 characters"})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			c.Abort()
+			return
+		}
+		// Kembalikan body supaya handler tetap bisa membacanya
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := idempotencyScope(c)
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Request.Method + "\n" + c.Request.URL.Path + "\n"))
+		hash.Write(body)
+		fingerprint := hex.EncodeToString(hash.Sum(nil))
+
+		ctx := c.Request.Context()
+		cacheKey := "idempotency:" + scope + ":" + key
+
+		pending, _ := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+		// SetNX memastikan hanya satu request yang "memiliki" key ini, juga lintas instance jika memakai Redis
+		acquired, err := store.SetNX(ctx, cacheKey, pending, ttl)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store idempotency key"})
+			c.Abort()
+			return
+		}
+		if !acquired {
+			replayIdempotentResponse(c, store, cacheKey, fingerprint)
+			c.Abort()
+			return
+		}
+
+		recorder := &idempotencyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			// Error server tidak disimpan supaya client bisa mencoba lagi dengan key yang sama
+			if err := store.Delete(ctx, cacheKey); err != nil {
+				logging.FromContext(ctx).Error("failed to release idempotency key", "idempotency_key", key, "error", err)
+			}
+			return
+		}
+
+		completed, _ := json.Marshal(idempotencyRecord{
+			Fingerprint:  fingerprint,
+			Completed:    true,
+			StatusCode:   status,
+			ContentType:  recorder.Header().Get("Content-Type"),
+			ResponseBody: recorder.body.Bytes(),
+		})
+		if err := store.Set(ctx, cacheKey, completed, ttl); err != nil {
+			logging.FromContext(ctx).Error("failed to store idempotent response", "idempotency_key", key, "error", err)
+		}
+	}
+}
+
+// idempotencyScope menentukan namespace key: ID user yang login, atau untuk endpoint tanpa login (register)
+// hash IP dan User-Agent client, supaya client lain yang memakai key yang sama tidak menerima response milik
+// orang lain.
+func idempotencyScope(c *gin.Context) string {
+	if value, exists := c.Get("userID"); exists {
+		if id, ok := value.(uuid.UUID); ok && id != uuid.Nil {
+			return id.String()
+		}
+	}
+	client := sha256.Sum256([]byte(c.ClientIP() + "\n" + c.Request.UserAgent()))
+	return "anonymous-" + hex.EncodeToString(client[:16])
+}
+
+// replayIdempotentResponse menangani key yang sudah pernah dipakai.
+func replayIdempotentResponse(c *gin.Context, store cache.Store, cacheKey, fingerprint string) {
+	raw, err := store.Get(c.Request.Context(), cacheKey)
+	if errors.Is(err, cache.ErrMiss) {
+		// Record kedaluwarsa atau dilepas tepat setelah SetNX gagal; client cukup mencoba lagi
+		c.JSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is still being processed"})
+		return
+	}
+	var existing idempotencyRecord
+	if err == nil {
+		err = json.Unmarshal(raw, &existing)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load idempotency key"})
+		return
+	}
+
+	if existing.Fingerprint != fingerprint {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key has already been used with a different request"})
+		return
+	}
+	if !existing.Completed {
+		c.JSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is still being processed"})
+		return
+	}
+
+	c.Header("Idempotent-Replayed", "true")
+	c.Data(existing.StatusCode, existing.ContentType, existing.ResponseBody)
+}
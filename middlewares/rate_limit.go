@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"taskify/cache"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthRateLimitMiddleware membatasi request ke endpoint autentikasi per IP, sebanyak limit request
+// per menit (AUTH_RATE_LIMIT, 0 untuk menonaktifkan). Counter disimpan di store sehingga batasnya
+// berlaku lintas instance jika memakai Redis.
+func AuthRateLimitMiddleware(store cache.Store, limit int) gin.HandlerFunc {
+	return rateLimit(store, "auth", limit, time.Minute)
+}
+
+// rateLimit memakai fixed window: satu counter per IP per jendela waktu.
+func rateLimit(store cache.Store, name string, limit int, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		now := time.Now()
+		windowStart := now.Truncate(window)
+		key := fmt.Sprintf("ratelimit:%s:%s:%d", name, c.ClientIP(), windowStart.Unix())
+
+		count, err := store.Incr(c.Request.Context(), key, window)
+		if err != nil {
+			// Jika cache tidak tersedia, request tetap dilayani daripada memblokir semua login
+			c.Next()
+			return
+		}
+
+		remaining := int64(limit) - count
+		if remaining < 0 {
+			remaining = 0
+		}
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.Header("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+
+		if count > int64(limit) {
+			retryAfter := int(windowStart.Add(window).Sub(now).Seconds()) + 1
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests, please try again later"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,165 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"mime"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+
+	"taskify/logging"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// validRequestID membatasi X-Request-ID dari client supaya tidak bisa menyuntikkan teks aneh ke log.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestLogger memberi setiap request sebuah request ID (dari header X-Request-ID jika valid, atau UUID baru),
+// mengirimkannya kembali di header response dan di body error JSON, memasang logger per request di context
+// (request_id, method, route, trace_id), lalu mencatat satu baris log saat request selesai.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(requestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Set("requestID", requestID)
+		c.Header(requestIDHeader, requestID)
+
+		ctx := c.Request.Context()
+		args := []any{"request_id", requestID, "method", c.Request.Method, "route", c.FullPath()}
+		if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+			args = append(args, "trace_id", span.TraceID().String())
+		}
+		c.Request = c.Request.WithContext(logging.With(ctx, args...))
+
+		writer := &errorBodyWriter{ResponseWriter: c.Writer, requestID: requestID}
+		c.Writer = writer
+		c.Next()
+		writer.flush()
+
+		// Ambil ulang logger karena AuthMiddleware menambahkan user_id ke context request
+		ctx = c.Request.Context()
+		logger := logging.FromContext(ctx)
+		status := c.Writer.Status()
+
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		attrs := []slog.Attr{
+			slog.String("path", logging.RedactPath(c.Request.URL.Path, c.Params)),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("bytes", c.Writer.Size()),
+		}
+		if logger.Enabled(ctx, slog.LevelDebug) {
+			attrs = append(attrs, headerAttrs(c.Request.Header))
+		}
+		logger.LogAttrs(ctx, level, "request completed", attrs...)
+	}
+}
+
+// Recovery menangkap panic di handler, mencatatnya beserta stack trace di logger request, dan membalas 500.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			logging.FromContext(c.Request.Context()).Error("panic recovered",
+				"panic", recovered,
+				"stack", string(debug.Stack()),
+			)
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}()
+		c.Next()
+	}
+}
+
+// RequestID mengambil request ID yang dipasang RequestLogger.
+func RequestID(c *gin.Context) string {
+	return c.GetString("requestID")
+}
+
+// headerAttrs mengubah header request menjadi group log; nilai sensitif (Authorization, Cookie) diredaksi
+// oleh handler logging.
+func headerAttrs(header http.Header) slog.Attr {
+	attrs := make([]any, 0, len(header))
+	for name, values := range header {
+		attrs = append(attrs, slog.String(strings.ToLower(name), strings.Join(values, ", ")))
+	}
+	return slog.Group("headers", attrs...)
+}
+
+// errorBodyWriter menahan body response error (status >= 400) berformat JSON object, lalu menambahkan
+// field request_id sebelum dikirim. Response sukses diteruskan langsung tanpa buffer.
+type errorBodyWriter struct {
+	gin.ResponseWriter
+	requestID string
+	buffer    bytes.Buffer
+}
+
+// Unwrap membuat http.ResponseController (misalnya untuk stream SSE) bisa mencapai writer aslinya.
+func (w *errorBodyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *errorBodyWriter) capturing() bool {
+	if w.Status() < http.StatusBadRequest {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	return mediaType == "application/json"
+}
+
+func (w *errorBodyWriter) Write(b []byte) (int, error) {
+	if w.capturing() {
+		return w.buffer.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *errorBodyWriter) WriteString(s string) (int, error) {
+	if w.capturing() {
+		return w.buffer.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// flush mengirim body error yang ditahan, dengan request_id jika body-nya JSON object.
+func (w *errorBodyWriter) flush() {
+	if w.buffer.Len() == 0 {
+		return
+	}
+
+	body := w.buffer.Bytes()
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err == nil && fields != nil {
+		if _, exists := fields["request_id"]; !exists {
+			fields["request_id"], _ = json.Marshal(w.requestID)
+			if encoded, err := json.Marshal(fields); err == nil {
+				body = encoded
+			}
+		}
+	}
+	w.buffer.Reset()
+	w.ResponseWriter.Write(body)
+}
@@ -0,0 +1,24 @@
+package middlewares
+
+import (
+	"taskify/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// WebSocketTokenMiddleware memindahkan token dari header Sec-WebSocket-Protocol ("bearer, <token>") ke header
+// Authorization untuk request upgrade WebSocket yang tidak membawa Authorization, sehingga AuthMiddleware
+// sesudahnya bisa memvalidasinya seperti biasa. Token tidak diterima dari query string supaya tidak tercatat
+// di log akses.
+func WebSocketTokenMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" && websocket.IsWebSocketUpgrade(c.Request) {
+			protocols := websocket.Subprotocols(c.Request)
+			if len(protocols) == 2 && protocols[0] == utils.WebSocketTokenProtocol {
+				c.Request.Header.Set("Authorization", "Bearer "+protocols[1])
+			}
+		}
+		c.Next()
+	}
+}
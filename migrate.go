@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"gorm.io/gorm"
+
+	"taskify/config"
+	"taskify/logging"
+	"taskify/migrations"
+)
+
+const migrateUsage = "usage: taskify migrate up | down [steps] | status | baseline [version]"
+
+// migrateMain menjalankan subcommand migrate dengan konfigurasi database saja (config.LoadDatabase), sehingga
+// migrasi bisa dijalankan, misalnya dari job deploy, tanpa JWT_SECRET, Redis, atau pengaturan server lainnya.
+func migrateMain(args []string) {
+	cfg, err := config.LoadDatabase()
+	if err != nil {
+		fatalConfig(err)
+	}
+	db, err := config.ConnectDatabase(cfg)
+	if err != nil {
+		logging.Fatal("failed to connect to database", "error", err)
+	}
+	if err := runMigrateCommand(db, args); err != nil {
+		logging.Fatal("migration failed", "error", err)
+	}
+}
+
+// runMigrateCommand menangani subcommand `taskify migrate up|down [steps]|status|baseline [version]`.
+func runMigrateCommand(db *gorm.DB, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	runner := migrations.NewRunner(db)
+	switch args[0] {
+	case "up":
+		applied, err := runner.Up()
+		for _, m := range applied {
+			fmt.Printf("applied  %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+		return nil
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid steps %q: %s", args[1], migrateUsage)
+			}
+			steps = n
+		}
+		reverted, err := runner.Down(steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			fmt.Println("no applied migrations to revert")
+		}
+		return nil
+
+	case "baseline":
+		version := migrations.LegacySchemaVersion
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid version %q: %s", args[1], migrateUsage)
+			}
+			version = n
+		}
+		recorded, err := runner.Baseline(version)
+		if err != nil {
+			return err
+		}
+		for _, m := range recorded {
+			fmt.Printf("baseline %04d_%s\n", m.Version, m.Name)
+		}
+		fmt.Println("run `taskify migrate up` to apply the remaining migrations")
+		return nil
+
+	case "status":
+		statuses, err := runner.Status()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range statuses {
+			state, appliedAt := "pending", "-"
+			if s.Applied {
+				state = "applied"
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if s.Unknown {
+				state = "unknown"
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+		}
+		return w.Flush()
+	}
+
+	return fmt.Errorf("unknown migrate command %q: %s", args[0], migrateUsage)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"taskify/migrations"
+)
+
+func TestMigrateBaselineAdoptsLegacySchema(t *testing.T) {
+	db := openTestDatabase(t)
+	runner := migrations.NewRunner(db)
+
+	if _, err := runner.Baseline(migrations.LegacySchemaVersion); err == nil || !strings.Contains(err.Error(), `table "users" does not exist`) {
+		t.Fatalf("baseline on an empty database should fail, got %v", err)
+	}
+
+	// Skema DDL.sql lama setara dengan migrasi sampai LegacySchemaVersion, tetapi tanpa schema_migrations
+	for _, m := range migrations.All() {
+		if m.Version > migrations.LegacySchemaVersion {
+			break
+		}
+		if err := m.Up(db); err != nil {
+			t.Fatalf("create legacy schema: %v", err)
+		}
+	}
+	if _, err := runner.Up(); err == nil {
+		t.Fatal("migrate up on a legacy schema should fail without a baseline")
+	}
+
+	recorded, err := runner.Baseline(migrations.LegacySchemaVersion)
+	if err != nil || len(recorded) != migrations.LegacySchemaVersion {
+		t.Fatalf("baseline: recorded %d migrations, err %v", len(recorded), err)
+	}
+	if _, err := runner.Baseline(migrations.LegacySchemaVersion); err == nil {
+		t.Fatal("a second baseline should be rejected")
+	}
+
+	applied, err := runner.Up()
+	if err != nil || len(applied) != len(migrations.All())-migrations.LegacySchemaVersion || applied[0].Version != migrations.LegacySchemaVersion+1 {
+		t.Fatalf("migrate up after baseline: applied %v, err %v", applied, err)
+	}
+	if err := runner.Check(); err != nil {
+		t.Fatalf("schema should be up to date: %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type user0001 struct {
+	ID       uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Name     string    `gorm:"type:varchar(255);not null"`
+	Email    string    `gorm:"type:varchar(255);unique;not null"`
+	Password string    `gorm:"type:varchar(255);not null"`
+}
+
+func (user0001) TableName() string { return "users" }
+
+type project0001 struct {
+	ID          uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Name        string    `gorm:"type:varchar(255);not null"`
+	Description string    `gorm:"type:text"`
+	CreatedByID uuid.UUID `gorm:"type:char(36);not null"`
+	User        user0001  `gorm:"foreignKey:CreatedByID"`
+}
+
+func (project0001) TableName() string { return "projects" }
+
+type task0001 struct {
+	ID          uuid.UUID   `gorm:"type:char(36);primaryKey"`
+	ProjectID   uuid.UUID   `gorm:"type:char(36);not null"`
+	Title       string      `gorm:"type:varchar(255);not null"`
+	Description string      `gorm:"type:text"`
+	Status      string      `gorm:"type:varchar(20);default:'todo';not null;check:chk_tasks_status,status IN ('todo','in_progress','done')"`
+	Deadline    *time.Time  `gorm:"type:date"`
+	Project     project0001 `gorm:"foreignKey:ProjectID"`
+}
+
+func (task0001) TableName() string { return "tasks" }
+
+func init() {
+	register(Migration{
+		Version: 1,
+		Name:    "create_core_tables",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&user0001{}, &project0001{}, &task0001{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&task0001{}, &project0001{}, &user0001{})
+		},
+	})
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type project0002 struct {
+	ID         uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Archived   bool      `gorm:"not null;default:false"`
+	ArchivedAt *time.Time
+}
+
+func (project0002) TableName() string { return "projects" }
+
+func init() {
+	register(Migration{
+		Version: 2,
+		Name:    "add_project_archiving",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&project0002{}, "Archived"); err != nil {
+				return err
+			}
+			return tx.Migrator().AddColumn(&project0002{}, "ArchivedAt")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropColumn(&project0002{}, "ArchivedAt"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&project0002{}, "Archived")
+		},
+	})
+}
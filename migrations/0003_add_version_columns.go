@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type project0003 struct {
+	ID      uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Version uint      `gorm:"not null;default:1"`
+}
+
+func (project0003) TableName() string { return "projects" }
+
+type task0003 struct {
+	ID      uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Version uint      `gorm:"not null;default:1"`
+}
+
+func (task0003) TableName() string { return "tasks" }
+
+func init() {
+	register(Migration{
+		Version: 3,
+		Name:    "add_version_columns",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&project0003{}, "Version"); err != nil {
+				return err
+			}
+			return tx.Migrator().AddColumn(&task0003{}, "Version")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropColumn(&task0003{}, "Version"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&project0003{}, "Version")
+		},
+	})
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type idempotencyKey0004 struct {
+	ID           uuid.UUID `gorm:"type:char(36);primaryKey"`
+	UserID       uuid.UUID `gorm:"type:char(36);not null;uniqueIndex:idx_idempotency_user_key"`
+	Key          string    `gorm:"column:idempotency_key;type:varchar(255);not null;uniqueIndex:idx_idempotency_user_key"`
+	Fingerprint  string    `gorm:"type:char(64);not null"`
+	Completed    bool      `gorm:"not null;default:false"`
+	StatusCode   int       `gorm:"not null;default:0"`
+	ContentType  string    `gorm:"type:varchar(255)"`
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time `gorm:"not null;index"`
+}
+
+func (idempotencyKey0004) TableName() string { return "idempotency_keys" }
+
+func init() {
+	register(Migration{
+		Version: 4,
+		Name:    "create_idempotency_keys",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&idempotencyKey0004{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&idempotencyKey0004{})
+		},
+	})
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type taskHistory0005 struct {
+	ID            uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	TaskID        uuid.UUID  `gorm:"type:char(36);not null;index"`
+	Action        string     `gorm:"type:varchar(20);not null"`
+	FromProjectID uuid.UUID  `gorm:"type:char(36);not null"`
+	ToProjectID   uuid.UUID  `gorm:"type:char(36);not null"`
+	SourceTaskID  *uuid.UUID `gorm:"type:char(36)"`
+	ActorID       uuid.UUID  `gorm:"type:char(36);not null"`
+	CreatedAt     time.Time
+}
+
+func (taskHistory0005) TableName() string { return "task_histories" }
+
+func init() {
+	register(Migration{
+		Version: 5,
+		Name:    "create_task_histories",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&taskHistory0005{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&taskHistory0005{})
+		},
+	})
+}
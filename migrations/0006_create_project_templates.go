@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type projectTemplate0006 struct {
+	ID          uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Name        string    `gorm:"type:varchar(255);not null"`
+	Description string    `gorm:"type:text"`
+	CreatedByID uuid.UUID `gorm:"type:char(36);not null;index"`
+	CreatedAt   time.Time
+}
+
+func (projectTemplate0006) TableName() string { return "project_templates" }
+
+type templateTask0006 struct {
+	ID                 uuid.UUID `gorm:"type:char(36);primaryKey"`
+	TemplateID         uuid.UUID `gorm:"type:char(36);not null;index"`
+	Position           int       `gorm:"not null;default:0"`
+	Title              string    `gorm:"type:varchar(255);not null"`
+	Description        string    `gorm:"type:text"`
+	Status             string    `gorm:"type:varchar(20);default:'todo';not null"`
+	DeadlineOffsetDays *int
+	Template           projectTemplate0006 `gorm:"foreignKey:TemplateID"`
+}
+
+func (templateTask0006) TableName() string { return "template_tasks" }
+
+func init() {
+	register(Migration{
+		Version: 6,
+		Name:    "create_project_templates",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&projectTemplate0006{}, &templateTask0006{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&templateTask0006{}, &projectTemplate0006{})
+		},
+	})
+}
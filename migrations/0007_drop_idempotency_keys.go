@@ -0,0 +1,17 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// Record idempotency dipindahkan ke cache (Redis/in-memory) sehingga tabelnya tidak dipakai lagi.
+func init() {
+	register(Migration{
+		Version: 7,
+		Name:    "drop_idempotency_keys",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&idempotencyKey0004{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&idempotencyKey0004{})
+		},
+	})
+}
@@ -0,0 +1,80 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type outboxEvent0008 struct {
+	ID          uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	Type        string     `gorm:"type:varchar(50);not null"`
+	ProjectID   uuid.UUID  `gorm:"type:char(36);not null;index"`
+	ActorID     uuid.UUID  `gorm:"type:char(36);not null"`
+	Payload     string     `gorm:"type:text;not null"`
+	CreatedAt   time.Time  `gorm:"index"`
+	ProcessedAt *time.Time `gorm:"index"`
+}
+
+func (outboxEvent0008) TableName() string { return "outbox_events" }
+
+type webhook0008 struct {
+	ID          uuid.UUID `gorm:"type:char(36);primaryKey"`
+	ProjectID   uuid.UUID `gorm:"type:char(36);not null;index"`
+	URL         string    `gorm:"type:varchar(2048);not null"`
+	Secret      string    `gorm:"type:varchar(255);not null"`
+	Events      string    `gorm:"type:varchar(500);not null"`
+	Active      bool      `gorm:"not null;default:true"`
+	CreatedByID uuid.UUID `gorm:"type:char(36);not null"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (webhook0008) TableName() string { return "webhooks" }
+
+type webhookDelivery0008 struct {
+	ID             uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	WebhookID      uuid.UUID  `gorm:"type:char(36);not null;index"`
+	EventID        uuid.UUID  `gorm:"type:char(36);not null;index"`
+	EventType      string     `gorm:"type:varchar(50);not null"`
+	URL            string     `gorm:"type:varchar(2048);not null"`
+	Secret         string     `gorm:"type:varchar(255);not null"`
+	Status         string     `gorm:"type:varchar(20);not null;index"`
+	Attempts       int        `gorm:"not null;default:0"`
+	NextAttemptAt  *time.Time `gorm:"index"`
+	LastStatusCode int        `gorm:"not null;default:0"`
+	LastError      string     `gorm:"type:text"`
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (webhookDelivery0008) TableName() string { return "webhook_deliveries" }
+
+type webhookDeliveryAttempt0008 struct {
+	ID           uuid.UUID `gorm:"type:char(36);primaryKey"`
+	DeliveryID   uuid.UUID `gorm:"type:char(36);not null;index"`
+	Attempt      int       `gorm:"not null"`
+	StatusCode   int       `gorm:"not null;default:0"`
+	Error        string    `gorm:"type:text"`
+	ResponseBody string    `gorm:"type:text"`
+	DurationMs   int64     `gorm:"not null;default:0"`
+	CreatedAt    time.Time
+}
+
+func (webhookDeliveryAttempt0008) TableName() string { return "webhook_delivery_attempts" }
+
+// Outbox event domain dan langganan webhook beserta log pengirimannya.
+func init() {
+	register(Migration{
+		Version: 8,
+		Name:    "create_webhooks",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&outboxEvent0008{}, &webhook0008{}, &webhookDelivery0008{}, &webhookDeliveryAttempt0008{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&webhookDeliveryAttempt0008{}, &webhookDelivery0008{}, &webhook0008{}, &outboxEvent0008{})
+		},
+	})
+}
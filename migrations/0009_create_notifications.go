@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type notification0009 struct {
+	ID        uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	UserID    uuid.UUID  `gorm:"type:char(36);not null;index:idx_notifications_user_created,priority:1;uniqueIndex:idx_notifications_user_event,priority:1"`
+	Type      string     `gorm:"type:varchar(50);not null"`
+	ProjectID uuid.UUID  `gorm:"type:char(36);not null"`
+	TaskID    *uuid.UUID `gorm:"type:char(36)"`
+	ActorID   *uuid.UUID `gorm:"type:char(36)"`
+	EventID   *uuid.UUID `gorm:"type:char(36);uniqueIndex:idx_notifications_user_event,priority:2"`
+	Title     string     `gorm:"type:varchar(255);not null"`
+	Payload   string     `gorm:"type:text;not null"`
+	ReadAt    *time.Time `gorm:"index"`
+	CreatedAt time.Time  `gorm:"index:idx_notifications_user_created,priority:2"`
+}
+
+func (notification0009) TableName() string { return "notifications" }
+
+type taskWatcher0009 struct {
+	TaskID    uuid.UUID `gorm:"type:char(36);primaryKey"`
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey;index"`
+	CreatedAt time.Time
+}
+
+func (taskWatcher0009) TableName() string { return "task_watchers" }
+
+type notificationPreference0009 struct {
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey"`
+	EventType string    `gorm:"type:varchar(50);primaryKey"`
+	Enabled   bool      `gorm:"not null"`
+	UpdatedAt time.Time
+}
+
+func (notificationPreference0009) TableName() string { return "notification_preferences" }
+
+// Inbox notifikasi, watcher tugas, dan preferensi notifikasi per user.
+func init() {
+	register(Migration{
+		Version: 9,
+		Name:    "create_notifications",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&notification0009{}, &taskWatcher0009{}, &notificationPreference0009{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&notificationPreference0009{}, &taskWatcher0009{}, &notification0009{})
+		},
+	})
+}
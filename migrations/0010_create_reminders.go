@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type reminderSetting0010 struct {
+	UserID        uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Offsets       string    `gorm:"type:varchar(255);not null"`
+	OverdueAlerts bool      `gorm:"not null"`
+	DailyDigest   bool      `gorm:"not null;index"`
+	DigestHour    int       `gorm:"not null"`
+	Timezone      string    `gorm:"type:varchar(64);not null"`
+	UpdatedAt     time.Time
+}
+
+func (reminderSetting0010) TableName() string { return "reminder_settings" }
+
+type sentReminder0010 struct {
+	Key    string     `gorm:"type:varchar(191);primaryKey"`
+	Kind   string     `gorm:"type:varchar(20);not null"`
+	UserID uuid.UUID  `gorm:"type:char(36);not null"`
+	TaskID *uuid.UUID `gorm:"type:char(36)"`
+	SentAt time.Time  `gorm:"not null;index"`
+}
+
+func (sentReminder0010) TableName() string { return "sent_reminders" }
+
+// Pengaturan pengingat deadline per user dan catatan pengingat terkirim (mencegah kiriman ganda antar
+// instance).
+func init() {
+	register(Migration{
+		Version: 10,
+		Name:    "create_reminders",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&reminderSetting0010{}, &sentReminder0010{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&sentReminder0010{}, &reminderSetting0010{})
+		},
+	})
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type calendarFeed0011 struct {
+	ID             uuid.UUID `gorm:"type:char(36);primaryKey"`
+	UserID         uuid.UUID `gorm:"type:char(36);not null;uniqueIndex:idx_calendar_feeds_scope,priority:1"`
+	ProjectID      uuid.UUID `gorm:"type:char(36);not null;uniqueIndex:idx_calendar_feeds_scope,priority:2;index"`
+	TokenHash      string    `gorm:"type:char(64);not null;uniqueIndex"`
+	CreatedAt      time.Time
+	LastAccessedAt *time.Time
+}
+
+func (calendarFeed0011) TableName() string { return "calendar_feeds" }
+
+// URL rahasia feed iCalendar per user dan per proyek.
+func init() {
+	register(Migration{
+		Version: 11,
+		Name:    "create_calendar_feeds",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&calendarFeed0011{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&calendarFeed0011{})
+		},
+	})
+}
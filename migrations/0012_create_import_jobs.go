@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type importJob0012 struct {
+	ID         uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	UserID     uuid.UUID  `gorm:"type:char(36);not null;index:idx_import_jobs_user_created,priority:1"`
+	Format     string     `gorm:"type:varchar(20);not null"`
+	Filename   string     `gorm:"type:varchar(255);not null"`
+	Options    string     `gorm:"type:text;not null"`
+	Data       []byte     `gorm:"not null"`
+	Status     string     `gorm:"type:varchar(20);not null;index:idx_import_jobs_status_lease,priority:1"`
+	LeaseUntil *time.Time `gorm:"index:idx_import_jobs_status_lease,priority:2"`
+	Attempts   int        `gorm:"not null;default:0"`
+	Processed  int        `gorm:"not null;default:0"`
+	Total      int        `gorm:"not null;default:0"`
+	ProjectID  *uuid.UUID `gorm:"type:char(36)"`
+	Report     []byte
+	Error      string    `gorm:"type:text"`
+	CreatedAt  time.Time `gorm:"index:idx_import_jobs_user_created,priority:2"`
+	StartedAt  *time.Time
+	FinishedAt *time.Time `gorm:"index"`
+	UpdatedAt  time.Time
+}
+
+func (importJob0012) TableName() string { return "import_jobs" }
+
+// Job import proyek (Trello, Jira, CSV, JSON) yang diproses worker latar belakang.
+func init() {
+	register(Migration{
+		Version: 12,
+		Name:    "create_import_jobs",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&importJob0012{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&importJob0012{})
+		},
+	})
+}
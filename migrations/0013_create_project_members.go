@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type projectMember0013 struct {
+	ProjectID uuid.UUID `gorm:"type:char(36);primaryKey"`
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey;index"`
+	CreatedAt time.Time
+}
+
+func (projectMember0013) TableName() string { return "project_members" }
+
+// Anggota proyek yang boleh mengelola tugas di proyek milik user lain.
+func init() {
+	register(Migration{
+		Version: 13,
+		Name:    "create_project_members",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&projectMember0013{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&projectMember0013{})
+		},
+	})
+}
@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type outboxEvent0014 struct {
+	ID        uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	Attempts  int        `gorm:"not null;default:0"`
+	LastError string     `gorm:"type:text"`
+	DeadAt    *time.Time `gorm:"index"`
+}
+
+func (outboxEvent0014) TableName() string { return "outbox_events" }
+
+// Pencatatan kegagalan pembagian event outbox, supaya satu event yang gagal tidak menahan event lain.
+func init() {
+	register(Migration{
+		Version: 14,
+		Name:    "add_outbox_failures",
+		Up: func(tx *gorm.DB) error {
+			for _, column := range []string{"Attempts", "LastError", "DeadAt"} {
+				if err := tx.Migrator().AddColumn(&outboxEvent0014{}, column); err != nil {
+					return err
+				}
+			}
+			return tx.Migrator().CreateIndex(&outboxEvent0014{}, "DeadAt")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropIndex(&outboxEvent0014{}, "DeadAt"); err != nil {
+				return err
+			}
+			for _, column := range []string{"DeadAt", "LastError", "Attempts"} {
+				if err := tx.Migrator().DropColumn(&outboxEvent0014{}, column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,43 @@
+// Package migrations berisi migrasi skema database yang berurutan dan berversi, beserta runner
+// yang mencatat migrasi yang sudah dijalankan di tabel schema_migrations.
+//
+// Setiap migrasi mendefinisikan snapshot struct-nya sendiri (bukan memakai package models) supaya
+// hasilnya tetap sama walaupun model berubah di kemudian hari, dan memakai GORM Migrator agar
+// berjalan di MySQL, PostgreSQL maupun SQLite.
+package migrations
+
+import (
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// Migration adalah satu langkah perubahan skema. Up dan Down dijalankan di dalam transaksi
+// (catatan: MySQL melakukan implicit commit untuk DDL, jadi rollback hanya efektif di PostgreSQL/SQLite).
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+var registry []Migration
+
+// register dipanggil dari init() setiap file migrasi.
+func register(m Migration) {
+	for _, existing := range registry {
+		if existing.Version == m.Version {
+			panic(fmt.Sprintf("migrations: duplicate version %d (%s and %s)", m.Version, existing.Name, m.Name))
+		}
+	}
+	registry = append(registry, m)
+}
+
+// All mengembalikan semua migrasi terurut berdasarkan versi.
+func All() []Migration {
+	all := make([]Migration, len(registry))
+	copy(all, registry)
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+	return all
+}
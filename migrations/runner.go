@@ -0,0 +1,294 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	lockRowID       = 1
+	lockPollPeriod  = 500 * time.Millisecond
+	lockStaleAfter  = 15 * time.Minute // Lock yang lebih tua dari ini dianggap milik proses yang mati
+	defaultLockWait = time.Minute
+)
+
+// ErrLockTimeout dikembalikan jika instance lain memegang lock migrasi terlalu lama.
+var ErrLockTimeout = errors.New("timed out waiting for the migration lock")
+
+// schemaMigration mencatat satu migrasi yang sudah dijalankan.
+type schemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string { return "schema_migrations" }
+
+// migrationLock adalah tabel satu baris yang dipakai sebagai lock lintas instance. Cara ini dipakai
+// (bukan GET_LOCK/pg_advisory_lock) supaya sama di semua driver.
+type migrationLock struct {
+	ID       int     `gorm:"primaryKey;autoIncrement:false"`
+	LockedBy *string `gorm:"type:varchar(255)"`
+	LockedAt *time.Time
+}
+
+func (migrationLock) TableName() string { return "schema_migrations_lock" }
+
+// Status menggambarkan keadaan satu migrasi di database.
+type Status struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	Unknown   bool       `json:"unknown,omitempty"` // Tercatat di database tapi tidak dikenal oleh binary ini
+}
+
+// Runner menjalankan migrasi terhadap satu koneksi database.
+type Runner struct {
+	db         *gorm.DB
+	migrations []Migration
+	owner      string
+	LockWait   time.Duration
+}
+
+// NewRunner membuat runner untuk semua migrasi yang terdaftar.
+func NewRunner(db *gorm.DB) *Runner {
+	host, _ := os.Hostname()
+	return &Runner{
+		db:         db,
+		migrations: All(),
+		owner:      fmt.Sprintf("%s/%d/%s", host, os.Getpid(), uuid.NewString()),
+		LockWait:   defaultLockWait,
+	}
+}
+
+// Up menjalankan semua migrasi yang belum diterapkan, berurutan, dan mengembalikan yang baru dijalankan.
+func (r *Runner) Up() ([]Migration, error) {
+	var applied []Migration
+	err := r.withLock(func() error {
+		done, err := r.appliedVersions()
+		if err != nil {
+			return err
+		}
+		for _, m := range r.migrations {
+			if _, ok := done[m.Version]; ok {
+				continue
+			}
+			err := r.db.Transaction(func(tx *gorm.DB) error {
+				if err := m.Up(tx); err != nil {
+					return err
+				}
+				return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now().UTC()}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s failed: %w", m.Version, m.Name, err)
+			}
+			applied = append(applied, m)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down membatalkan sejumlah steps migrasi terakhir yang sudah diterapkan, dari yang terbaru.
+func (r *Runner) Down(steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := r.withLock(func() error {
+		done, err := r.appliedVersions()
+		if err != nil {
+			return err
+		}
+		for i := len(r.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			m := r.migrations[i]
+			if _, ok := done[m.Version]; !ok {
+				continue
+			}
+			err := r.db.Transaction(func(tx *gorm.DB) error {
+				if err := m.Down(tx); err != nil {
+					return err
+				}
+				return tx.Delete(&schemaMigration{}, "version = ?", m.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("rollback of %04d_%s failed: %w", m.Version, m.Name, err)
+			}
+			reverted = append(reverted, m)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// LegacySchemaVersion adalah migrasi terakhir yang setara dengan skema DDL.sql lama (sebelum migrasi
+// berversi dipakai), yaitu default untuk Baseline.
+const LegacySchemaVersion = 6
+
+// Baseline mencatat migrasi sampai version sebagai sudah diterapkan tanpa menjalankannya, untuk database
+// lama yang skemanya dibuat dari DDL.sql. Hanya bisa dipakai sekali, pada database yang tabel users,
+// projects, dan tasks-nya sudah ada tetapi belum punya catatan migrasi.
+func (r *Runner) Baseline(version int) ([]Migration, error) {
+	var recorded []Migration
+	err := r.withLock(func() error {
+		done, err := r.appliedVersions()
+		if err != nil {
+			return err
+		}
+		if len(done) > 0 {
+			return errors.New("database already has recorded migrations; baseline is only for databases created without them")
+		}
+		for _, table := range []string{"users", "projects", "tasks"} {
+			if !r.db.Migrator().HasTable(table) {
+				return fmt.Errorf("table %q does not exist; run `taskify migrate up` on a new database instead", table)
+			}
+		}
+
+		known := false
+		for _, m := range r.migrations {
+			if m.Version == version {
+				known = true
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown migration version %d", version)
+		}
+
+		return r.db.Transaction(func(tx *gorm.DB) error {
+			now := time.Now().UTC()
+			for _, m := range r.migrations {
+				if m.Version > version {
+					break
+				}
+				if err := tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: now}).Error; err != nil {
+					return err
+				}
+				recorded = append(recorded, m)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recorded, nil
+}
+
+// Status membandingkan migrasi yang dikenal binary ini dengan yang tercatat di database.
+func (r *Runner) Status() ([]Status, error) {
+	if err := r.ensureTables(); err != nil {
+		return nil, err
+	}
+	done, err := r.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(r.migrations))
+	known := map[int]bool{}
+	for _, m := range r.migrations {
+		known[m.Version] = true
+		status := Status{Version: m.Version, Name: m.Name}
+		if record, ok := done[m.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	for version, record := range done {
+		if !known[version] {
+			appliedAt := record.AppliedAt
+			statuses = append(statuses, Status{Version: version, Name: record.Name, Applied: true, AppliedAt: &appliedAt, Unknown: true})
+		}
+	}
+	return statuses, nil
+}
+
+// Check mengembalikan error jika skema database tidak sama persis dengan migrasi yang dikenal binary ini,
+// baik karena ada migrasi yang belum dijalankan maupun karena database lebih baru dari binary.
+func (r *Runner) Check() error {
+	statuses, err := r.Status()
+	if err != nil {
+		return err
+	}
+
+	var pending, unknown []string
+	for _, s := range statuses {
+		switch {
+		case s.Unknown:
+			unknown = append(unknown, fmt.Sprintf("%04d_%s", s.Version, s.Name))
+		case !s.Applied:
+			pending = append(pending, fmt.Sprintf("%04d_%s", s.Version, s.Name))
+		}
+	}
+
+	var problems []string
+	if len(pending) > 0 {
+		problems = append(problems, "pending migrations: "+strings.Join(pending, ", ")+" (run `taskify migrate up`)")
+	}
+	if len(unknown) > 0 {
+		problems = append(problems, "database has migrations unknown to this binary: "+strings.Join(unknown, ", "))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("schema mismatch: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func (r *Runner) ensureTables() error {
+	if err := r.db.AutoMigrate(&schemaMigration{}, &migrationLock{}); err != nil {
+		return fmt.Errorf("failed to prepare migration tables: %w", err)
+	}
+	// Baris lock dibuat sekali; OnConflict DoNothing supaya aman jika beberapa instance start bersamaan
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&migrationLock{ID: lockRowID}).Error
+}
+
+func (r *Runner) appliedVersions() (map[int]schemaMigration, error) {
+	var records []schemaMigration
+	if err := r.db.Order("version").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	done := make(map[int]schemaMigration, len(records))
+	for _, record := range records {
+		done[record.Version] = record
+	}
+	return done, nil
+}
+
+// withLock menjalankan fn sambil memegang lock migrasi, supaya dua instance tidak bermigrasi bersamaan.
+func (r *Runner) withLock(fn func() error) error {
+	if err := r.ensureTables(); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(r.LockWait)
+	for {
+		now := time.Now().UTC()
+		// UPDATE bersyarat bersifat atomik: hanya satu instance yang berhasil mengubah baris lock
+		result := r.db.Model(&migrationLock{}).
+			Where("id = ? AND (locked_by IS NULL OR locked_at < ?)", lockRowID, now.Add(-lockStaleAfter)).
+			Updates(map[string]interface{}{"locked_by": r.owner, "locked_at": now})
+		if result.Error != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", result.Error)
+		}
+		if result.RowsAffected == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			return ErrLockTimeout
+		}
+		time.Sleep(lockPollPeriod)
+	}
+
+	defer r.db.Model(&migrationLock{}).
+		Where("id = ? AND locked_by = ?", lockRowID, r.owner).
+		Updates(map[string]interface{}{"locked_by": nil, "locked_at": nil})
+
+	return fn()
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarFeed adalah URL rahasia feed iCalendar milik user. ProjectID uuid.Nil berarti feed semua proyek
+// user; selain itu feed satu proyek. Token di URL hanya disimpan sebagai hash SHA-256, jadi URL hanya bisa
+// dilihat saat dibuat dan dicabut dengan menghapus atau membuat ulang feed.
+type CalendarFeed struct {
+	ID             uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	UserID         uuid.UUID  `gorm:"type:char(36);not null;uniqueIndex:idx_calendar_feeds_scope,priority:1" json:"-"`
+	ProjectID      uuid.UUID  `gorm:"type:char(36);not null;uniqueIndex:idx_calendar_feeds_scope,priority:2;index" json:"-"`
+	TokenHash      string     `gorm:"type:char(64);not null;uniqueIndex" json:"-"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastAccessedAt *time.Time `json:"last_accessed_at"`
+}
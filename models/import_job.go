@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status import job.
+const (
+	ImportQueued    = "queued"    // Menunggu diambil worker
+	ImportRunning   = "running"   // Sedang diproses sampai LeaseUntil; lewat dari itu dianggap ditinggalkan
+	ImportCompleted = "completed" // Selesai; hasilnya ada di Report
+	ImportFailed    = "failed"    // File tidak bisa dibaca, atau import atomic dibatalkan karena ada baris yang tidak valid
+)
+
+// ImportJob adalah import proyek yang diproses worker di latar belakang, untuk file besar. File disimpan di
+// Data sampai job selesai, jadi worker di instance mana pun bisa memprosesnya. Proyek hasil import memakai
+// ID job ini, sehingga percobaan ulang setelah instance mati tidak membuat proyek ganda.
+type ImportJob struct {
+	ID         uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	UserID     uuid.UUID  `gorm:"type:char(36);not null;index:idx_import_jobs_user_created,priority:1" json:"-"`
+	Format     string     `gorm:"type:varchar(20);not null" json:"format"`
+	Filename   string     `gorm:"type:varchar(255);not null" json:"filename"`
+	Options    string     `gorm:"type:text;not null" json:"-"` // Opsi import dalam JSON
+	Data       []byte     `gorm:"not null" json:"-"`
+	Status     string     `gorm:"type:varchar(20);not null;index:idx_import_jobs_status_lease,priority:1" json:"status"`
+	LeaseUntil *time.Time `gorm:"index:idx_import_jobs_status_lease,priority:2" json:"-"`
+	Attempts   int        `gorm:"not null;default:0" json:"attempts"`
+	Processed  int        `gorm:"not null;default:0" json:"processed"` // Tugas yang sudah disimpan
+	Total      int        `gorm:"not null;default:0" json:"total"`     // Tugas valid yang akan disimpan
+	ProjectID  *uuid.UUID `gorm:"type:char(36)" json:"project_id"`
+	Report     []byte     `json:"-"` // Laporan import dalam JSON
+	Error      string     `gorm:"type:text" json:"error,omitempty"`
+	CreatedAt  time.Time  `gorm:"index:idx_import_jobs_user_created,priority:2" json:"created_at"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `gorm:"index" json:"finished_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
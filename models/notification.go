@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationTypes adalah jenis event yang bisa menghasilkan notifikasi inbox, sekaligus kunci preferensi
+// notifikasi per user. Notifikasi dikirim ke watcher tugas, kecuali user yang memicu perubahannya.
+var NotificationTypes = []string{
+	EventTaskUpdated,
+	EventTaskStatusChanged,
+	EventTaskDeleted,
+	EventTaskMoved,
+}
+
+// IsNotificationType melaporkan apakah eventType termasuk NotificationTypes.
+func IsNotificationType(eventType string) bool {
+	for _, t := range NotificationTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification adalah satu pesan di inbox user. EventID menunjuk event outbox pemicunya (unik per user,
+// sehingga event yang diproses ulang tidak menghasilkan notifikasi ganda); Payload berisi JSON data event.
+type Notification struct {
+	ID        uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:char(36);not null;index:idx_notifications_user_created,priority:1;uniqueIndex:idx_notifications_user_event,priority:1" json:"user_id"`
+	Type      string     `gorm:"type:varchar(50);not null" json:"type"`
+	ProjectID uuid.UUID  `gorm:"type:char(36);not null" json:"project_id"`
+	TaskID    *uuid.UUID `gorm:"type:char(36)" json:"task_id"`
+	ActorID   *uuid.UUID `gorm:"type:char(36)" json:"actor_id"`
+	EventID   *uuid.UUID `gorm:"type:char(36);uniqueIndex:idx_notifications_user_event,priority:2" json:"event_id"`
+	Title     string     `gorm:"type:varchar(255);not null" json:"title"`
+	Payload   string     `gorm:"type:text;not null" json:"-"`
+	ReadAt    *time.Time `gorm:"index" json:"read_at"`
+	CreatedAt time.Time  `gorm:"index:idx_notifications_user_created,priority:2" json:"created_at"`
+}
+
+// TaskWatcher menandai user yang ingin diberi tahu setiap kali tugas berubah. Pembuat tugas otomatis
+// menjadi watcher-nya.
+type TaskWatcher struct {
+	TaskID    uuid.UUID `gorm:"type:char(36);primaryKey" json:"task_id"`
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey;index" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NotificationPreference menyimpan pilihan user untuk satu jenis notifikasi. Jenis tanpa preferensi
+// tersimpan dianggap aktif.
+type NotificationPreference struct {
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey" json:"-"`
+	EventType string    `gorm:"type:varchar(50);primaryKey" json:"event_type"`
+	Enabled   bool      `gorm:"not null" json:"enabled"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
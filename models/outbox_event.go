@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Jenis event domain. Event ditulis ke outbox di transaksi yang sama dengan perubahan datanya, lalu
+// diteruskan ke webhook oleh dispatcher.
+const (
+	EventTaskCreated       = "task.created"
+	EventTaskUpdated       = "task.updated"
+	EventTaskStatusChanged = "task.status_changed"
+	EventTaskDeleted       = "task.deleted"
+	EventTaskMoved         = "task.moved"
+	EventProjectUpdated    = "project.updated"
+	EventProjectArchived   = "project.archived"
+	EventProjectUnarchived = "project.unarchived"
+	EventProjectDeleted    = "project.deleted"
+)
+
+// EventTypes adalah semua jenis event yang bisa dilanggan webhook.
+var EventTypes = []string{
+	EventTaskCreated,
+	EventTaskUpdated,
+	EventTaskStatusChanged,
+	EventTaskDeleted,
+	EventTaskMoved,
+	EventProjectUpdated,
+	EventProjectArchived,
+	EventProjectUnarchived,
+	EventProjectDeleted,
+}
+
+// OutboxEvent adalah event domain yang menunggu diteruskan (transactional outbox). ProcessedAt diisi
+// setelah event dibagikan ke webhook yang berlangganan; Payload berisi JSON data event. Attempts dan
+// LastError mencatat pembagian yang gagal, dan DeadAt diisi setelah terlalu sering gagal sehingga event
+// tidak dicoba lagi (dead letter).
+type OutboxEvent struct {
+	ID          uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	Type        string     `gorm:"type:varchar(50);not null" json:"type"`
+	ProjectID   uuid.UUID  `gorm:"type:char(36);not null;index" json:"project_id"`
+	ActorID     uuid.UUID  `gorm:"type:char(36);not null" json:"actor_id"`
+	Payload     string     `gorm:"type:text;not null" json:"-"`
+	CreatedAt   time.Time  `gorm:"index" json:"created_at"`
+	ProcessedAt *time.Time `gorm:"index" json:"processed_at"`
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"`
+	LastError   string     `gorm:"type:text" json:"last_error"`
+	DeadAt      *time.Time `gorm:"index" json:"dead_at"`
+}
@@ -1,11 +1,27 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
 
 type Project struct {
-	ID          uuid.UUID `gorm:"type:char(36);primaryKey;default:uuid()" json:"id"`
-	Name        string    `gorm:"type:varchar(255);not null" json:"name"`
-	Description string    `gorm:"type:text" json:"description"`
-	CreatedByID uuid.UUID `gorm:"type:char(36);not null" json:"created_by_id"`
-	User        User      `gorm:"foreignKey:CreatedByID"`
+	ID          uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	Name        string     `gorm:"type:varchar(255);not null" json:"name"`
+	Description string     `gorm:"type:text" json:"description"`
+	CreatedByID uuid.UUID  `gorm:"type:char(36);not null" json:"created_by_id"`
+	Archived    bool       `gorm:"not null;default:false" json:"archived"` // Proyek yang diarsipkan bersifat read-only
+	ArchivedAt  *time.Time `json:"archived_at"`
+	Version     uint       `gorm:"not null;default:1" json:"version"` // Dinaikkan setiap perubahan, dipakai sebagai ETag
+	User        User       `gorm:"foreignKey:CreatedByID"`
+}
+
+// BeforeCreate mengisi ID di sisi aplikasi supaya tidak bergantung pada fungsi uuid() milik MySQL.
+func (p *Project) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
 }
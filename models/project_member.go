@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectMember memberi user selain pemilik akses ke tugas proyek: melihat, membuat, mengubah, menghapus,
+// dan memantau tugas. Pengaturan proyek (ubah, arsip, hapus, webhook, anggota) tetap hanya milik pemilik.
+type ProjectMember struct {
+	ProjectID uuid.UUID `gorm:"type:char(36);primaryKey" json:"project_id"`
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey;index" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	User      User      `gorm:"foreignKey:UserID" json:"user"`
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Jenis pengingat yang dikirim scheduler, dipakai di SentReminder.Kind.
+const (
+	ReminderBeforeDeadline = "reminder"
+	ReminderOverdue        = "overdue"
+	ReminderDigest         = "digest"
+)
+
+// ReminderOffsets adalah daftar jarak pengingat sebelum deadline dalam menit, disimpan sebagai teks dipisah
+// koma.
+type ReminderOffsets []int
+
+func (o ReminderOffsets) Value() (driver.Value, error) {
+	parts := make([]string, len(o))
+	for i, minutes := range o {
+		parts[i] = strconv.Itoa(minutes)
+	}
+	return strings.Join(parts, ","), nil
+}
+
+func (o *ReminderOffsets) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	case nil:
+	default:
+		return fmt.Errorf("cannot scan %T into ReminderOffsets", value)
+	}
+	*o = ReminderOffsets{}
+	if raw == "" {
+		return nil
+	}
+	for _, part := range strings.Split(raw, ",") {
+		minutes, err := strconv.Atoi(part)
+		if err != nil {
+			return fmt.Errorf("invalid reminder offset %q: %w", part, err)
+		}
+		*o = append(*o, minutes)
+	}
+	return nil
+}
+
+// ReminderSetting adalah pengaturan pengingat deadline milik satu user. Deadline tugas berupa tanggal dan
+// dianggap jatuh tempo di akhir tanggal tersebut menurut Timezone user.
+type ReminderSetting struct {
+	UserID        uuid.UUID       `gorm:"type:char(36);primaryKey" json:"-"`
+	Offsets       ReminderOffsets `gorm:"type:varchar(255);not null" json:"offsets_minutes"` // Kosong berarti tanpa pengingat sebelum deadline
+	OverdueAlerts bool            `gorm:"not null" json:"overdue_alerts"`
+	DailyDigest   bool            `gorm:"not null;index" json:"daily_digest"`
+	DigestHour    int             `gorm:"not null" json:"digest_hour"` // Jam lokal (0-23) pengiriman digest
+	Timezone      string          `gorm:"type:varchar(64);not null" json:"timezone"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// DefaultReminderSetting adalah pengaturan user yang belum pernah mengubahnya: pengingat sehari sebelum
+// deadline dan peringatan overdue, tanpa digest harian.
+func DefaultReminderSetting(userID uuid.UUID) ReminderSetting {
+	return ReminderSetting{
+		UserID:        userID,
+		Offsets:       ReminderOffsets{24 * 60},
+		OverdueAlerts: true,
+		DigestHour:    8,
+		Timezone:      "UTC",
+	}
+}
+
+// SentReminder mencatat pengingat yang sudah dikirim (atau sedang dikirim). Key unik per pengingat, misalnya
+// "reminder:<task>:<deadline>:<offset>", sehingga scheduler di beberapa instance tidak mengirimnya dua kali.
+type SentReminder struct {
+	Key    string     `gorm:"type:varchar(191);primaryKey"`
+	Kind   string     `gorm:"type:varchar(20);not null"`
+	UserID uuid.UUID  `gorm:"type:char(36);not null"`
+	TaskID *uuid.UUID `gorm:"type:char(36)"`
+	SentAt time.Time  `gorm:"not null;index"`
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type TaskStatus string
@@ -14,12 +15,30 @@ const (
 	Done       TaskStatus = "done"
 )
 
+// IsValid melaporkan apakah status termasuk salah satu nilai enum yang dikenal.
+func (s TaskStatus) IsValid() bool {
+	switch s {
+	case Todo, InProgress, Done:
+		return true
+	}
+	return false
+}
+
 type Task struct {
-	ID          uuid.UUID  `gorm:"type:char(36);primaryKey;default:uuid()" json:"id"`
+	ID          uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
 	ProjectID   uuid.UUID  `gorm:"type:char(36);not null" json:"project_id"`
 	Title       string     `gorm:"type:varchar(255);not null" json:"title"`
 	Description string     `gorm:"type:text" json:"description"`
-	Status      TaskStatus `gorm:"type:enum('todo','in_progress','done');default:'todo';not null" json:"status"`
+	Status      TaskStatus `gorm:"type:varchar(20);default:'todo';not null;check:chk_tasks_status,status IN ('todo','in_progress','done')" json:"status"`
 	Deadline    *time.Time `json:"deadline" time_format:"2006-01-02"`
+	Version     uint       `gorm:"not null;default:1" json:"version"` // Dinaikkan setiap perubahan, dipakai sebagai ETag
 	Project     Project    `gorm:"foreignKey:ProjectID"`
 }
+
+// BeforeCreate mengisi ID di sisi aplikasi supaya tidak bergantung pada fungsi uuid() milik MySQL.
+func (t *Task) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
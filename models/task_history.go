@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TaskHistoryAction string
+
+const (
+	TaskMoved  TaskHistoryAction = "moved"
+	TaskCopied TaskHistoryAction = "copied"
+)
+
+// TaskHistory mencatat perpindahan tugas antar proyek. Untuk aksi "copied", TaskID adalah tugas hasil
+// salinan dan SourceTaskID menunjuk ke tugas asalnya.
+type TaskHistory struct {
+	ID            uuid.UUID         `gorm:"type:char(36);primaryKey" json:"id"`
+	TaskID        uuid.UUID         `gorm:"type:char(36);not null;index" json:"task_id"`
+	Action        TaskHistoryAction `gorm:"type:varchar(20);not null" json:"action"`
+	FromProjectID uuid.UUID         `gorm:"type:char(36);not null" json:"from_project_id"`
+	ToProjectID   uuid.UUID         `gorm:"type:char(36);not null" json:"to_project_id"`
+	SourceTaskID  *uuid.UUID        `gorm:"type:char(36)" json:"source_task_id"`
+	ActorID       uuid.UUID         `gorm:"type:char(36);not null" json:"actor_id"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
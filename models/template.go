@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectTemplate adalah kumpulan tugas yang bisa dipakai ulang untuk membuat proyek baru.
+type ProjectTemplate struct {
+	ID          uuid.UUID      `gorm:"type:char(36);primaryKey" json:"id"`
+	Name        string         `gorm:"type:varchar(255);not null" json:"name"`
+	Description string         `gorm:"type:text" json:"description"`
+	CreatedByID uuid.UUID      `gorm:"type:char(36);not null;index" json:"created_by_id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Tasks       []TemplateTask `gorm:"foreignKey:TemplateID" json:"tasks"`
+}
+
+// TemplateTask menyimpan tugas di dalam template. Deadline disimpan sebagai selisih hari dari tanggal
+// mulai proyek, sehingga bisa digeser ke tanggal mulai yang baru saat template dipakai.
+type TemplateTask struct {
+	ID                 uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	TemplateID         uuid.UUID  `gorm:"type:char(36);not null;index" json:"template_id"`
+	Position           int        `gorm:"not null;default:0" json:"position"`
+	Title              string     `gorm:"type:varchar(255);not null" json:"title"`
+	Description        string     `gorm:"type:text" json:"description"`
+	Status             TaskStatus `gorm:"type:varchar(20);default:'todo';not null" json:"status"`
+	DeadlineOffsetDays *int       `json:"deadline_offset_days"`
+}
@@ -1,10 +1,21 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
 
 type User struct {
-	ID       uuid.UUID `gorm:"type:char(36);primaryKey;default:uuid()" json:"id"`
+	ID       uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
 	Name     string    `gorm:"type:varchar(255);not null" json:"name"`
 	Email    string    `gorm:"type:varchar(255);unique;not null" json:"email"`
 	Password string    `gorm:"type:varchar(255);not null" json:"-"`
 }
+
+// BeforeCreate mengisi ID di sisi aplikasi supaya tidak bergantung pada fungsi uuid() milik MySQL.
+func (u *User) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == uuid.Nil {
+		u.ID = uuid.New()
+	}
+	return nil
+}
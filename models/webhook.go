@@ -0,0 +1,99 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEvents adalah daftar jenis event yang dilanggan webhook, disimpan sebagai teks dipisah koma.
+// "*" berarti semua event.
+type WebhookEvents []string
+
+// Includes melaporkan apakah eventType termasuk yang dilanggan.
+func (e WebhookEvents) Includes(eventType string) bool {
+	for _, subscribed := range e {
+		if subscribed == "*" || subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (e WebhookEvents) Value() (driver.Value, error) {
+	return strings.Join(e, ","), nil
+}
+
+func (e *WebhookEvents) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	case nil:
+	default:
+		return fmt.Errorf("cannot scan %T into WebhookEvents", value)
+	}
+	*e = WebhookEvents{}
+	if raw != "" {
+		*e = strings.Split(raw, ",")
+	}
+	return nil
+}
+
+// Webhook adalah langganan event sebuah proyek. Setiap event dikirim sebagai POST ke URL dengan
+// tanda tangan HMAC-SHA256 dari Secret.
+type Webhook struct {
+	ID          uuid.UUID     `gorm:"type:char(36);primaryKey" json:"id"`
+	ProjectID   uuid.UUID     `gorm:"type:char(36);not null;index" json:"project_id"`
+	URL         string        `gorm:"type:varchar(2048);not null" json:"url"`
+	Secret      string        `gorm:"type:varchar(255);not null" json:"-"`
+	Events      WebhookEvents `gorm:"type:varchar(500);not null" json:"events"`
+	Active      bool          `gorm:"not null;default:true" json:"active"`
+	CreatedByID uuid.UUID     `gorm:"type:char(36);not null" json:"created_by_id"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// Status pengiriman webhook.
+const (
+	DeliveryPending   = "pending"   // Menunggu dikirim atau dicoba ulang pada NextAttemptAt
+	DeliverySucceeded = "succeeded" // Penerima membalas 2xx
+	DeliveryDead      = "dead"      // Semua percobaan gagal (dead letter); bisa dikirim ulang manual
+)
+
+// WebhookDelivery adalah pengiriman satu event ke satu webhook. URL dan Secret disalin saat event dibagikan,
+// sehingga pengiriman tetap bisa dilanjutkan walaupun webhook-nya dihapus bersama proyeknya.
+type WebhookDelivery struct {
+	ID             uuid.UUID                `gorm:"type:char(36);primaryKey" json:"id"`
+	WebhookID      uuid.UUID                `gorm:"type:char(36);not null;index" json:"webhook_id"`
+	EventID        uuid.UUID                `gorm:"type:char(36);not null;index" json:"event_id"`
+	EventType      string                   `gorm:"type:varchar(50);not null" json:"event_type"`
+	URL            string                   `gorm:"type:varchar(2048);not null" json:"url"`
+	Secret         string                   `gorm:"type:varchar(255);not null" json:"-"`
+	Status         string                   `gorm:"type:varchar(20);not null;index" json:"status"`
+	Attempts       int                      `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  *time.Time               `gorm:"index" json:"next_attempt_at"`
+	LastStatusCode int                      `gorm:"not null;default:0" json:"last_status_code"`
+	LastError      string                   `gorm:"type:text" json:"last_error"`
+	DeliveredAt    *time.Time               `json:"delivered_at"`
+	CreatedAt      time.Time                `json:"created_at"`
+	UpdatedAt      time.Time                `json:"updated_at"`
+	Log            []WebhookDeliveryAttempt `gorm:"foreignKey:DeliveryID" json:"log"`
+}
+
+// WebhookDeliveryAttempt mencatat hasil satu percobaan pengiriman.
+type WebhookDeliveryAttempt struct {
+	ID           uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	DeliveryID   uuid.UUID `gorm:"type:char(36);not null;index" json:"-"`
+	Attempt      int       `gorm:"not null" json:"attempt"`
+	StatusCode   int       `gorm:"not null;default:0" json:"status_code"` // 0 jika tidak ada response (timeout, DNS, ...)
+	Error        string    `gorm:"type:text" json:"error"`
+	ResponseBody string    `gorm:"type:text" json:"response_body"` // Dipotong maksimal 1 KB
+	DurationMs   int64     `gorm:"not null;default:0" json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
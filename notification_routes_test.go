@@ -0,0 +1,244 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"taskify/models"
+)
+
+// addMember menjadikan member anggota proyek milik owner.
+func (s *testServer) addMember(owner testUser, projectID string, member testUser) {
+	s.t.Helper()
+
+	rec := s.do(http.MethodPost, "/api/projects/"+projectID+"/members", owner.Token, gin.H{"email": member.Email})
+	expectStatus(s.t, rec, http.StatusCreated)
+}
+
+// updateTask mengubah tugas lewat PATCH atas nama user.
+func (s *testServer) updateTask(user testUser, projectID, taskID string, changes gin.H) {
+	s.t.Helper()
+
+	rec := s.do(http.MethodPatch, "/api/projects/"+projectID+"/tasks/"+taskID, user.Token, changes)
+	expectStatus(s.t, rec, http.StatusOK)
+}
+
+func TestTaskWatchers(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	stranger := s.register("stranger")
+	projectID := s.createProject(owner, "Watched")
+	taskID := s.createTask(owner, projectID, "Report", nil)
+	s.dispatch()
+	path := "/api/projects/" + projectID + "/tasks/" + taskID
+
+	// Pembuat tugas otomatis menjadi watcher
+	rec := s.do(http.MethodGet, path+"/watchers", owner.Token, nil)
+	expectStatus(t, rec, http.StatusOK)
+	body := decode(t, rec)
+	if count(body, "watchers") != 1 || str(body, "watchers.0.user_id") != owner.ID {
+		t.Fatalf("expected the creator to watch the task, got %v", body)
+	}
+
+	expectStatus(t, s.do(http.MethodDelete, path+"/watch", owner.Token, nil), http.StatusOK)
+	if body := decode(t, s.do(http.MethodGet, path+"/watchers", owner.Token, nil)); count(body, "watchers") != 0 {
+		t.Fatalf("expected no watchers after unwatch, got %v", body)
+	}
+
+	// Watch bersifat idempoten
+	expectStatus(t, s.do(http.MethodPut, path+"/watch", owner.Token, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodPut, path+"/watch", owner.Token, nil), http.StatusOK)
+	if body := decode(t, s.do(http.MethodGet, path+"/watchers", owner.Token, nil)); count(body, "watchers") != 1 {
+		t.Fatalf("expected one watcher, got %v", body)
+	}
+
+	expectStatus(t, s.do(http.MethodPut, path+"/watch", stranger.Token, nil), http.StatusNotFound)
+	// Anggota proyek boleh memantau tugas di proyek orang lain
+	s.addMember(owner, projectID, stranger)
+	expectStatus(t, s.do(http.MethodPut, path+"/watch", stranger.Token, nil), http.StatusOK)
+	if body := decode(t, s.do(http.MethodGet, path+"/watchers", stranger.Token, nil)); count(body, "watchers") != 2 {
+		t.Fatalf("expected the member to watch the task, got %v", body)
+	}
+	expectStatus(t, s.do(http.MethodPut, "/api/projects/"+projectID+"/tasks/"+uuid.NewString()+"/watch", owner.Token, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodPut, path+"/watch", "", nil), http.StatusUnauthorized)
+}
+
+func TestNotificationsFromTaskEvents(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	member := s.register("member")
+	other := s.register("other")
+	projectID := s.createProject(owner, "Inbox")
+	s.addMember(owner, projectID, member)
+	taskID := s.createTask(owner, projectID, "Launch", nil)
+
+	// Perubahan oleh watcher sendiri tidak menghasilkan notifikasi
+	s.updateTask(owner, projectID, taskID, gin.H{"title": "Launch v2"})
+	s.dispatch()
+	if body := decode(t, s.do(http.MethodGet, "/api/me/notifications", owner.Token, nil)); count(body, "notifications") != 0 {
+		t.Fatalf("expected no notifications for own changes, got %v", body)
+	}
+
+	s.updateTask(member, projectID, taskID, gin.H{"status": "done"})
+	s.dispatch()
+	// Relay ulang tidak menggandakan notifikasi
+	s.dispatch()
+
+	rec := s.do(http.MethodGet, "/api/me/notifications", owner.Token, nil)
+	expectStatus(t, rec, http.StatusOK)
+	body := decode(t, rec)
+	if count(body, "notifications") != 1 || lookup(body, "unread_count") != float64(1) {
+		t.Fatalf("expected one unread notification, got %v", body)
+	}
+	if str(body, "notifications.0.type") != models.EventTaskStatusChanged ||
+		str(body, "notifications.0.task_id") != taskID ||
+		str(body, "notifications.0.actor_id") != member.ID ||
+		str(body, "notifications.0.title") != `Task "Launch v2" moved from todo to done` ||
+		str(body, "notifications.0.data.status") != "done" ||
+		lookup(body, "notifications.0.read_at") != nil {
+		t.Fatalf("unexpected notification: %v", body)
+	}
+	notificationID := str(body, "notifications.0.id")
+
+	// Notifikasi hanya bisa dibaca pemiliknya
+	expectStatus(t, s.do(http.MethodPost, "/api/me/notifications/"+notificationID+"/read", other.Token, nil), http.StatusNotFound)
+	rec = s.do(http.MethodPost, "/api/me/notifications/"+notificationID+"/read", owner.Token, nil)
+	expectStatus(t, rec, http.StatusOK)
+	if body := decode(t, rec); lookup(body, "notification.read_at") == nil {
+		t.Fatalf("expected read_at to be set, got %v", body)
+	}
+	body = decode(t, s.do(http.MethodGet, "/api/me/notifications?unread=true", owner.Token, nil))
+	if count(body, "notifications") != 0 || lookup(body, "unread_count") != float64(0) {
+		t.Fatalf("expected an empty unread inbox, got %v", body)
+	}
+
+	// Tugas yang dihapus memberi tahu watcher lalu melepas watcher-nya
+	expectStatus(t, s.do(http.MethodDelete, "/api/projects/"+projectID+"/tasks/"+taskID, member.Token, nil), http.StatusOK)
+	s.dispatch()
+	body = decode(t, s.do(http.MethodGet, "/api/me/notifications", owner.Token, nil))
+	if count(body, "notifications") != 2 || str(body, "notifications.0.title") != `Task "Launch v2" was deleted` {
+		t.Fatalf("expected the deletion notification first, got %v", body)
+	}
+	var watchers int64
+	s.db.Model(&models.TaskWatcher{}).Where("task_id = ?", taskID).Count(&watchers)
+	if watchers != 0 {
+		t.Fatalf("expected watchers of a deleted task to be removed, got %d", watchers)
+	}
+}
+
+func TestNotificationsStopWhenMemberLeaves(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	member := s.register("member")
+	projectID := s.createProject(owner, "Shared")
+	s.addMember(owner, projectID, member)
+
+	// Anggota yang membuat tugas otomatis memantaunya dan diberi tahu perubahan oleh pemilik
+	taskID := s.createTask(member, projectID, "Draft", nil)
+	s.dispatch()
+	s.updateTask(owner, projectID, taskID, gin.H{"title": "Draft v2"})
+	s.dispatch()
+	body := decode(t, s.do(http.MethodGet, "/api/me/notifications", member.Token, nil))
+	if count(body, "notifications") != 1 || str(body, "notifications.0.type") != models.EventTaskUpdated || str(body, "notifications.0.actor_id") != owner.ID {
+		t.Fatalf("expected the member to be notified of the owner's change, got %v", body)
+	}
+
+	expectStatus(t, s.do(http.MethodDelete, "/api/projects/"+projectID+"/members/"+member.ID, owner.Token, nil), http.StatusOK)
+	s.updateTask(owner, projectID, taskID, gin.H{"title": "Draft v3"})
+	s.dispatch()
+	if body := decode(t, s.do(http.MethodGet, "/api/me/notifications", member.Token, nil)); count(body, "notifications") != 1 {
+		t.Fatalf("a removed member must not be notified, got %v", body)
+	}
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	member := s.register("member")
+	projectID := s.createProject(owner, "Quiet")
+	s.addMember(owner, projectID, member)
+	taskID := s.createTask(owner, projectID, "Muted", nil)
+	s.dispatch()
+
+	rec := s.do(http.MethodGet, "/api/me/notification-preferences", owner.Token, nil)
+	expectStatus(t, rec, http.StatusOK)
+	body := decode(t, rec)
+	if count(body, "preferences") != len(models.NotificationTypes) || lookup(body, "preferences.0.enabled") != true {
+		t.Fatalf("expected every type enabled by default, got %v", body)
+	}
+
+	expectStatus(t, s.do(http.MethodPut, "/api/me/notification-preferences", owner.Token, gin.H{"preferences": gin.H{"comment.created": true}}), http.StatusBadRequest)
+	rec = s.do(http.MethodPut, "/api/me/notification-preferences", owner.Token, gin.H{"preferences": gin.H{models.EventTaskUpdated: false}})
+	expectStatus(t, rec, http.StatusOK)
+	for _, preference := range lookup(decode(t, rec), "preferences").([]interface{}) {
+		p := preference.(map[string]interface{})
+		if p["enabled"] != (p["event_type"] != models.EventTaskUpdated) {
+			t.Fatalf("unexpected preference: %v", p)
+		}
+	}
+
+	// Jenis yang dimatikan tidak masuk inbox, jenis lain tetap masuk
+	s.updateTask(member, projectID, taskID, gin.H{"title": "Still muted"})
+	s.updateTask(member, projectID, taskID, gin.H{"status": "done"})
+	s.dispatch()
+	body = decode(t, s.do(http.MethodGet, "/api/me/notifications", owner.Token, nil))
+	if count(body, "notifications") != 1 || str(body, "notifications.0.type") != models.EventTaskStatusChanged {
+		t.Fatalf("expected only the status change, got %v", body)
+	}
+}
+
+func TestNotificationPagination(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	member := s.register("member")
+	projectID := s.createProject(owner, "Busy")
+	s.addMember(owner, projectID, member)
+	taskID := s.createTask(owner, projectID, "Hot", nil)
+	s.dispatch()
+
+	for i := 0; i < 5; i++ {
+		s.updateTask(member, projectID, taskID, gin.H{"title": fmt.Sprintf("Hot %d", i)})
+	}
+	s.dispatch()
+
+	seen := map[string]bool{}
+	cursor := ""
+	for pages := 0; ; pages++ {
+		path := "/api/me/notifications?limit=2"
+		if cursor != "" {
+			path += "&cursor=" + cursor
+		}
+		rec := s.do(http.MethodGet, path, owner.Token, nil)
+		expectStatus(t, rec, http.StatusOK)
+		body := decode(t, rec)
+		for _, n := range lookup(body, "notifications").([]interface{}) {
+			seen[n.(map[string]interface{})["id"].(string)] = true
+		}
+		if lookup(body, "next_cursor") == nil {
+			if pages != 2 || count(body, "notifications") != 1 {
+				t.Fatalf("expected three pages of 2, 2 and 1, got last page %v after %d pages", body, pages)
+			}
+			break
+		}
+		cursor = str(body, "next_cursor")
+	}
+	if len(seen) != 5 {
+		t.Fatalf("expected all 5 notifications across pages, got %d", len(seen))
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/api/me/notifications?cursor=bogus", owner.Token, nil), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodGet, "/api/me/notifications?limit=0", owner.Token, nil), http.StatusBadRequest)
+
+	rec := s.do(http.MethodPost, "/api/me/notifications/read-all", owner.Token, nil)
+	expectStatus(t, rec, http.StatusOK)
+	if body := decode(t, rec); lookup(body, "updated") != float64(5) {
+		t.Fatalf("expected 5 notifications marked read, got %v", body)
+	}
+	if body := decode(t, s.do(http.MethodGet, "/api/me/notifications", owner.Token, nil)); lookup(body, "unread_count") != float64(0) {
+		t.Fatalf("expected no unread notifications, got %v", body)
+	}
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"taskify/config"
+	"taskify/models"
+)
+
+func TestProjectRoutes(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Website")
+	s.createTask(owner, projectID, "Design", gin.H{"deadline": "2030-01-10"})
+	detail := "/api/projects/detail/" + projectID
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		body    interface{}
+		headers []header
+		status  int
+		check   func(t *testing.T, body map[string]interface{})
+	}{
+		{
+			name: "create", method: http.MethodPost, path: "/api/projects",
+			body:   gin.H{"name": "Mobile", "created_by": owner.ID},
+			status: http.StatusCreated,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if str(body, "project.name") != "Mobile" || lookup(body, "project.version") != float64(1) {
+					t.Fatalf("unexpected project: %v", body["project"])
+				}
+			},
+		},
+		{
+			name: "create requires name", method: http.MethodPost, path: "/api/projects",
+			body: gin.H{"created_by": owner.ID}, status: http.StatusBadRequest,
+		},
+		{
+			name: "create rejects unknown creator", method: http.MethodPost, path: "/api/projects",
+			body: gin.H{"name": "Ghost", "created_by": uuid.NewString()}, status: http.StatusBadRequest,
+		},
+		{
+			name: "list", method: http.MethodGet, path: "/api/projects", status: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if count(body, "projects") == 0 {
+					t.Fatal("expected the owner's projects")
+				}
+			},
+		},
+		{
+			name: "list rejects invalid include_archived", method: http.MethodGet,
+			path: "/api/projects?include_archived=maybe", status: http.StatusBadRequest,
+		},
+		{
+			name: "get", method: http.MethodGet, path: detail, status: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if str(body, "project.id") != projectID {
+					t.Fatalf("unexpected project: %v", body["project"])
+				}
+			},
+		},
+		{
+			name: "get returns 304 for current etag", method: http.MethodGet, path: detail,
+			headers: []header{{"If-None-Match", `"v1"`}}, status: http.StatusNotModified,
+		},
+		{name: "get rejects invalid id", method: http.MethodGet, path: "/api/projects/detail/not-a-uuid", status: http.StatusBadRequest},
+		{name: "get unknown project", method: http.MethodGet, path: "/api/projects/detail/" + uuid.NewString(), status: http.StatusNotFound},
+		{
+			name: "put with stale if-match", method: http.MethodPut, path: detail,
+			body: gin.H{"name": "Stale"}, headers: []header{{"If-Match", `"v99"`}},
+			status: http.StatusPreconditionFailed,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if str(body, "project.name") != "Website" {
+					t.Fatalf("412 should include the current project, got %v", body)
+				}
+			},
+		},
+		{
+			name: "put", method: http.MethodPut, path: detail,
+			body: gin.H{"name": "Website v2"}, headers: []header{{"If-Match", `"v1"`}},
+			status: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if str(body, "project.name") != "Website v2" || lookup(body, "project.version") != float64(2) {
+					t.Fatalf("unexpected project: %v", body["project"])
+				}
+			},
+		},
+		{
+			name: "patch clears description", method: http.MethodPatch, path: detail,
+			body: `{"description": null}`, headers: []header{{"Content-Type", "application/merge-patch+json"}},
+			status: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if str(body, "project.description") != "" || str(body, "project.name") != "Website v2" {
+					t.Fatalf("unexpected project: %v", body["project"])
+				}
+			},
+		},
+		{
+			name: "patch rejects unknown field", method: http.MethodPatch, path: detail,
+			body: gin.H{"owner": "someone"}, status: http.StatusBadRequest,
+		},
+		{
+			name: "patch rejects unsupported content type", method: http.MethodPatch, path: detail,
+			body: `name=x`, headers: []header{{"Content-Type", "application/x-www-form-urlencoded"}},
+			status: http.StatusUnsupportedMediaType,
+		},
+		{
+			name: "duplicate", method: http.MethodPost, path: "/api/projects/" + projectID + "/duplicate",
+			body: gin.H{"name": "Website copy", "start_date": "2031-05-01", "reset_status": true}, status: http.StatusCreated,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if lookup(body, "tasks_copied") != float64(1) || str(body, "project.name") != "Website copy" {
+					t.Fatalf("unexpected duplicate response: %v", body)
+				}
+			},
+		},
+		{
+			name: "save as template", method: http.MethodPost, path: "/api/projects/" + projectID + "/template",
+			body: gin.H{"name": "Website template"}, status: http.StatusCreated,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if count(body, "template.tasks") != 1 {
+					t.Fatalf("expected one template task, got %v", body["template"])
+				}
+			},
+		},
+		{name: "archive", method: http.MethodPost, path: detail + "/archive", status: http.StatusOK},
+		{name: "archive twice", method: http.MethodPost, path: detail + "/archive", status: http.StatusConflict},
+		{
+			name: "archived project is hidden by default", method: http.MethodGet, path: "/api/projects", status: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				for _, p := range lookup(body, "projects").([]interface{}) {
+					if str(p, "id") == projectID {
+						t.Fatal("archived project should be hidden")
+					}
+				}
+			},
+		},
+		{
+			name: "archived project is listed with include_archived", method: http.MethodGet,
+			path: "/api/projects?include_archived=true", status: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				for _, p := range lookup(body, "projects").([]interface{}) {
+					if str(p, "id") == projectID {
+						return
+					}
+				}
+				t.Fatal("archived project should be listed")
+			},
+		},
+		{name: "archived project is read-only", method: http.MethodPut, path: detail, body: gin.H{"name": "Nope"}, status: http.StatusConflict},
+		{name: "unarchive", method: http.MethodPost, path: detail + "/unarchive", status: http.StatusOK},
+		{name: "unarchive twice", method: http.MethodPost, path: detail + "/unarchive", status: http.StatusConflict},
+		{name: "delete", method: http.MethodDelete, path: detail, status: http.StatusOK},
+		{name: "deleted project is gone", method: http.MethodGet, path: detail, status: http.StatusNotFound},
+		{name: "tasks of deleted project are gone", method: http.MethodGet, path: "/api/projects/" + projectID + "/tasks", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.do(tt.method, tt.path, owner.Token, tt.body, tt.headers...)
+			expectStatus(t, rec, tt.status)
+			if tt.check != nil {
+				tt.check(t, decode(t, rec))
+			}
+		})
+	}
+}
+
+// Setiap rute proyek harus menolak user lain dengan 404 yang sama seperti proyek yang tidak ada,
+// dan proyek pemilik tidak boleh berubah karenanya.
+func TestProjectRoutesDenyOtherUsers(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	intruder := s.register("intruder")
+	projectID := s.createProject(owner, "Private")
+	detail := "/api/projects/detail/" + projectID
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+	}{
+		{"get", http.MethodGet, detail, nil},
+		{"put", http.MethodPut, detail, gin.H{"name": "Hijacked"}},
+		{"patch", http.MethodPatch, detail, gin.H{"name": "Hijacked"}},
+		{"delete", http.MethodDelete, detail, nil},
+		{"archive", http.MethodPost, detail + "/archive", nil},
+		{"unarchive", http.MethodPost, detail + "/unarchive", nil},
+		{"duplicate", http.MethodPost, "/api/projects/" + projectID + "/duplicate", gin.H{}},
+		{"save as template", http.MethodPost, "/api/projects/" + projectID + "/template", gin.H{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.do(tt.method, tt.path, intruder.Token, tt.body)
+			expectStatus(t, rec, http.StatusNotFound)
+			if got := str(decode(t, rec), "error"); got != "Project not found or you don't have access" {
+				t.Fatalf("unexpected error message %q", got)
+			}
+		})
+	}
+
+	t.Run("list only shows own projects", func(t *testing.T) {
+		rec := s.do(http.MethodGet, "/api/projects", intruder.Token, nil)
+		expectStatus(t, rec, http.StatusOK)
+		if n := count(decode(t, rec), "projects"); n != 0 {
+			t.Fatalf("expected no projects for the intruder, got %d", n)
+		}
+	})
+
+	t.Run("owner project is untouched", func(t *testing.T) {
+		rec := s.do(http.MethodGet, detail, owner.Token, nil)
+		expectStatus(t, rec, http.StatusOK)
+		body := decode(t, rec)
+		if str(body, "project.name") != "Private" || lookup(body, "project.version") != float64(1) {
+			t.Fatalf("project was modified: %v", body["project"])
+		}
+	})
+}
+
+func TestProjectIfMatchRequired(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) { cfg.API.RequireIfMatch = true })
+	owner := s.register("owner")
+	detail := "/api/projects/detail/" + s.createProject(owner, "Strict")
+
+	expectStatus(t, s.do(http.MethodPut, detail, owner.Token, gin.H{"name": "No header"}), http.StatusPreconditionRequired)
+	expectStatus(t, s.do(http.MethodDelete, detail, owner.Token, nil), http.StatusPreconditionRequired)
+
+	rec := s.do(http.MethodPut, detail, owner.Token, gin.H{"name": "With header"}, header{"If-Match", `"v1"`})
+	expectStatus(t, rec, http.StatusOK)
+	if rec.Header().Get("ETag") != `"v2"` {
+		t.Fatalf("expected ETag \"v2\", got %q", rec.Header().Get("ETag"))
+	}
+}
+
+func TestCreateProjectIdempotencyKey(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	other := s.register("other")
+	key := header{"Idempotency-Key", "create-project"}
+	body := gin.H{"name": "Once", "created_by": owner.ID}
+
+	first := s.do(http.MethodPost, "/api/projects", owner.Token, body, key)
+	expectStatus(t, first, http.StatusCreated)
+	replay := s.do(http.MethodPost, "/api/projects", owner.Token, body, key)
+	expectStatus(t, replay, http.StatusCreated)
+	if str(decode(t, replay), "project.id") != str(decode(t, first), "project.id") {
+		t.Fatal("retry should replay the original project instead of creating another")
+	}
+
+	// Key di-scope per user, jadi user lain dengan key yang sama tidak mendapat response milik owner
+	rec := s.do(http.MethodPost, "/api/projects", other.Token, gin.H{"name": "Mine", "created_by": other.ID}, key)
+	expectStatus(t, rec, http.StatusCreated)
+	if rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatal("idempotency keys must not be shared between users")
+	}
+
+	list := s.do(http.MethodGet, "/api/projects", owner.Token, nil)
+	expectStatus(t, list, http.StatusOK)
+	if n := count(decode(t, list), "projects"); n != 1 {
+		t.Fatalf("expected exactly one project, got %d", n)
+	}
+}
+
+func TestIdempotencyKeyIsIgnoredForSecrets(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Secrets")
+
+	// Response yang berisi token feed atau signing secret tidak disimpan, jadi retry membuat rahasia baru
+	for _, request := range []struct {
+		path   string
+		body   gin.H
+		secret string
+	}{
+		{"/api/me/calendar-feed", nil, "url"},
+		{"/api/projects/" + projectID + "/calendar-feed", nil, "url"},
+		{"/api/projects/" + projectID + "/webhooks", gin.H{"url": "https://example.com/hook", "events": []string{"*"}}, "webhook.secret"},
+	} {
+		key := header{"Idempotency-Key", "secret-" + request.path}
+		first := s.do(http.MethodPost, request.path, owner.Token, request.body, key)
+		expectStatus(t, first, http.StatusCreated)
+		retry := s.do(http.MethodPost, request.path, owner.Token, request.body, key)
+		expectStatus(t, retry, http.StatusCreated)
+		if retry.Header().Get("Idempotent-Replayed") != "" || str(decode(t, retry), request.secret) == str(decode(t, first), request.secret) {
+			t.Fatalf("%s: responses with secrets must not be replayed", request.path)
+		}
+	}
+
+	// Endpoint notifikasi tetap mendukung Idempotency-Key
+	key := header{"Idempotency-Key", "read-all"}
+	expectStatus(t, s.do(http.MethodPost, "/api/me/notifications/read-all", owner.Token, nil, key), http.StatusOK)
+	if rec := s.do(http.MethodPost, "/api/me/notifications/read-all", owner.Token, nil, key); rec.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatal("expected notification POSTs to honour Idempotency-Key")
+	}
+}
+
+func TestProjectMembers(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	member := s.register("member")
+	stranger := s.register("stranger")
+	projectID := s.createProject(owner, "Shared")
+	membersPath := "/api/projects/" + projectID + "/members"
+
+	t.Run("only the owner adds members", func(t *testing.T) {
+		expectStatus(t, s.do(http.MethodPost, membersPath, stranger.Token, gin.H{"email": member.Email}), http.StatusNotFound)
+		expectStatus(t, s.do(http.MethodPost, membersPath, owner.Token, gin.H{"email": "nobody@example.com"}), http.StatusNotFound)
+		expectStatus(t, s.do(http.MethodPost, membersPath, owner.Token, gin.H{"email": owner.Email}), http.StatusBadRequest)
+		expectStatus(t, s.do(http.MethodPost, membersPath, owner.Token, gin.H{"email": "not-an-email"}), http.StatusBadRequest)
+
+		rec := s.do(http.MethodPost, membersPath, owner.Token, gin.H{"email": member.Email})
+		expectStatus(t, rec, http.StatusCreated)
+		if body := decode(t, rec); str(body, "member.user_id") != member.ID || str(body, "member.user.email") != member.Email {
+			t.Fatalf("unexpected member: %v", body)
+		}
+		expectStatus(t, s.do(http.MethodPost, membersPath, owner.Token, gin.H{"email": member.Email}), http.StatusConflict)
+
+		rec = s.do(http.MethodGet, membersPath, member.Token, nil)
+		expectStatus(t, rec, http.StatusOK)
+		if body := decode(t, rec); count(body, "members") != 1 || str(body, "members.0.user_id") != member.ID {
+			t.Fatalf("unexpected member list: %v", body)
+		}
+		expectStatus(t, s.do(http.MethodGet, membersPath, stranger.Token, nil), http.StatusNotFound)
+	})
+
+	t.Run("members read the project and manage tasks but not the project", func(t *testing.T) {
+		taskID := s.createTask(member, projectID, "Shared work", gin.H{"deadline": "2030-01-02"})
+		expectStatus(t, s.do(http.MethodGet, "/api/projects/"+projectID+"/tasks", member.Token, nil), http.StatusOK)
+		expectStatus(t, s.do(http.MethodGet, "/api/projects/"+projectID+"/tasks/"+taskID, owner.Token, nil), http.StatusOK)
+
+		// Proyek yang diikuti terlihat seperti proyek sendiri
+		expectStatus(t, s.do(http.MethodGet, "/api/projects/detail/"+projectID, member.Token, nil), http.StatusOK)
+		if body := decode(t, s.do(http.MethodGet, "/api/projects", member.Token, nil)); count(body, "projects") != 1 || str(body, "projects.0.id") != projectID {
+			t.Fatalf("expected the shared project in the member's list, got %v", body)
+		}
+		expectStatus(t, s.do(http.MethodGet, "/api/projects/"+projectID+"/export", member.Token, nil), http.StatusOK)
+		if body := s.fetchCalendar(s.createCalendarFeed(member, "/api/me/calendar-feed")); !strings.Contains(body, "SUMMARY:Shared work") {
+			t.Fatalf("expected the shared task in the member's calendar, got %s", body)
+		}
+		expectStatus(t, s.do(http.MethodGet, "/api/projects/detail/"+projectID, stranger.Token, nil), http.StatusNotFound)
+
+		expectStatus(t, s.do(http.MethodPost, "/api/projects/detail/"+projectID+"/archive", member.Token, nil), http.StatusNotFound)
+
+		expectStatus(t, s.do(http.MethodPatch, "/api/projects/detail/"+projectID, member.Token, gin.H{"name": "Mine now"}), http.StatusNotFound)
+		expectStatus(t, s.do(http.MethodDelete, "/api/projects/detail/"+projectID, member.Token, nil), http.StatusNotFound)
+		expectStatus(t, s.do(http.MethodGet, "/api/projects/"+projectID+"/webhooks", member.Token, nil), http.StatusNotFound)
+		expectStatus(t, s.do(http.MethodPost, membersPath, member.Token, gin.H{"email": stranger.Email}), http.StatusNotFound)
+		expectStatus(t, s.do(http.MethodGet, "/api/projects/"+projectID+"/tasks", stranger.Token, nil), http.StatusNotFound)
+	})
+
+	t.Run("members leave and lose access", func(t *testing.T) {
+		expectStatus(t, s.do(http.MethodDelete, membersPath+"/"+owner.ID, member.Token, nil), http.StatusNotFound)
+		expectStatus(t, s.do(http.MethodDelete, membersPath+"/"+member.ID, member.Token, nil), http.StatusOK)
+		expectStatus(t, s.do(http.MethodDelete, membersPath+"/"+member.ID, owner.Token, nil), http.StatusNotFound)
+		expectStatus(t, s.do(http.MethodGet, "/api/projects/"+projectID+"/tasks", member.Token, nil), http.StatusNotFound)
+		expectStatus(t, s.do(http.MethodGet, "/api/projects/detail/"+projectID, member.Token, nil), http.StatusNotFound)
+		if body := decode(t, s.do(http.MethodGet, "/api/projects", member.Token, nil)); count(body, "projects") != 0 {
+			t.Fatalf("expected the project to leave the member's list, got %v", body)
+		}
+	})
+
+	t.Run("members are removed with the project", func(t *testing.T) {
+		s.addMember(owner, projectID, member)
+		expectStatus(t, s.do(http.MethodDelete, "/api/projects/detail/"+projectID, owner.Token, nil), http.StatusOK)
+		var members int64
+		s.db.Model(&models.ProjectMember{}).Where("project_id = ?", projectID).Count(&members)
+		if members != 0 {
+			t.Fatalf("expected members of a deleted project to be removed, got %d", members)
+		}
+	})
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"taskify/config"
+	"taskify/models"
+)
+
+// upload mengirim content sebagai field multipart "file" dengan nama filename.
+func (s *testServer) upload(path, token, filename, content string) *httptest.ResponseRecorder {
+	s.t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		s.t.Fatalf("create form file: %v", err)
+	}
+	part.Write([]byte(content))
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// projectTasks mengambil tugas proyek, diindeks berdasarkan judul.
+func (s *testServer) projectTasks(user testUser, projectID string) map[string]map[string]interface{} {
+	s.t.Helper()
+
+	rec := s.do(http.MethodGet, "/api/projects/"+projectID+"/tasks", user.Token, nil)
+	expectStatus(s.t, rec, http.StatusOK)
+	tasks := map[string]map[string]interface{}{}
+	for _, task := range lookup(decode(s.t, rec), "tasks").([]interface{}) {
+		task := task.(map[string]interface{})
+		tasks[task["title"].(string)] = task
+	}
+	return tasks
+}
+
+// countProjects menghitung proyek milik user langsung di database.
+func (s *testServer) countProjects(user testUser) int64 {
+	var projects int64
+	s.db.Model(&models.Project{}).Where("created_by_id = ?", user.ID).Count(&projects)
+	return projects
+}
+
+var csvHeader = header{Name: "Content-Type", Value: "text/csv"}
+
+func TestProjectExport(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	stranger := s.register("stranger")
+	projectID := s.createProject(owner, "Garden Plan!")
+	s.createTask(owner, projectID, "Buy seeds", gin.H{"deadline": "2030-03-01", "description": "Tomatoes, basil\nand \"peppers\""})
+	s.createTask(owner, projectID, "Dig beds", gin.H{"status": "done"})
+
+	rec := s.do(http.MethodGet, "/api/projects/"+projectID+"/export", owner.Token, nil)
+	expectStatus(t, rec, http.StatusOK)
+	if disposition := rec.Header().Get("Content-Disposition"); disposition != `attachment; filename="garden-plan.json"` {
+		t.Fatalf("unexpected Content-Disposition %q", disposition)
+	}
+	body := decode(t, rec)
+	if lookup(body, "format_version") != float64(1) || str(body, "project.name") != "Garden Plan!" || count(body, "tasks") != 2 ||
+		str(body, "tasks.0.title") != "Buy seeds" || str(body, "tasks.0.deadline") != "2030-03-01" ||
+		str(body, "tasks.1.status") != "done" || lookup(body, "tasks.1.deadline") != nil {
+		t.Fatalf("unexpected JSON export: %v", body)
+	}
+
+	rec = s.do(http.MethodGet, "/api/projects/"+projectID+"/export?format=csv", owner.Token, nil)
+	expectStatus(t, rec, http.StatusOK)
+	if contentType := rec.Header().Get("Content-Type"); contentType != "text/csv; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", contentType)
+	}
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV export: %v", err)
+	}
+	if len(records) != 3 || strings.Join(records[0], ",") != "id,title,description,status,deadline" ||
+		records[1][1] != "Buy seeds" || records[1][2] != "Tomatoes, basil\nand \"peppers\"" || records[1][4] != "2030-03-01" ||
+		records[2][3] != "done" || records[2][4] != "" {
+		t.Fatalf("unexpected CSV export: %q", records)
+	}
+
+	// Proyek arsip tetap bisa diexport
+	expectStatus(t, s.do(http.MethodPost, "/api/projects/detail/"+projectID+"/archive", owner.Token, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodGet, "/api/projects/"+projectID+"/export", owner.Token, nil), http.StatusOK)
+
+	expectStatus(t, s.do(http.MethodGet, "/api/projects/"+projectID+"/export?format=xml", owner.Token, nil), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodGet, "/api/projects/"+projectID+"/export", stranger.Token, nil), http.StatusNotFound)
+}
+
+func TestProjectImportRoundTrip(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Launch")
+	s.createTask(owner, projectID, "Write, review; ship", gin.H{"deadline": "2030-03-01", "description": "Line one\nLine two"})
+	s.createTask(owner, projectID, "Announce", gin.H{"status": "in_progress"})
+
+	// JSON dikirim langsung sebagai body; nama proyek diambil dari file kecuali name diberikan
+	exported := s.do(http.MethodGet, "/api/projects/"+projectID+"/export", owner.Token, nil).Body.String()
+	rec := s.do(http.MethodPost, "/api/projects/import", owner.Token, exported)
+	expectStatus(t, rec, http.StatusCreated)
+	body := decode(t, rec)
+	if str(body, "project.name") != "Launch" || str(body, "project.description") != "Launch description" ||
+		lookup(body, "report.imported") != float64(2) || str(body, "report.project_id") != str(body, "project.id") {
+		t.Fatalf("unexpected import: %v", body)
+	}
+	tasks := s.projectTasks(owner, str(body, "project.id"))
+	if len(tasks) != 2 || str(tasks["Write, review; ship"], "deadline") != "2030-03-01T00:00:00Z" ||
+		str(tasks["Write, review; ship"], "description") != "Line one\nLine two" || str(tasks["Announce"], "status") != "in_progress" {
+		t.Fatalf("unexpected imported tasks: %v", tasks)
+	}
+
+	// CSV lewat multipart; tanpa nama di file, nama proyek diambil dari nama file
+	exported = s.do(http.MethodGet, "/api/projects/"+projectID+"/export?format=csv", owner.Token, nil).Body.String()
+	rec = s.upload("/api/projects/import", owner.Token, "launch-copy.csv", exported)
+	expectStatus(t, rec, http.StatusCreated)
+	body = decode(t, rec)
+	if str(body, "project.name") != "launch-copy" || lookup(body, "report.imported") != float64(2) {
+		t.Fatalf("unexpected CSV import: %v", body)
+	}
+	if tasks := s.projectTasks(owner, str(body, "project.id")); len(tasks) != 2 || str(tasks["Announce"], "status") != "in_progress" {
+		t.Fatalf("unexpected imported tasks: %v", tasks)
+	}
+
+	rec = s.upload("/api/projects/import?name=Relaunch", owner.Token, "launch.json", s.do(http.MethodGet, "/api/projects/"+projectID+"/export", owner.Token, nil).Body.String())
+	expectStatus(t, rec, http.StatusCreated)
+	if name := str(decode(t, rec), "project.name"); name != "Relaunch" {
+		t.Fatalf("expected the name parameter to win, got %q", name)
+	}
+}
+
+func TestProjectImportValidation(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	file := "Title,Status,Deadline,Assignee\n" +
+		"Plan,In Progress,2030-03-01,ana\n" +
+		",todo,,bob\n" +
+		"Review,Blocked,2030-03-02T23:30:00-05:00,\n" +
+		"Ship,done,next week,\n"
+
+	// Dry run: laporan lengkap tanpa menyimpan apa pun
+	rec := s.do(http.MethodPost, "/api/projects/import?name=Sprint&dry_run=true", owner.Token, file, csvHeader)
+	expectStatus(t, rec, http.StatusOK)
+	body := decode(t, rec)
+	if lookup(body, "report.dry_run") != true || lookup(body, "report.aborted") != true || lookup(body, "report.rows") != float64(4) ||
+		lookup(body, "report.valid") != float64(2) || lookup(body, "report.invalid") != float64(2) || lookup(body, "report.imported") != float64(0) ||
+		str(body, "report.warnings.0") != `Column "Assignee" is not recognised and was ignored` {
+		t.Fatalf("unexpected dry run report: %v", body)
+	}
+	for i, want := range []struct {
+		row    float64
+		result string
+		detail string
+	}{
+		{2, "ok", ""},
+		{3, "error", "errors.0"},
+		{4, "ok", "warnings.0"},
+		{5, "error", "errors.0"},
+	} {
+		result := lookup(body, "report.results").([]interface{})[i]
+		if lookup(result, "row") != want.row || str(result, "result") != want.result || (want.detail != "" && str(result, want.detail) == "") {
+			t.Fatalf("unexpected result %d: %v", i, result)
+		}
+	}
+	if str(body, "report.results.0.status") != "in_progress" || str(body, "report.results.1.errors.0") != "title is required" ||
+		str(body, "report.results.2.warnings.0") != `Unknown status "Blocked" was mapped to todo` ||
+		str(body, "report.results.3.errors.0") != `deadline "next week" must use the YYYY-MM-DD format` {
+		t.Fatalf("unexpected row details: %v", body)
+	}
+
+	// Atomic (default) menolak seluruh file
+	rec = s.do(http.MethodPost, "/api/projects/import?name=Sprint", owner.Token, file, csvHeader)
+	expectStatus(t, rec, http.StatusUnprocessableEntity)
+	if lookup(decode(t, rec), "report.aborted") != true || s.countProjects(owner) != 0 {
+		t.Fatal("expected the atomic import to be aborted without creating a project")
+	}
+
+	// best_effort melewati baris yang tidak valid; status_map mengganti pemetaan status
+	rec = s.do(http.MethodPost, "/api/projects/import?name=Sprint&mode=best_effort&status_map=blocked:in_progress", owner.Token, file, csvHeader)
+	expectStatus(t, rec, http.StatusCreated)
+	body = decode(t, rec)
+	if lookup(body, "report.imported") != float64(2) || lookup(body, "report.results.2.warnings") != nil {
+		t.Fatalf("unexpected best effort report: %v", body)
+	}
+	tasks := s.projectTasks(owner, str(body, "project.id"))
+	if len(tasks) != 2 || str(tasks["Plan"], "status") != "in_progress" || str(tasks["Review"], "status") != "in_progress" ||
+		str(tasks["Review"], "deadline") != "2030-03-02T00:00:00Z" {
+		t.Fatalf("unexpected imported tasks: %v", tasks)
+	}
+
+	for _, tt := range []struct {
+		name    string
+		path    string
+		body    string
+		headers []header
+	}{
+		{"unknown format", "/api/projects/import?name=X", "title\nA\n", []header{{Name: "Content-Type", Value: "text/plain"}}},
+		{"missing title column", "/api/projects/import?name=X", "name\nA\n", []header{csvHeader}},
+		{"missing project name", "/api/projects/import", "title\nA\n", []header{csvHeader}},
+		{"invalid status map", "/api/projects/import?name=X&status_map=blocked:later", "title\nA\n", []header{csvHeader}},
+		{"invalid mode", "/api/projects/import?name=X&mode=sometimes", "title\nA\n", []header{csvHeader}},
+		{"malformed JSON", "/api/projects/import?format=json", `{"project":`, nil},
+		{"newer format", "/api/projects/import", `{"format_version": 2, "project": {"name": "X"}}`, nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			expectStatus(t, s.do(http.MethodPost, tt.path, owner.Token, tt.body, tt.headers...), http.StatusBadRequest)
+		})
+	}
+	expectStatus(t, s.do(http.MethodPost, "/api/projects/import?name=X", "", "title\nA\n", csvHeader), http.StatusUnauthorized)
+}
+
+func TestProjectImportLimits(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.API.ImportMaxRows = 2
+		cfg.API.ImportMaxBytes = 64
+	})
+	owner := s.register("owner")
+
+	expectStatus(t, s.do(http.MethodPost, "/api/projects/import?name=X", owner.Token, "title\nA\nB\n", csvHeader), http.StatusCreated)
+	expectStatus(t, s.do(http.MethodPost, "/api/projects/import?name=X", owner.Token, "title\nA\nB\nC\n", csvHeader), http.StatusBadRequest)
+
+	large := "title\n" + strings.Repeat("x", 64) + "\n"
+	expectStatus(t, s.do(http.MethodPost, "/api/projects/import?name=X", owner.Token, large, csvHeader), http.StatusRequestEntityTooLarge)
+	expectStatus(t, s.upload("/api/projects/import?name=X", owner.Token, "big.csv", large), http.StatusRequestEntityTooLarge)
+}
@@ -0,0 +1,160 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+)
+
+// Hub membagikan pesan dari Broker ke pelanggan lokal di instance ini. Untuk setiap topic hanya ada satu
+// langganan ke Broker, berapa pun jumlah client yang mendengarkan topic itu.
+type Hub struct {
+	broker Broker
+	buffer int
+
+	mu     sync.Mutex
+	topics map[string]*hubTopic
+	closed bool
+}
+
+type hubTopic struct {
+	cancel      context.CancelFunc
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription adalah langganan satu client ke satu topic. Pesan dibaca dari C; setelah C ditutup, Err
+// menjelaskan sebabnya.
+type Subscription struct {
+	C <-chan Message
+
+	hub   *Hub
+	topic string
+	ch    chan Message
+	once  sync.Once
+	err   error
+}
+
+// NewHub membuat Hub di atas broker. buffer adalah jumlah pesan yang boleh menumpuk per pelanggan sebelum
+// pelanggan tersebut dianggap lambat dan diputus (backpressure), supaya satu client lambat tidak menahan
+// client lain.
+func NewHub(broker Broker, buffer int) *Hub {
+	return &Hub{broker: broker, buffer: buffer, topics: map[string]*hubTopic{}}
+}
+
+// Publish meneruskan ke Broker.Publish.
+func (h *Hub) Publish(ctx context.Context, topic string, data []byte) (Message, error) {
+	return h.broker.Publish(ctx, topic, data)
+}
+
+// Since meneruskan ke Broker.Since.
+func (h *Hub) Since(ctx context.Context, topic string, afterID uint64) ([]Message, bool, error) {
+	return h.broker.Since(ctx, topic, afterID)
+}
+
+// Subscribe mendaftarkan pelanggan baru untuk topic. Panggil Close setelah selesai.
+func (h *Hub) Subscribe(ctx context.Context, topic string) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, ErrClosed
+	}
+
+	t, ok := h.topics[topic]
+	if !ok {
+		topicCtx, cancel := context.WithCancel(context.Background())
+		messages, err := h.broker.Subscribe(topicCtx, topic)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		t = &hubTopic{cancel: cancel, subscribers: map[*Subscription]struct{}{}}
+		h.topics[topic] = t
+		go h.fanOut(topic, t, messages)
+	}
+
+	sub := &Subscription{hub: h, topic: topic, ch: make(chan Message, h.buffer)}
+	sub.C = sub.ch
+	t.subscribers[sub] = struct{}{}
+	return sub, nil
+}
+
+// Close memutus semua pelanggan dengan ErrClosed dan menolak langganan baru. Dipanggil saat shutdown supaya
+// stream yang terbuka tidak menahan server berhenti.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for topic, t := range h.topics {
+		// Lepas dari topic juga, supaya fanOut yang sedang berjalan tidak mengirim ke channel yang sudah ditutup
+		for sub := range t.subscribers {
+			delete(t.subscribers, sub)
+			sub.end(ErrClosed)
+		}
+		t.cancel()
+		delete(h.topics, topic)
+	}
+}
+
+// fanOut meneruskan pesan dari Broker ke semua pelanggan topic tanpa pernah menunggu pelanggan yang lambat.
+func (h *Hub) fanOut(topic string, t *hubTopic, messages <-chan Message) {
+	for message := range messages {
+		h.mu.Lock()
+		for sub := range t.subscribers {
+			select {
+			case sub.ch <- message:
+			default:
+				h.removeLocked(sub, ErrSlowConsumer)
+			}
+		}
+		h.mu.Unlock()
+	}
+
+	// Channel Broker tertutup sementara topic masih dipakai: putus semua pelanggan agar client tersambung ulang
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.topics[topic] == t {
+		for sub := range t.subscribers {
+			sub.end(ErrBrokerLost)
+		}
+		delete(h.topics, topic)
+	}
+}
+
+// removeLocked melepas sub dari topic-nya dan menutup langganan Broker jika sub pelanggan terakhir.
+func (h *Hub) removeLocked(sub *Subscription, err error) {
+	t, ok := h.topics[sub.topic]
+	if !ok {
+		return
+	}
+	if _, ok := t.subscribers[sub]; !ok {
+		return
+	}
+	delete(t.subscribers, sub)
+	sub.end(err)
+	if len(t.subscribers) == 0 {
+		t.cancel()
+		delete(h.topics, sub.topic)
+	}
+}
+
+// Close mengakhiri langganan. Aman dipanggil lebih dari sekali.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.removeLocked(s, nil)
+}
+
+// Err mengembalikan sebab C ditutup: ErrSlowConsumer, ErrBrokerLost, ErrClosed, atau nil jika ditutup oleh Close.
+func (s *Subscription) Err() error {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.err
+}
+
+func (s *Subscription) end(err error) {
+	s.once.Do(func() {
+		s.err = err
+		close(s.ch)
+	})
+}
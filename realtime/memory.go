@@ -0,0 +1,89 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryBroker adalah Broker in-memory untuk development, test, dan instalasi satu instance.
+type MemoryBroker struct {
+	sendMu  sync.Mutex // Menjaga urutan pengiriman sama dengan urutan ID
+	mu      sync.Mutex
+	logSize int
+	topics  map[string]*memoryTopic
+}
+
+type memoryTopic struct {
+	seq         uint64
+	log         []Message
+	subscribers map[chan Message]context.Context
+}
+
+// NewMemoryBroker membuat MemoryBroker yang menyimpan paling banyak logSize pesan terakhir per topic.
+func NewMemoryBroker(logSize int) *MemoryBroker {
+	return &MemoryBroker{logSize: logSize, topics: map[string]*memoryTopic{}}
+}
+
+func (b *MemoryBroker) Publish(_ context.Context, topic string, data []byte) (Message, error) {
+	b.sendMu.Lock()
+	defer b.sendMu.Unlock()
+
+	b.mu.Lock()
+	t := b.topic(topic)
+	t.seq++
+	message := Message{ID: t.seq, Data: append([]byte(nil), data...)}
+	t.log = append(t.log, message)
+	if len(t.log) > b.logSize {
+		t.log = append([]Message(nil), t.log[len(t.log)-b.logSize:]...)
+	}
+	subscribers := make(map[chan Message]context.Context, len(t.subscribers))
+	for ch, ctx := range t.subscribers {
+		subscribers[ch] = ctx
+	}
+	b.mu.Unlock()
+
+	// Pelanggannya (Hub) tidak pernah memblok lama karena hanya meneruskan pesan tanpa menunggu client
+	deliver(subscribers, message)
+	return message, nil
+}
+
+func (b *MemoryBroker) Since(_ context.Context, topic string, afterID uint64) ([]Message, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t, ok := b.topics[topic]
+	if !ok {
+		return nil, afterID == 0, nil
+	}
+	messages, complete := sinceLog(t.log, afterID)
+	return messages, complete, nil
+}
+
+func (b *MemoryBroker) Subscribe(ctx context.Context, topic string) (<-chan Message, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Message, 16)
+	b.topic(topic).subscribers[ch] = ctx
+
+	go func() {
+		<-ctx.Done()
+		// sendMu memastikan tidak ada Publish yang sedang mengirim ke ch saat ch ditutup
+		b.sendMu.Lock()
+		defer b.sendMu.Unlock()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.topics[topic].subscribers, ch)
+		close(ch)
+	}()
+	return ch, nil
+}
+
+func (b *MemoryBroker) topic(name string) *memoryTopic {
+	t, ok := b.topics[name]
+	if !ok {
+		t = &memoryTopic{subscribers: map[chan Message]context.Context{}}
+		b.topics[name] = t
+	}
+	return t
+}
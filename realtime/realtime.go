@@ -0,0 +1,67 @@
+// Package realtime menyiarkan event ke client yang terhubung lama (Server-Sent Events). Broker adalah backend
+// pub/sub yang bisa diganti: in-memory untuk satu instance, atau Redis jika REDIS_ADDR diset sehingga event
+// dari satu instance sampai ke client di semua instance. Setiap topic juga punya log terbatas supaya client
+// yang tersambung ulang bisa melanjutkan dari event terakhir yang diterimanya.
+package realtime
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrClosed menandai langganan yang ditutup karena Hub dimatikan (shutdown).
+	ErrClosed = errors.New("realtime: hub closed")
+	// ErrSlowConsumer menandai langganan yang diputus karena tidak membaca pesan secepat pesan datang.
+	ErrSlowConsumer = errors.New("realtime: subscriber too slow")
+	// ErrBrokerLost menandai langganan yang terputus dari broker.
+	ErrBrokerLost = errors.New("realtime: broker subscription lost")
+)
+
+// Message adalah satu pesan di sebuah topic. ID naik satu per satu per topic, sehingga penerima bisa
+// mendeteksi pesan yang terlewat.
+type Message struct {
+	ID   uint64
+	Data []byte
+}
+
+// Broker adalah kontrak yang dipenuhi oleh semua backend pub/sub.
+type Broker interface {
+	// Publish menambahkan data ke log topic lalu menyiarkannya ke semua pelanggan topic di semua instance.
+	Publish(ctx context.Context, topic string, data []byte) (Message, error)
+	// Since mengembalikan pesan di log topic dengan ID lebih besar dari afterID, terlama lebih dulu.
+	// complete false berarti sebagian pesan sesudah afterID sudah terbuang dari log (atau afterID tidak dikenal).
+	Since(ctx context.Context, topic string, afterID uint64) (messages []Message, complete bool, err error)
+	// Subscribe mengirim setiap pesan baru di topic ke channel sampai ctx dibatalkan, lalu menutup channel.
+	Subscribe(ctx context.Context, topic string) (<-chan Message, error)
+}
+
+// sinceLog menerapkan aturan Since di atas log yang terurut berdasarkan ID.
+func sinceLog(log []Message, afterID uint64) ([]Message, bool) {
+	if len(log) == 0 {
+		return nil, true
+	}
+	oldest, newest := log[0].ID, log[len(log)-1].ID
+	// afterID di depan log berarti penomoran topic sudah dimulai ulang (misalnya log kedaluwarsa)
+	if afterID > newest {
+		return nil, false
+	}
+
+	messages := []Message{}
+	for _, message := range log {
+		if message.ID > afterID {
+			messages = append(messages, message)
+		}
+	}
+	return messages, oldest <= afterID+1
+}
+
+// deliver mengirim message ke setiap pelanggan, kecuali yang context-nya sudah dibatalkan.
+func deliver(subscribers map[chan Message]context.Context, message Message) {
+	for ch, ctx := range subscribers {
+		select {
+		case ch <- message:
+		case <-ctx.Done():
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package realtime
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix memisahkan key dan channel realtime dari key cache di database Redis yang sama.
+const redisKeyPrefix = "realtime:"
+
+// RedisBroker adalah Broker di atas Redis: log setiap topic disimpan sebagai list yang dipangkas ke logSize,
+// dan pesan baru disiarkan lewat PUBLISH ke semua instance. Setiap instance memakai satu koneksi SUBSCRIBE
+// yang channel-nya ditambah dan dilepas sesuai topic yang sedang punya pelanggan.
+type RedisBroker struct {
+	client  *redis.Client
+	logSize int
+	logTTL  time.Duration
+
+	sendMu      sync.Mutex // Dipegang route selama mengirim, supaya channel pelanggan tidak ditutup di tengahnya
+	mu          sync.Mutex
+	pubsub      *redis.PubSub
+	subscribers map[string]map[chan Message]context.Context // Per channel Redis
+}
+
+// NewRedisBroker membuat RedisBroker. Log topic disimpan paling banyak logSize pesan dan kedaluwarsa setelah
+// logTTL tanpa pesan baru.
+func NewRedisBroker(client *redis.Client, logSize int, logTTL time.Duration) *RedisBroker {
+	return &RedisBroker{
+		client:      client,
+		logSize:     logSize,
+		logTTL:      logTTL,
+		subscribers: map[string]map[chan Message]context.Context{},
+	}
+}
+
+// publishScript memberi nomor pesan, menambahkannya ke log, dan menyiarkannya secara atomik, sehingga urutan
+// di log dan di channel selalu sama dengan urutan ID.
+var publishScript = redis.NewScript(`
+local id = redis.call("INCR", KEYS[1])
+local message = id .. "\n" .. ARGV[1]
+redis.call("RPUSH", KEYS[2], message)
+redis.call("LTRIM", KEYS[2], -tonumber(ARGV[2]), -1)
+if tonumber(ARGV[3]) > 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[3])
+	redis.call("PEXPIRE", KEYS[2], ARGV[3])
+end
+redis.call("PUBLISH", ARGV[4], message)
+return id
+`)
+
+func (b *RedisBroker) Publish(ctx context.Context, topic string, data []byte) (Message, error) {
+	keys := []string{redisKeyPrefix + topic + ":seq", redisKeyPrefix + topic + ":log"}
+	id, err := publishScript.Run(ctx, b.client, keys, data, b.logSize, b.logTTL.Milliseconds(), redisChannel(topic)).Uint64()
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{ID: id, Data: data}, nil
+}
+
+func (b *RedisBroker) Since(ctx context.Context, topic string, afterID uint64) ([]Message, bool, error) {
+	raw, err := b.client.LRange(ctx, redisKeyPrefix+topic+":log", 0, -1).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if len(raw) == 0 {
+		return nil, afterID == 0, nil
+	}
+
+	log := make([]Message, 0, len(raw))
+	for _, encoded := range raw {
+		message, err := decodeRedisMessage(encoded)
+		if err != nil {
+			return nil, false, err
+		}
+		log = append(log, message)
+	}
+	messages, complete := sinceLog(log, afterID)
+	return messages, complete, nil
+}
+
+func (b *RedisBroker) Subscribe(ctx context.Context, topic string) (<-chan Message, error) {
+	channel := redisChannel(topic)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.pubsub == nil {
+		b.pubsub = b.client.Subscribe(context.Background())
+		go b.route(b.pubsub.Channel())
+	}
+	if len(b.subscribers[channel]) == 0 {
+		if err := b.pubsub.Subscribe(ctx, channel); err != nil {
+			return nil, err
+		}
+		b.subscribers[channel] = map[chan Message]context.Context{}
+	}
+
+	ch := make(chan Message, 16)
+	b.subscribers[channel][ch] = ctx
+
+	go func() {
+		<-ctx.Done()
+		b.sendMu.Lock()
+		defer b.sendMu.Unlock()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers[channel], ch)
+		close(ch)
+		if len(b.subscribers[channel]) == 0 {
+			delete(b.subscribers, channel)
+			if err := b.pubsub.Unsubscribe(context.Background(), channel); err != nil {
+				slog.Warn("failed to unsubscribe from realtime channel", "channel", channel, "error", err)
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// route meneruskan pesan dari koneksi SUBSCRIBE ke pelanggan channel-nya.
+func (b *RedisBroker) route(messages <-chan *redis.Message) {
+	for raw := range messages {
+		message, err := decodeRedisMessage(raw.Payload)
+		if err != nil {
+			slog.Warn("dropping malformed realtime message", "channel", raw.Channel, "error", err)
+			continue
+		}
+
+		b.sendMu.Lock()
+		b.mu.Lock()
+		subscribers := make(map[chan Message]context.Context, len(b.subscribers[raw.Channel]))
+		for ch, ctx := range b.subscribers[raw.Channel] {
+			subscribers[ch] = ctx
+		}
+		b.mu.Unlock()
+		deliver(subscribers, message)
+		b.sendMu.Unlock()
+	}
+}
+
+func redisChannel(topic string) string {
+	return redisKeyPrefix + topic
+}
+
+// decodeRedisMessage mem-parse pesan berformat "<id>\n<data>" yang ditulis publishScript.
+func decodeRedisMessage(encoded string) (Message, error) {
+	raw := []byte(encoded)
+	i := bytes.IndexByte(raw, '\n')
+	if i < 0 {
+		return Message{}, fmt.Errorf("realtime: malformed message %q", encoded)
+	}
+	id, err := strconv.ParseUint(string(raw[:i]), 10, 64)
+	if err != nil {
+		return Message{}, fmt.Errorf("realtime: malformed message id: %w", err)
+	}
+	return Message{ID: id, Data: raw[i+1:]}, nil
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"taskify/config"
+	"taskify/mail"
+	"taskify/repository"
+	"taskify/service"
+)
+
+// sentEmail adalah email yang ditulis backend file.
+type sentEmail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// newReminderServer membuat test server yang menulis email ke direktori sementara.
+func newReminderServer(t *testing.T) (*testServer, string) {
+	dir := t.TempDir()
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.Mail.Backend = "file"
+		cfg.Mail.Dir = dir
+	})
+	return s, dir
+}
+
+// sendReminders menjalankan satu putaran scheduler seolah waktu sekarang adalah now.
+func (s *testServer) sendReminders(now string) int {
+	s.t.Helper()
+
+	at, err := time.Parse(time.RFC3339, now)
+	if err != nil {
+		s.t.Fatalf("parse time: %v", err)
+	}
+	sent, err := s.app.reminders.SendDue(context.Background(), at)
+	if err != nil {
+		s.t.Fatalf("send reminders: %v", err)
+	}
+	return sent
+}
+
+// mailbox membaca semua email di dir sesuai urutan pengiriman.
+func mailbox(t *testing.T, dir string) []sentEmail {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read mail directory: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	var emails []sentEmail
+	for _, name := range names {
+		raw, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("read email: %v", err)
+		}
+		head, body, _ := strings.Cut(string(raw), "\r\n\r\n")
+		email := sentEmail{Body: body}
+		for _, line := range strings.Split(head, "\r\n") {
+			if value, ok := strings.CutPrefix(line, "To: "); ok {
+				email.To = value
+			}
+			if value, ok := strings.CutPrefix(line, "Subject: "); ok {
+				email.Subject = value
+			}
+		}
+		emails = append(emails, email)
+	}
+	return emails
+}
+
+func TestReminderSettings(t *testing.T) {
+	s := newTestServer(t)
+	user := s.register("planner")
+
+	rec := s.do(http.MethodGet, "/api/me/reminder-settings", user.Token, nil)
+	expectStatus(t, rec, http.StatusOK)
+	body := decode(t, rec)
+	if count(body, "settings.offsets_minutes") != 1 || lookup(body, "settings.offsets_minutes.0") != float64(1440) ||
+		lookup(body, "settings.overdue_alerts") != true || lookup(body, "settings.daily_digest") != false ||
+		str(body, "settings.timezone") != "UTC" {
+		t.Fatalf("unexpected default settings: %v", body)
+	}
+
+	for _, invalid := range []gin.H{
+		{"offsets_minutes": []int{0}},
+		{"offsets_minutes": []int{7*24*60 + 1}},
+		{"offsets_minutes": []int{1, 2, 3, 4, 5, 6}},
+		{"digest_hour": 24},
+		{"timezone": "Mars/Olympus"},
+	} {
+		expectStatus(t, s.do(http.MethodPut, "/api/me/reminder-settings", user.Token, invalid), http.StatusBadRequest)
+	}
+
+	rec = s.do(http.MethodPut, "/api/me/reminder-settings", user.Token, gin.H{
+		"offsets_minutes": []int{60, 1440, 60},
+		"daily_digest":    true,
+		"timezone":        "Asia/Jakarta",
+	})
+	expectStatus(t, rec, http.StatusOK)
+
+	// Perubahan parsial mempertahankan nilai yang tidak dikirim
+	expectStatus(t, s.do(http.MethodPut, "/api/me/reminder-settings", user.Token, gin.H{"digest_hour": 7}), http.StatusOK)
+	body = decode(t, s.do(http.MethodGet, "/api/me/reminder-settings", user.Token, nil))
+	if count(body, "settings.offsets_minutes") != 2 || lookup(body, "settings.offsets_minutes.0") != float64(1440) ||
+		lookup(body, "settings.daily_digest") != true || lookup(body, "settings.digest_hour") != float64(7) ||
+		str(body, "settings.timezone") != "Asia/Jakarta" {
+		t.Fatalf("unexpected settings after update: %v", body)
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/api/me/reminder-settings", "", nil), http.StatusUnauthorized)
+}
+
+func TestDeadlineRemindersAndOverdueAlerts(t *testing.T) {
+	s, dir := newReminderServer(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Launch")
+	s.createTask(owner, projectID, "Ship it", gin.H{"deadline": "2030-03-01"})
+	s.createTask(owner, projectID, "Finished", gin.H{"deadline": "2030-03-01", "status": "done"})
+	s.createTask(owner, projectID, "Someday", nil)
+
+	// Bawaan: pengingat 24 jam sebelum akhir tanggal deadline (UTC)
+	if sent := s.sendReminders("2030-02-28T23:00:00Z"); sent != 0 {
+		t.Fatalf("expected no reminder before the offset, sent %d", sent)
+	}
+	if sent := s.sendReminders("2030-03-01T01:00:00Z"); sent != 1 {
+		t.Fatalf("expected one reminder, sent %d", sent)
+	}
+	if sent := s.sendReminders("2030-03-01T02:00:00Z"); sent != 0 {
+		t.Fatalf("expected the reminder to be sent only once, sent %d", sent)
+	}
+
+	// Overdue dikirim sekali setelah tanggal deadline berakhir, dan hanya dalam 24 jam pertama
+	if sent := s.sendReminders("2030-03-02T01:00:00Z"); sent != 1 {
+		t.Fatalf("expected one overdue alert, sent %d", sent)
+	}
+	if sent := s.sendReminders("2030-03-02T05:00:00Z") + s.sendReminders("2030-03-05T00:00:00Z"); sent != 0 {
+		t.Fatalf("expected no further emails, sent %d", sent)
+	}
+
+	emails := mailbox(t, dir)
+	if len(emails) != 2 {
+		t.Fatalf("expected 2 emails, got %v", emails)
+	}
+	if emails[0].To != owner.Email || emails[0].Subject != `Reminder: "Ship it" is due 2030-03-01` || !strings.Contains(emails[0].Body, `project "Launch"`) {
+		t.Fatalf("unexpected reminder: %+v", emails[0])
+	}
+	if emails[1].Subject != `Overdue: "Ship it" was due 2030-03-01` {
+		t.Fatalf("unexpected overdue alert: %+v", emails[1])
+	}
+}
+
+func TestReminderOffsetsFollowUserTimezone(t *testing.T) {
+	s, dir := newReminderServer(t)
+	owner := s.register("owner")
+	expectStatus(t, s.do(http.MethodPut, "/api/me/reminder-settings", owner.Token, gin.H{
+		"offsets_minutes": []int{1440, 60},
+		"overdue_alerts":  false,
+		"timezone":        "Asia/Jakarta",
+	}), http.StatusOK)
+	projectID := s.createProject(owner, "Local")
+	s.createTask(owner, projectID, "Late start", gin.H{"deadline": "2030-03-01"})
+
+	// Deadline berakhir 2030-03-02 00:00 WIB (2030-03-01 17:00 UTC). Tiga puluh menit sebelumnya kedua offset
+	// sudah terlewati, tetapi hanya offset terdekat yang dikirim.
+	if sent := s.sendReminders("2030-03-01T16:30:00Z"); sent != 1 {
+		t.Fatalf("expected a single reminder, sent %d", sent)
+	}
+	if sent := s.sendReminders("2030-03-01T16:45:00Z") + s.sendReminders("2030-03-01T18:00:00Z"); sent != 0 {
+		t.Fatalf("expected no further emails with overdue alerts off, sent %d", sent)
+	}
+	if emails := mailbox(t, dir); len(emails) != 1 {
+		t.Fatalf("expected one email, got %v", emails)
+	}
+}
+
+func TestDailyDigest(t *testing.T) {
+	s, dir := newReminderServer(t)
+	owner := s.register("owner")
+	expectStatus(t, s.do(http.MethodPut, "/api/me/reminder-settings", owner.Token, gin.H{
+		"offsets_minutes": []int{},
+		"overdue_alerts":  false,
+		"daily_digest":    true,
+		"digest_hour":     8,
+	}), http.StatusOK)
+	projectID := s.createProject(owner, "Daily")
+	s.createTask(owner, projectID, "Today", gin.H{"deadline": "2030-03-01"})
+	s.createTask(owner, projectID, "Late", gin.H{"deadline": "2030-02-25"})
+	s.createTask(owner, projectID, "Wrapped", gin.H{"deadline": "2030-02-26", "status": "done"})
+	s.createTask(owner, projectID, "Later", gin.H{"deadline": "2030-03-10"})
+
+	if sent := s.sendReminders("2030-03-01T07:59:00Z"); sent != 0 {
+		t.Fatalf("expected no digest before the digest hour, sent %d", sent)
+	}
+	if sent := s.sendReminders("2030-03-01T08:30:00Z"); sent != 1 {
+		t.Fatalf("expected one digest, sent %d", sent)
+	}
+	if sent := s.sendReminders("2030-03-01T20:00:00Z"); sent != 0 {
+		t.Fatalf("expected one digest per day, sent %d", sent)
+	}
+	if sent := s.sendReminders("2030-03-02T08:00:00Z"); sent != 1 {
+		t.Fatalf("expected the next day's digest, sent %d", sent)
+	}
+
+	emails := mailbox(t, dir)
+	if len(emails) != 2 {
+		t.Fatalf("expected 2 digests, got %v", emails)
+	}
+	first := emails[0]
+	if first.Subject != "Taskify digest for 2030-03-01: 1 due today, 1 overdue" ||
+		!strings.Contains(first.Body, "- Today (Daily, todo)") || !strings.Contains(first.Body, "- Late (Daily, todo) due 2030-02-25") ||
+		strings.Contains(first.Body, "Wrapped") || strings.Contains(first.Body, "Later") {
+		t.Fatalf("unexpected digest: %+v", first)
+	}
+	if emails[1].Subject != "Taskify digest for 2030-03-02: 0 due today, 2 overdue" {
+		t.Fatalf("unexpected second digest: %+v", emails[1])
+	}
+}
+
+func TestRemindersAreSentOnceAcrossInstances(t *testing.T) {
+	s, dir := newReminderServer(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Shared")
+	for _, title := range []string{"One", "Two", "Three"} {
+		s.createTask(owner, projectID, title, gin.H{"deadline": "2030-03-01"})
+	}
+
+	// Beberapa scheduler di atas database yang sama, seperti beberapa instance di belakang load balancer
+	mailer, err := mail.NewFileMailer(dir, "Taskify <no-reply@taskify.local>")
+	if err != nil {
+		t.Fatalf("create mailer: %v", err)
+	}
+	now := time.Date(2030, 3, 1, 12, 0, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	total := 0
+	for i := 0; i < 4; i++ {
+		scheduler := service.NewReminderService(
+			repository.NewGormUserRepository(s.db), repository.NewGormTaskRepository(s.db),
+			repository.NewGormReminderRepository(s.db), mailer, config.Default().Reminder,
+		)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sent, err := scheduler.SendDue(context.Background(), now)
+			if err != nil {
+				t.Errorf("send reminders: %v", err)
+			}
+			mu.Lock()
+			total += sent
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if total != 3 || len(mailbox(t, dir)) != 3 {
+		t.Fatalf("expected exactly 3 reminders across instances, sent %d", total)
+	}
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// GormTransactor adalah Transactor untuk repository berbasis GORM.
+type GormTransactor struct {
+	db *gorm.DB
+}
+
+// NewGormTransactor membuat Transactor di atas koneksi db.
+func NewGormTransactor(db *gorm.DB) *GormTransactor {
+	return &GormTransactor{db: db}
+}
+
+func (t *GormTransactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// Jika ctx sudah membawa transaksi, GORM menjadikan transaksi bersarang ini sebagai SAVEPOINT
+	return conn(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn mengembalikan transaksi yang dibawa ctx, atau koneksi biasa jika tidak ada.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// translate mengubah error GORM menjadi error milik package ini supaya service tidak bergantung pada GORM.
+func translate(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicate
+	}
+	return err
+}
+
+// updateVersioned menjalankan UPDATE hanya jika versi di database masih sama dengan versi yang dibaca,
+// sekaligus menaikkan versi. Hasil false berarti baris sudah diubah request lain di antaranya.
+func updateVersioned(db *gorm.DB, model interface{}, id interface{}, version uint, updates Updates) (bool, error) {
+	columns := make(map[string]interface{}, len(updates)+1)
+	for column, value := range updates {
+		columns[column] = value
+	}
+	columns["version"] = gorm.Expr("version + 1")
+	result := db.Model(model).Where("id = ? AND version = ?", id, version).Updates(columns)
+	return result.RowsAffected > 0, translate(result.Error)
+}
+
+// deleteVersioned menghapus baris hanya jika versinya belum berubah sejak dibaca.
+func deleteVersioned(db *gorm.DB, model interface{}, id interface{}, version uint) (bool, error) {
+	result := db.Where("id = ? AND version = ?", id, version).Delete(model)
+	return result.RowsAffected > 0, translate(result.Error)
+}
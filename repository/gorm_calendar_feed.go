@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"taskify/models"
+)
+
+// GormCalendarFeedRepository adalah CalendarFeedRepository berbasis GORM.
+type GormCalendarFeedRepository struct {
+	db *gorm.DB
+}
+
+// NewGormCalendarFeedRepository membuat CalendarFeedRepository di atas koneksi db.
+func NewGormCalendarFeedRepository(db *gorm.DB) *GormCalendarFeedRepository {
+	return &GormCalendarFeedRepository{db: db}
+}
+
+func (r *GormCalendarFeedRepository) Save(ctx context.Context, feed *models.CalendarFeed) error {
+	if feed.ID == uuid.Nil {
+		feed.ID = uuid.New()
+	}
+	err := conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "project_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token_hash", "created_at", "last_accessed_at"}),
+	}).Create(feed).Error
+	if err != nil {
+		return translate(err)
+	}
+	// Saat feed lama ditimpa, ID yang tersimpan tetap ID lama
+	stored, err := r.Find(ctx, feed.UserID, feed.ProjectID)
+	if err != nil {
+		return err
+	}
+	*feed = stored
+	return nil
+}
+
+func (r *GormCalendarFeedRepository) Find(ctx context.Context, userID, projectID uuid.UUID) (models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := conn(ctx, r.db).Where("user_id = ? AND project_id = ?", userID, projectID).First(&feed).Error
+	return feed, translate(err)
+}
+
+func (r *GormCalendarFeedRepository) FindByTokenHash(ctx context.Context, tokenHash string) (models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := conn(ctx, r.db).Where("token_hash = ?", tokenHash).First(&feed).Error
+	return feed, translate(err)
+}
+
+func (r *GormCalendarFeedRepository) Delete(ctx context.Context, userID, projectID uuid.UUID) (bool, error) {
+	result := conn(ctx, r.db).Where("user_id = ? AND project_id = ?", userID, projectID).Delete(&models.CalendarFeed{})
+	return result.RowsAffected > 0, translate(result.Error)
+}
+
+func (r *GormCalendarFeedRepository) DeleteByProject(ctx context.Context, projectID uuid.UUID) error {
+	return translate(conn(ctx, r.db).Where("project_id = ?", projectID).Delete(&models.CalendarFeed{}).Error)
+}
+
+func (r *GormCalendarFeedRepository) Touch(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return translate(conn(ctx, r.db).Model(&models.CalendarFeed{}).Where("id = ?", id).Update("last_accessed_at", at).Error)
+}
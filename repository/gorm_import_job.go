@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"taskify/models"
+)
+
+// GormImportJobRepository adalah ImportJobRepository berbasis GORM.
+type GormImportJobRepository struct {
+	db *gorm.DB
+}
+
+// NewGormImportJobRepository membuat ImportJobRepository di atas koneksi db.
+func NewGormImportJobRepository(db *gorm.DB) *GormImportJobRepository {
+	return &GormImportJobRepository{db: db}
+}
+
+func (r *GormImportJobRepository) Create(ctx context.Context, job *models.ImportJob) error {
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+	return translate(conn(ctx, r.db).Create(job).Error)
+}
+
+func (r *GormImportJobRepository) Find(ctx context.Context, id uuid.UUID) (models.ImportJob, error) {
+	var job models.ImportJob
+	err := conn(ctx, r.db).Omit("data").Where("id = ?", id).First(&job).Error
+	return job, translate(err)
+}
+
+func (r *GormImportJobRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit int) ([]models.ImportJob, error) {
+	var jobs []models.ImportJob
+	err := conn(ctx, r.db).Omit("data", "report").Where("user_id = ?", userID).
+		Order("created_at DESC, id").Limit(limit).Find(&jobs).Error
+	return jobs, translate(err)
+}
+
+func (r *GormImportJobRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]models.ImportJob, error) {
+	var jobs []models.ImportJob
+	err := dueImportJobs(conn(ctx, r.db), now).Omit("data", "report").Order("created_at, id").Limit(limit).Find(&jobs).Error
+	return jobs, translate(err)
+}
+
+func (r *GormImportJobRepository) Claim(ctx context.Context, id uuid.UUID, now, leaseUntil time.Time) (bool, error) {
+	result := dueImportJobs(conn(ctx, r.db).Model(&models.ImportJob{}), now).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":      models.ImportRunning,
+		"lease_until": leaseUntil.UTC(),
+		"attempts":    gorm.Expr("attempts + 1"),
+		"started_at":  now.UTC(),
+	})
+	return result.RowsAffected > 0, translate(result.Error)
+}
+
+func (r *GormImportJobRepository) LoadData(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	var job models.ImportJob
+	err := conn(ctx, r.db).Select("data").Where("id = ?", id).First(&job).Error
+	return job.Data, translate(err)
+}
+
+func (r *GormImportJobRepository) Update(ctx context.Context, id uuid.UUID, updates Updates) error {
+	return translate(conn(ctx, r.db).Model(&models.ImportJob{}).Where("id = ?", id).Updates(map[string]interface{}(updates)).Error)
+}
+
+func (r *GormImportJobRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) error {
+	return translate(conn(ctx, r.db).Where("finished_at < ?", before.UTC()).Delete(&models.ImportJob{}).Error)
+}
+
+// dueImportJobs membatasi query ke job yang menunggu diproses pada now. Waktu dibandingkan dalam UTC karena
+// SQLite membandingkannya sebagai teks.
+func dueImportJobs(db *gorm.DB, now time.Time) *gorm.DB {
+	return db.Where("status = ? OR (status = ? AND lease_until <= ?)", models.ImportQueued, models.ImportRunning, now.UTC())
+}
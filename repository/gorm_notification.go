@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"taskify/models"
+)
+
+// GormNotificationRepository adalah NotificationRepository berbasis GORM.
+type GormNotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewGormNotificationRepository membuat NotificationRepository di atas koneksi db.
+func NewGormNotificationRepository(db *gorm.DB) *GormNotificationRepository {
+	return &GormNotificationRepository{db: db}
+}
+
+func (r *GormNotificationRepository) Create(ctx context.Context, notifications ...*models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return translate(conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(notifications).Error)
+}
+
+func (r *GormNotificationRepository) FindByID(ctx context.Context, id uuid.UUID) (models.Notification, error) {
+	var notification models.Notification
+	err := conn(ctx, r.db).Where("id = ?", id).First(&notification).Error
+	return notification, translate(err)
+}
+
+func (r *GormNotificationRepository) ListByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, after *NotificationCursor, limit int) ([]models.Notification, error) {
+	query := conn(ctx, r.db).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if after != nil {
+		query = query.Where("created_at < ? OR (created_at = ? AND id < ?)", after.CreatedAt, after.CreatedAt, after.ID)
+	}
+
+	var notifications []models.Notification
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&notifications).Error
+	return notifications, translate(err)
+}
+
+func (r *GormNotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, translate(err)
+}
+
+func (r *GormNotificationRepository) MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, at time.Time) (int64, error) {
+	query := conn(ctx, r.db).Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	result := query.Update("read_at", at)
+	return result.RowsAffected, translate(result.Error)
+}
+
+func (r *GormNotificationRepository) AddWatcher(ctx context.Context, watcher *models.TaskWatcher) error {
+	return translate(conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(watcher).Error)
+}
+
+func (r *GormNotificationRepository) RemoveWatcher(ctx context.Context, taskID, userID uuid.UUID) error {
+	return translate(conn(ctx, r.db).Where("task_id = ? AND user_id = ?", taskID, userID).Delete(&models.TaskWatcher{}).Error)
+}
+
+func (r *GormNotificationRepository) ListWatchers(ctx context.Context, taskID uuid.UUID) ([]models.TaskWatcher, error) {
+	var watchers []models.TaskWatcher
+	err := conn(ctx, r.db).Where("task_id = ?", taskID).Order("created_at, user_id").Find(&watchers).Error
+	return watchers, translate(err)
+}
+
+func (r *GormNotificationRepository) DeleteWatchers(ctx context.Context, taskIDs ...uuid.UUID) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	return translate(conn(ctx, r.db).Where("task_id IN ?", taskIDs).Delete(&models.TaskWatcher{}).Error)
+}
+
+func (r *GormNotificationRepository) DeleteOrphanWatchers(ctx context.Context) error {
+	db := conn(ctx, r.db)
+	tasks := db.Model(&models.Task{}).Select("id")
+	return translate(db.Where("task_id NOT IN (?)", tasks).Delete(&models.TaskWatcher{}).Error)
+}
+
+func (r *GormNotificationRepository) ListPreferences(ctx context.Context, userID uuid.UUID) ([]models.NotificationPreference, error) {
+	var preferences []models.NotificationPreference
+	err := conn(ctx, r.db).Where("user_id = ?", userID).Order("event_type").Find(&preferences).Error
+	return preferences, translate(err)
+}
+
+func (r *GormNotificationRepository) SavePreferences(ctx context.Context, preferences ...*models.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+	return translate(conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "event_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(preferences).Error)
+}
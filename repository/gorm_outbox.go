@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"taskify/models"
+)
+
+// GormOutboxRepository adalah OutboxRepository berbasis GORM.
+type GormOutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewGormOutboxRepository membuat OutboxRepository di atas koneksi db.
+func NewGormOutboxRepository(db *gorm.DB) *GormOutboxRepository {
+	return &GormOutboxRepository{db: db}
+}
+
+func (r *GormOutboxRepository) Add(ctx context.Context, event *models.OutboxEvent) error {
+	return translate(conn(ctx, r.db).Create(event).Error)
+}
+
+func (r *GormOutboxRepository) FindByID(ctx context.Context, id uuid.UUID) (models.OutboxEvent, error) {
+	var event models.OutboxEvent
+	err := conn(ctx, r.db).Where("id = ?", id).First(&event).Error
+	return event, translate(err)
+}
+
+func (r *GormOutboxRepository) FindLatest(ctx context.Context, projectID uuid.UUID, eventType string) (models.OutboxEvent, error) {
+	var event models.OutboxEvent
+	err := conn(ctx, r.db).Where("project_id = ? AND type = ?", projectID, eventType).Order("created_at DESC").First(&event).Error
+	return event, translate(err)
+}
+
+func (r *GormOutboxRepository) ListPending(ctx context.Context, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	err := conn(ctx, r.db).Where("processed_at IS NULL AND dead_at IS NULL").Order("created_at, id").Limit(limit).Find(&events).Error
+	return events, translate(err)
+}
+
+func (r *GormOutboxRepository) MarkProcessed(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	// Kondisi processed_at IS NULL membuat dua instance yang membaca event yang sama tidak memprosesnya dua kali
+	result := conn(ctx, r.db).Model(&models.OutboxEvent{}).Where("id = ? AND processed_at IS NULL", id).Update("processed_at", at)
+	return result.RowsAffected > 0, translate(result.Error)
+}
+
+func (r *GormOutboxRepository) RecordFailure(ctx context.Context, id uuid.UUID, message string, maxAttempts int, at time.Time) (bool, error) {
+	db := conn(ctx, r.db)
+	pending := db.Model(&models.OutboxEvent{}).Where("id = ? AND processed_at IS NULL AND dead_at IS NULL", id)
+	err := pending.Updates(map[string]any{"attempts": gorm.Expr("attempts + 1"), "last_error": message}).Error
+	if err != nil {
+		return false, translate(err)
+	}
+	// Langkah terpisah karena urutan evaluasi SET berbeda antar database
+	result := db.Model(&models.OutboxEvent{}).
+		Where("id = ? AND processed_at IS NULL AND dead_at IS NULL AND attempts >= ?", id, maxAttempts).
+		Update("dead_at", at)
+	return result.RowsAffected > 0, translate(result.Error)
+}
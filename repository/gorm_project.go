@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"taskify/models"
+)
+
+// GormProjectRepository adalah ProjectRepository berbasis GORM.
+type GormProjectRepository struct {
+	db *gorm.DB
+}
+
+// NewGormProjectRepository membuat ProjectRepository di atas koneksi db.
+func NewGormProjectRepository(db *gorm.DB) *GormProjectRepository {
+	return &GormProjectRepository{db: db}
+}
+
+func (r *GormProjectRepository) Create(ctx context.Context, project *models.Project) error {
+	return translate(conn(ctx, r.db).Omit("User").Create(project).Error)
+}
+
+func (r *GormProjectRepository) FindByID(ctx context.Context, id uuid.UUID) (models.Project, error) {
+	var project models.Project
+	// PRELOAD USER: Agar detail User muncul di JSON respons
+	err := conn(ctx, r.db).Preload("User").Where("id = ?", id).First(&project).Error
+	return project, translate(err)
+}
+
+func (r *GormProjectRepository) ListByUser(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]models.Project, error) {
+	var projects []models.Project
+	query := conn(ctx, r.db).Preload("User").
+		Where("created_by_id = ? OR id IN (?)", userID, memberProjectIDs(conn(ctx, r.db), userID))
+	if !includeArchived {
+		query = query.Where("archived = ?", false)
+	}
+	err := query.Order("name").Find(&projects).Error
+	return projects, translate(err)
+}
+
+func (r *GormProjectRepository) Update(ctx context.Context, id uuid.UUID, version uint, updates Updates) (bool, error) {
+	return updateVersioned(conn(ctx, r.db), &models.Project{}, id, version, updates)
+}
+
+func (r *GormProjectRepository) Delete(ctx context.Context, id uuid.UUID, version uint) (bool, error) {
+	return deleteVersioned(conn(ctx, r.db), &models.Project{}, id, version)
+}
+
+func (r *GormProjectRepository) AddMember(ctx context.Context, member *models.ProjectMember) error {
+	return translate(conn(ctx, r.db).Omit("User").Create(member).Error)
+}
+
+// memberProjectIDs adalah subquery ID proyek tempat userID menjadi anggota.
+func memberProjectIDs(db *gorm.DB, userID uuid.UUID) *gorm.DB {
+	return db.Model(&models.ProjectMember{}).Select("project_id").Where("user_id = ?", userID)
+}
+
+func (r *GormProjectRepository) ListMembers(ctx context.Context, projectID uuid.UUID) ([]models.ProjectMember, error) {
+	var members []models.ProjectMember
+	err := conn(ctx, r.db).Preload("User").Where("project_id = ?", projectID).Order("created_at, user_id").Find(&members).Error
+	return members, translate(err)
+}
+
+func (r *GormProjectRepository) IsMember(ctx context.Context, projectID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&models.ProjectMember{}).Where("project_id = ? AND user_id = ?", projectID, userID).Count(&count).Error
+	return count > 0, translate(err)
+}
+
+func (r *GormProjectRepository) RemoveMember(ctx context.Context, projectID, userID uuid.UUID) (bool, error) {
+	result := conn(ctx, r.db).Where("project_id = ? AND user_id = ?", projectID, userID).Delete(&models.ProjectMember{})
+	return result.RowsAffected > 0, translate(result.Error)
+}
+
+func (r *GormProjectRepository) DeleteMembers(ctx context.Context, projectID uuid.UUID) error {
+	return translate(conn(ctx, r.db).Where("project_id = ?", projectID).Delete(&models.ProjectMember{}).Error)
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"taskify/models"
+)
+
+// GormReminderRepository adalah ReminderRepository berbasis GORM.
+type GormReminderRepository struct {
+	db *gorm.DB
+}
+
+// NewGormReminderRepository membuat ReminderRepository di atas koneksi db.
+func NewGormReminderRepository(db *gorm.DB) *GormReminderRepository {
+	return &GormReminderRepository{db: db}
+}
+
+func (r *GormReminderRepository) FindSetting(ctx context.Context, userID uuid.UUID) (models.ReminderSetting, error) {
+	var setting models.ReminderSetting
+	err := conn(ctx, r.db).Where("user_id = ?", userID).First(&setting).Error
+	return setting, translate(err)
+}
+
+func (r *GormReminderRepository) SaveSetting(ctx context.Context, setting *models.ReminderSetting) error {
+	return translate(conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"offsets", "overdue_alerts", "daily_digest", "digest_hour", "timezone", "updated_at"}),
+	}).Create(setting).Error)
+}
+
+func (r *GormReminderRepository) ListDigestSettings(ctx context.Context) ([]models.ReminderSetting, error) {
+	var settings []models.ReminderSetting
+	err := conn(ctx, r.db).Where("daily_digest = ?", true).Order("user_id").Find(&settings).Error
+	return settings, translate(err)
+}
+
+func (r *GormReminderRepository) Claim(ctx context.Context, reminder *models.SentReminder) (bool, error) {
+	result := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(reminder)
+	return result.RowsAffected == 1, translate(result.Error)
+}
+
+func (r *GormReminderRepository) Release(ctx context.Context, key string) error {
+	// Kondisi struct supaya kolom key (kata kunci di MySQL) di-quote sesuai dialek
+	return translate(conn(ctx, r.db).Where(&models.SentReminder{Key: key}).Delete(&models.SentReminder{}).Error)
+}
+
+func (r *GormReminderRepository) DeleteSentBefore(ctx context.Context, before time.Time) error {
+	return translate(conn(ctx, r.db).Where("sent_at < ?", before).Delete(&models.SentReminder{}).Error)
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"taskify/models"
+)
+
+// GormTaskRepository adalah TaskRepository berbasis GORM.
+type GormTaskRepository struct {
+	db *gorm.DB
+}
+
+// NewGormTaskRepository membuat TaskRepository di atas koneksi db.
+func NewGormTaskRepository(db *gorm.DB) *GormTaskRepository {
+	return &GormTaskRepository{db: db}
+}
+
+func (r *GormTaskRepository) Create(ctx context.Context, tasks ...*models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	return translate(conn(ctx, r.db).Omit("Project").Create(tasks).Error)
+}
+
+func (r *GormTaskRepository) FindByID(ctx context.Context, id uuid.UUID) (models.Task, error) {
+	var task models.Task
+	// PRELOAD PROJECT DAN USER: Agar detail Project dan User muncul di JSON respons
+	err := conn(ctx, r.db).Preload("Project.User").Where("id = ?", id).First(&task).Error
+	return task, translate(err)
+}
+
+func (r *GormTaskRepository) FindInProject(ctx context.Context, projectID, taskID uuid.UUID) (models.Task, error) {
+	var task models.Task
+	err := conn(ctx, r.db).Preload("Project.User").Where("id = ? AND project_id = ?", taskID, projectID).First(&task).Error
+	return task, translate(err)
+}
+
+func (r *GormTaskRepository) ListByProject(ctx context.Context, projectID uuid.UUID) ([]models.Task, error) {
+	var tasks []models.Task
+	err := conn(ctx, r.db).Preload("Project.User").Where("project_id = ?", projectID).Order("title").Find(&tasks).Error
+	return tasks, translate(err)
+}
+
+func (r *GormTaskRepository) Update(ctx context.Context, id uuid.UUID, version uint, updates Updates) (bool, error) {
+	return updateVersioned(conn(ctx, r.db), &models.Task{}, id, version, updates)
+}
+
+func (r *GormTaskRepository) Delete(ctx context.Context, id uuid.UUID, version uint) (bool, error) {
+	return deleteVersioned(conn(ctx, r.db), &models.Task{}, id, version)
+}
+
+func (r *GormTaskRepository) DeleteByProject(ctx context.Context, projectID uuid.UUID) error {
+	return translate(conn(ctx, r.db).Where("project_id = ?", projectID).Delete(&models.Task{}).Error)
+}
+
+func (r *GormTaskRepository) CountByStatus(ctx context.Context) (map[models.TaskStatus]int64, error) {
+	var rows []struct {
+		Status models.TaskStatus
+		Count  int64
+	}
+	err := conn(ctx, r.db).Model(&models.Task{}).Select("status, COUNT(*) AS count").Group("status").Scan(&rows).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+
+	counts := make(map[models.TaskStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *GormTaskRepository) ListByDeadline(ctx context.Context, filter DeadlineFilter) ([]models.Task, error) {
+	query := conn(ctx, r.db).Preload("Project.User").
+		Joins("JOIN projects ON projects.id = tasks.project_id").
+		Where("tasks.deadline IS NOT NULL AND projects.archived = ?", false)
+	if filter.OwnerID != uuid.Nil {
+		query = query.Where("projects.created_by_id = ?", filter.OwnerID)
+	}
+	if filter.UserID != uuid.Nil {
+		query = query.Where("projects.created_by_id = ? OR projects.id IN (?)", filter.UserID, memberProjectIDs(conn(ctx, r.db), filter.UserID))
+	}
+	if !filter.From.IsZero() {
+		// Deadline disimpan dalam UTC; SQLite membandingkannya sebagai teks, jadi batasnya harus UTC juga
+		query = query.Where("tasks.deadline >= ?", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("tasks.deadline < ?", filter.To.UTC())
+	}
+	if filter.OpenOnly {
+		query = query.Where("tasks.status <> ?", models.Done)
+	}
+
+	var tasks []models.Task
+	err := query.Order("tasks.deadline, tasks.title").Find(&tasks).Error
+	return tasks, translate(err)
+}
+
+func (r *GormTaskRepository) AddHistory(ctx context.Context, history *models.TaskHistory) error {
+	return translate(conn(ctx, r.db).Create(history).Error)
+}
+
+func (r *GormTaskRepository) ListHistory(ctx context.Context, taskID uuid.UUID) ([]models.TaskHistory, error) {
+	var history []models.TaskHistory
+	err := conn(ctx, r.db).Where("task_id = ?", taskID).Order("created_at DESC").Find(&history).Error
+	return history, translate(err)
+}
+
+func (r *GormTaskRepository) DeleteHistory(ctx context.Context, taskIDs ...uuid.UUID) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	return translate(conn(ctx, r.db).Where("task_id IN ?", taskIDs).Delete(&models.TaskHistory{}).Error)
+}
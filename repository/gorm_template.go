@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"taskify/models"
+)
+
+// GormTemplateRepository adalah TemplateRepository berbasis GORM.
+type GormTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewGormTemplateRepository membuat TemplateRepository di atas koneksi db.
+func NewGormTemplateRepository(db *gorm.DB) *GormTemplateRepository {
+	return &GormTemplateRepository{db: db}
+}
+
+func (r *GormTemplateRepository) Create(ctx context.Context, template *models.ProjectTemplate) error {
+	// Create dengan association menyimpan template dan tugas-tugasnya dalam satu transaksi
+	return translate(conn(ctx, r.db).Create(template).Error)
+}
+
+func (r *GormTemplateRepository) FindByID(ctx context.Context, id uuid.UUID) (models.ProjectTemplate, error) {
+	var template models.ProjectTemplate
+	err := conn(ctx, r.db).Preload("Tasks", orderTemplateTasks).Where("id = ?", id).First(&template).Error
+	return template, translate(err)
+}
+
+func (r *GormTemplateRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]models.ProjectTemplate, error) {
+	var templates []models.ProjectTemplate
+	err := conn(ctx, r.db).Preload("Tasks", orderTemplateTasks).Where("created_by_id = ?", ownerID).Order("name").Find(&templates).Error
+	return templates, translate(err)
+}
+
+func (r *GormTemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", id).Delete(&models.TemplateTask{}).Error; err != nil {
+			return translate(err)
+		}
+		return translate(tx.Delete(&models.ProjectTemplate{}, "id = ?", id).Error)
+	})
+}
+
+// orderTemplateTasks menjaga urutan tugas template sesuai urutan saat disimpan.
+func orderTemplateTasks(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
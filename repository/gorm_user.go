@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"taskify/models"
+)
+
+// GormUserRepository adalah UserRepository berbasis GORM.
+type GormUserRepository struct {
+	db *gorm.DB
+}
+
+// NewGormUserRepository membuat UserRepository di atas koneksi db.
+func NewGormUserRepository(db *gorm.DB) *GormUserRepository {
+	return &GormUserRepository{db: db}
+}
+
+func (r *GormUserRepository) Create(ctx context.Context, user *models.User) error {
+	return translate(conn(ctx, r.db).Create(user).Error)
+}
+
+func (r *GormUserRepository) FindByID(ctx context.Context, id uuid.UUID) (models.User, error) {
+	var user models.User
+	err := conn(ctx, r.db).Where("id = ?", id).First(&user).Error
+	return user, translate(err)
+}
+
+func (r *GormUserRepository) FindByEmail(ctx context.Context, email string) (models.User, error) {
+	var user models.User
+	err := conn(ctx, r.db).Where("email = ?", email).First(&user).Error
+	return user, translate(err)
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"taskify/models"
+)
+
+// GormWebhookRepository adalah WebhookRepository berbasis GORM.
+type GormWebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewGormWebhookRepository membuat WebhookRepository di atas koneksi db.
+func NewGormWebhookRepository(db *gorm.DB) *GormWebhookRepository {
+	return &GormWebhookRepository{db: db}
+}
+
+func (r *GormWebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	return translate(conn(ctx, r.db).Create(webhook).Error)
+}
+
+func (r *GormWebhookRepository) FindByID(ctx context.Context, id uuid.UUID) (models.Webhook, error) {
+	var webhook models.Webhook
+	err := conn(ctx, r.db).Where("id = ?", id).First(&webhook).Error
+	return webhook, translate(err)
+}
+
+func (r *GormWebhookRepository) ListByProject(ctx context.Context, projectID uuid.UUID) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	err := conn(ctx, r.db).Where("project_id = ?", projectID).Order("created_at, id").Find(&webhooks).Error
+	return webhooks, translate(err)
+}
+
+func (r *GormWebhookRepository) Update(ctx context.Context, id uuid.UUID, updates Updates) error {
+	return translate(conn(ctx, r.db).Model(&models.Webhook{}).Where("id = ?", id).Updates(map[string]interface{}(updates)).Error)
+}
+
+func (r *GormWebhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		deliveries := tx.Model(&models.WebhookDelivery{}).Select("id").Where("webhook_id = ?", id)
+		if err := tx.Where("delivery_id IN (?)", deliveries).Delete(&models.WebhookDeliveryAttempt{}).Error; err != nil {
+			return translate(err)
+		}
+		if err := tx.Where("webhook_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return translate(err)
+		}
+		return translate(tx.Delete(&models.Webhook{}, "id = ?", id).Error)
+	})
+}
+
+func (r *GormWebhookRepository) DeleteByProject(ctx context.Context, projectID uuid.UUID) error {
+	return translate(conn(ctx, r.db).Where("project_id = ?", projectID).Delete(&models.Webhook{}).Error)
+}
+
+func (r *GormWebhookRepository) CreateDeliveries(ctx context.Context, deliveries ...*models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return translate(conn(ctx, r.db).Omit("Log").Create(deliveries).Error)
+}
+
+func (r *GormWebhookRepository) FindDelivery(ctx context.Context, id uuid.UUID) (models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := conn(ctx, r.db).Preload("Log", orderAttempts).Where("id = ?", id).First(&delivery).Error
+	return delivery, translate(err)
+}
+
+func (r *GormWebhookRepository) ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := conn(ctx, r.db).Preload("Log", orderAttempts).Where("webhook_id = ?", webhookID).
+		Order("created_at DESC, id").Limit(limit).Find(&deliveries).Error
+	return deliveries, translate(err)
+}
+
+func (r *GormWebhookRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := conn(ctx, r.db).Where("status = ? AND next_attempt_at <= ?", models.DeliveryPending, now).
+		Order("next_attempt_at, id").Limit(limit).Find(&deliveries).Error
+	return deliveries, translate(err)
+}
+
+func (r *GormWebhookRepository) ClaimDelivery(ctx context.Context, id uuid.UUID, now, leaseUntil time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", id, models.DeliveryPending, now).
+		Update("next_attempt_at", leaseUntil)
+	return result.RowsAffected > 0, translate(result.Error)
+}
+
+func (r *GormWebhookRepository) UpdateDelivery(ctx context.Context, id uuid.UUID, leaseUntil time.Time, updates Updates) (bool, error) {
+	result := conn(ctx, r.db).Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", id, models.DeliveryPending, leaseUntil).
+		Updates(map[string]interface{}(updates))
+	return result.RowsAffected > 0, translate(result.Error)
+}
+
+func (r *GormWebhookRepository) AddAttempt(ctx context.Context, attempt *models.WebhookDeliveryAttempt) error {
+	return translate(conn(ctx, r.db).Create(attempt).Error)
+}
+
+// orderAttempts mengurutkan log percobaan dari yang pertama.
+func orderAttempts(db *gorm.DB) *gorm.DB {
+	return db.Order("attempt")
+}
@@ -0,0 +1,697 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/models"
+)
+
+// Memory adalah implementasi in-memory dari semua repository dan Transactor, untuk menguji service dan
+// handler tanpa database. Transaction menyalin seluruh state dan mengembalikannya jika fn gagal, jadi
+// Memory tidak ditujukan untuk transaksi yang berjalan bersamaan.
+type Memory struct {
+	mu    sync.Mutex
+	state memoryState
+}
+
+type memoryState struct {
+	users      map[uuid.UUID]models.User
+	projects   map[uuid.UUID]models.Project
+	members    map[memberKey]models.ProjectMember
+	tasks      map[uuid.UUID]models.Task
+	history    []models.TaskHistory
+	templates  map[uuid.UUID]models.ProjectTemplate
+	outbox     []models.OutboxEvent
+	webhooks   map[uuid.UUID]models.Webhook
+	deliveries map[uuid.UUID]models.WebhookDelivery
+	attempts   []models.WebhookDeliveryAttempt
+
+	notifications map[uuid.UUID]models.Notification
+	watchers      map[watcherKey]models.TaskWatcher
+	preferences   map[preferenceKey]models.NotificationPreference
+
+	reminderSettings map[uuid.UUID]models.ReminderSetting
+	sentReminders    map[string]models.SentReminder
+	calendarFeeds    map[calendarFeedKey]models.CalendarFeed
+	importJobs       map[uuid.UUID]models.ImportJob
+}
+
+// NewMemory membuat backend in-memory yang kosong.
+func NewMemory() *Memory {
+	return &Memory{state: memoryState{
+		users:      map[uuid.UUID]models.User{},
+		projects:   map[uuid.UUID]models.Project{},
+		members:    map[memberKey]models.ProjectMember{},
+		tasks:      map[uuid.UUID]models.Task{},
+		templates:  map[uuid.UUID]models.ProjectTemplate{},
+		webhooks:   map[uuid.UUID]models.Webhook{},
+		deliveries: map[uuid.UUID]models.WebhookDelivery{},
+
+		notifications: map[uuid.UUID]models.Notification{},
+		watchers:      map[watcherKey]models.TaskWatcher{},
+		preferences:   map[preferenceKey]models.NotificationPreference{},
+
+		reminderSettings: map[uuid.UUID]models.ReminderSetting{},
+		sentReminders:    map[string]models.SentReminder{},
+		calendarFeeds:    map[calendarFeedKey]models.CalendarFeed{},
+		importJobs:       map[uuid.UUID]models.ImportJob{},
+	}}
+}
+
+// Users mengembalikan UserRepository di atas state ini.
+func (m *Memory) Users() UserRepository { return memoryUsers{m} }
+
+// Projects mengembalikan ProjectRepository di atas state ini.
+func (m *Memory) Projects() ProjectRepository { return memoryProjects{m} }
+
+// Tasks mengembalikan TaskRepository di atas state ini.
+func (m *Memory) Tasks() TaskRepository { return memoryTasks{m} }
+
+// Templates mengembalikan TemplateRepository di atas state ini.
+func (m *Memory) Templates() TemplateRepository { return memoryTemplates{m} }
+
+// Outbox mengembalikan OutboxRepository di atas state ini.
+func (m *Memory) Outbox() OutboxRepository { return memoryOutbox{m} }
+
+// Webhooks mengembalikan WebhookRepository di atas state ini.
+func (m *Memory) Webhooks() WebhookRepository { return memoryWebhooks{m} }
+
+// Notifications mengembalikan NotificationRepository di atas state ini.
+func (m *Memory) Notifications() NotificationRepository { return memoryNotifications{m} }
+
+// Reminders mengembalikan ReminderRepository di atas state ini.
+func (m *Memory) Reminders() ReminderRepository { return memoryReminders{m} }
+
+// CalendarFeeds mengembalikan CalendarFeedRepository di atas state ini.
+func (m *Memory) CalendarFeeds() CalendarFeedRepository { return memoryCalendarFeeds{m} }
+
+// ImportJobs mengembalikan ImportJobRepository di atas state ini.
+func (m *Memory) ImportJobs() ImportJobRepository { return memoryImportJobs{m} }
+
+func (m *Memory) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.mu.Lock()
+	snapshot := m.state.clone()
+	m.mu.Unlock()
+
+	if err := fn(ctx); err != nil {
+		m.mu.Lock()
+		m.state = snapshot
+		m.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (s memoryState) clone() memoryState {
+	clone := memoryState{
+		users:      make(map[uuid.UUID]models.User, len(s.users)),
+		projects:   make(map[uuid.UUID]models.Project, len(s.projects)),
+		members:    make(map[memberKey]models.ProjectMember, len(s.members)),
+		tasks:      make(map[uuid.UUID]models.Task, len(s.tasks)),
+		history:    append([]models.TaskHistory(nil), s.history...),
+		templates:  make(map[uuid.UUID]models.ProjectTemplate, len(s.templates)),
+		outbox:     append([]models.OutboxEvent(nil), s.outbox...),
+		webhooks:   make(map[uuid.UUID]models.Webhook, len(s.webhooks)),
+		deliveries: make(map[uuid.UUID]models.WebhookDelivery, len(s.deliveries)),
+		attempts:   append([]models.WebhookDeliveryAttempt(nil), s.attempts...),
+
+		notifications: make(map[uuid.UUID]models.Notification, len(s.notifications)),
+		watchers:      make(map[watcherKey]models.TaskWatcher, len(s.watchers)),
+		preferences:   make(map[preferenceKey]models.NotificationPreference, len(s.preferences)),
+
+		reminderSettings: make(map[uuid.UUID]models.ReminderSetting, len(s.reminderSettings)),
+		sentReminders:    make(map[string]models.SentReminder, len(s.sentReminders)),
+		calendarFeeds:    make(map[calendarFeedKey]models.CalendarFeed, len(s.calendarFeeds)),
+		importJobs:       make(map[uuid.UUID]models.ImportJob, len(s.importJobs)),
+	}
+	for id, user := range s.users {
+		clone.users[id] = user
+	}
+	for id, project := range s.projects {
+		clone.projects[id] = project
+	}
+	for key, member := range s.members {
+		clone.members[key] = member
+	}
+	for id, task := range s.tasks {
+		clone.tasks[id] = task
+	}
+	for id, template := range s.templates {
+		template.Tasks = append([]models.TemplateTask(nil), template.Tasks...)
+		clone.templates[id] = template
+	}
+	for id, webhook := range s.webhooks {
+		webhook.Events = append(models.WebhookEvents(nil), webhook.Events...)
+		clone.webhooks[id] = webhook
+	}
+	for id, delivery := range s.deliveries {
+		clone.deliveries[id] = delivery
+	}
+	for id, notification := range s.notifications {
+		clone.notifications[id] = notification
+	}
+	for key, watcher := range s.watchers {
+		clone.watchers[key] = watcher
+	}
+	for key, preference := range s.preferences {
+		clone.preferences[key] = preference
+	}
+	for userID, setting := range s.reminderSettings {
+		setting.Offsets = append(models.ReminderOffsets(nil), setting.Offsets...)
+		clone.reminderSettings[userID] = setting
+	}
+	for key, reminder := range s.sentReminders {
+		clone.sentReminders[key] = reminder
+	}
+	for key, feed := range s.calendarFeeds {
+		clone.calendarFeeds[key] = feed
+	}
+	for id, job := range s.importJobs {
+		clone.importJobs[id] = job
+	}
+	return clone
+}
+
+// projectWithUser meniru Preload("User").
+func (s memoryState) projectWithUser(project models.Project) models.Project {
+	project.User = s.users[project.CreatedByID]
+	return project
+}
+
+// taskWithProject meniru Preload("Project.User").
+func (s memoryState) taskWithProject(task models.Task) models.Task {
+	task.Project = s.projectWithUser(s.projects[task.ProjectID])
+	return task
+}
+
+type memoryUsers struct{ m *Memory }
+
+func (r memoryUsers) Create(_ context.Context, user *models.User) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for _, existing := range r.m.state.users {
+		if strings.EqualFold(existing.Email, user.Email) {
+			return ErrDuplicate
+		}
+	}
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	if _, ok := r.m.state.users[user.ID]; ok {
+		return ErrDuplicate
+	}
+	r.m.state.users[user.ID] = *user
+	return nil
+}
+
+func (r memoryUsers) FindByID(_ context.Context, id uuid.UUID) (models.User, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	user, ok := r.m.state.users[id]
+	if !ok {
+		return models.User{}, ErrNotFound
+	}
+	return user, nil
+}
+
+func (r memoryUsers) FindByEmail(_ context.Context, email string) (models.User, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for _, user := range r.m.state.users {
+		if strings.EqualFold(user.Email, email) {
+			return user, nil
+		}
+	}
+	return models.User{}, ErrNotFound
+}
+
+type memoryProjects struct{ m *Memory }
+
+// memberKey adalah primary key ProjectMember.
+type memberKey struct{ projectID, userID uuid.UUID }
+
+// canAccess melaporkan apakah userID pemilik atau anggota project.
+func (s memoryState) canAccess(project models.Project, userID uuid.UUID) bool {
+	_, isMember := s.members[memberKey{project.ID, userID}]
+	return project.CreatedByID == userID || isMember
+}
+
+func (r memoryProjects) Create(_ context.Context, project *models.Project) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	if _, ok := r.m.state.users[project.CreatedByID]; !ok {
+		return fmt.Errorf("foreign key violation: user %s does not exist", project.CreatedByID)
+	}
+	if project.ID == uuid.Nil {
+		project.ID = uuid.New()
+	}
+	if _, ok := r.m.state.projects[project.ID]; ok {
+		return ErrDuplicate
+	}
+	if project.Version == 0 {
+		project.Version = 1
+	}
+	stored := *project
+	stored.User = models.User{}
+	r.m.state.projects[project.ID] = stored
+	return nil
+}
+
+func (r memoryProjects) FindByID(_ context.Context, id uuid.UUID) (models.Project, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	project, ok := r.m.state.projects[id]
+	if !ok {
+		return models.Project{}, ErrNotFound
+	}
+	return r.m.state.projectWithUser(project), nil
+}
+
+func (r memoryProjects) ListByUser(_ context.Context, userID uuid.UUID, includeArchived bool) ([]models.Project, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	projects := []models.Project{}
+	for _, project := range r.m.state.projects {
+		if r.m.state.canAccess(project, userID) && (includeArchived || !project.Archived) {
+			projects = append(projects, r.m.state.projectWithUser(project))
+		}
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	return projects, nil
+}
+
+func (r memoryProjects) Update(_ context.Context, id uuid.UUID, version uint, updates Updates) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	project, ok := r.m.state.projects[id]
+	if !ok || project.Version != version {
+		return false, nil
+	}
+	for column, value := range updates {
+		var err error
+		switch column {
+		case "name":
+			project.Name, err = memoryValue[string](column, value)
+		case "description":
+			project.Description, err = memoryValue[string](column, value)
+		case "archived":
+			project.Archived, err = memoryValue[bool](column, value)
+		case "archived_at":
+			project.ArchivedAt, err = memoryValue[*time.Time](column, value)
+		default:
+			err = fmt.Errorf("memory repository: unknown project column %q", column)
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	project.Version++
+	r.m.state.projects[id] = project
+	return true, nil
+}
+
+func (r memoryProjects) Delete(_ context.Context, id uuid.UUID, version uint) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	project, ok := r.m.state.projects[id]
+	if !ok || project.Version != version {
+		return false, nil
+	}
+	delete(r.m.state.projects, id)
+	return true, nil
+}
+
+func (r memoryProjects) AddMember(_ context.Context, member *models.ProjectMember) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	if _, ok := r.m.state.projects[member.ProjectID]; !ok {
+		return fmt.Errorf("foreign key violation: project %s does not exist", member.ProjectID)
+	}
+	if _, ok := r.m.state.users[member.UserID]; !ok {
+		return fmt.Errorf("foreign key violation: user %s does not exist", member.UserID)
+	}
+	key := memberKey{member.ProjectID, member.UserID}
+	if _, ok := r.m.state.members[key]; ok {
+		return ErrDuplicate
+	}
+	if member.CreatedAt.IsZero() {
+		member.CreatedAt = time.Now()
+	}
+	stored := *member
+	stored.User = models.User{}
+	r.m.state.members[key] = stored
+	return nil
+}
+
+func (r memoryProjects) ListMembers(_ context.Context, projectID uuid.UUID) ([]models.ProjectMember, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	members := []models.ProjectMember{}
+	for key, member := range r.m.state.members {
+		if key.projectID == projectID {
+			member.User = r.m.state.users[member.UserID]
+			members = append(members, member)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if !members[i].CreatedAt.Equal(members[j].CreatedAt) {
+			return members[i].CreatedAt.Before(members[j].CreatedAt)
+		}
+		return members[i].UserID.String() < members[j].UserID.String()
+	})
+	return members, nil
+}
+
+func (r memoryProjects) IsMember(_ context.Context, projectID, userID uuid.UUID) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	_, ok := r.m.state.members[memberKey{projectID, userID}]
+	return ok, nil
+}
+
+func (r memoryProjects) RemoveMember(_ context.Context, projectID, userID uuid.UUID) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	key := memberKey{projectID, userID}
+	if _, ok := r.m.state.members[key]; !ok {
+		return false, nil
+	}
+	delete(r.m.state.members, key)
+	return true, nil
+}
+
+func (r memoryProjects) DeleteMembers(_ context.Context, projectID uuid.UUID) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for key := range r.m.state.members {
+		if key.projectID == projectID {
+			delete(r.m.state.members, key)
+		}
+	}
+	return nil
+}
+
+type memoryTasks struct{ m *Memory }
+
+func (r memoryTasks) Create(_ context.Context, tasks ...*models.Task) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for _, task := range tasks {
+		if _, ok := r.m.state.projects[task.ProjectID]; !ok {
+			return fmt.Errorf("foreign key violation: project %s does not exist", task.ProjectID)
+		}
+		if !task.Status.IsValid() {
+			return fmt.Errorf("check constraint violation: invalid task status %q", task.Status)
+		}
+	}
+	for _, task := range tasks {
+		if task.ID == uuid.Nil {
+			task.ID = uuid.New()
+		}
+		if task.Version == 0 {
+			task.Version = 1
+		}
+		stored := *task
+		stored.Project = models.Project{}
+		r.m.state.tasks[task.ID] = stored
+	}
+	return nil
+}
+
+func (r memoryTasks) FindByID(_ context.Context, id uuid.UUID) (models.Task, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	task, ok := r.m.state.tasks[id]
+	if !ok {
+		return models.Task{}, ErrNotFound
+	}
+	return r.m.state.taskWithProject(task), nil
+}
+
+func (r memoryTasks) FindInProject(_ context.Context, projectID, taskID uuid.UUID) (models.Task, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	task, ok := r.m.state.tasks[taskID]
+	if !ok || task.ProjectID != projectID {
+		return models.Task{}, ErrNotFound
+	}
+	return r.m.state.taskWithProject(task), nil
+}
+
+func (r memoryTasks) ListByProject(_ context.Context, projectID uuid.UUID) ([]models.Task, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	tasks := []models.Task{}
+	for _, task := range r.m.state.tasks {
+		if task.ProjectID == projectID {
+			tasks = append(tasks, r.m.state.taskWithProject(task))
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].Title < tasks[j].Title })
+	return tasks, nil
+}
+
+func (r memoryTasks) Update(_ context.Context, id uuid.UUID, version uint, updates Updates) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	task, ok := r.m.state.tasks[id]
+	if !ok || task.Version != version {
+		return false, nil
+	}
+	for column, value := range updates {
+		var err error
+		switch column {
+		case "title":
+			task.Title, err = memoryValue[string](column, value)
+		case "description":
+			task.Description, err = memoryValue[string](column, value)
+		case "status":
+			task.Status, err = memoryValue[models.TaskStatus](column, value)
+			if err == nil && !task.Status.IsValid() {
+				err = fmt.Errorf("check constraint violation: invalid task status %q", task.Status)
+			}
+		case "deadline":
+			task.Deadline, err = memoryValue[*time.Time](column, value)
+		case "project_id":
+			task.ProjectID, err = memoryValue[uuid.UUID](column, value)
+		default:
+			err = fmt.Errorf("memory repository: unknown task column %q", column)
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	task.Version++
+	r.m.state.tasks[id] = task
+	return true, nil
+}
+
+func (r memoryTasks) Delete(_ context.Context, id uuid.UUID, version uint) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	task, ok := r.m.state.tasks[id]
+	if !ok || task.Version != version {
+		return false, nil
+	}
+	delete(r.m.state.tasks, id)
+	return true, nil
+}
+
+func (r memoryTasks) DeleteByProject(_ context.Context, projectID uuid.UUID) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for id, task := range r.m.state.tasks {
+		if task.ProjectID == projectID {
+			delete(r.m.state.tasks, id)
+		}
+	}
+	return nil
+}
+
+func (r memoryTasks) CountByStatus(context.Context) (map[models.TaskStatus]int64, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	counts := map[models.TaskStatus]int64{}
+	for _, task := range r.m.state.tasks {
+		counts[task.Status]++
+	}
+	return counts, nil
+}
+
+func (r memoryTasks) ListByDeadline(_ context.Context, filter DeadlineFilter) ([]models.Task, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	tasks := []models.Task{}
+	for _, task := range r.m.state.tasks {
+		project := r.m.state.projects[task.ProjectID]
+		switch {
+		case task.Deadline == nil, project.Archived,
+			filter.OwnerID != uuid.Nil && project.CreatedByID != filter.OwnerID,
+			filter.UserID != uuid.Nil && !r.m.state.canAccess(project, filter.UserID),
+			!filter.From.IsZero() && task.Deadline.Before(filter.From),
+			!filter.To.IsZero() && !task.Deadline.Before(filter.To),
+			filter.OpenOnly && task.Status == models.Done:
+			continue
+		}
+		tasks = append(tasks, r.m.state.taskWithProject(task))
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if !tasks[i].Deadline.Equal(*tasks[j].Deadline) {
+			return tasks[i].Deadline.Before(*tasks[j].Deadline)
+		}
+		return tasks[i].Title < tasks[j].Title
+	})
+	return tasks, nil
+}
+
+func (r memoryTasks) AddHistory(_ context.Context, history *models.TaskHistory) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	if history.ID == uuid.Nil {
+		history.ID = uuid.New()
+	}
+	if history.CreatedAt.IsZero() {
+		history.CreatedAt = time.Now()
+	}
+	r.m.state.history = append(r.m.state.history, *history)
+	return nil
+}
+
+func (r memoryTasks) ListHistory(_ context.Context, taskID uuid.UUID) ([]models.TaskHistory, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	history := []models.TaskHistory{}
+	// Ditelusuri dari belakang supaya yang terbaru lebih dulu
+	for i := len(r.m.state.history) - 1; i >= 0; i-- {
+		if r.m.state.history[i].TaskID == taskID {
+			history = append(history, r.m.state.history[i])
+		}
+	}
+	return history, nil
+}
+
+func (r memoryTasks) DeleteHistory(_ context.Context, taskIDs ...uuid.UUID) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	deleted := make(map[uuid.UUID]bool, len(taskIDs))
+	for _, id := range taskIDs {
+		deleted[id] = true
+	}
+	kept := r.m.state.history[:0]
+	for _, entry := range r.m.state.history {
+		if !deleted[entry.TaskID] {
+			kept = append(kept, entry)
+		}
+	}
+	r.m.state.history = kept
+	return nil
+}
+
+type memoryTemplates struct{ m *Memory }
+
+func (r memoryTemplates) Create(_ context.Context, template *models.ProjectTemplate) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	if template.ID == uuid.Nil {
+		template.ID = uuid.New()
+	}
+	if template.CreatedAt.IsZero() {
+		template.CreatedAt = time.Now()
+	}
+	for i := range template.Tasks {
+		if template.Tasks[i].ID == uuid.Nil {
+			template.Tasks[i].ID = uuid.New()
+		}
+		template.Tasks[i].TemplateID = template.ID
+	}
+	stored := *template
+	stored.Tasks = append([]models.TemplateTask(nil), template.Tasks...)
+	r.m.state.templates[template.ID] = stored
+	return nil
+}
+
+func (r memoryTemplates) FindByID(_ context.Context, id uuid.UUID) (models.ProjectTemplate, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	template, ok := r.m.state.templates[id]
+	if !ok {
+		return models.ProjectTemplate{}, ErrNotFound
+	}
+	return sortedTemplate(template), nil
+}
+
+func (r memoryTemplates) ListByOwner(_ context.Context, ownerID uuid.UUID) ([]models.ProjectTemplate, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	templates := []models.ProjectTemplate{}
+	for _, template := range r.m.state.templates {
+		if template.CreatedByID == ownerID {
+			templates = append(templates, sortedTemplate(template))
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+func (r memoryTemplates) Delete(_ context.Context, id uuid.UUID) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	delete(r.m.state.templates, id)
+	return nil
+}
+
+// sortedTemplate mengembalikan salinan template dengan tugas terurut sesuai posisi.
+func sortedTemplate(template models.ProjectTemplate) models.ProjectTemplate {
+	template.Tasks = append([]models.TemplateTask(nil), template.Tasks...)
+	sort.SliceStable(template.Tasks, func(i, j int) bool { return template.Tasks[i].Position < template.Tasks[j].Position })
+	return template
+}
+
+// memoryValue mengambil nilai kolom dengan tipe T; nil diterima untuk kolom pointer (NULL).
+func memoryValue[T any](column string, value interface{}) (T, error) {
+	var zero T
+	if value == nil {
+		return zero, nil
+	}
+	typed, ok := value.(T)
+	if !ok {
+		return zero, fmt.Errorf("memory repository: column %q expects %T, got %T", column, zero, value)
+	}
+	return typed, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/models"
+)
+
+type memoryCalendarFeeds struct{ m *Memory }
+
+// calendarFeedKey adalah unique key CalendarFeed.
+type calendarFeedKey struct{ userID, projectID uuid.UUID }
+
+func (r memoryCalendarFeeds) Save(_ context.Context, feed *models.CalendarFeed) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	key := calendarFeedKey{feed.UserID, feed.ProjectID}
+	if existing, ok := r.m.state.calendarFeeds[key]; ok {
+		feed.ID = existing.ID
+	}
+	for _, other := range r.m.state.calendarFeeds {
+		if other.TokenHash == feed.TokenHash && other.ID != feed.ID {
+			return ErrDuplicate
+		}
+	}
+	if feed.ID == uuid.Nil {
+		feed.ID = uuid.New()
+	}
+	if feed.CreatedAt.IsZero() {
+		feed.CreatedAt = time.Now()
+	}
+	r.m.state.calendarFeeds[key] = *feed
+	return nil
+}
+
+func (r memoryCalendarFeeds) Find(_ context.Context, userID, projectID uuid.UUID) (models.CalendarFeed, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	feed, ok := r.m.state.calendarFeeds[calendarFeedKey{userID, projectID}]
+	if !ok {
+		return models.CalendarFeed{}, ErrNotFound
+	}
+	return feed, nil
+}
+
+func (r memoryCalendarFeeds) FindByTokenHash(_ context.Context, tokenHash string) (models.CalendarFeed, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for _, feed := range r.m.state.calendarFeeds {
+		if feed.TokenHash == tokenHash {
+			return feed, nil
+		}
+	}
+	return models.CalendarFeed{}, ErrNotFound
+}
+
+func (r memoryCalendarFeeds) Delete(_ context.Context, userID, projectID uuid.UUID) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	key := calendarFeedKey{userID, projectID}
+	if _, ok := r.m.state.calendarFeeds[key]; !ok {
+		return false, nil
+	}
+	delete(r.m.state.calendarFeeds, key)
+	return true, nil
+}
+
+func (r memoryCalendarFeeds) DeleteByProject(_ context.Context, projectID uuid.UUID) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for key := range r.m.state.calendarFeeds {
+		if key.projectID == projectID {
+			delete(r.m.state.calendarFeeds, key)
+		}
+	}
+	return nil
+}
+
+func (r memoryCalendarFeeds) Touch(_ context.Context, id uuid.UUID, at time.Time) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for key, feed := range r.m.state.calendarFeeds {
+		if feed.ID == id {
+			feed.LastAccessedAt = &at
+			r.m.state.calendarFeeds[key] = feed
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/models"
+)
+
+type memoryImportJobs struct{ m *Memory }
+
+func (r memoryImportJobs) Create(_ context.Context, job *models.ImportJob) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+	if _, ok := r.m.state.importJobs[job.ID]; ok {
+		return ErrDuplicate
+	}
+	now := time.Now()
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = now
+	}
+	job.UpdatedAt = now
+	r.m.state.importJobs[job.ID] = *job
+	return nil
+}
+
+func (r memoryImportJobs) Find(_ context.Context, id uuid.UUID) (models.ImportJob, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	job, ok := r.m.state.importJobs[id]
+	if !ok {
+		return models.ImportJob{}, ErrNotFound
+	}
+	job.Data = nil
+	return job, nil
+}
+
+func (r memoryImportJobs) ListByUser(_ context.Context, userID uuid.UUID, limit int) ([]models.ImportJob, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	jobs := []models.ImportJob{}
+	for _, job := range r.m.state.importJobs {
+		if job.UserID == userID {
+			job.Data, job.Report = nil, nil
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+func (r memoryImportJobs) ListDue(_ context.Context, now time.Time, limit int) ([]models.ImportJob, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	jobs := []models.ImportJob{}
+	for _, job := range r.m.state.importJobs {
+		if importJobDue(job, now) {
+			job.Data, job.Report = nil, nil
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.Before(jobs[j].CreatedAt) })
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+func (r memoryImportJobs) Claim(_ context.Context, id uuid.UUID, now, leaseUntil time.Time) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	job, ok := r.m.state.importJobs[id]
+	if !ok || !importJobDue(job, now) {
+		return false, nil
+	}
+	job.Status = models.ImportRunning
+	job.LeaseUntil = &leaseUntil
+	job.Attempts++
+	job.StartedAt = &now
+	job.UpdatedAt = now
+	r.m.state.importJobs[id] = job
+	return true, nil
+}
+
+func (r memoryImportJobs) LoadData(_ context.Context, id uuid.UUID) ([]byte, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	job, ok := r.m.state.importJobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return job.Data, nil
+}
+
+func (r memoryImportJobs) Update(_ context.Context, id uuid.UUID, updates Updates) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	job, ok := r.m.state.importJobs[id]
+	if !ok {
+		return nil
+	}
+	for column, value := range updates {
+		var err error
+		switch column {
+		case "status":
+			job.Status, err = memoryValue[string](column, value)
+		case "lease_until":
+			job.LeaseUntil, err = memoryValue[*time.Time](column, value)
+		case "processed":
+			job.Processed, err = memoryValue[int](column, value)
+		case "total":
+			job.Total, err = memoryValue[int](column, value)
+		case "project_id":
+			job.ProjectID, err = memoryValue[*uuid.UUID](column, value)
+		case "report":
+			job.Report, err = memoryValue[[]byte](column, value)
+		case "error":
+			job.Error, err = memoryValue[string](column, value)
+		case "data":
+			job.Data, err = memoryValue[[]byte](column, value)
+		case "finished_at":
+			job.FinishedAt, err = memoryValue[*time.Time](column, value)
+		default:
+			err = fmt.Errorf("memory repository: unknown import job column %q", column)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	job.UpdatedAt = time.Now()
+	r.m.state.importJobs[id] = job
+	return nil
+}
+
+func (r memoryImportJobs) DeleteFinishedBefore(_ context.Context, before time.Time) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for id, job := range r.m.state.importJobs {
+		if job.FinishedAt != nil && job.FinishedAt.Before(before) {
+			delete(r.m.state.importJobs, id)
+		}
+	}
+	return nil
+}
+
+// importJobDue meniru kondisi dueImportJobs.
+func importJobDue(job models.ImportJob, now time.Time) bool {
+	if job.Status == models.ImportQueued {
+		return true
+	}
+	return job.Status == models.ImportRunning && job.LeaseUntil != nil && !job.LeaseUntil.After(now)
+}
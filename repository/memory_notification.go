@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/models"
+)
+
+type memoryNotifications struct{ m *Memory }
+
+// watcherKey adalah primary key TaskWatcher.
+type watcherKey struct{ taskID, userID uuid.UUID }
+
+// preferenceKey adalah primary key NotificationPreference.
+type preferenceKey struct {
+	userID    uuid.UUID
+	eventType string
+}
+
+func (r memoryNotifications) Create(_ context.Context, notifications ...*models.Notification) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for _, notification := range notifications {
+		if notification.EventID != nil && r.m.state.hasNotification(notification.UserID, *notification.EventID) {
+			continue
+		}
+		if notification.ID == uuid.Nil {
+			notification.ID = uuid.New()
+		}
+		if notification.CreatedAt.IsZero() {
+			notification.CreatedAt = time.Now()
+		}
+		r.m.state.notifications[notification.ID] = *notification
+	}
+	return nil
+}
+
+func (s memoryState) hasNotification(userID, eventID uuid.UUID) bool {
+	for _, notification := range s.notifications {
+		if notification.UserID == userID && notification.EventID != nil && *notification.EventID == eventID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r memoryNotifications) FindByID(_ context.Context, id uuid.UUID) (models.Notification, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	notification, ok := r.m.state.notifications[id]
+	if !ok {
+		return models.Notification{}, ErrNotFound
+	}
+	return notification, nil
+}
+
+func (r memoryNotifications) ListByUser(_ context.Context, userID uuid.UUID, unreadOnly bool, after *NotificationCursor, limit int) ([]models.Notification, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	// newer melaporkan apakah a berada sebelum b pada urutan created_at DESC, id DESC
+	newer := func(aTime time.Time, aID uuid.UUID, bTime time.Time, bID uuid.UUID) bool {
+		if !aTime.Equal(bTime) {
+			return aTime.After(bTime)
+		}
+		return bytes.Compare(aID[:], bID[:]) > 0
+	}
+
+	notifications := []models.Notification{}
+	for _, notification := range r.m.state.notifications {
+		if notification.UserID != userID || (unreadOnly && notification.ReadAt != nil) {
+			continue
+		}
+		if after != nil && !newer(after.CreatedAt, after.ID, notification.CreatedAt, notification.ID) {
+			continue
+		}
+		notifications = append(notifications, notification)
+	}
+	sort.Slice(notifications, func(i, j int) bool {
+		return newer(notifications[i].CreatedAt, notifications[i].ID, notifications[j].CreatedAt, notifications[j].ID)
+	})
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+	}
+	return notifications, nil
+}
+
+func (r memoryNotifications) CountUnread(_ context.Context, userID uuid.UUID) (int64, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	var count int64
+	for _, notification := range r.m.state.notifications {
+		if notification.UserID == userID && notification.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r memoryNotifications) MarkRead(_ context.Context, userID uuid.UUID, ids []uuid.UUID, at time.Time) (int64, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	selected := map[uuid.UUID]bool{}
+	for _, id := range ids {
+		selected[id] = true
+	}
+	var updated int64
+	for id, notification := range r.m.state.notifications {
+		if notification.UserID != userID || notification.ReadAt != nil || (len(ids) > 0 && !selected[id]) {
+			continue
+		}
+		notification.ReadAt = &at
+		r.m.state.notifications[id] = notification
+		updated++
+	}
+	return updated, nil
+}
+
+func (r memoryNotifications) AddWatcher(_ context.Context, watcher *models.TaskWatcher) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	key := watcherKey{watcher.TaskID, watcher.UserID}
+	if _, ok := r.m.state.watchers[key]; ok {
+		return nil
+	}
+	if watcher.CreatedAt.IsZero() {
+		watcher.CreatedAt = time.Now()
+	}
+	r.m.state.watchers[key] = *watcher
+	return nil
+}
+
+func (r memoryNotifications) RemoveWatcher(_ context.Context, taskID, userID uuid.UUID) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	delete(r.m.state.watchers, watcherKey{taskID, userID})
+	return nil
+}
+
+func (r memoryNotifications) ListWatchers(_ context.Context, taskID uuid.UUID) ([]models.TaskWatcher, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	watchers := []models.TaskWatcher{}
+	for key, watcher := range r.m.state.watchers {
+		if key.taskID == taskID {
+			watchers = append(watchers, watcher)
+		}
+	}
+	sort.Slice(watchers, func(i, j int) bool { return watchers[i].CreatedAt.Before(watchers[j].CreatedAt) })
+	return watchers, nil
+}
+
+func (r memoryNotifications) DeleteWatchers(_ context.Context, taskIDs ...uuid.UUID) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	deleted := map[uuid.UUID]bool{}
+	for _, id := range taskIDs {
+		deleted[id] = true
+	}
+	for key := range r.m.state.watchers {
+		if deleted[key.taskID] {
+			delete(r.m.state.watchers, key)
+		}
+	}
+	return nil
+}
+
+func (r memoryNotifications) DeleteOrphanWatchers(_ context.Context) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for key := range r.m.state.watchers {
+		if _, ok := r.m.state.tasks[key.taskID]; !ok {
+			delete(r.m.state.watchers, key)
+		}
+	}
+	return nil
+}
+
+func (r memoryNotifications) ListPreferences(_ context.Context, userID uuid.UUID) ([]models.NotificationPreference, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	preferences := []models.NotificationPreference{}
+	for key, preference := range r.m.state.preferences {
+		if key.userID == userID {
+			preferences = append(preferences, preference)
+		}
+	}
+	sort.Slice(preferences, func(i, j int) bool { return preferences[i].EventType < preferences[j].EventType })
+	return preferences, nil
+}
+
+func (r memoryNotifications) SavePreferences(_ context.Context, preferences ...*models.NotificationPreference) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for _, preference := range preferences {
+		if preference.UpdatedAt.IsZero() {
+			preference.UpdatedAt = time.Now()
+		}
+		r.m.state.preferences[preferenceKey{preference.UserID, preference.EventType}] = *preference
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/models"
+)
+
+type memoryReminders struct{ m *Memory }
+
+func (r memoryReminders) FindSetting(_ context.Context, userID uuid.UUID) (models.ReminderSetting, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	setting, ok := r.m.state.reminderSettings[userID]
+	if !ok {
+		return models.ReminderSetting{}, ErrNotFound
+	}
+	setting.Offsets = append(models.ReminderOffsets{}, setting.Offsets...)
+	return setting, nil
+}
+
+func (r memoryReminders) SaveSetting(_ context.Context, setting *models.ReminderSetting) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	if setting.UpdatedAt.IsZero() {
+		setting.UpdatedAt = time.Now()
+	}
+	stored := *setting
+	stored.Offsets = append(models.ReminderOffsets{}, setting.Offsets...)
+	r.m.state.reminderSettings[setting.UserID] = stored
+	return nil
+}
+
+func (r memoryReminders) ListDigestSettings(context.Context) ([]models.ReminderSetting, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	settings := []models.ReminderSetting{}
+	for _, setting := range r.m.state.reminderSettings {
+		if setting.DailyDigest {
+			setting.Offsets = append(models.ReminderOffsets{}, setting.Offsets...)
+			settings = append(settings, setting)
+		}
+	}
+	sort.Slice(settings, func(i, j int) bool {
+		return bytes.Compare(settings[i].UserID[:], settings[j].UserID[:]) < 0
+	})
+	return settings, nil
+}
+
+func (r memoryReminders) Claim(_ context.Context, reminder *models.SentReminder) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	if _, ok := r.m.state.sentReminders[reminder.Key]; ok {
+		return false, nil
+	}
+	r.m.state.sentReminders[reminder.Key] = *reminder
+	return true, nil
+}
+
+func (r memoryReminders) Release(_ context.Context, key string) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	delete(r.m.state.sentReminders, key)
+	return nil
+}
+
+func (r memoryReminders) DeleteSentBefore(_ context.Context, before time.Time) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for key, reminder := range r.m.state.sentReminders {
+		if reminder.SentAt.Before(before) {
+			delete(r.m.state.sentReminders, key)
+		}
+	}
+	return nil
+}
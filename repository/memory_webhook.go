@@ -0,0 +1,346 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/models"
+)
+
+type memoryOutbox struct{ m *Memory }
+
+func (r memoryOutbox) Add(_ context.Context, event *models.OutboxEvent) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	r.m.state.outbox = append(r.m.state.outbox, *event)
+	return nil
+}
+
+func (r memoryOutbox) FindByID(_ context.Context, id uuid.UUID) (models.OutboxEvent, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for _, event := range r.m.state.outbox {
+		if event.ID == id {
+			return event, nil
+		}
+	}
+	return models.OutboxEvent{}, ErrNotFound
+}
+
+func (r memoryOutbox) FindLatest(_ context.Context, projectID uuid.UUID, eventType string) (models.OutboxEvent, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for i := len(r.m.state.outbox) - 1; i >= 0; i-- {
+		if event := r.m.state.outbox[i]; event.ProjectID == projectID && event.Type == eventType {
+			return event, nil
+		}
+	}
+	return models.OutboxEvent{}, ErrNotFound
+}
+
+func (r memoryOutbox) ListPending(_ context.Context, limit int) ([]models.OutboxEvent, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	// Event disimpan sesuai urutan Add, jadi sudah terlama lebih dulu
+	events := []models.OutboxEvent{}
+	for _, event := range r.m.state.outbox {
+		if event.ProcessedAt == nil && event.DeadAt == nil && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (r memoryOutbox) MarkProcessed(_ context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for i, event := range r.m.state.outbox {
+		if event.ID == id {
+			if event.ProcessedAt != nil {
+				return false, nil
+			}
+			r.m.state.outbox[i].ProcessedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r memoryOutbox) RecordFailure(_ context.Context, id uuid.UUID, message string, maxAttempts int, at time.Time) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for i := range r.m.state.outbox {
+		event := &r.m.state.outbox[i]
+		if event.ID != id || event.ProcessedAt != nil || event.DeadAt != nil {
+			continue
+		}
+		event.Attempts++
+		event.LastError = message
+		if event.Attempts >= maxAttempts {
+			event.DeadAt = &at
+			return true, nil
+		}
+		return false, nil
+	}
+	return false, nil
+}
+
+type memoryWebhooks struct{ m *Memory }
+
+func (r memoryWebhooks) Create(_ context.Context, webhook *models.Webhook) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	if webhook.ID == uuid.Nil {
+		webhook.ID = uuid.New()
+	}
+	if _, ok := r.m.state.webhooks[webhook.ID]; ok {
+		return ErrDuplicate
+	}
+	now := time.Now()
+	webhook.CreatedAt, webhook.UpdatedAt = now, now
+	r.m.state.webhooks[webhook.ID] = *webhook
+	return nil
+}
+
+func (r memoryWebhooks) FindByID(_ context.Context, id uuid.UUID) (models.Webhook, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	webhook, ok := r.m.state.webhooks[id]
+	if !ok {
+		return models.Webhook{}, ErrNotFound
+	}
+	return webhook, nil
+}
+
+func (r memoryWebhooks) ListByProject(_ context.Context, projectID uuid.UUID) ([]models.Webhook, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	webhooks := []models.Webhook{}
+	for _, webhook := range r.m.state.webhooks {
+		if webhook.ProjectID == projectID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt) })
+	return webhooks, nil
+}
+
+func (r memoryWebhooks) Update(_ context.Context, id uuid.UUID, updates Updates) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	webhook, ok := r.m.state.webhooks[id]
+	if !ok {
+		return nil
+	}
+	for column, value := range updates {
+		var err error
+		switch column {
+		case "url":
+			webhook.URL, err = memoryValue[string](column, value)
+		case "secret":
+			webhook.Secret, err = memoryValue[string](column, value)
+		case "events":
+			webhook.Events, err = memoryValue[models.WebhookEvents](column, value)
+		case "active":
+			webhook.Active, err = memoryValue[bool](column, value)
+		default:
+			err = fmt.Errorf("memory repository: unknown webhook column %q", column)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	webhook.UpdatedAt = time.Now()
+	r.m.state.webhooks[id] = webhook
+	return nil
+}
+
+func (r memoryWebhooks) Delete(_ context.Context, id uuid.UUID) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	deleted := map[uuid.UUID]bool{}
+	for deliveryID, delivery := range r.m.state.deliveries {
+		if delivery.WebhookID == id {
+			deleted[deliveryID] = true
+			delete(r.m.state.deliveries, deliveryID)
+		}
+	}
+	kept := r.m.state.attempts[:0]
+	for _, attempt := range r.m.state.attempts {
+		if !deleted[attempt.DeliveryID] {
+			kept = append(kept, attempt)
+		}
+	}
+	r.m.state.attempts = kept
+	delete(r.m.state.webhooks, id)
+	return nil
+}
+
+func (r memoryWebhooks) DeleteByProject(_ context.Context, projectID uuid.UUID) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for id, webhook := range r.m.state.webhooks {
+		if webhook.ProjectID == projectID {
+			delete(r.m.state.webhooks, id)
+		}
+	}
+	return nil
+}
+
+func (r memoryWebhooks) CreateDeliveries(_ context.Context, deliveries ...*models.WebhookDelivery) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	now := time.Now()
+	for _, delivery := range deliveries {
+		if delivery.ID == uuid.Nil {
+			delivery.ID = uuid.New()
+		}
+		delivery.CreatedAt, delivery.UpdatedAt = now, now
+		stored := *delivery
+		stored.Log = nil
+		r.m.state.deliveries[delivery.ID] = stored
+	}
+	return nil
+}
+
+func (r memoryWebhooks) FindDelivery(_ context.Context, id uuid.UUID) (models.WebhookDelivery, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	delivery, ok := r.m.state.deliveries[id]
+	if !ok {
+		return models.WebhookDelivery{}, ErrNotFound
+	}
+	return r.m.state.deliveryWithLog(delivery), nil
+}
+
+func (r memoryWebhooks) ListDeliveries(_ context.Context, webhookID uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	deliveries := []models.WebhookDelivery{}
+	for _, delivery := range r.m.state.deliveries {
+		if delivery.WebhookID == webhookID {
+			deliveries = append(deliveries, r.m.state.deliveryWithLog(delivery))
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt) })
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (r memoryWebhooks) ListDueDeliveries(_ context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	deliveries := []models.WebhookDelivery{}
+	for _, delivery := range r.m.state.deliveries {
+		if delivery.Status == models.DeliveryPending && delivery.NextAttemptAt != nil && !delivery.NextAttemptAt.After(now) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].NextAttemptAt.Before(*deliveries[j].NextAttemptAt) })
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (r memoryWebhooks) ClaimDelivery(_ context.Context, id uuid.UUID, now, leaseUntil time.Time) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	delivery, ok := r.m.state.deliveries[id]
+	if !ok || delivery.Status != models.DeliveryPending || delivery.NextAttemptAt == nil || delivery.NextAttemptAt.After(now) {
+		return false, nil
+	}
+	delivery.NextAttemptAt = &leaseUntil
+	r.m.state.deliveries[id] = delivery
+	return true, nil
+}
+
+func (r memoryWebhooks) UpdateDelivery(_ context.Context, id uuid.UUID, leaseUntil time.Time, updates Updates) (bool, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	delivery, ok := r.m.state.deliveries[id]
+	if !ok || delivery.Status != models.DeliveryPending || delivery.NextAttemptAt == nil || !delivery.NextAttemptAt.Equal(leaseUntil) {
+		return false, nil
+	}
+	for column, value := range updates {
+		var err error
+		switch column {
+		case "status":
+			delivery.Status, err = memoryValue[string](column, value)
+		case "attempts":
+			delivery.Attempts, err = memoryValue[int](column, value)
+		case "next_attempt_at":
+			delivery.NextAttemptAt, err = memoryValue[*time.Time](column, value)
+		case "last_status_code":
+			delivery.LastStatusCode, err = memoryValue[int](column, value)
+		case "last_error":
+			delivery.LastError, err = memoryValue[string](column, value)
+		case "delivered_at":
+			delivery.DeliveredAt, err = memoryValue[*time.Time](column, value)
+		default:
+			err = fmt.Errorf("memory repository: unknown webhook delivery column %q", column)
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	delivery.UpdatedAt = time.Now()
+	r.m.state.deliveries[id] = delivery
+	return true, nil
+}
+
+func (r memoryWebhooks) AddAttempt(_ context.Context, attempt *models.WebhookDeliveryAttempt) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	if attempt.ID == uuid.Nil {
+		attempt.ID = uuid.New()
+	}
+	if attempt.CreatedAt.IsZero() {
+		attempt.CreatedAt = time.Now()
+	}
+	r.m.state.attempts = append(r.m.state.attempts, *attempt)
+	return nil
+}
+
+// deliveryWithLog meniru Preload("Log") yang diurutkan berdasarkan nomor percobaan.
+func (s memoryState) deliveryWithLog(delivery models.WebhookDelivery) models.WebhookDelivery {
+	delivery.Log = []models.WebhookDeliveryAttempt{}
+	for _, attempt := range s.attempts {
+		if attempt.DeliveryID == delivery.ID {
+			delivery.Log = append(delivery.Log, attempt)
+		}
+	}
+	sort.Slice(delivery.Log, func(i, j int) bool { return delivery.Log[i].Attempt < delivery.Log[j].Attempt })
+	return delivery
+}
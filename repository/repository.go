@@ -0,0 +1,237 @@
+// Package repository memisahkan akses data dari business logic. Service hanya bergantung pada interface
+// di file ini, sehingga implementasi GORM bisa diganti dengan implementasi in-memory di test.
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/models"
+)
+
+var (
+	// ErrNotFound dikembalikan jika record yang dicari tidak ada.
+	ErrNotFound = errors.New("repository: record not found")
+	// ErrDuplicate dikembalikan jika penyimpanan melanggar unique constraint.
+	ErrDuplicate = errors.New("repository: duplicate record")
+)
+
+// Updates adalah perubahan kolom (nama kolom database -> nilai baru). Map dipakai supaya nilai kosong,
+// false dan NULL tetap ditulis.
+type Updates map[string]interface{}
+
+// Transactor menjalankan fn di dalam satu transaksi. Semua repository yang dipanggil dengan ctx milik fn
+// ikut dalam transaksi tersebut; Transaction yang bersarang menjadi savepoint. Error dari fn membatalkan
+// seluruh perubahan di dalamnya.
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// UserRepository menyimpan akun user.
+type UserRepository interface {
+	Create(ctx context.Context, user *models.User) error
+	FindByID(ctx context.Context, id uuid.UUID) (models.User, error)
+	FindByEmail(ctx context.Context, email string) (models.User, error)
+}
+
+// ProjectRepository menyimpan proyek. Proyek yang dikembalikan sudah berisi data User pemiliknya.
+type ProjectRepository interface {
+	Create(ctx context.Context, project *models.Project) error
+	FindByID(ctx context.Context, id uuid.UUID) (models.Project, error)
+	// ListByUser mengembalikan proyek milik userID beserta proyek tempat userID menjadi anggota, urut nama.
+	ListByUser(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]models.Project, error)
+	// Update menerapkan perubahan dan menaikkan versi hanya jika versi di database masih sama dengan version.
+	// Hasil false berarti proyek sudah diubah (atau dihapus) request lain.
+	Update(ctx context.Context, id uuid.UUID, version uint, updates Updates) (bool, error)
+	// Delete menghapus proyek hanya jika versinya masih sama dengan version.
+	Delete(ctx context.Context, id uuid.UUID, version uint) (bool, error)
+
+	// AddMember mengembalikan ErrDuplicate jika user sudah menjadi anggota proyek.
+	AddMember(ctx context.Context, member *models.ProjectMember) error
+	// ListMembers mengembalikan anggota proyek beserta data user-nya, urut dari yang paling lama bergabung.
+	ListMembers(ctx context.Context, projectID uuid.UUID) ([]models.ProjectMember, error)
+	IsMember(ctx context.Context, projectID, userID uuid.UUID) (bool, error)
+	// RemoveMember mengembalikan false jika userID bukan anggota proyek.
+	RemoveMember(ctx context.Context, projectID, userID uuid.UUID) (bool, error)
+	DeleteMembers(ctx context.Context, projectID uuid.UUID) error
+}
+
+// TaskRepository menyimpan tugas beserta riwayat perpindahannya. Tugas yang dikembalikan sudah berisi
+// data Project dan User pemiliknya.
+type TaskRepository interface {
+	Create(ctx context.Context, tasks ...*models.Task) error
+	FindByID(ctx context.Context, id uuid.UUID) (models.Task, error)
+	FindInProject(ctx context.Context, projectID, taskID uuid.UUID) (models.Task, error)
+	// ListByProject mengembalikan tugas-tugas proyek, diurutkan berdasarkan judul.
+	ListByProject(ctx context.Context, projectID uuid.UUID) ([]models.Task, error)
+	// Update menerapkan perubahan dan menaikkan versi hanya jika versi di database masih sama dengan version.
+	Update(ctx context.Context, id uuid.UUID, version uint, updates Updates) (bool, error)
+	// Delete menghapus tugas hanya jika versinya masih sama dengan version.
+	Delete(ctx context.Context, id uuid.UUID, version uint) (bool, error)
+	DeleteByProject(ctx context.Context, projectID uuid.UUID) error
+	// CountByStatus menghitung semua tugas per status; status tanpa tugas tidak muncul di hasil.
+	CountByStatus(ctx context.Context) (map[models.TaskStatus]int64, error)
+	// ListByDeadline mengembalikan tugas berdeadline yang cocok dengan filter, diurutkan berdasarkan deadline
+	// lalu judul.
+	ListByDeadline(ctx context.Context, filter DeadlineFilter) ([]models.Task, error)
+
+	AddHistory(ctx context.Context, history *models.TaskHistory) error
+	// ListHistory mengembalikan riwayat tugas, terbaru lebih dulu.
+	ListHistory(ctx context.Context, taskID uuid.UUID) ([]models.TaskHistory, error)
+	DeleteHistory(ctx context.Context, taskIDs ...uuid.UUID) error
+}
+
+// DeadlineFilter memilih tugas untuk TaskRepository.ListByDeadline. Tugas tanpa deadline dan tugas di proyek
+// yang diarsipkan tidak pernah ikut.
+type DeadlineFilter struct {
+	OwnerID  uuid.UUID // Pemilik proyek; uuid.Nil berarti semua user
+	UserID   uuid.UUID // Pemilik atau anggota proyek; uuid.Nil berarti tanpa batasan ini
+	From, To time.Time // Rentang deadline [From, To); nilai nol berarti tanpa batas
+	OpenOnly bool      // Lewati tugas yang sudah done
+}
+
+// TemplateRepository menyimpan template proyek. Template yang dikembalikan sudah berisi tugas-tugasnya,
+// diurutkan sesuai posisi.
+type TemplateRepository interface {
+	// Create menyimpan template beserta tugas-tugasnya.
+	Create(ctx context.Context, template *models.ProjectTemplate) error
+	FindByID(ctx context.Context, id uuid.UUID) (models.ProjectTemplate, error)
+	ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]models.ProjectTemplate, error)
+	// Delete menghapus template beserta tugas-tugasnya.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// OutboxRepository menyimpan event domain (transactional outbox). Add dipanggil dengan ctx transaksi yang
+// sama dengan perubahan datanya, sehingga event hanya ada jika perubahannya tersimpan.
+type OutboxRepository interface {
+	Add(ctx context.Context, event *models.OutboxEvent) error
+	FindByID(ctx context.Context, id uuid.UUID) (models.OutboxEvent, error)
+	// FindLatest mengembalikan event eventType terbaru milik projectID.
+	FindLatest(ctx context.Context, projectID uuid.UUID, eventType string) (models.OutboxEvent, error)
+	// ListPending mengembalikan event yang belum diproses dan belum dead, terlama lebih dulu.
+	ListPending(ctx context.Context, limit int) ([]models.OutboxEvent, error)
+	// MarkProcessed menandai event sudah diproses. Hasil false berarti event sudah diklaim instance lain.
+	MarkProcessed(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+	// RecordFailure menambah jumlah percobaan event yang belum diproses dan menyimpan pesan error-nya. Setelah
+	// maxAttempts percobaan event ditandai dead pada at; hasil true berarti event baru saja menjadi dead.
+	RecordFailure(ctx context.Context, id uuid.UUID, message string, maxAttempts int, at time.Time) (bool, error)
+}
+
+// WebhookRepository menyimpan langganan webhook beserta pengiriman dan log percobaannya.
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	FindByID(ctx context.Context, id uuid.UUID) (models.Webhook, error)
+	// ListByProject mengembalikan webhook proyek, terlama lebih dulu.
+	ListByProject(ctx context.Context, projectID uuid.UUID) ([]models.Webhook, error)
+	Update(ctx context.Context, id uuid.UUID, updates Updates) error
+	// Delete menghapus webhook beserta pengiriman dan log percobaannya.
+	Delete(ctx context.Context, id uuid.UUID) error
+	// DeleteByProject menghapus webhook proyek; pengiriman yang sudah dibuat tetap disimpan.
+	DeleteByProject(ctx context.Context, projectID uuid.UUID) error
+
+	CreateDeliveries(ctx context.Context, deliveries ...*models.WebhookDelivery) error
+	// FindDelivery mengembalikan pengiriman beserta log percobaannya.
+	FindDelivery(ctx context.Context, id uuid.UUID) (models.WebhookDelivery, error)
+	// ListDeliveries mengembalikan pengiriman terbaru sebuah webhook beserta log percobaannya.
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]models.WebhookDelivery, error)
+	// ListDueDeliveries mengembalikan pengiriman pending yang jadwalnya sudah lewat dari now.
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error)
+	// ClaimDelivery menggeser jadwal pengiriman pending yang sudah jatuh tempo (<= now) ke leaseUntil supaya
+	// instance lain tidak mengirimnya bersamaan. Hasil false berarti pengiriman sudah diklaim instance lain.
+	ClaimDelivery(ctx context.Context, id uuid.UUID, now, leaseUntil time.Time) (bool, error)
+	// UpdateDelivery menyimpan hasil percobaan hanya jika lease dari ClaimDelivery masih dipegang, yaitu
+	// pengiriman masih pending dengan jadwal leaseUntil. Hasil false berarti lease sudah habis dan pengiriman
+	// diklaim ulang, sehingga hasilnya harus dibuang.
+	UpdateDelivery(ctx context.Context, id uuid.UUID, leaseUntil time.Time, updates Updates) (bool, error)
+	AddAttempt(ctx context.Context, attempt *models.WebhookDeliveryAttempt) error
+}
+
+// NotificationCursor adalah posisi halaman inbox: notifikasi sesudah cursor adalah yang lebih lama dari
+// (CreatedAt, ID).
+type NotificationCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// NotificationRepository menyimpan inbox notifikasi, watcher tugas, dan preferensi notifikasi user.
+type NotificationRepository interface {
+	// Create menyimpan notifikasi; notifikasi dengan (UserID, EventID) yang sudah ada dilewati.
+	Create(ctx context.Context, notifications ...*models.Notification) error
+	FindByID(ctx context.Context, id uuid.UUID) (models.Notification, error)
+	// ListByUser mengembalikan paling banyak limit notifikasi user, terbaru lebih dulu, dimulai sesudah after
+	// (nil berarti dari awal).
+	ListByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, after *NotificationCursor, limit int) ([]models.Notification, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int64, error)
+	// MarkRead menandai notifikasi user sebagai dibaca; ids kosong berarti semua notifikasi yang belum dibaca.
+	// Hasilnya jumlah notifikasi yang berubah.
+	MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, at time.Time) (int64, error)
+
+	// AddWatcher menambahkan watcher; watcher yang sudah ada diabaikan.
+	AddWatcher(ctx context.Context, watcher *models.TaskWatcher) error
+	RemoveWatcher(ctx context.Context, taskID, userID uuid.UUID) error
+	ListWatchers(ctx context.Context, taskID uuid.UUID) ([]models.TaskWatcher, error)
+	// DeleteWatchers menghapus watcher tugas-tugas taskIDs.
+	DeleteWatchers(ctx context.Context, taskIDs ...uuid.UUID) error
+	// DeleteOrphanWatchers menghapus watcher yang tugasnya sudah tidak ada, misalnya setelah proyek dihapus.
+	DeleteOrphanWatchers(ctx context.Context) error
+
+	// ListPreferences mengembalikan preferensi yang tersimpan; jenis tanpa preferensi dianggap aktif.
+	ListPreferences(ctx context.Context, userID uuid.UUID) ([]models.NotificationPreference, error)
+	// SavePreferences menyimpan (insert atau update) preferensi.
+	SavePreferences(ctx context.Context, preferences ...*models.NotificationPreference) error
+}
+
+// ReminderRepository menyimpan pengaturan pengingat deadline per user dan catatan pengingat yang sudah dikirim.
+type ReminderRepository interface {
+	// FindSetting mengembalikan ErrNotFound jika user belum pernah menyimpan pengaturannya.
+	FindSetting(ctx context.Context, userID uuid.UUID) (models.ReminderSetting, error)
+	// SaveSetting membuat atau menimpa pengaturan user.
+	SaveSetting(ctx context.Context, setting *models.ReminderSetting) error
+	// ListDigestSettings mengembalikan pengaturan semua user yang mengaktifkan digest harian.
+	ListDigestSettings(ctx context.Context) ([]models.ReminderSetting, error)
+
+	// Claim mencatat pengingat sebelum dikirim. Hasil false berarti Key sudah tercatat, misalnya karena
+	// instance lain sudah mengirimnya.
+	Claim(ctx context.Context, reminder *models.SentReminder) (bool, error)
+	// Release menghapus catatan pengingat yang gagal dikirim supaya dicoba lagi.
+	Release(ctx context.Context, key string) error
+	// DeleteSentBefore menghapus catatan pengingat yang dikirim sebelum before.
+	DeleteSentBefore(ctx context.Context, before time.Time) error
+}
+
+// CalendarFeedRepository menyimpan URL rahasia feed iCalendar. Setiap user punya paling banyak satu feed per
+// proyek dan satu feed semua proyek (ProjectID uuid.Nil).
+type CalendarFeedRepository interface {
+	// Save membuat feed, atau mengganti token dan waktu pembuatan feed yang sudah ada untuk user dan proyek
+	// yang sama.
+	Save(ctx context.Context, feed *models.CalendarFeed) error
+	Find(ctx context.Context, userID, projectID uuid.UUID) (models.CalendarFeed, error)
+	FindByTokenHash(ctx context.Context, tokenHash string) (models.CalendarFeed, error)
+	// Delete menghapus feed; hasil false berarti feed tidak ada.
+	Delete(ctx context.Context, userID, projectID uuid.UUID) (bool, error)
+	DeleteByProject(ctx context.Context, projectID uuid.UUID) error
+	Touch(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+// ImportJobRepository menyimpan job import latar belakang. Semua method yang mengembalikan job tidak
+// memuat Data dan Report kecuali disebutkan.
+type ImportJobRepository interface {
+	Create(ctx context.Context, job *models.ImportJob) error
+	// Find memuat Report, tetapi tidak Data.
+	Find(ctx context.Context, id uuid.UUID) (models.ImportJob, error)
+	// ListByUser mengembalikan paling banyak limit job milik userID, terbaru lebih dulu.
+	ListByUser(ctx context.Context, userID uuid.UUID, limit int) ([]models.ImportJob, error)
+	// ListDue mengembalikan job yang menunggu diproses: queued, atau running yang lease-nya sudah habis
+	// (instance pemrosesnya mati di tengah jalan). Yang paling lama lebih dulu.
+	ListDue(ctx context.Context, now time.Time, limit int) ([]models.ImportJob, error)
+	// Claim menandai job yang masih menunggu (lihat ListDue) sebagai running sampai leaseUntil dan menaikkan
+	// Attempts. Hasil false berarti job sudah diambil instance lain.
+	Claim(ctx context.Context, id uuid.UUID, now, leaseUntil time.Time) (bool, error)
+	LoadData(ctx context.Context, id uuid.UUID) ([]byte, error)
+	Update(ctx context.Context, id uuid.UUID, updates Updates) error
+	// DeleteFinishedBefore menghapus job yang selesai sebelum before.
+	DeleteFinishedBefore(ctx context.Context, before time.Time) error
+}
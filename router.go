@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+
+	"taskify/cache"
+	"taskify/config"
+	"taskify/metrics"
+	"taskify/middlewares"
+	"taskify/realtime"
+	"taskify/repository"
+	"taskify/routes"
+	"taskify/service"
+	"taskify/tracing"
+	"taskify/usecase"
+	"taskify/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+// streamClientBuffer adalah jumlah event yang boleh menumpuk untuk satu client stream sebelum client itu
+// dianggap terlalu lambat dan diputus (lalu tersambung ulang dengan Last-Event-ID).
+const streamClientBuffer = 64
+
+// app adalah aplikasi yang sudah dirakit: router HTTP beserta worker latar belakangnya.
+type app struct {
+	router     *gin.Engine
+	dispatcher *service.WebhookDispatcher
+	reminders  *service.ReminderService
+	imports    *service.ImportJobService
+	hub        *realtime.Hub
+	remind     bool // Jalankan scheduler pengingat di instance ini
+	importJobs bool // Jalankan worker import di instance ini
+}
+
+// startWorkers menjalankan worker latar belakang app di bawah workers, sehingga ikut berhenti saat shutdown.
+func (a *app) startWorkers(workers *backgroundWorkers) {
+	workers.Go("webhook-dispatcher", a.dispatcher.Run)
+	if a.remind {
+		workers.Go("reminder-scheduler", a.reminders.Run)
+	}
+	if a.importJobs {
+		workers.Go("import-worker", a.imports.Run)
+	}
+}
+
+// closeStreams memutus semua stream real-time yang terbuka. Didaftarkan ke http.Server.RegisterOnShutdown
+// supaya koneksi SSE dan WebSocket tidak menahan graceful shutdown sampai SHUTDOWN_TIMEOUT.
+func (a *app) closeStreams() {
+	a.hub.Close()
+}
+
+// newApp menyusun dependency (repository GORM -> service -> handler) di atas db, store, dan broker sesuai
+// cfg, lalu mendaftarkan semua rute di bawah /api. Dipakai oleh main dan oleh integration test.
+func newApp(cfg config.Config, db *gorm.DB, store cache.Store, broker realtime.Broker) (*app, error) {
+	// Setiap query GORM dicatat ke metrik Prometheus dan menjadi child span dari request-nya
+	if err := db.Use(metrics.GormPlugin{}); err != nil {
+		return nil, err
+	}
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	users := repository.NewGormUserRepository(db)
+	projects := repository.NewGormProjectRepository(db)
+	tasks := repository.NewGormTaskRepository(db)
+	templates := repository.NewGormTemplateRepository(db)
+	outbox := repository.NewGormOutboxRepository(db)
+	webhooks := repository.NewGormWebhookRepository(db)
+	notifications := service.NewNotificationService(projects, tasks, repository.NewGormNotificationRepository(db), store)
+	calendar := service.NewCalendarService(projects, tasks, repository.NewGormCalendarFeedRepository(db), store)
+	tx := repository.NewGormTransactor(db)
+
+	mailer, err := config.ConnectMailer(cfg.Mail)
+	if err != nil {
+		return nil, err
+	}
+	reminders := service.NewReminderService(users, tasks, repository.NewGormReminderRepository(db), mailer, cfg.Reminder)
+
+	hub := realtime.NewHub(broker, streamClientBuffer)
+	events := service.NewEventStreamService(projects, outbox, hub, store)
+
+	tokens := utils.NewTokenManager(cfg.Auth.JWTSecret, cfg.Auth.TokenTTL, store)
+
+	projectService := service.NewProjectService(users, projects, tasks, outbox, tx, store, cfg.API.ImportMaxRows)
+	imports := service.NewImportJobService(repository.NewGormImportJobRepository(db), projectService, cfg.Import)
+
+	authHandler := usecase.NewAuthHandler(service.NewAuthService(users, tokens))
+	projectHandler := usecase.NewProjectHandler(projectService, cfg.API.RequireIfMatch, cfg.API.ImportMaxBytes)
+	taskHandler := usecase.NewTaskHandler(service.NewTaskService(projects, tasks, outbox, tx, store, cfg.API.BulkMaxOperations), cfg.API.RequireIfMatch)
+	templateHandler := usecase.NewTemplateHandler(service.NewTemplateService(templates, projects, tasks, tx))
+	webhookHandler := usecase.NewWebhookHandler(service.NewWebhookService(projects, webhooks, store, cfg.Webhook.AllowPrivateTargets))
+	notificationHandler := usecase.NewNotificationHandler(notifications)
+	reminderHandler := usecase.NewReminderHandler(reminders)
+	calendarHandler := usecase.NewCalendarHandler(calendar)
+	importHandler := usecase.NewImportJobHandler(imports, cfg.API.ImportMaxBytes)
+	eventHandler := usecase.NewEventStreamHandler(events, cfg.Stream.HeartbeatInterval)
+	collabHandler := usecase.NewCollabHandler(
+		service.NewCollabService(projects, tasks, hub, store, cfg.Collab.LockTTL, cfg.Collab.PresenceTTL),
+		events, tokens, cfg.Collab.PingInterval, cfg.Collab.AuthRecheckInterval,
+	)
+	healthHandler := usecase.NewHealthHandler(readinessChecks(db, store)...)
+
+	registry := metrics.NewRegistry(
+		collectors.NewDBStatsCollector(sqlDB, db.Dialector.Name()),
+		metrics.NewTaskStatusCollector(tasks),
+	)
+
+	// Tracing, log request, dan metrik dipasang sebelum Recovery supaya request yang panic tetap
+	// tercatat sebagai 500; RequestLogger setelah tracing agar log membawa trace_id
+	router := gin.New()
+	router.Use(tracing.Middleware(), middlewares.RequestLogger(), metrics.Middleware(), middlewares.Recovery())
+
+	routes.HealthRoutes(router, healthHandler)
+	routes.MetricsRoutes(router, metrics.Handler(registry))
+
+	mw := routes.Middlewares{
+		Auth:          middlewares.AuthMiddleware(tokens),
+		Idempotency:   middlewares.IdempotencyMiddleware(store, cfg.API.IdempotencyTTL),
+		AuthRateLimit: middlewares.AuthRateLimitMiddleware(store, cfg.Auth.RateLimit),
+		WebSocketAuth: middlewares.WebSocketTokenMiddleware(),
+	}
+
+	api := router.Group("/api")
+	{
+		routes.AuthRoutes(api, authHandler, mw)
+		routes.ProjectRoutes(api, projectHandler, templateHandler, mw)
+		routes.TaskRoutes(api, taskHandler, mw)
+		routes.TemplateRoutes(api, templateHandler, mw)
+		routes.WebhookRoutes(api, webhookHandler, mw)
+		routes.NotificationRoutes(api, notificationHandler, mw)
+		routes.ReminderRoutes(api, reminderHandler, mw)
+		routes.CalendarRoutes(api, calendarHandler, mw)
+		routes.ImportRoutes(api, importHandler, mw)
+		routes.EventRoutes(api, eventHandler, mw)
+		routes.CollabRoutes(api, collabHandler, mw)
+	}
+
+	return &app{
+		router:     router,
+		dispatcher: service.NewWebhookDispatcher(outbox, webhooks, tx, events, cfg.Webhook, notifications, calendar),
+		reminders:  reminders,
+		imports:    imports,
+		hub:        hub,
+		remind:     cfg.Reminder.Enabled,
+		importJobs: cfg.Import.Enabled,
+	}, nil
+}
+
+// readinessChecks menentukan dependency yang harus bisa dihubungi sebelum instance menerima traffic.
+func readinessChecks(db *gorm.DB, store cache.Store) []usecase.HealthCheck {
+	checks := []usecase.HealthCheck{{
+		Name: "database",
+		Check: func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		},
+	}}
+	if store != nil {
+		checks = append(checks, usecase.HealthCheck{Name: "cache", Check: store.Ping})
+	}
+	return checks
+}
@@ -7,10 +7,22 @@ import (
 )
 
 // AuthRoutes mengatur rute-rute yang berkaitan dengan autentikasi
-func AuthRoutes(api *gin.RouterGroup) {
+func AuthRoutes(api *gin.RouterGroup, h *usecase.AuthHandler, mw Middlewares) {
 	auth := api.Group("/auth")
 	{
-		auth.POST("/register", usecase.Register)
-		auth.POST("/login", usecase.Login)
+		// Endpoint publik dibatasi per IP untuk mencegah brute force
+		public := auth.Group("/")
+		public.Use(mw.AuthRateLimit)
+		public.POST("/register", mw.Idempotency, h.Register) // Dukungan Idempotency-Key untuk POST, di-scope per client
+		// Login tidak memakai Idempotency-Key: response-nya berisi token, yang tidak boleh disimpan di cache
+		// dan diputar ulang (misalnya setelah token itu dicabut lewat logout)
+		public.POST("/login", h.Login)
+
+		authenticated := auth.Group("/")
+		authenticated.Use(mw.Auth)
+		// Logout tidak memakai Idempotency-Key: setelah berhasil tokennya dicabut, jadi retry sudah ditolak
+		// AuthMiddleware (401) sebelum key sempat diperiksa. Client memperlakukan 401 pada retry logout sebagai
+		// berhasil
+		authenticated.POST("/logout", h.Logout)
 	}
 }
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"taskify/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CalendarRoutes mengatur rute feed kalender iCalendar
+func CalendarRoutes(api *gin.RouterGroup, h *usecase.CalendarHandler, mw Middlewares) {
+	// Feed kalender diotorisasi dengan token rahasia di URL karena aplikasi kalender tidak mengirim Authorization
+	api.GET("/calendar/:token", h.ServeCalendarFeed)
+
+	// Group untuk endpoint yang memerlukan otentikasi
+	authenticated := api.Group("/")
+	authenticated.Use(mw.Auth) // Terapkan AuthMiddleware
+	// Tanpa Idempotency-Key: response POST berisi token feed, yang tidak boleh disimpan di cache dan diputar
+	// ulang (misalnya setelah feed itu dibuat ulang atau dicabut)
+
+	{
+		authenticated.GET("/me/calendar-feed", h.GetCalendarFeed)
+		authenticated.POST("/me/calendar-feed", h.RegenerateCalendarFeed)
+		authenticated.DELETE("/me/calendar-feed", h.RevokeCalendarFeed)
+
+		authenticated.GET("/projects/:project_id/calendar-feed", h.GetCalendarFeed)
+		authenticated.POST("/projects/:project_id/calendar-feed", h.RegenerateCalendarFeed)
+		authenticated.DELETE("/projects/:project_id/calendar-feed", h.RevokeCalendarFeed)
+	}
+}
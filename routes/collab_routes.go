@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"taskify/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CollabRoutes mengatur rute channel kolaborasi WebSocket
+func CollabRoutes(api *gin.RouterGroup, h *usecase.CollabHandler, mw Middlewares) {
+	// Group untuk endpoint yang memerlukan otentikasi; browser mengirim token lewat subprotocol WebSocket
+	authenticated := api.Group("/")
+	authenticated.Use(mw.WebSocketAuth, mw.Auth)
+
+	{
+		authenticated.GET("/projects/:project_id/ws", h.ProjectSocket)
+	}
+}
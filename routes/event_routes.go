@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"taskify/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EventRoutes mengatur rute stream event real-time
+func EventRoutes(api *gin.RouterGroup, h *usecase.EventStreamHandler, mw Middlewares) {
+	// Group untuk endpoint yang memerlukan otentikasi
+	authenticated := api.Group("/")
+	authenticated.Use(mw.Auth) // Terapkan AuthMiddleware
+
+	{
+		authenticated.GET("/projects/:project_id/events", h.StreamProjectEvents)
+	}
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"taskify/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthRoutes mengatur probe liveness dan readiness. Rute ini berada di luar /api dan tanpa autentikasi.
+func HealthRoutes(router gin.IRoutes, h *usecase.HealthHandler) {
+	router.GET("/healthz", h.Liveness)
+	router.GET("/readyz", h.Readiness)
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"taskify/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportRoutes mengatur rute import proyek di latar belakang
+func ImportRoutes(api *gin.RouterGroup, h *usecase.ImportJobHandler, mw Middlewares) {
+	// Group untuk endpoint yang memerlukan otentikasi
+	authenticated := api.Group("/")
+	authenticated.Use(mw.Auth)        // Terapkan AuthMiddleware
+	authenticated.Use(mw.Idempotency) // Dukungan Idempotency-Key untuk POST (butuh userID dari AuthMiddleware)
+
+	{
+		authenticated.POST("/imports", h.CreateImportJob)
+		authenticated.GET("/imports", h.ListImportJobs)
+		authenticated.GET("/imports/:import_id", h.GetImportJob)
+	}
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsRoutes menyajikan metrik Prometheus di /metrics, di luar /api dan tanpa autentikasi.
+// Batasi aksesnya di level jaringan (misalnya hanya dari Prometheus) jika server terbuka ke publik.
+func MetricsRoutes(router gin.IRoutes, handler http.Handler) {
+	router.GET("/metrics", gin.WrapH(handler))
+}
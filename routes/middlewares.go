@@ -0,0 +1,12 @@
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// Middlewares berisi middleware bersama yang sudah dikonfigurasi (secret JWT, cache, TTL) oleh newApp,
+// sehingga setiap kelompok rute memakai instance yang sama.
+type Middlewares struct {
+	Auth          gin.HandlerFunc // Wajib login dengan Bearer token
+	Idempotency   gin.HandlerFunc // Dukungan header Idempotency-Key untuk POST
+	AuthRateLimit gin.HandlerFunc // Pembatasan per IP untuk endpoint autentikasi publik
+	WebSocketAuth gin.HandlerFunc // Token dari subprotocol WebSocket, dipasang sebelum Auth
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"taskify/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationRoutes mengatur rute inbox notifikasi, preferensi notifikasi, dan watcher tugas
+func NotificationRoutes(api *gin.RouterGroup, h *usecase.NotificationHandler, mw Middlewares) {
+	// Group untuk endpoint yang memerlukan otentikasi
+	authenticated := api.Group("/")
+	authenticated.Use(mw.Auth)        // Terapkan AuthMiddleware
+	authenticated.Use(mw.Idempotency) // Dukungan Idempotency-Key untuk POST (butuh userID dari AuthMiddleware)
+
+	{
+		authenticated.GET("/me/notifications", h.GetNotifications)
+		authenticated.POST("/me/notifications/read-all", h.MarkAllNotificationsRead)
+		authenticated.POST("/me/notifications/:notification_id/read", h.MarkNotificationRead)
+		authenticated.GET("/me/notification-preferences", h.GetNotificationPreferences)
+		authenticated.PUT("/me/notification-preferences", h.UpdateNotificationPreferences)
+
+		authenticated.GET("/projects/:project_id/tasks/:task_id/watchers", h.GetTaskWatchers)
+		authenticated.PUT("/projects/:project_id/tasks/:task_id/watch", h.WatchTask)
+		authenticated.DELETE("/projects/:project_id/tasks/:task_id/watch", h.UnwatchTask)
+	}
+}
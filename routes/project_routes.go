@@ -1,23 +1,34 @@
 package routes
 
 import (
-	"taskify/middlewares" // Pastikan import middlewares
 	"taskify/usecase"
 
 	"github.com/gin-gonic/gin"
 )
 
 // ProjectRoutes mengatur rute-rute yang berkaitan dengan proyek
-func ProjectRoutes(api *gin.RouterGroup) {
+func ProjectRoutes(api *gin.RouterGroup, h *usecase.ProjectHandler, templates *usecase.TemplateHandler, mw Middlewares) {
 	// Group untuk endpoint yang memerlukan otentikasi
 	authenticated := api.Group("/")
-	authenticated.Use(middlewares.AuthMiddleware()) // Terapkan AuthMiddleware
+	authenticated.Use(mw.Auth)        // Terapkan AuthMiddleware
+	authenticated.Use(mw.Idempotency) // Dukungan Idempotency-Key untuk POST (butuh userID dari AuthMiddleware)
 
 	{
-		authenticated.POST("/projects", usecase.CreateProject)
-		authenticated.GET("/projects", usecase.GetProjects)
-		authenticated.GET("/projects/detail/:id", usecase.GetProjectByID)
-		authenticated.PUT("/projects/detail/:id", usecase.UpdateProject)
-		authenticated.DELETE("/projects/detail/:id", usecase.DeleteProject)
+		authenticated.POST("/projects", h.CreateProject)
+		authenticated.GET("/projects", h.GetProjects)
+		authenticated.GET("/projects/detail/:id", h.GetProjectByID)
+		authenticated.PUT("/projects/detail/:id", h.UpdateProject)
+		authenticated.PATCH("/projects/detail/:id", h.PatchProject)
+		authenticated.DELETE("/projects/detail/:id", h.DeleteProject)
+		authenticated.POST("/projects/detail/:id/archive", h.ArchiveProject)
+		authenticated.POST("/projects/detail/:id/unarchive", h.UnarchiveProject)
+		authenticated.POST("/projects/:project_id/duplicate", h.DuplicateProject)
+		authenticated.POST("/projects/:project_id/template", templates.SaveProjectAsTemplate)
+		authenticated.GET("/projects/:project_id/export", h.ExportProject)
+		authenticated.POST("/projects/import", h.ImportProject)
+
+		authenticated.GET("/projects/:project_id/members", h.GetProjectMembers)
+		authenticated.POST("/projects/:project_id/members", h.AddProjectMember)
+		authenticated.DELETE("/projects/:project_id/members/:user_id", h.RemoveProjectMember)
 	}
 }
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"taskify/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReminderRoutes mengatur rute pengaturan pengingat deadline
+func ReminderRoutes(api *gin.RouterGroup, h *usecase.ReminderHandler, mw Middlewares) {
+	// Group untuk endpoint yang memerlukan otentikasi
+	authenticated := api.Group("/")
+	authenticated.Use(mw.Auth) // Terapkan AuthMiddleware
+
+	{
+		authenticated.GET("/me/reminder-settings", h.GetReminderSettings)
+		authenticated.PUT("/me/reminder-settings", h.UpdateReminderSettings)
+	}
+}
@@ -1,24 +1,29 @@
 package routes
 
 import (
-	"taskify/middlewares" // Pastikan import middlewares
 	"taskify/usecase"
 
 	"github.com/gin-gonic/gin"
 )
 
 // TaskRoutes mengatur rute-rute yang berkaitan dengan tugas
-func TaskRoutes(api *gin.RouterGroup) {
+func TaskRoutes(api *gin.RouterGroup, h *usecase.TaskHandler, mw Middlewares) {
 	// Group untuk endpoint yang memerlukan otentikasi
 	authenticated := api.Group("/")
-	authenticated.Use(middlewares.AuthMiddleware()) // Terapkan AuthMiddleware
+	authenticated.Use(mw.Auth)        // Terapkan AuthMiddleware
+	authenticated.Use(mw.Idempotency) // Dukungan Idempotency-Key untuk POST (butuh userID dari AuthMiddleware)
 
 	{
 		// Rute Tasks di bawah Project
-		authenticated.POST("/projects/:project_id/tasks", usecase.CreateTask)
-		authenticated.GET("/projects/:project_id/tasks", usecase.GetTasksByProject)
-		authenticated.GET("/projects/:project_id/tasks/:task_id", usecase.GetTaskByID)
-		authenticated.PUT("/projects/:project_id/tasks/:task_id", usecase.UpdateTask)
-		authenticated.DELETE("/projects/:project_id/tasks/:task_id", usecase.DeleteTask)
+		authenticated.POST("/projects/:project_id/tasks", h.CreateTask)
+		authenticated.GET("/projects/:project_id/tasks", h.GetTasksByProject)
+		authenticated.POST("/projects/:project_id/tasks/bulk", h.BulkTasks)
+		authenticated.GET("/projects/:project_id/tasks/:task_id", h.GetTaskByID)
+		authenticated.PUT("/projects/:project_id/tasks/:task_id", h.UpdateTask)
+		authenticated.PATCH("/projects/:project_id/tasks/:task_id", h.PatchTask)
+		authenticated.DELETE("/projects/:project_id/tasks/:task_id", h.DeleteTask)
+		authenticated.POST("/projects/:project_id/tasks/:task_id/move", h.MoveTask)
+		authenticated.POST("/projects/:project_id/tasks/:task_id/copy", h.CopyTask)
+		authenticated.GET("/projects/:project_id/tasks/:task_id/history", h.GetTaskHistory)
 	}
 }
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"taskify/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TemplateRoutes mengatur rute-rute yang berkaitan dengan template proyek
+func TemplateRoutes(api *gin.RouterGroup, h *usecase.TemplateHandler, mw Middlewares) {
+	// Group untuk endpoint yang memerlukan otentikasi
+	authenticated := api.Group("/")
+	authenticated.Use(mw.Auth)        // Terapkan AuthMiddleware
+	authenticated.Use(mw.Idempotency) // Dukungan Idempotency-Key untuk POST (butuh userID dari AuthMiddleware)
+
+	{
+		authenticated.GET("/templates", h.GetTemplates)
+		authenticated.GET("/templates/:template_id", h.GetTemplateByID)
+		authenticated.DELETE("/templates/:template_id", h.DeleteTemplate)
+		authenticated.POST("/templates/:template_id/instantiate", h.InstantiateTemplate)
+	}
+}
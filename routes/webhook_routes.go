@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"taskify/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookRoutes mengatur rute-rute yang berkaitan dengan webhook proyek
+func WebhookRoutes(api *gin.RouterGroup, h *usecase.WebhookHandler, mw Middlewares) {
+	// Group untuk endpoint yang memerlukan otentikasi
+	authenticated := api.Group("/")
+	authenticated.Use(mw.Auth) // Terapkan AuthMiddleware
+
+	{
+		// Pembuatan webhook tidak memakai Idempotency-Key: response-nya berisi signing secret, yang tidak
+		// boleh disimpan di cache
+		authenticated.POST("/projects/:project_id/webhooks", h.CreateWebhook)
+		authenticated.GET("/projects/:project_id/webhooks", h.GetWebhooks)
+		authenticated.GET("/projects/:project_id/webhooks/:webhook_id", h.GetWebhookByID)
+		authenticated.PATCH("/projects/:project_id/webhooks/:webhook_id", h.PatchWebhook)
+		authenticated.DELETE("/projects/:project_id/webhooks/:webhook_id", h.DeleteWebhook)
+		authenticated.GET("/projects/:project_id/webhooks/:webhook_id/deliveries", h.GetWebhookDeliveries)
+		authenticated.POST("/projects/:project_id/webhooks/:webhook_id/deliveries/:delivery_id/redeliver", mw.Idempotency, h.RedeliverWebhook) // Dukungan Idempotency-Key untuk POST
+	}
+}
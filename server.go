@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"taskify/config"
+)
+
+// backgroundWorkers menjalankan goroutine latar belakang (dispatcher, scheduler, dan sejenisnya) yang ikut
+// dihentikan saat shutdown: context-nya dibatalkan lalu ditunggu sampai selesai.
+type backgroundWorkers struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newBackgroundWorkers() *backgroundWorkers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &backgroundWorkers{ctx: ctx, cancel: cancel}
+}
+
+// Go menjalankan fn di goroutine baru. fn harus berhenti begitu ctx dibatalkan.
+func (w *backgroundWorkers) Go(name string, fn func(ctx context.Context)) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		fn(w.ctx)
+		slog.Info("background worker stopped", "worker", name)
+	}()
+}
+
+// Shutdown membatalkan context worker dan menunggu semuanya selesai, paling lama sampai ctx berakhir.
+func (w *backgroundWorkers) Shutdown(ctx context.Context) error {
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// newHTTPServer membungkus handler dengan timeout dari ServerConfig, supaya client lambat tidak
+// menahan koneksi selamanya.
+func newHTTPServer(cfg config.ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+// serve melayani request di listener sampai ctx dibatalkan (SIGINT/SIGTERM), lalu berhenti menerima koneksi
+// baru, menunggu request yang sedang berjalan dan worker latar belakang selesai, paling lama shutdownTimeout.
+func serve(ctx context.Context, server *http.Server, listener net.Listener, workers *backgroundWorkers, shutdownTimeout time.Duration) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		// Server berhenti sendiri sebelum ada sinyal: hentikan worker lalu laporkan errornya
+		workers.cancel()
+		workers.wg.Wait()
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down, draining in-flight requests", "timeout", shutdownTimeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		errs = append(errs, err)
+	}
+	if err := workers.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"taskify/config"
+)
+
+func TestServeDrainsInFlightRequestsAndWorkers(t *testing.T) {
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		io.WriteString(w, "done")
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newHTTPServer(config.ServerConfig{ReadTimeout: time.Second, WriteTimeout: time.Second}, handler)
+
+	var workerStopped atomic.Bool
+	workers := newBackgroundWorkers()
+	workers.Go("test", func(ctx context.Context) {
+		<-ctx.Done()
+		workerStopped.Store(true)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- serve(ctx, server, listener, workers, 5*time.Second)
+	}()
+
+	type result struct {
+		body string
+		err  error
+	}
+	response := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err != nil {
+			response <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		response <- result{body: string(body), err: err}
+	}()
+
+	// Sinyal shutdown datang saat request masih diproses
+	<-started
+	cancel()
+
+	got := <-response
+	if got.err != nil || got.body != "done" {
+		t.Fatalf("in-flight request was not drained: body=%q err=%v", got.body, got.err)
+	}
+	if err := <-served; err != nil {
+		t.Fatalf("serve returned error: %v", err)
+	}
+	if !workerStopped.Load() {
+		t.Fatal("background worker was not stopped")
+	}
+
+	// Setelah shutdown, koneksi baru ditolak
+	if _, err := http.Get("http://" + listener.Addr().String()); err == nil {
+		t.Fatal("expected new connections to be refused after shutdown")
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"taskify/models"
+	"taskify/repository"
+	"taskify/utils"
+)
+
+// AuthService menangani pendaftaran dan login user.
+type AuthService struct {
+	users  repository.UserRepository
+	tokens *utils.TokenManager
+}
+
+// NewAuthService membuat AuthService di atas repository user dan TokenManager untuk menerbitkan JWT.
+func NewAuthService(users repository.UserRepository, tokens *utils.TokenManager) *AuthService {
+	return &AuthService{users: users, tokens: tokens}
+}
+
+// Register membuat akun baru dengan password yang sudah di-hash.
+func (s *AuthService) Register(ctx context.Context, name, email, password string) (models.User, error) {
+	if _, err := s.users.FindByEmail(ctx, email); err == nil {
+		return models.User{}, ErrEmailTaken
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return models.User{}, err
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	user := models.User{
+		ID:       uuid.New(),
+		Name:     name,
+		Email:    email,
+		Password: hashedPassword,
+	}
+	if err := s.users.Create(ctx, &user); err != nil {
+		// Dua pendaftaran bersamaan dengan email yang sama lolos dari pengecekan di atas
+		if errors.Is(err, repository.ErrDuplicate) {
+			return models.User{}, ErrEmailTaken
+		}
+		return models.User{}, err
+	}
+	return user, nil
+}
+
+// Login memverifikasi email dan password lalu mengembalikan JWT untuk user tersebut.
+func (s *AuthService) Login(ctx context.Context, email, password string) (string, models.User, error) {
+	user, err := s.users.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", models.User{}, ErrInvalidCredentials
+		}
+		return "", models.User{}, err
+	}
+
+	if !utils.CheckPasswordHash(password, user.Password) {
+		return "", models.User{}, ErrInvalidCredentials
+	}
+
+	token, err := s.tokens.Generate(user.ID)
+	if err != nil {
+		return "", models.User{}, err
+	}
+	return token, user, nil
+}
+
+// Logout mencabut token yang sedang dipakai sehingga tidak bisa digunakan lagi walaupun belum kedaluwarsa.
+func (s *AuthService) Logout(ctx context.Context, claims *utils.JWTClaims) error {
+	return s.tokens.Revoke(ctx, claims)
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/cache"
+	"taskify/models"
+	"taskify/repository"
+)
+
+// calendarTokenPrefix menandai token feed kalender supaya mudah dikenali jika bocor, seperti "whsec_" pada
+// secret webhook.
+const calendarTokenPrefix = "cal_"
+
+// CalendarFeedData adalah isi satu feed kalender: nama kalender dan tugas berdeadline di dalamnya.
+type CalendarFeedData struct {
+	Name  string
+	Tasks []models.Task
+}
+
+// CalendarService mengelola URL rahasia feed iCalendar per user dan per proyek, dan mengambil tugas
+// berdeadline untuk feed tersebut. Feed proyek ikut dihapus saat proyeknya dihapus lewat ConsumeEvent.
+type CalendarService struct {
+	feeds  repository.CalendarFeedRepository
+	tasks  repository.TaskRepository
+	access projectAccess
+}
+
+// NewCalendarService membuat CalendarService. projectCache boleh nil jika cache akses tidak dipakai.
+func NewCalendarService(projects repository.ProjectRepository, tasks repository.TaskRepository, feeds repository.CalendarFeedRepository, projectCache cache.Store) *CalendarService {
+	return &CalendarService{
+		feeds:  feeds,
+		tasks:  tasks,
+		access: projectAccess{projects: projects, cache: projectCache},
+	}
+}
+
+// Feed mengembalikan feed userID untuk projectID (uuid.Nil untuk feed semua proyek).
+func (s *CalendarService) Feed(ctx context.Context, userID, projectID uuid.UUID) (models.CalendarFeed, error) {
+	if err := s.checkScope(ctx, userID, projectID); err != nil {
+		return models.CalendarFeed{}, err
+	}
+	feed, err := s.feeds.Find(ctx, userID, projectID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.CalendarFeed{}, ErrCalendarFeedNotFound
+	}
+	return feed, err
+}
+
+// Regenerate membuat feed baru, atau mengganti token feed yang sudah ada sehingga URL lamanya berhenti
+// berfungsi. Token hanya dikembalikan di sini.
+func (s *CalendarService) Regenerate(ctx context.Context, userID, projectID uuid.UUID) (models.CalendarFeed, string, error) {
+	if err := s.checkScope(ctx, userID, projectID); err != nil {
+		return models.CalendarFeed{}, "", err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return models.CalendarFeed{}, "", err
+	}
+	token := calendarTokenPrefix + hex.EncodeToString(raw)
+	feed := models.CalendarFeed{UserID: userID, ProjectID: projectID, TokenHash: hashCalendarToken(token), CreatedAt: time.Now()}
+	if err := s.feeds.Save(ctx, &feed); err != nil {
+		return models.CalendarFeed{}, "", err
+	}
+	return feed, token, nil
+}
+
+// Revoke menghapus feed sehingga URL-nya berhenti berfungsi.
+func (s *CalendarService) Revoke(ctx context.Context, userID, projectID uuid.UUID) error {
+	if err := s.checkScope(ctx, userID, projectID); err != nil {
+		return err
+	}
+	deleted, err := s.feeds.Delete(ctx, userID, projectID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrCalendarFeedNotFound
+	}
+	return nil
+}
+
+// Resolve mencari feed dari token di URL lalu mengembalikan isinya: tugas berdeadline di proyek feed, atau
+// di semua proyek yang bisa diakses pemilik feed dan tidak diarsipkan. Token yang tidak dikenal atau sudah dicabut
+// menghasilkan ErrCalendarFeedNotFound.
+func (s *CalendarService) Resolve(ctx context.Context, token string) (CalendarFeedData, error) {
+	if !strings.HasPrefix(token, calendarTokenPrefix) {
+		return CalendarFeedData{}, ErrCalendarFeedNotFound
+	}
+	feed, err := s.feeds.FindByTokenHash(ctx, hashCalendarToken(token))
+	if errors.Is(err, repository.ErrNotFound) {
+		return CalendarFeedData{}, ErrCalendarFeedNotFound
+	}
+	if err != nil {
+		return CalendarFeedData{}, err
+	}
+
+	var data CalendarFeedData
+	if feed.ProjectID == uuid.Nil {
+		data.Name = "Taskify"
+		if data.Tasks, err = s.tasks.ListByDeadline(ctx, repository.DeadlineFilter{UserID: feed.UserID}); err != nil {
+			return CalendarFeedData{}, err
+		}
+	} else {
+		// Feed proyek yang proyeknya sudah tidak bisa diakses pemilik feed dianggap tidak ada
+		project, err := s.access.findMember(ctx, feed.ProjectID, feed.UserID)
+		if errors.Is(err, ErrProjectNotFound) {
+			return CalendarFeedData{}, ErrCalendarFeedNotFound
+		}
+		if err != nil {
+			return CalendarFeedData{}, err
+		}
+		tasks, err := s.tasks.ListByProject(ctx, project.ID)
+		if err != nil {
+			return CalendarFeedData{}, err
+		}
+		data.Name = "Taskify: " + project.Name
+		for _, task := range tasks {
+			if task.Deadline != nil {
+				data.Tasks = append(data.Tasks, task)
+			}
+		}
+	}
+
+	if err := s.feeds.Touch(ctx, feed.ID, time.Now()); err != nil {
+		return CalendarFeedData{}, err
+	}
+	return data, nil
+}
+
+// ConsumeEvent menghapus feed proyek yang dihapus.
+func (s *CalendarService) ConsumeEvent(ctx context.Context, event models.OutboxEvent) error {
+	if event.Type != models.EventProjectDeleted {
+		return nil
+	}
+	return s.feeds.DeleteByProject(ctx, event.ProjectID)
+}
+
+// checkScope memastikan userID pemilik atau anggota projectID; uuid.Nil (semua proyek) selalu boleh.
+func (s *CalendarService) checkScope(ctx context.Context, userID, projectID uuid.UUID) error {
+	if projectID == uuid.Nil {
+		return nil
+	}
+	_, err := s.access.member(ctx, projectID, userID)
+	return err
+}
+
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
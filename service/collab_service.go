@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/cache"
+	"taskify/logging"
+	"taskify/realtime"
+	"taskify/repository"
+)
+
+// Presence adalah keadaan satu koneksi kolaborasi di sebuah proyek: tugas yang sedang dilihat dan tugas yang
+// sedang diedit (soft lock). Satu user bisa punya beberapa koneksi, misalnya di beberapa tab.
+type Presence struct {
+	ConnectionID  string     `json:"connection_id"`
+	UserID        uuid.UUID  `json:"user_id"`
+	ViewingTaskID *uuid.UUID `json:"viewing_task_id"`
+	EditingTaskID *uuid.UUID `json:"editing_task_id"`
+	EditingUntil  *time.Time `json:"editing_until"`
+	ExpiresAt     time.Time  `json:"expires_at"` // Dihapus dari daftar jika tidak diperbarui sampai waktu ini
+}
+
+// TaskLock adalah soft lock "sedang mengedit" sebuah tugas. Lock hanya bersifat pemberitahuan (endpoint REST
+// tetap bisa mengubah tugasnya) dan berakhir sendiri pada Until jika tidak diperpanjang.
+type TaskLock struct {
+	TaskID       uuid.UUID `json:"task_id"`
+	ConnectionID string    `json:"connection_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	Until        time.Time `json:"until"`
+}
+
+// lockAcquireAttempts membatasi percobaan acquireLock saat lock terus berganti di antara langkah-langkahnya.
+const lockAcquireAttempts = 3
+
+// presenceMessage adalah pesan di topic presence proyek. hello dikirim koneksi baru dan dibalas semua
+// koneksi lain dengan state terbarunya, supaya koneksi baru langsung mengenal semua yang sedang hadir.
+type presenceMessage struct {
+	Kind     string   `json:"kind"` // hello, state, atau leave
+	Presence Presence `json:"presence"`
+}
+
+// CollabService mengelola kehadiran (siapa melihat tugas apa) dan soft lock pengeditan di proyek. Kehadiran
+// disiarkan lewat realtime.Hub dan lock disimpan di cache.Store, sehingga keduanya berlaku lintas instance.
+type CollabService struct {
+	hub         *realtime.Hub
+	locks       cache.Store
+	tasks       repository.TaskRepository
+	access      projectAccess
+	lockTTL     time.Duration
+	presenceTTL time.Duration
+}
+
+// NewCollabService membuat CollabService. lockTTL adalah umur soft lock sebelum harus diperpanjang, dan
+// presenceTTL adalah umur kehadiran koneksi yang berhenti mengirim pembaruan (misalnya instance-nya mati).
+func NewCollabService(projects repository.ProjectRepository, tasks repository.TaskRepository, hub *realtime.Hub, store cache.Store, lockTTL, presenceTTL time.Duration) *CollabService {
+	return &CollabService{
+		hub:         hub,
+		locks:       store,
+		tasks:       tasks,
+		access:      projectAccess{projects: projects, cache: store},
+		lockTTL:     lockTTL,
+		presenceTTL: presenceTTL,
+	}
+}
+
+// Join memeriksa akses userID (pemilik atau anggota) ke proyek lalu membuka sesi kolaborasi untuk satu
+// koneksi. Panggil Leave setelah koneksi berakhir.
+func (s *CollabService) Join(ctx context.Context, userID, projectID uuid.UUID) (*CollabSession, error) {
+	if _, err := s.access.member(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	sub, err := s.hub.Subscribe(ctx, presenceTopic(projectID))
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(chan []Presence, 1)
+	session := &CollabSession{
+		ID:        uuid.NewString(),
+		Presence:  updates,
+		service:   s,
+		projectID: projectID,
+		sub:       sub,
+		logger:    logging.FromContext(ctx),
+		roster:    map[string]Presence{},
+		updates:   updates,
+		done:      make(chan struct{}),
+	}
+	session.self = Presence{ConnectionID: session.ID, UserID: userID}
+
+	session.mu.Lock()
+	session.announceLocked(ctx, "hello")
+	session.mu.Unlock()
+
+	go session.run()
+	return session, nil
+}
+
+// CollabSession adalah keadaan kolaborasi satu koneksi. Presence menerima daftar kehadiran terbaru setiap
+// kali berubah dan ditutup saat sesi berakhir; Err menjelaskan sebabnya.
+type CollabSession struct {
+	ID       string
+	Presence <-chan []Presence
+
+	service   *CollabService
+	projectID uuid.UUID
+	sub       *realtime.Subscription
+	logger    *slog.Logger
+
+	mu      sync.Mutex
+	self    Presence
+	roster  map[string]Presence // Koneksi lain, per ConnectionID
+	left    bool
+	updates chan []Presence
+	done    chan struct{}
+	once    sync.Once
+}
+
+// View mencatat tugas yang sedang dilihat koneksi ini; nil berarti tidak sedang melihat tugas tertentu.
+func (cs *CollabSession) View(ctx context.Context, taskID *uuid.UUID) error {
+	if taskID != nil {
+		if err := cs.checkTask(ctx, *taskID); err != nil {
+			return err
+		}
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.self.ViewingTaskID = taskID
+	cs.announceLocked(ctx, "state")
+	return nil
+}
+
+// StartEditing mengambil atau memperpanjang soft lock pengeditan tugas. Jika tugas sedang diedit koneksi
+// lain, ErrTaskLocked dikembalikan bersama lock pemegangnya. Satu koneksi hanya bisa mengedit satu tugas;
+// lock tugas sebelumnya dilepas.
+func (cs *CollabSession) StartEditing(ctx context.Context, taskID uuid.UUID) (TaskLock, error) {
+	project, err := cs.service.access.member(ctx, cs.projectID, cs.self.UserID)
+	if err != nil {
+		return TaskLock{}, err
+	}
+	if project.Archived {
+		return TaskLock{}, ErrProjectArchived
+	}
+	if err := cs.checkTask(ctx, taskID); err != nil {
+		return TaskLock{}, err
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.self.EditingTaskID != nil && *cs.self.EditingTaskID != taskID {
+		cs.releaseLocked(ctx)
+	}
+
+	lock := TaskLock{TaskID: taskID, ConnectionID: cs.ID, UserID: cs.self.UserID, Until: time.Now().Add(cs.service.lockTTL)}
+	if holder, err := cs.service.acquireLock(ctx, lock); err != nil {
+		return holder, err
+	}
+
+	cs.self.EditingTaskID, cs.self.EditingUntil = &lock.TaskID, &lock.Until
+	cs.announceLocked(ctx, "state")
+	return lock, nil
+}
+
+// StopEditing melepas soft lock tugas jika dipegang koneksi ini.
+func (cs *CollabSession) StopEditing(ctx context.Context, taskID uuid.UUID) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.self.EditingTaskID == nil || *cs.self.EditingTaskID != taskID {
+		return
+	}
+	cs.releaseLocked(ctx)
+	cs.announceLocked(ctx, "state")
+}
+
+// Roster mengembalikan daftar kehadiran saat ini, termasuk koneksi ini sendiri.
+func (cs *CollabSession) Roster() []Presence {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.rosterLocked()
+}
+
+// Leave melepas lock, mengumumkan bahwa koneksi ini pergi, lalu mengakhiri sesi. Aman dipanggil lebih dari sekali.
+func (cs *CollabSession) Leave(ctx context.Context) {
+	cs.once.Do(func() {
+		cs.mu.Lock()
+		cs.releaseLocked(ctx)
+		cs.announceLocked(ctx, "leave")
+		cs.left = true
+		cs.mu.Unlock()
+
+		close(cs.done)
+		cs.sub.Close()
+	})
+}
+
+// Err mengembalikan sebab Presence ditutup (lihat realtime.Subscription.Err).
+func (cs *CollabSession) Err() error {
+	return cs.sub.Err()
+}
+
+// run memproses pesan presence dari koneksi lain dan memperbarui kehadiran koneksi ini secara berkala,
+// sebelum kedaluwarsa di instance lain.
+func (cs *CollabSession) run() {
+	defer close(cs.updates)
+
+	ctx := context.Background()
+	refresh := time.NewTicker(cs.service.presenceTTL / 3)
+	defer refresh.Stop()
+
+	for {
+		select {
+		case <-cs.done:
+			return
+
+		case raw, ok := <-cs.sub.C:
+			if !ok {
+				return
+			}
+			var message presenceMessage
+			if err := json.Unmarshal(raw.Data, &message); err != nil {
+				cs.logger.Warn("dropping malformed presence message", "error", err)
+				continue
+			}
+			if message.Presence.ConnectionID == cs.ID {
+				continue
+			}
+
+			cs.mu.Lock()
+			switch message.Kind {
+			case "hello":
+				cs.roster[message.Presence.ConnectionID] = message.Presence
+				cs.announceLocked(ctx, "state")
+			case "state":
+				cs.roster[message.Presence.ConnectionID] = message.Presence
+				cs.notifyLocked()
+			case "leave":
+				delete(cs.roster, message.Presence.ConnectionID)
+				cs.notifyLocked()
+			}
+			cs.mu.Unlock()
+
+		case now := <-refresh.C:
+			cs.mu.Lock()
+			for id, presence := range cs.roster {
+				if now.After(presence.ExpiresAt) {
+					delete(cs.roster, id)
+				}
+			}
+			// Lock yang tidak diperpanjang berakhir sendiri di cache; ikuti di state koneksi ini
+			if cs.self.EditingUntil != nil && now.After(*cs.self.EditingUntil) {
+				cs.self.EditingTaskID, cs.self.EditingUntil = nil, nil
+			}
+			cs.announceLocked(ctx, "state")
+			cs.mu.Unlock()
+		}
+	}
+}
+
+// announceLocked menyiarkan state koneksi ini lalu memberi tahu client lokal. Panggil dengan cs.mu terkunci.
+func (cs *CollabSession) announceLocked(ctx context.Context, kind string) {
+	if cs.left {
+		return
+	}
+	cs.self.ExpiresAt = time.Now().Add(cs.service.presenceTTL)
+	data, err := json.Marshal(presenceMessage{Kind: kind, Presence: cs.self})
+	if err == nil {
+		_, err = cs.service.hub.Publish(ctx, presenceTopic(cs.projectID), data)
+	}
+	if err != nil {
+		cs.logger.Warn("failed to publish presence", "project_id", cs.projectID.String(), "error", err)
+	}
+	if kind != "leave" {
+		cs.notifyLocked()
+	}
+}
+
+// notifyLocked mengirim daftar kehadiran terbaru ke Presence, menggantikan daftar lama yang belum dibaca.
+func (cs *CollabSession) notifyLocked() {
+	select {
+	case <-cs.updates:
+	default:
+	}
+	select {
+	case cs.updates <- cs.rosterLocked():
+	default:
+	}
+}
+
+func (cs *CollabSession) rosterLocked() []Presence {
+	now := time.Now()
+	roster := []Presence{cs.self}
+	for _, presence := range cs.roster {
+		if presence.EditingUntil != nil && now.After(*presence.EditingUntil) {
+			presence.EditingTaskID, presence.EditingUntil = nil, nil
+		}
+		roster = append(roster, presence)
+	}
+	sort.Slice(roster, func(i, j int) bool {
+		if roster[i].UserID != roster[j].UserID {
+			return roster[i].UserID.String() < roster[j].UserID.String()
+		}
+		return roster[i].ConnectionID < roster[j].ConnectionID
+	})
+	return roster
+}
+
+// releaseLocked menghapus lock yang dipegang koneksi ini dari cache, jika masih miliknya.
+func (cs *CollabSession) releaseLocked(ctx context.Context) {
+	if cs.self.EditingTaskID == nil {
+		return
+	}
+	taskID := *cs.self.EditingTaskID
+	cs.self.EditingTaskID, cs.self.EditingUntil = nil, nil
+
+	holder, raw, err := cs.service.currentLock(ctx, taskID)
+	if err != nil || holder.ConnectionID != cs.ID {
+		return
+	}
+	// Hanya dihapus jika belum berganti pemegang sejak dibaca (misalnya kedaluwarsa lalu diambil koneksi lain)
+	if _, err := cs.service.locks.CompareAndDelete(ctx, lockKey(taskID), raw); err != nil {
+		cs.logger.Warn("failed to release task lock", "task_id", taskID.String(), "error", err)
+	}
+}
+
+// checkTask memastikan taskID adalah tugas di proyek sesi ini.
+func (cs *CollabSession) checkTask(ctx context.Context, taskID uuid.UUID) error {
+	task, err := cs.service.tasks.FindByID(ctx, taskID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && task.ProjectID != cs.projectID) {
+		return ErrTaskNotFound
+	}
+	return err
+}
+
+// acquireLock mengambil lock tugas untuk koneksi lock.ConnectionID, atau memperpanjangnya jika koneksi itu
+// sudah memegangnya. Setiap langkah atomik (SetNX atau CompareAndSwap terhadap nilai yang baru dibaca), jadi
+// dua koneksi tidak pernah sama-sama merasa memegang lock. Jika lock dipegang koneksi lain, ErrTaskLocked
+// dikembalikan bersama lock pemegangnya.
+func (s *CollabService) acquireLock(ctx context.Context, lock TaskLock) (TaskLock, error) {
+	encoded, err := json.Marshal(lock)
+	if err != nil {
+		return TaskLock{}, err
+	}
+	key := lockKey(lock.TaskID)
+
+	// Lock bisa kedaluwarsa atau berganti pemegang di antara langkah-langkahnya; ulangi beberapa kali saja
+	for range lockAcquireAttempts {
+		acquired, err := s.locks.SetNX(ctx, key, encoded, s.lockTTL)
+		if err != nil || acquired {
+			return TaskLock{}, err
+		}
+		holder, raw, err := s.currentLock(ctx, lock.TaskID)
+		if err != nil {
+			return TaskLock{}, err
+		}
+		if raw == nil {
+			continue
+		}
+		if holder.ConnectionID != lock.ConnectionID {
+			return holder, ErrTaskLocked
+		}
+		// Perpanjangan lock milik sendiri
+		swapped, err := s.locks.CompareAndSwap(ctx, key, raw, encoded, s.lockTTL)
+		if err != nil || swapped {
+			return TaskLock{}, err
+		}
+	}
+	holder, _, err := s.currentLock(ctx, lock.TaskID)
+	if err != nil {
+		return TaskLock{}, err
+	}
+	return holder, ErrTaskLocked
+}
+
+// currentLock membaca lock tugas dari cache beserta nilai mentahnya untuk CompareAndSwap/CompareAndDelete;
+// lock yang tidak ada dikembalikan sebagai TaskLock kosong dengan raw nil.
+func (s *CollabService) currentLock(ctx context.Context, taskID uuid.UUID) (TaskLock, []byte, error) {
+	raw, err := s.locks.Get(ctx, lockKey(taskID))
+	if errors.Is(err, cache.ErrMiss) {
+		return TaskLock{}, nil, nil
+	}
+	if err != nil {
+		return TaskLock{}, nil, err
+	}
+	var lock TaskLock
+	return lock, raw, json.Unmarshal(raw, &lock)
+}
+
+func presenceTopic(projectID uuid.UUID) string {
+	return "presence:" + projectID.String()
+}
+
+func lockKey(taskID uuid.UUID) string {
+	return "collab:lock:" + taskID.String()
+}
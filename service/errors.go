@@ -0,0 +1,84 @@
+package service
+
+import "fmt"
+
+// Kind mengelompokkan error service supaya lapisan HTTP bisa memilih status code tanpa mengenal
+// setiap error satu per satu.
+type Kind int
+
+const (
+	KindInvalid              Kind = iota + 1 // Input tidak valid (400)
+	KindUnauthorized                         // Kredensial salah (401)
+	KindNotFound                             // Resource tidak ada atau bukan milik user (404)
+	KindConflict                             // Bertentangan dengan keadaan resource saat ini (409)
+	KindPreconditionFailed                   // If-Match tidak cocok atau resource berubah di tengah jalan (412)
+	KindPreconditionRequired                 // If-Match wajib tetapi tidak dikirim (428)
+)
+
+// Error adalah error yang pesannya aman dikirim ke client. Error lain dari service dianggap error server.
+type Error struct {
+	Kind    Kind
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func newError(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// Invalidf membuat error input tidak valid dengan pesan terformat.
+func Invalidf(format string, args ...interface{}) *Error {
+	return newError(KindInvalid, fmt.Sprintf(format, args...))
+}
+
+var (
+	ErrEmailTaken         = newError(KindConflict, "Email already registered")
+	ErrInvalidCredentials = newError(KindUnauthorized, "Invalid credentials")
+	ErrInvalidCreator     = newError(KindInvalid, "Provided created_by ID does not correspond to a valid user")
+
+	ErrProjectNotFound        = newError(KindNotFound, "Project not found or you don't have access")
+	ErrProjectArchived        = newError(KindConflict, "Project is archived and read-only; unarchive it first")
+	ErrProjectAlreadyArchived = newError(KindConflict, "Project is already archived")
+	ErrProjectNotArchived     = newError(KindConflict, "Project is not archived")
+
+	ErrMemberNotFound     = newError(KindNotFound, "Member not found in this project")
+	ErrMemberUserNotFound = newError(KindNotFound, "No user is registered with this email")
+	ErrMemberExists       = newError(KindConflict, "User is already a member of this project")
+	ErrMemberIsOwner      = newError(KindInvalid, "The project owner already has full access")
+
+	ErrTaskNotFound          = newError(KindNotFound, "Task not found in this project or you don't have access")
+	ErrTargetProjectNotFound = newError(KindNotFound, "Target project not found or you don't have access to it")
+	ErrTargetProjectArchived = newError(KindConflict, "Target project is archived and read-only; unarchive it first")
+	ErrTaskInTargetProject   = newError(KindInvalid, "Task is already in the target project")
+	ErrTaskLocked            = newError(KindConflict, "Task is being edited by someone else")
+
+	ErrTemplateNotFound = newError(KindNotFound, "Template not found or you don't have access")
+
+	ErrWebhookNotFound  = newError(KindNotFound, "Webhook not found in this project or you don't have access")
+	ErrWebhookInactive  = newError(KindConflict, "Webhook is inactive; activate it before redelivering")
+	ErrDeliveryNotFound = newError(KindNotFound, "Delivery not found for this webhook")
+
+	ErrNotificationNotFound = newError(KindNotFound, "Notification not found")
+	ErrInvalidCursor        = newError(KindInvalid, "Invalid cursor; use next_cursor from the previous page")
+
+	ErrCalendarFeedNotFound = newError(KindNotFound, "Calendar feed not found")
+
+	ErrImportJobNotFound = newError(KindNotFound, "Import job not found")
+
+	ErrPreconditionRequired = newError(KindPreconditionRequired, "If-Match header is required for this request")
+	ErrPreconditionFailed   = newError(KindPreconditionFailed, "Resource has been modified by another request; refetch it and retry")
+)
+
+// Precondition dipanggil dengan versi resource saat ini sebelum perubahan diterapkan. Error yang
+// dikembalikan (biasanya ErrPreconditionRequired atau ErrPreconditionFailed) membatalkan perubahan.
+type Precondition func(version uint) error
+
+func checkPrecondition(check Precondition, version uint) error {
+	if check == nil {
+		return nil
+	}
+	return check(version)
+}
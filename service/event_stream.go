@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"taskify/cache"
+	"taskify/logging"
+	"taskify/models"
+	"taskify/realtime"
+	"taskify/repository"
+)
+
+// EventReset adalah jenis StreamEvent khusus: sebagian event sudah tidak ada di log (misalnya client terlalu
+// lama terputus), jadi client harus memuat ulang data proyek lewat REST sebelum melanjutkan.
+const EventReset = "reset"
+
+// StreamEvent adalah satu event di stream proyek. ID naik satu per satu per proyek dan dipakai client sebagai
+// Last-Event-ID saat tersambung ulang; Data berisi EventEnvelope dalam JSON.
+type StreamEvent struct {
+	ID   uint64
+	Type string
+	Data []byte
+}
+
+// EventStreamService menyiarkan event proyek ke client real-time (SSE) lewat realtime.Hub, dengan aturan
+// akses yang sama seperti endpoint REST proyek.
+type EventStreamService struct {
+	hub    *realtime.Hub
+	outbox repository.OutboxRepository
+	access projectAccess
+}
+
+// NewEventStreamService membuat EventStreamService. projectCache boleh nil jika cache akses tidak dipakai.
+func NewEventStreamService(projects repository.ProjectRepository, outbox repository.OutboxRepository, hub *realtime.Hub, projectCache cache.Store) *EventStreamService {
+	return &EventStreamService{
+		hub:    hub,
+		outbox: outbox,
+		access: projectAccess{projects: projects, cache: projectCache},
+	}
+}
+
+// PublishEvent menyiarkan event outbox ke stream proyeknya. Dipanggil oleh WebhookDispatcher setelah event
+// dibagikan, sehingga setiap event disiarkan tepat sekali walaupun ada beberapa instance.
+func (s *EventStreamService) PublishEvent(ctx context.Context, event models.OutboxEvent) error {
+	data, err := encodeEnvelope(event)
+	if err != nil {
+		return err
+	}
+	_, err = s.hub.Publish(ctx, projectTopic(event.ProjectID), data)
+	return err
+}
+
+// CheckAccess memastikan userID (pemilik atau anggota) masih boleh membaca proyek. Dipanggil berkala selama
+// stream terbuka. Proyek yang sudah dihapus juga menghasilkan ErrProjectNotFound; bedakan dengan Deletion.
+func (s *EventStreamService) CheckAccess(ctx context.Context, userID, projectID uuid.UUID) error {
+	_, err := s.access.member(ctx, projectID, userID)
+	return err
+}
+
+// Deletion melaporkan apakah proyek sudah dihapus, dan jika ya mengembalikan event project.deleted-nya seperti
+// yang akan disiarkan. Dipakai saat pengecekan ulang akses menemukan proyeknya hilang sebelum worker outbox
+// menyiarkan penghapusannya, supaya client tetap menerima event tersebut. Data kosong berarti event-nya tidak
+// ditemukan lagi.
+func (s *EventStreamService) Deletion(ctx context.Context, projectID uuid.UUID) (StreamEvent, bool, error) {
+	_, err := s.access.fetch(ctx, projectID)
+	if err == nil || !errors.Is(err, ErrProjectNotFound) {
+		return StreamEvent{}, false, err
+	}
+
+	deleted := StreamEvent{Type: models.EventProjectDeleted}
+	event, err := s.outbox.FindLatest(ctx, projectID, models.EventProjectDeleted)
+	if errors.Is(err, repository.ErrNotFound) {
+		return deleted, true, nil
+	}
+	if err != nil {
+		return StreamEvent{}, false, err
+	}
+	if deleted.Data, err = encodeEnvelope(event); err != nil {
+		return StreamEvent{}, false, err
+	}
+	return deleted, true, nil
+}
+
+// Subscribe membuka stream event proyek yang bisa diakses userID. Jika lastEventID > 0, event sesudahnya diputar ulang
+// dari log lebih dulu; jika log tidak lagi memuat semuanya, stream diawali EventReset. Panggil Close setelah
+// selesai.
+func (s *EventStreamService) Subscribe(ctx context.Context, userID, projectID uuid.UUID, lastEventID uint64) (*EventSubscription, error) {
+	if _, err := s.access.member(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+
+	// Berlangganan dulu baru membaca log, supaya event di antara keduanya tidak terlewat; duplikatnya
+	// dibuang berdasarkan ID
+	topic := projectTopic(projectID)
+	sub, err := s.hub.Subscribe(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan StreamEvent, 16)
+	stream := &EventSubscription{
+		Events: events,
+		sub:    sub,
+		done:   make(chan struct{}),
+	}
+	go stream.run(logging.FromContext(ctx), s.hub, topic, lastEventID, events)
+	return stream, nil
+}
+
+// EventSubscription adalah stream event satu client. Events ditutup saat langganan berakhir; Err
+// menjelaskan sebabnya.
+type EventSubscription struct {
+	Events <-chan StreamEvent
+
+	sub  *realtime.Subscription
+	done chan struct{}
+	once sync.Once
+}
+
+// Close mengakhiri stream. Aman dipanggil lebih dari sekali.
+func (e *EventSubscription) Close() {
+	e.once.Do(func() {
+		close(e.done)
+		e.sub.Close()
+	})
+}
+
+// Err mengembalikan sebab Events ditutup (lihat realtime.Subscription.Err).
+func (e *EventSubscription) Err() error {
+	return e.sub.Err()
+}
+
+// run meneruskan pesan Hub ke Events dengan urutan ID yang utuh: duplikat dibuang, dan pesan yang terlewat
+// (misalnya karena pub/sub Redis tidak menjamin pengiriman) diambil dari log.
+func (e *EventSubscription) run(logger *slog.Logger, hub *realtime.Hub, topic string, lastEventID uint64, events chan<- StreamEvent) {
+	defer close(events)
+
+	ctx := context.Background()
+	last := lastEventID
+	send := func(message realtime.Message) bool {
+		var envelope struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(message.Data, &envelope); err != nil {
+			logger.Warn("dropping malformed stream event", "topic", topic, "error", err)
+			return true
+		}
+		select {
+		case events <- StreamEvent{ID: message.ID, Type: envelope.Type, Data: message.Data}:
+			last = message.ID
+			return true
+		case <-e.done:
+			return false
+		}
+	}
+	// catchUp mengirim event di log sesudah last, atau EventReset jika log tidak lagi lengkap
+	catchUp := func() bool {
+		messages, complete, err := hub.Since(ctx, topic, last)
+		if err != nil {
+			logger.Warn("failed to read event log", "topic", topic, "error", err)
+		}
+		if err != nil || !complete {
+			reset := StreamEvent{Type: EventReset, Data: []byte(`{"type":"reset","reason":"some events are no longer available; reload the project"}`)}
+			select {
+			case events <- reset:
+			case <-e.done:
+				return false
+			}
+			last = 0
+			if len(messages) > 0 {
+				last = messages[len(messages)-1].ID
+			}
+			return true
+		}
+		for _, message := range messages {
+			if !send(message) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if lastEventID > 0 && !catchUp() {
+		return
+	}
+	for message := range e.sub.C {
+		switch {
+		case last == 0:
+			// Tanpa Last-Event-ID, pesan live pertama menjadi titik awal
+		case message.ID <= last:
+			continue
+		case message.ID > last+1:
+			if !catchUp() {
+				return
+			}
+			if message.ID <= last {
+				continue
+			}
+		}
+		if !send(message) {
+			return
+		}
+	}
+}
+
+func projectTopic(projectID uuid.UUID) string {
+	return "project:" + projectID.String()
+}
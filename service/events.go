@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/models"
+	"taskify/repository"
+)
+
+// eventRecorder menulis event domain ke outbox. Selalu panggil di dalam transaksi yang sama dengan
+// perubahan datanya: jika transaksi di-rollback, event-nya ikut hilang, dan event tidak pernah tertulis
+// tanpa perubahannya.
+type eventRecorder struct {
+	outbox repository.OutboxRepository
+}
+
+// taskEventData adalah isi field data pada event task.*.
+type taskEventData struct {
+	ID             uuid.UUID         `json:"id"`
+	ProjectID      uuid.UUID         `json:"project_id"`
+	Title          string            `json:"title"`
+	Description    string            `json:"description"`
+	Status         models.TaskStatus `json:"status"`
+	Deadline       *time.Time        `json:"deadline"`
+	Version        uint              `json:"version"`
+	PreviousStatus models.TaskStatus `json:"previous_status,omitempty"` // task.status_changed, dan task.updated yang mengubah status
+	FromProjectID  *uuid.UUID        `json:"from_project_id,omitempty"` // Hanya untuk task.moved
+	ToProjectID    *uuid.UUID        `json:"to_project_id,omitempty"`   // Hanya untuk task.moved
+}
+
+// projectEventData adalah isi field data pada event project.*.
+type projectEventData struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Archived    bool       `json:"archived"`
+	ArchivedAt  *time.Time `json:"archived_at"`
+	Version     uint       `json:"version"`
+}
+
+func newTaskEventData(task models.Task) taskEventData {
+	return taskEventData{
+		ID:          task.ID,
+		ProjectID:   task.ProjectID,
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		Deadline:    task.Deadline,
+		Version:     task.Version,
+	}
+}
+
+func newProjectEventData(project models.Project) projectEventData {
+	return projectEventData{
+		ID:          project.ID,
+		Name:        project.Name,
+		Description: project.Description,
+		Archived:    project.Archived,
+		ArchivedAt:  project.ArchivedAt,
+		Version:     project.Version,
+	}
+}
+
+// EventEnvelope adalah bentuk JSON event yang dikirim keluar, baik sebagai body webhook maupun sebagai data
+// event SSE. ID adalah ID event, sehingga penerima bisa membuang duplikat (pengiriman ulang membawa ID yang sama).
+type EventEnvelope struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	ProjectID uuid.UUID       `json:"project_id"`
+	ActorID   uuid.UUID       `json:"actor_id"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// encodeEnvelope membungkus event outbox sebagai EventEnvelope dalam JSON.
+func encodeEnvelope(event models.OutboxEvent) ([]byte, error) {
+	return json.Marshal(EventEnvelope{
+		ID:        event.ID,
+		Type:      event.Type,
+		CreatedAt: event.CreatedAt.UTC(),
+		ProjectID: event.ProjectID,
+		ActorID:   event.ActorID,
+		Data:      json.RawMessage(event.Payload),
+	})
+}
+
+// record menyimpan satu event untuk projectID yang dipicu oleh actorID.
+func (r eventRecorder) record(ctx context.Context, eventType string, projectID, actorID uuid.UUID, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return r.outbox.Add(ctx, &models.OutboxEvent{
+		ID:        uuid.New(),
+		Type:      eventType,
+		ProjectID: projectID,
+		ActorID:   actorID,
+		Payload:   string(payload),
+		CreatedAt: time.Now(),
+	})
+}
+
+// taskCreated mencatat task.created untuk tugas baru.
+func (r eventRecorder) taskCreated(ctx context.Context, task models.Task, actorID uuid.UUID) error {
+	return r.record(ctx, models.EventTaskCreated, task.ProjectID, actorID, newTaskEventData(task))
+}
+
+// taskUpdated mencatat task.updated, ditambah task.status_changed jika status berubah dari before. Keduanya
+// membawa previous_status jika status berubah.
+func (r eventRecorder) taskUpdated(ctx context.Context, before, after models.Task, actorID uuid.UUID) error {
+	data := newTaskEventData(after)
+	if before.Status != after.Status {
+		data.PreviousStatus = before.Status
+	}
+	if err := r.record(ctx, models.EventTaskUpdated, after.ProjectID, actorID, data); err != nil {
+		return err
+	}
+	if before.Status == after.Status {
+		return nil
+	}
+	return r.record(ctx, models.EventTaskStatusChanged, after.ProjectID, actorID, data)
+}
+
+// taskDeleted mencatat task.deleted dengan data terakhir tugas sebelum dihapus.
+func (r eventRecorder) taskDeleted(ctx context.Context, task models.Task, actorID uuid.UUID) error {
+	return r.record(ctx, models.EventTaskDeleted, task.ProjectID, actorID, newTaskEventData(task))
+}
+
+// taskMoved mencatat task.moved di proyek asal maupun proyek tujuan, supaya webhook kedua proyek
+// mengetahui perpindahannya.
+func (r eventRecorder) taskMoved(ctx context.Context, moved models.Task, fromProjectID, actorID uuid.UUID) error {
+	data := newTaskEventData(moved)
+	toProjectID := moved.ProjectID
+	data.FromProjectID, data.ToProjectID = &fromProjectID, &toProjectID
+	for _, projectID := range []uuid.UUID{fromProjectID, toProjectID} {
+		if err := r.record(ctx, models.EventTaskMoved, projectID, actorID, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// project mencatat event project.* dengan data proyek saat ini.
+func (r eventRecorder) project(ctx context.Context, eventType string, project models.Project, actorID uuid.UUID) error {
+	return r.record(ctx, eventType, project.ID, actorID, newProjectEventData(project))
+}
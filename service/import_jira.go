@@ -0,0 +1,175 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"taskify/models"
+)
+
+// FormatJira adalah export CSV issue Jira (Filters > Export > Export CSV).
+const FormatJira = "jira"
+
+// jiraDateLayouts adalah format tanggal yang dipakai export CSV Jira, tergantung pengaturan instance-nya.
+var jiraDateLayouts = []string{
+	"2/Jan/06 3:04 PM",
+	"2/Jan/06",
+	"2/Jan/2006 3:04 PM",
+	"2/Jan/2006",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	dateLayout,
+}
+
+// jiraIssue adalah satu baris export Jira.
+type jiraIssue struct {
+	row         int
+	key         string
+	id          string
+	parent      string // Issue id parent untuk sub-task
+	summary     string
+	description string
+	status      string
+	category    string // Status Category: To Do, In Progress, atau Done
+	due         string
+	labels      []string
+	subtasks    []importChecklistItem
+	nested      bool // Sub-task yang sudah menjadi checklist parent-nya
+}
+
+// parseJiraImport mengubah export CSV Jira menjadi ImportFile. Kolom dicocokkan tanpa membedakan huruf
+// besar kecil: Summary (wajib), Issue key, Issue id, Parent id (atau Parent), Status, Status Category, Due
+// Date, Description, Labels (boleh berulang), dan Project name. Status yang tidak dikenal dipetakan lewat
+// Status Category. Sub-task yang parent-nya ada di file yang sama menjadi checklist di deskripsi parent.
+// Row adalah nomor baris di file.
+func parseJiraImport(data []byte) (ImportFile, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return ImportFile{}, Invalidf("CSV file is empty; expected a Jira export with a Summary column")
+	}
+	if err != nil {
+		return ImportFile{}, Invalidf("Invalid CSV file: %v", err)
+	}
+
+	columns := make(map[string][]int, len(header))
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		columns[key] = append(columns[key], i)
+	}
+	if _, ok := columns["summary"]; !ok {
+		return ImportFile{}, Invalidf("File is not a Jira CSV export; expected a Summary column")
+	}
+
+	var file ImportFile
+	var issues []*jiraIssue
+	byID := make(map[string]*jiraIssue)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return ImportFile{}, Invalidf("Invalid CSV file: %v", err)
+		}
+		values := func(name string) []string {
+			var found []string
+			for _, i := range columns[name] {
+				if i < len(record) && strings.TrimSpace(record[i]) != "" {
+					found = append(found, strings.TrimSpace(record[i]))
+				}
+			}
+			return found
+		}
+		field := func(names ...string) string {
+			for _, name := range names {
+				if found := values(name); len(found) > 0 {
+					return found[0]
+				}
+			}
+			return ""
+		}
+
+		line, _ := reader.FieldPos(0)
+		issue := &jiraIssue{
+			row:         line,
+			key:         field("issue key"),
+			id:          field("issue id"),
+			parent:      field("parent id", "parent"),
+			summary:     field("summary"),
+			description: field("description"),
+			status:      field("status"),
+			category:    field("status category"),
+			due:         field("due date", "due"),
+			labels:      values("labels"),
+		}
+		if file.ProjectName == "" {
+			file.ProjectName = field("project name")
+		}
+		issues = append(issues, issue)
+		if issue.id != "" {
+			byID[issue.id] = issue
+		}
+	}
+
+	subtasks := 0
+	for _, issue := range issues {
+		parent, ok := byID[issue.parent]
+		if !ok || parent == issue {
+			continue
+		}
+		done, _ := mapStatus(issue.status, nil)
+		category, _ := mapStatus(issue.category, nil)
+		name := issue.summary
+		if issue.key != "" {
+			name = issue.key + " " + name
+		}
+		parent.subtasks = append(parent.subtasks, importChecklistItem{Name: name, Done: done == models.Done || category == models.Done})
+		issue.nested = true
+		subtasks++
+	}
+	if subtasks > 0 {
+		file.Warnings = append(file.Warnings, fmt.Sprintf("Added %d sub-tasks as checklist items of their parent issue", subtasks))
+	}
+
+	for _, issue := range issues {
+		if issue.nested {
+			continue
+		}
+		var checklists []importChecklist
+		if len(issue.subtasks) > 0 {
+			checklists = []importChecklist{{Name: "Sub-tasks", Items: issue.subtasks}}
+		}
+		source := ""
+		if issue.key != "" {
+			source = "Jira: " + issue.key
+		}
+		file.Rows = append(file.Rows, ImportRow{
+			Row:            issue.row,
+			Title:          issue.summary,
+			Description:    importDescription(issue.description, issue.labels, checklists, source),
+			Status:         issue.status,
+			FallbackStatus: issue.category,
+			Deadline:       jiraDeadline(issue.due),
+		})
+	}
+	return file, nil
+}
+
+// jiraDeadline mengubah tanggal Jira menjadi YYYY-MM-DD. Tanggal yang formatnya tidak dikenal dikembalikan
+// apa adanya supaya barisnya dilaporkan tidak valid oleh Import.
+func jiraDeadline(value string) string {
+	for _, layout := range jiraDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format(dateLayout)
+		}
+	}
+	return value
+}
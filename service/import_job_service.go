@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/config"
+	"taskify/models"
+	"taskify/repository"
+)
+
+const (
+	// importJobLease adalah lama job dipegang satu worker sejak diambil atau sejak progres terakhir. Job
+	// yang lease-nya habis dianggap ditinggalkan dan diambil ulang.
+	importJobLease = 2 * time.Minute
+	// importJobMaxAttempts membatasi berapa kali job yang terus ditinggalkan di tengah jalan dicoba ulang.
+	importJobMaxAttempts = 3
+	// importJobPollBatch adalah jumlah job yang diambil per putaran worker.
+	importJobPollBatch = 10
+	importJobListLimit = 20
+)
+
+// ImportJobView adalah import job beserta opsi dan laporannya, seperti yang dikirim ke client.
+type ImportJobView struct {
+	models.ImportJob
+	Options ImportOptions `json:"options"`
+	Report  *ImportReport `json:"report,omitempty"` // Ada setelah job selesai, atau gagal karena baris tidak valid
+}
+
+// ImportJobService menjalankan import proyek di latar belakang untuk file besar: job dibuat dan langsung
+// dikembalikan, lalu worker memprosesnya dan mencatat progres yang bisa di-poll client. Job diambil dengan
+// lease, jadi worker aman dijalankan di beberapa instance sekaligus.
+type ImportJobService struct {
+	jobs     repository.ImportJobRepository
+	projects *ProjectService
+	cfg      config.ImportConfig
+}
+
+// NewImportJobService membuat ImportJobService yang mengimport lewat projects.
+func NewImportJobService(jobs repository.ImportJobRepository, projects *ProjectService, cfg config.ImportConfig) *ImportJobService {
+	return &ImportJobService{jobs: jobs, projects: projects, cfg: cfg}
+}
+
+// Create menyimpan file import milik userID sebagai job baru yang menunggu diproses. Format dan opsi
+// diperiksa sekarang; isi file baru dibaca oleh worker.
+func (s *ImportJobService) Create(ctx context.Context, userID uuid.UUID, format, filename string, data []byte, opts ImportOptions) (ImportJobView, error) {
+	switch format {
+	case FormatJSON, FormatCSV, FormatTrello, FormatJira:
+	default:
+		return ImportJobView{}, Invalidf("format must be one of csv, json, trello or jira")
+	}
+	if opts.Mode == "" {
+		opts.Mode = BulkModeAtomic
+	}
+	if err := opts.validate(); err != nil {
+		return ImportJobView{}, err
+	}
+	encoded, err := json.Marshal(opts)
+	if err != nil {
+		return ImportJobView{}, err
+	}
+
+	job := models.ImportJob{
+		ID:       uuid.New(),
+		UserID:   userID,
+		Format:   format,
+		Filename: filename,
+		Options:  string(encoded),
+		Data:     data,
+		Status:   models.ImportQueued,
+	}
+	if err := s.jobs.Create(ctx, &job); err != nil {
+		return ImportJobView{}, err
+	}
+	job.Data = nil
+	return ImportJobView{ImportJob: job, Options: opts}, nil
+}
+
+// Get mengambil satu job milik userID beserta laporannya.
+func (s *ImportJobService) Get(ctx context.Context, userID, jobID uuid.UUID) (ImportJobView, error) {
+	job, err := s.jobs.Find(ctx, jobID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && job.UserID != userID) {
+		return ImportJobView{}, ErrImportJobNotFound
+	}
+	if err != nil {
+		return ImportJobView{}, err
+	}
+	return importJobView(job)
+}
+
+// List mengambil job terbaru milik userID, tanpa laporannya.
+func (s *ImportJobService) List(ctx context.Context, userID uuid.UUID) ([]ImportJobView, error) {
+	jobs, err := s.jobs.ListByUser(ctx, userID, importJobListLimit)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]ImportJobView, 0, len(jobs))
+	for _, job := range jobs {
+		view, err := importJobView(job)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, view)
+	}
+	return views, nil
+}
+
+func importJobView(job models.ImportJob) (ImportJobView, error) {
+	view := ImportJobView{ImportJob: job}
+	if err := json.Unmarshal([]byte(job.Options), &view.Options); err != nil {
+		return ImportJobView{}, err
+	}
+	if len(job.Report) > 0 {
+		view.Report = &ImportReport{}
+		if err := json.Unmarshal(job.Report, view.Report); err != nil {
+			return ImportJobView{}, err
+		}
+	}
+	return view, nil
+}
+
+// Run menjalankan ProcessDue setiap PollInterval sampai ctx dibatalkan.
+func (s *ImportJobService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.ProcessDue(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			slog.Error("failed to process import jobs", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue memproses job yang menunggu pada now dan mengembalikan jumlah job yang selesai (completed
+// atau failed). Job yang gagal karena error server dicatat ke log dan dicoba lagi setelah lease-nya habis.
+// Job yang sudah lama selesai dihapus.
+func (s *ImportJobService) ProcessDue(ctx context.Context, now time.Time) (int, error) {
+	jobs, err := s.jobs.ListDue(ctx, now, importJobPollBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	finished := 0
+	for _, job := range jobs {
+		// Lease dihitung saat klaim, bukan dari now, karena job sebelumnya di batch ini bisa berjalan lama
+		claimedAt := time.Now()
+		claimed, err := s.jobs.Claim(ctx, job.ID, claimedAt, claimedAt.Add(importJobLease))
+		if err != nil {
+			return finished, err
+		}
+		if !claimed {
+			continue
+		}
+		if err := s.process(ctx, job); err != nil {
+			if ctx.Err() != nil {
+				return finished, nil
+			}
+			slog.Error("failed to process import job", "job_id", job.ID.String(), "error", err)
+			continue
+		}
+		finished++
+	}
+	return finished, s.jobs.DeleteFinishedBefore(ctx, now.Add(-s.cfg.Retention))
+}
+
+// process menjalankan import satu job yang sudah di-Claim. Error yang dikembalikan adalah error server;
+// file atau opsi yang tidak valid membuat job failed.
+func (s *ImportJobService) process(ctx context.Context, job models.ImportJob) error {
+	// Attempts di job adalah nilai sebelum Claim
+	if job.Attempts >= importJobMaxAttempts {
+		return s.fail(ctx, job.ID, "Import was interrupted too many times; upload the file again", nil)
+	}
+
+	var opts ImportOptions
+	if err := json.Unmarshal([]byte(job.Options), &opts); err != nil {
+		return err
+	}
+	data, err := s.jobs.LoadData(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+
+	file, err := ParseImport(job.Format, data)
+	if err != nil {
+		return s.failWith(ctx, job.ID, err)
+	}
+	if file.ProjectName == "" && job.Filename != "" {
+		file.ProjectName = strings.TrimSuffix(job.Filename, filepath.Ext(job.Filename))
+	}
+
+	opts.ProjectID = job.ID
+	opts.Progress = func(ctx context.Context, done, total int) error {
+		leaseUntil := time.Now().Add(importJobLease)
+		return s.jobs.Update(ctx, job.ID, repository.Updates{"processed": done, "total": total, "lease_until": &leaseUntil})
+	}
+	project, report, err := s.projects.Import(ctx, job.UserID, file, opts)
+	if err != nil {
+		return s.failWith(ctx, job.ID, err)
+	}
+	if report.Aborted {
+		return s.fail(ctx, job.ID, "Import failed; no changes were applied", &report)
+	}
+
+	encoded, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	finishedAt := time.Now()
+	updates := repository.Updates{
+		"status":      models.ImportCompleted,
+		"processed":   report.Imported,
+		"total":       report.Valid,
+		"report":      encoded,
+		"data":        []byte{},
+		"lease_until": (*time.Time)(nil),
+		"finished_at": &finishedAt,
+	}
+	if report.ProjectID != nil {
+		updates["project_id"] = &project.ID
+	}
+	return s.jobs.Update(ctx, job.ID, updates)
+}
+
+// failWith membuat job failed dengan pesan err jika err adalah error untuk client, atau mengembalikan err
+// apa adanya jika error server supaya job dicoba lagi.
+func (s *ImportJobService) failWith(ctx context.Context, jobID uuid.UUID, err error) error {
+	var clientErr *Error
+	if !errors.As(err, &clientErr) {
+		return err
+	}
+	return s.fail(ctx, jobID, clientErr.Message, nil)
+}
+
+// fail menandai job failed dengan message dan laporan opsional. Filenya dibuang karena tidak akan
+// diproses lagi.
+func (s *ImportJobService) fail(ctx context.Context, jobID uuid.UUID, message string, report *ImportReport) error {
+	finishedAt := time.Now()
+	updates := repository.Updates{
+		"status":      models.ImportFailed,
+		"error":       message,
+		"data":        []byte{},
+		"lease_until": (*time.Time)(nil),
+		"finished_at": &finishedAt,
+	}
+	if report != nil {
+		encoded, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+		updates["report"] = encoded
+	}
+	return s.jobs.Update(ctx, jobID, updates)
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FormatTrello adalah export JSON board Trello (menu board > Print, export, and share > Export as JSON).
+const FormatTrello = "trello"
+
+// trelloBoard adalah bagian export board Trello yang dipakai import.
+type trelloBoard struct {
+	Name  string `json:"name"`
+	Desc  string `json:"desc"`
+	Lists []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Cards []struct {
+		ID          string  `json:"id"`
+		Name        string  `json:"name"`
+		Desc        string  `json:"desc"`
+		IDList      string  `json:"idList"`
+		Due         *string `json:"due"`
+		DueComplete bool    `json:"dueComplete"`
+		Closed      bool    `json:"closed"`
+		ShortURL    string  `json:"shortUrl"`
+		Labels      []struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		} `json:"labels"`
+	} `json:"cards"`
+	Checklists []struct {
+		IDCard     string  `json:"idCard"`
+		Name       string  `json:"name"`
+		Pos        float64 `json:"pos"`
+		CheckItems []struct {
+			Name  string  `json:"name"`
+			State string  `json:"state"`
+			Pos   float64 `json:"pos"`
+		} `json:"checkItems"`
+	} `json:"checklists"`
+}
+
+// parseTrelloImport mengubah board Trello menjadi ImportFile: list menjadi status (dipetakan seperti status
+// CSV), kartu menjadi tugas, dan label serta checklist ditulis ke deskripsi. Kartu yang sudah ditandai
+// selesai (dueComplete) menjadi done. Kartu dan list yang diarsipkan dilewati. Row adalah urutan kartu di
+// file, mulai dari 1.
+func parseTrelloImport(data []byte) (ImportFile, error) {
+	var board trelloBoard
+	if err := json.Unmarshal(data, &board); err != nil {
+		return ImportFile{}, Invalidf("Invalid Trello JSON file: %v", err)
+	}
+	if board.Lists == nil || board.Cards == nil {
+		return ImportFile{}, Invalidf("File is not a Trello board export; expected lists and cards")
+	}
+
+	lists := make(map[string]string, len(board.Lists))
+	closedLists := make(map[string]bool)
+	for _, list := range board.Lists {
+		lists[list.ID] = list.Name
+		closedLists[list.ID] = list.Closed
+	}
+
+	sort.SliceStable(board.Checklists, func(i, j int) bool { return board.Checklists[i].Pos < board.Checklists[j].Pos })
+	checklists := make(map[string][]importChecklist)
+	for _, checklist := range board.Checklists {
+		sort.SliceStable(checklist.CheckItems, func(i, j int) bool { return checklist.CheckItems[i].Pos < checklist.CheckItems[j].Pos })
+		items := make([]importChecklistItem, 0, len(checklist.CheckItems))
+		for _, item := range checklist.CheckItems {
+			items = append(items, importChecklistItem{Name: item.Name, Done: item.State == "complete"})
+		}
+		checklists[checklist.IDCard] = append(checklists[checklist.IDCard], importChecklist{Name: checklist.Name, Items: items})
+	}
+
+	file := ImportFile{ProjectName: board.Name, ProjectDescription: board.Desc}
+	skipped := 0
+	for i, card := range board.Cards {
+		if card.Closed || closedLists[card.IDList] {
+			skipped++
+			continue
+		}
+
+		row := ImportRow{Row: i + 1, Title: card.Name, Status: lists[card.IDList]}
+		if _, ok := lists[card.IDList]; !ok {
+			row.Warnings = append(row.Warnings, fmt.Sprintf("List %q was not found in the board", card.IDList))
+		}
+		if card.DueComplete {
+			row.Status = "done"
+		}
+		if card.Due != nil {
+			row.Deadline = *card.Due
+		}
+
+		labels := make([]string, 0, len(card.Labels))
+		for _, label := range card.Labels {
+			if name := strings.TrimSpace(label.Name); name != "" {
+				labels = append(labels, name)
+			} else if label.Color != "" {
+				labels = append(labels, label.Color)
+			}
+		}
+		source := ""
+		if card.ShortURL != "" {
+			source = "Trello: " + card.ShortURL
+		}
+		row.Description = importDescription(card.Desc, labels, checklists[card.ID], source)
+		file.Rows = append(file.Rows, row)
+	}
+	if skipped > 0 {
+		file.Warnings = append(file.Warnings, fmt.Sprintf("Skipped %d archived cards", skipped))
+	}
+	return file, nil
+}
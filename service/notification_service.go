@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/cache"
+	"taskify/models"
+	"taskify/repository"
+)
+
+const (
+	notificationPageSize    = 20
+	notificationPageMaxSize = 100
+)
+
+// NotificationPage adalah satu halaman inbox. NextCursor kosong berarti tidak ada halaman berikutnya.
+type NotificationPage struct {
+	Notifications []models.Notification
+	UnreadCount   int64
+	NextCursor    string
+}
+
+// NotificationService mengelola inbox notifikasi, watcher tugas, dan preferensi notifikasi user.
+// Notifikasi dibuat dari event outbox oleh ConsumeEvent, yang dipanggil WebhookDispatcher di transaksi yang
+// sama dengan klaim event-nya, jadi setiap event menghasilkan notifikasi tepat sekali.
+type NotificationService struct {
+	notifications repository.NotificationRepository
+	tasks         repository.TaskRepository
+	access        projectAccess
+}
+
+// NewNotificationService membuat NotificationService. projectCache boleh nil jika cache akses tidak dipakai.
+func NewNotificationService(projects repository.ProjectRepository, tasks repository.TaskRepository, notifications repository.NotificationRepository, projectCache cache.Store) *NotificationService {
+	return &NotificationService{
+		notifications: notifications,
+		tasks:         tasks,
+		access:        projectAccess{projects: projects, cache: projectCache},
+	}
+}
+
+// ConsumeEvent membuat notifikasi untuk watcher tugas dari satu event outbox. User yang memicu event, user
+// yang menonaktifkan jenis event tersebut, dan user yang tidak lagi punya akses ke proyeknya tidak diberi
+// notifikasi. Pembuat tugas baru otomatis menjadi watcher-nya.
+func (s *NotificationService) ConsumeEvent(ctx context.Context, event models.OutboxEvent) error {
+	switch {
+	case event.Type == models.EventProjectDeleted:
+		// Tugas proyek ikut terhapus tanpa event task.deleted per tugas
+		return s.notifications.DeleteOrphanWatchers(ctx)
+	case event.Type != models.EventTaskCreated && !models.IsNotificationType(event.Type):
+		return nil
+	}
+
+	var data taskEventData
+	if err := json.Unmarshal([]byte(event.Payload), &data); err != nil {
+		return fmt.Errorf("decode %s payload: %w", event.Type, err)
+	}
+
+	switch event.Type {
+	case models.EventTaskCreated:
+		return s.notifications.AddWatcher(ctx, &models.TaskWatcher{TaskID: data.ID, UserID: event.ActorID})
+	case models.EventTaskMoved:
+		// task.moved dicatat di proyek asal dan tujuan; cukup diberitahukan sekali
+		if data.ToProjectID != nil && event.ProjectID != *data.ToProjectID {
+			return nil
+		}
+	}
+
+	watchers, err := s.notifications.ListWatchers(ctx, data.ID)
+	if err != nil {
+		return err
+	}
+	var notifications []*models.Notification
+	for _, watcher := range watchers {
+		if watcher.UserID == event.ActorID {
+			continue
+		}
+		// Anggota yang sudah dikeluarkan dari proyek tidak lagi diberi tahu
+		if _, err := s.access.member(ctx, data.ProjectID, watcher.UserID); errors.Is(err, ErrProjectNotFound) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		enabled, err := s.enabled(ctx, watcher.UserID, event.Type)
+		if err != nil {
+			return err
+		}
+		if !enabled {
+			continue
+		}
+		// Perubahan status juga dicatat sebagai task.status_changed; cukup satu notifikasi yang lebih spesifik
+		if event.Type == models.EventTaskUpdated && data.PreviousStatus != "" {
+			statusEnabled, err := s.enabled(ctx, watcher.UserID, models.EventTaskStatusChanged)
+			if err != nil {
+				return err
+			}
+			if statusEnabled {
+				continue
+			}
+		}
+		taskID, actorID, eventID := data.ID, event.ActorID, event.ID
+		notifications = append(notifications, &models.Notification{
+			ID:        uuid.New(),
+			UserID:    watcher.UserID,
+			Type:      event.Type,
+			ProjectID: data.ProjectID,
+			TaskID:    &taskID,
+			ActorID:   &actorID,
+			EventID:   &eventID,
+			Title:     notificationTitle(event.Type, data),
+			Payload:   event.Payload,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+	if err := s.notifications.Create(ctx, notifications...); err != nil {
+		return err
+	}
+
+	if event.Type == models.EventTaskDeleted {
+		return s.notifications.DeleteWatchers(ctx, data.ID)
+	}
+	return nil
+}
+
+// notificationTitle membuat ringkasan notifikasi yang bisa langsung ditampilkan.
+func notificationTitle(eventType string, data taskEventData) string {
+	switch eventType {
+	case models.EventTaskStatusChanged:
+		return fmt.Sprintf("Task \"%s\" moved from %s to %s", data.Title, data.PreviousStatus, data.Status)
+	case models.EventTaskDeleted:
+		return fmt.Sprintf("Task \"%s\" was deleted", data.Title)
+	case models.EventTaskMoved:
+		return fmt.Sprintf("Task \"%s\" was moved to another project", data.Title)
+	default:
+		return fmt.Sprintf("Task \"%s\" was updated", data.Title)
+	}
+}
+
+// enabled melaporkan apakah userID mau menerima notifikasi eventType.
+func (s *NotificationService) enabled(ctx context.Context, userID uuid.UUID, eventType string) (bool, error) {
+	preferences, err := s.notifications.ListPreferences(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, preference := range preferences {
+		if preference.EventType == eventType {
+			return preference.Enabled, nil
+		}
+	}
+	return true, nil
+}
+
+// List mengembalikan satu halaman inbox userID, terbaru lebih dulu, beserta jumlah notifikasi yang belum
+// dibaca. cursor adalah NextCursor dari halaman sebelumnya (kosong untuk halaman pertama); limit di luar
+// 1..100 memakai nilai default atau batas maksimumnya.
+func (s *NotificationService) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, cursor string, limit int) (NotificationPage, error) {
+	after, err := decodeNotificationCursor(cursor)
+	if err != nil {
+		return NotificationPage{}, err
+	}
+	switch {
+	case limit <= 0:
+		limit = notificationPageSize
+	case limit > notificationPageMaxSize:
+		limit = notificationPageMaxSize
+	}
+
+	// Ambil satu lebih banyak untuk mengetahui apakah masih ada halaman berikutnya
+	notifications, err := s.notifications.ListByUser(ctx, userID, unreadOnly, after, limit+1)
+	if err != nil {
+		return NotificationPage{}, err
+	}
+	page := NotificationPage{Notifications: notifications}
+	if len(notifications) > limit {
+		page.Notifications = notifications[:limit]
+		last := page.Notifications[limit-1]
+		page.NextCursor = encodeNotificationCursor(repository.NotificationCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	if page.UnreadCount, err = s.notifications.CountUnread(ctx, userID); err != nil {
+		return NotificationPage{}, err
+	}
+	return page, nil
+}
+
+// MarkRead menandai satu notifikasi milik userID sebagai dibaca. Notifikasi yang sudah dibaca tidak berubah.
+func (s *NotificationService) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) (models.Notification, error) {
+	notification, err := s.notifications.FindByID(ctx, notificationID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && notification.UserID != userID) {
+		return models.Notification{}, ErrNotificationNotFound
+	}
+	if err != nil {
+		return models.Notification{}, err
+	}
+	if notification.ReadAt != nil {
+		return notification, nil
+	}
+
+	now := time.Now()
+	if _, err := s.notifications.MarkRead(ctx, userID, []uuid.UUID{notificationID}, now); err != nil {
+		return models.Notification{}, err
+	}
+	notification.ReadAt = &now
+	return notification, nil
+}
+
+// MarkAllRead menandai semua notifikasi userID sebagai dibaca dan mengembalikan jumlah yang berubah.
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.notifications.MarkRead(ctx, userID, nil, time.Now())
+}
+
+// Watch menjadikan userID watcher tugas di proyek yang bisa diaksesnya. Memanggilnya lagi tidak mengubah apa pun.
+func (s *NotificationService) Watch(ctx context.Context, userID, projectID, taskID uuid.UUID) error {
+	if err := s.checkTask(ctx, userID, projectID, taskID); err != nil {
+		return err
+	}
+	return s.notifications.AddWatcher(ctx, &models.TaskWatcher{TaskID: taskID, UserID: userID, CreatedAt: time.Now()})
+}
+
+// Unwatch berhenti memberi tahu userID tentang perubahan tugas.
+func (s *NotificationService) Unwatch(ctx context.Context, userID, projectID, taskID uuid.UUID) error {
+	if err := s.checkTask(ctx, userID, projectID, taskID); err != nil {
+		return err
+	}
+	return s.notifications.RemoveWatcher(ctx, taskID, userID)
+}
+
+// Watchers mengembalikan watcher tugas di proyek yang bisa diakses userID.
+func (s *NotificationService) Watchers(ctx context.Context, userID, projectID, taskID uuid.UUID) ([]models.TaskWatcher, error) {
+	if err := s.checkTask(ctx, userID, projectID, taskID); err != nil {
+		return nil, err
+	}
+	return s.notifications.ListWatchers(ctx, taskID)
+}
+
+func (s *NotificationService) checkTask(ctx context.Context, userID, projectID, taskID uuid.UUID) error {
+	if _, err := s.access.member(ctx, projectID, userID); err != nil {
+		return err
+	}
+	_, err := s.tasks.FindInProject(ctx, projectID, taskID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrTaskNotFound
+	}
+	return err
+}
+
+// Preferences mengembalikan preferensi userID untuk setiap jenis di models.NotificationTypes, termasuk
+// jenis yang belum pernah diatur (aktif).
+func (s *NotificationService) Preferences(ctx context.Context, userID uuid.UUID) ([]models.NotificationPreference, error) {
+	stored, err := s.notifications.ListPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byType := make(map[string]models.NotificationPreference, len(stored))
+	for _, preference := range stored {
+		byType[preference.EventType] = preference
+	}
+
+	preferences := make([]models.NotificationPreference, 0, len(models.NotificationTypes))
+	for _, eventType := range models.NotificationTypes {
+		preference, ok := byType[eventType]
+		if !ok {
+			preference = models.NotificationPreference{UserID: userID, EventType: eventType, Enabled: true}
+		}
+		preferences = append(preferences, preference)
+	}
+	return preferences, nil
+}
+
+// UpdatePreferences mengaktifkan atau menonaktifkan jenis notifikasi (event type -> aktif) untuk userID
+// lalu mengembalikan semua preferensinya. Jenis yang tidak disebut tidak berubah.
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID uuid.UUID, changes map[string]bool) ([]models.NotificationPreference, error) {
+	now := time.Now()
+	preferences := make([]*models.NotificationPreference, 0, len(changes))
+	for eventType, enabled := range changes {
+		if !models.IsNotificationType(eventType) {
+			return nil, Invalidf("Unknown notification type %q; supported: %s", eventType, strings.Join(models.NotificationTypes, ", "))
+		}
+		preferences = append(preferences, &models.NotificationPreference{UserID: userID, EventType: eventType, Enabled: enabled, UpdatedAt: now})
+	}
+	if err := s.notifications.SavePreferences(ctx, preferences...); err != nil {
+		return nil, err
+	}
+	return s.Preferences(ctx, userID)
+}
+
+// encodeNotificationCursor membuat cursor opaque "<unix nano>.<id>" dalam base64 URL-safe.
+func encodeNotificationCursor(cursor repository.NotificationCursor) string {
+	raw := strconv.FormatInt(cursor.CreatedAt.UnixNano(), 10) + "." + cursor.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeNotificationCursor(cursor string) (*repository.NotificationCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &repository.NotificationCursor{CreatedAt: time.Unix(0, unixNano), ID: parsed}, nil
+}
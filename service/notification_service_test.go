@@ -0,0 +1,165 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"taskify/models"
+)
+
+// inbox mengembalikan halaman pertama notifikasi user.
+func (e *testEnv) inbox(user models.User) NotificationPage {
+	e.t.Helper()
+
+	page, err := e.notifications.List(e.ctx, user.ID, false, "", 100)
+	if err != nil {
+		e.t.Fatalf("list notifications: %v", err)
+	}
+	return page
+}
+
+func TestNotificationsFromMemberChanges(t *testing.T) {
+	env := newTestEnv(t)
+	owner := env.user("owner")
+	member := env.user("member")
+	project := env.project(owner, "Inbox")
+	env.addMember(owner, project.ID, member)
+	task := env.task(owner, project.ID, "Launch")
+	env.relay()
+
+	// Perubahan oleh watcher sendiri tidak menghasilkan notifikasi
+	if _, err := env.tasks.Update(env.ctx, owner.ID, project.ID, task.ID, TaskChanges{Title: ptr("Launch v2")}, nil); err != nil {
+		t.Fatalf("owner update: %v", err)
+	}
+	env.relay()
+	if page := env.inbox(owner); len(page.Notifications) != 0 {
+		t.Fatalf("expected no notifications for own changes, got %+v", page.Notifications)
+	}
+
+	// Perubahan status menghasilkan satu notifikasi task.status_changed, bukan juga task.updated
+	if _, err := env.tasks.Update(env.ctx, member.ID, project.ID, task.ID, TaskChanges{Status: ptr(models.Done)}, nil); err != nil {
+		t.Fatalf("member update: %v", err)
+	}
+	env.relay()
+	env.relay()
+	page := env.inbox(owner)
+	if len(page.Notifications) != 1 || page.UnreadCount != 1 {
+		t.Fatalf("expected one unread notification, got %+v", page)
+	}
+	notification := page.Notifications[0]
+	if notification.Type != models.EventTaskStatusChanged || *notification.ActorID != member.ID || *notification.TaskID != task.ID ||
+		notification.Title != `Task "Launch v2" moved from todo to done` {
+		t.Fatalf("unexpected notification: %+v", notification)
+	}
+	if page := env.inbox(member); len(page.Notifications) != 0 {
+		t.Fatalf("the actor must not be notified, got %+v", page.Notifications)
+	}
+
+	// Notifikasi hanya bisa dibaca pemiliknya
+	_, err := env.notifications.MarkRead(env.ctx, member.ID, notification.ID)
+	expectError(t, err, ErrNotificationNotFound)
+	read, err := env.notifications.MarkRead(env.ctx, owner.ID, notification.ID)
+	if err != nil || read.ReadAt == nil {
+		t.Fatalf("expected the notification to be read, got %+v, %v", read, err)
+	}
+
+	// Tugas yang dihapus memberi tahu watcher lalu melepas watcher-nya
+	if err := env.tasks.Delete(env.ctx, member.ID, project.ID, task.ID, nil); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	env.relay()
+	if page := env.inbox(owner); len(page.Notifications) != 2 || page.Notifications[0].Type != models.EventTaskDeleted {
+		t.Fatalf("expected the deletion notification first, got %+v", page.Notifications)
+	}
+	if watchers, _ := env.repo.Notifications().ListWatchers(env.ctx, task.ID); len(watchers) != 0 {
+		t.Fatalf("expected the watchers to be removed, got %v", watchers)
+	}
+}
+
+func TestNotificationsRespectPreferencesAndAccess(t *testing.T) {
+	env := newTestEnv(t)
+	owner := env.user("owner")
+	member := env.user("member")
+	project := env.project(owner, "Quiet")
+	env.addMember(owner, project.ID, member)
+	task := env.task(member, project.ID, "Shared")
+	env.relay()
+
+	_, err := env.notifications.UpdatePreferences(env.ctx, member.ID, map[string]bool{"comment.created": true})
+	if serviceErr, ok := err.(*Error); !ok || serviceErr.Kind != KindInvalid {
+		t.Fatalf("expected an unknown type to be rejected, got %v", err)
+	}
+	if _, err := env.notifications.UpdatePreferences(env.ctx, member.ID, map[string]bool{models.EventTaskStatusChanged: false}); err != nil {
+		t.Fatalf("update preferences: %v", err)
+	}
+
+	// Dengan task.status_changed dimatikan, perubahan status tetap diberitahukan sebagai task.updated
+	if _, err := env.tasks.Update(env.ctx, owner.ID, project.ID, task.ID, TaskChanges{Status: ptr(models.InProgress)}, nil); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	env.relay()
+	page := env.inbox(member)
+	if len(page.Notifications) != 1 || page.Notifications[0].Type != models.EventTaskUpdated {
+		t.Fatalf("expected a single task.updated notification, got %+v", page.Notifications)
+	}
+
+	// Anggota yang keluar tidak lagi diberi tahu, dan tidak bisa memantau tugasnya lagi
+	if err := env.projects.RemoveMember(env.ctx, member.ID, project.ID, member.ID); err != nil {
+		t.Fatalf("leave: %v", err)
+	}
+	if _, err := env.tasks.Update(env.ctx, owner.ID, project.ID, task.ID, TaskChanges{Title: ptr("Shared v2")}, nil); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	env.relay()
+	if page := env.inbox(member); len(page.Notifications) != 1 {
+		t.Fatalf("a removed member must not be notified, got %+v", page.Notifications)
+	}
+	expectError(t, env.notifications.Watch(env.ctx, member.ID, project.ID, task.ID), ErrProjectNotFound)
+}
+
+func TestNotificationPages(t *testing.T) {
+	env := newTestEnv(t)
+	owner := env.user("owner")
+	member := env.user("member")
+	project := env.project(owner, "Busy")
+	env.addMember(owner, project.ID, member)
+	task := env.task(owner, project.ID, "Hot")
+	env.relay()
+	for i := 0; i < 5; i++ {
+		if _, err := env.tasks.Update(env.ctx, member.ID, project.ID, task.ID, TaskChanges{Title: ptr(fmt.Sprintf("Hot %d", i))}, nil); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+	}
+	env.relay()
+
+	var sizes []int
+	seen := map[string]bool{}
+	cursor := ""
+	for {
+		page, err := env.notifications.List(env.ctx, owner.ID, false, cursor, 2)
+		if err != nil {
+			t.Fatalf("list page: %v", err)
+		}
+		sizes = append(sizes, len(page.Notifications))
+		for _, notification := range page.Notifications {
+			seen[notification.ID.String()] = true
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if fmt.Sprint(sizes) != "[2 2 1]" || len(seen) != 5 {
+		t.Fatalf("expected pages of 2, 2 and 1 covering 5 notifications, got %v and %d", sizes, len(seen))
+	}
+
+	_, err := env.notifications.List(env.ctx, owner.ID, false, "bogus", 2)
+	expectError(t, err, ErrInvalidCursor)
+
+	if updated, err := env.notifications.MarkAllRead(env.ctx, owner.ID); err != nil || updated != 5 {
+		t.Fatalf("expected 5 notifications marked read, got %d, %v", updated, err)
+	}
+	if page, _ := env.notifications.List(env.ctx, owner.ID, true, "", 0); len(page.Notifications) != 0 || page.UnreadCount != 0 {
+		t.Fatalf("expected an empty unread inbox, got %+v", page)
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/cache"
+	"taskify/logging"
+	"taskify/models"
+	"taskify/repository"
+)
+
+// projectCacheTTL membatasi umur data proyek di cache jika invalidasi terlewat.
+const projectCacheTTL = 5 * time.Minute
+
+func projectCacheKey(projectID uuid.UUID) string {
+	return "project:" + projectID.String()
+}
+
+// projectAccess memeriksa kepemilikan dan keanggotaan proyek. Dipakai bersama oleh service proyek, tugas
+// dan template supaya aturan aksesnya sama di semua tempat.
+type projectAccess struct {
+	projects repository.ProjectRepository
+	cache    cache.Store // Opsional; nil berarti selalu membaca dari repository
+}
+
+// Aturan akses proyek: pemilik dan anggota boleh membaca proyek beserta semua isinya (tugas, stream, kanal
+// kolaborasi, feed kalender, export) serta mengelola tugasnya; hanya pemilik yang boleh mengubah proyek itu
+// sendiri (nama, arsip, hapus, anggota, webhook). Proyek yang tidak boleh diakses selalu ErrProjectNotFound.
+
+// find mengambil proyek langsung dari repository dan memastikan userID adalah pemiliknya.
+func (a projectAccess) find(ctx context.Context, projectID, userID uuid.UUID) (models.Project, error) {
+	project, err := a.fetch(ctx, projectID)
+	if err != nil {
+		return models.Project{}, err
+	}
+	return a.authorize(ctx, project, userID, false)
+}
+
+// cached sama seperti find, tetapi data proyek diambil dari cache jika ada, sehingga handler tugas tidak
+// perlu query ulang ke database di setiap request.
+func (a projectAccess) cached(ctx context.Context, projectID, userID uuid.UUID) (models.Project, error) {
+	project, err := a.load(ctx, projectID)
+	if err != nil {
+		return models.Project{}, err
+	}
+	return a.authorize(ctx, project, userID, false)
+}
+
+// findMember sama seperti find, tetapi juga menerima anggota proyek.
+func (a projectAccess) findMember(ctx context.Context, projectID, userID uuid.UUID) (models.Project, error) {
+	project, err := a.fetch(ctx, projectID)
+	if err != nil {
+		return models.Project{}, err
+	}
+	return a.authorize(ctx, project, userID, true)
+}
+
+// member sama seperti cached, tetapi juga menerima anggota proyek.
+func (a projectAccess) member(ctx context.Context, projectID, userID uuid.UUID) (models.Project, error) {
+	project, err := a.load(ctx, projectID)
+	if err != nil {
+		return models.Project{}, err
+	}
+	return a.authorize(ctx, project, userID, true)
+}
+
+// authorize memastikan userID pemilik project, atau anggotanya jika allowMembers. Keanggotaan selalu dibaca
+// dari repository (tidak pernah dari cache) supaya anggota yang dikeluarkan langsung kehilangan akses.
+func (a projectAccess) authorize(ctx context.Context, project models.Project, userID uuid.UUID, allowMembers bool) (models.Project, error) {
+	if project.CreatedByID == userID {
+		return project, nil
+	}
+	if !allowMembers {
+		return models.Project{}, ErrProjectNotFound
+	}
+	isMember, err := a.projects.IsMember(ctx, project.ID, userID)
+	if err != nil {
+		return models.Project{}, err
+	}
+	if !isMember {
+		return models.Project{}, ErrProjectNotFound
+	}
+	return project, nil
+}
+
+// load mengambil proyek dari cache jika ada, atau dari repository lalu menyimpannya di cache.
+func (a projectAccess) load(ctx context.Context, projectID uuid.UUID) (models.Project, error) {
+	if a.cache == nil {
+		return a.fetch(ctx, projectID)
+	}
+
+	var project models.Project
+	raw, err := a.cache.Get(ctx, projectCacheKey(projectID))
+	switch {
+	case err == nil && json.Unmarshal(raw, &project) == nil:
+		// Cache hit
+	case err != nil && !errors.Is(err, cache.ErrMiss):
+		// Cache tidak bisa dihubungi: jatuh ke repository supaya request tetap dilayani
+		logging.FromContext(ctx).Warn("project cache unavailable", "error", err)
+		fallthrough
+	default:
+		project, err = a.fetch(ctx, projectID)
+		if err != nil {
+			return models.Project{}, err
+		}
+		if encoded, err := json.Marshal(project); err == nil {
+			if err := a.cache.Set(ctx, projectCacheKey(projectID), encoded, projectCacheTTL); err != nil {
+				logging.FromContext(ctx).Warn("failed to cache project", "project_id", projectID.String(), "error", err)
+			}
+		}
+	}
+	return project, nil
+}
+
+// fetch mengambil proyek dari repository; proyek yang tidak ada menjadi ErrProjectNotFound.
+func (a projectAccess) fetch(ctx context.Context, projectID uuid.UUID) (models.Project, error) {
+	project, err := a.projects.FindByID(ctx, projectID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.Project{}, ErrProjectNotFound
+	}
+	return project, err
+}
+
+// invalidate dipanggil setiap kali proyek diubah atau dihapus.
+func (a projectAccess) invalidate(ctx context.Context, projectIDs ...uuid.UUID) {
+	if a.cache == nil {
+		return
+	}
+	keys := make([]string, 0, len(projectIDs))
+	for _, id := range projectIDs {
+		keys = append(keys, projectCacheKey(id))
+	}
+	if err := a.cache.Delete(ctx, keys...); err != nil {
+		logging.FromContext(ctx).Warn("failed to invalidate project cache", "error", err)
+	}
+}
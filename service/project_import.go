@@ -0,0 +1,481 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/models"
+	"taskify/repository"
+)
+
+// utf8BOM ditulis Excel di awal file CSV.
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// importBatchSize adalah jumlah tugas yang disimpan per batch pada import yang melaporkan progres.
+const importBatchSize = 200
+
+// ImportFile adalah isi file import yang sudah di-parse tetapi belum divalidasi per baris.
+type ImportFile struct {
+	ProjectName        string
+	ProjectDescription string
+	Rows               []ImportRow
+	Warnings           []string // Peringatan tingkat file, misalnya kolom CSV yang tidak dikenali
+}
+
+// ImportRow adalah satu calon tugas dari file import. Row adalah nomor baris di file: untuk CSV nomor
+// barisnya (header adalah baris 1), untuk JSON urutannya di tasks (mulai dari 1).
+type ImportRow struct {
+	Row            int
+	Title          string
+	Description    string
+	Status         string
+	FallbackStatus string // Dipakai jika Status tidak dikenal, misalnya status category Jira
+	Deadline       string // YYYY-MM-DD atau RFC 3339
+	Warnings       []string
+}
+
+// ImportOptions adalah opsi import proyek. Tag JSON dipakai untuk menyimpan opsi di import job.
+type ImportOptions struct {
+	Name      string                       `json:"name,omitempty"`       // Kosong berarti memakai nama proyek dari file
+	Mode      string                       `json:"mode"`                 // atomic (default) atau best_effort, sama seperti bulk tugas
+	DryRun    bool                         `json:"dry_run"`              // Hanya validasi dan laporan, tidak ada yang disimpan
+	StatusMap map[string]models.TaskStatus `json:"status_map,omitempty"` // Pemetaan status tambahan; mengalahkan pemetaan bawaan
+
+	// ProjectID, jika diisi, menjadi ID proyek baru; proyek sisa import sebelumnya dengan ID ini dibuang
+	// lebih dulu. Dipakai import job supaya percobaan ulang tidak membuat proyek ganda.
+	ProjectID uuid.UUID `json:"-"`
+	// Progress, jika diisi, membuat tugas disimpan per batch di luar satu transaksi besar dan dipanggil
+	// setelah setiap batch dengan jumlah tugas yang sudah disimpan. Jika import gagal di tengah jalan,
+	// proyeknya dibuang.
+	Progress func(ctx context.Context, done, total int) error `json:"-"`
+}
+
+// ImportRowResult adalah hasil validasi satu baris.
+type ImportRowResult struct {
+	Row      int               `json:"row"`
+	Title    string            `json:"title"`
+	Result   string            `json:"result"`           // ok atau error
+	Status   models.TaskStatus `json:"status,omitempty"` // Status Taskify hasil pemetaan
+	Errors   []string          `json:"errors,omitempty"`
+	Warnings []string          `json:"warnings,omitempty"`
+}
+
+// ImportReport adalah laporan import. Aborted bernilai true jika mode atomic dibatalkan karena ada baris
+// yang tidak valid (pada dry run: import sungguhan akan dibatalkan).
+type ImportReport struct {
+	Mode      string            `json:"mode"`
+	DryRun    bool              `json:"dry_run"`
+	Aborted   bool              `json:"aborted"`
+	ProjectID *uuid.UUID        `json:"project_id,omitempty"`
+	Rows      int               `json:"rows"`
+	Valid     int               `json:"valid"`
+	Invalid   int               `json:"invalid"`
+	Imported  int               `json:"imported"`
+	Warnings  []string          `json:"warnings,omitempty"`
+	Results   []ImportRowResult `json:"results"`
+}
+
+// Import membuat proyek baru milik userID dari file import. Setiap baris divalidasi dan dilaporkan
+// sendiri-sendiri; status yang tidak dikenal dipetakan ke status Taskify. Mode atomic tidak menyimpan apa
+// pun jika ada baris yang tidak valid, sedangkan best_effort melewati baris tersebut. Proyek yang dibuat
+// dikembalikan bersama laporannya; proyek kosong jika dry run atau dibatalkan.
+func (s *ProjectService) Import(ctx context.Context, userID uuid.UUID, file ImportFile, opts ImportOptions) (models.Project, ImportReport, error) {
+	if opts.Mode == "" {
+		opts.Mode = BulkModeAtomic
+	}
+	if err := opts.validate(); err != nil {
+		return models.Project{}, ImportReport{}, err
+	}
+	name := strings.TrimSpace(opts.Name)
+	if name == "" {
+		name = strings.TrimSpace(file.ProjectName)
+	}
+	if name == "" {
+		return models.Project{}, ImportReport{}, Invalidf("Project name is required; pass name or include it in the file")
+	}
+	if len(name) > 255 {
+		return models.Project{}, ImportReport{}, Invalidf("Project name must be at most 255 characters")
+	}
+	if len(file.Rows) > s.importMaxRows {
+		return models.Project{}, ImportReport{}, Invalidf("An import may contain at most %d tasks, got %d", s.importMaxRows, len(file.Rows))
+	}
+
+	statusMap := make(map[string]models.TaskStatus, len(opts.StatusMap))
+	for from, to := range opts.StatusMap {
+		statusMap[statusKey(from)] = to
+	}
+
+	project := models.Project{
+		ID:          opts.ProjectID,
+		Name:        name,
+		Description: file.ProjectDescription,
+		CreatedByID: userID,
+		Version:     1,
+	}
+	if project.ID == uuid.Nil {
+		project.ID = uuid.New()
+	}
+	report := ImportReport{
+		Mode:     opts.Mode,
+		DryRun:   opts.DryRun,
+		Rows:     len(file.Rows),
+		Warnings: file.Warnings,
+		Results:  make([]ImportRowResult, 0, len(file.Rows)),
+	}
+	tasks := make([]*models.Task, 0, len(file.Rows))
+	for _, row := range file.Rows {
+		task, result := importTask(row, statusMap)
+		if result.Result == "ok" {
+			report.Valid++
+			task.ProjectID = project.ID
+			tasks = append(tasks, task)
+		} else {
+			report.Invalid++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	if report.Invalid > 0 && opts.Mode == BulkModeAtomic {
+		report.Aborted = true
+		return models.Project{}, report, nil
+	}
+	if opts.DryRun {
+		return models.Project{}, report, nil
+	}
+
+	var created models.Project
+	var err error
+	if opts.Progress != nil {
+		created, err = s.importInBatches(ctx, project, tasks, opts.Progress)
+	} else {
+		created, err = createProjectWithTasks(ctx, s.tx, s.projects, s.tasks, project, tasks)
+	}
+	if err != nil {
+		return models.Project{}, ImportReport{}, err
+	}
+	report.ProjectID = &created.ID
+	report.Imported = len(tasks)
+	return created, report, nil
+}
+
+// validate memeriksa mode dan pemetaan status. Mode kosong berarti atomic.
+func (o ImportOptions) validate() error {
+	if o.Mode != "" && o.Mode != BulkModeAtomic && o.Mode != BulkModeBestEffort {
+		return Invalidf("mode must be either atomic or best_effort")
+	}
+	for from, to := range o.StatusMap {
+		if !to.IsValid() {
+			return Invalidf("Status %q must be mapped to todo, in_progress or done", from)
+		}
+	}
+	return nil
+}
+
+// importInBatches membuat project lalu menyimpan tasks per importBatchSize, memanggil progress setelah
+// setiap batch. Proyek sisa percobaan sebelumnya dengan ID yang sama dibuang lebih dulu, dan proyek yang
+// baru sebagian tersimpan dibuang jika import gagal.
+func (s *ProjectService) importInBatches(ctx context.Context, project models.Project, tasks []*models.Task, progress func(ctx context.Context, done, total int) error) (models.Project, error) {
+	if err := s.discardImport(ctx, project.ID); err != nil {
+		return models.Project{}, err
+	}
+	if err := s.projects.Create(ctx, &project); err != nil {
+		return models.Project{}, err
+	}
+
+	err := progress(ctx, 0, len(tasks))
+	for done := 0; err == nil && done < len(tasks); {
+		batch := tasks[done:min(done+importBatchSize, len(tasks))]
+		if err = s.tasks.Create(ctx, batch...); err == nil {
+			done += len(batch)
+			err = progress(ctx, done, len(tasks))
+		}
+	}
+	if err != nil {
+		// Tetap dibuang walaupun ctx sudah dibatalkan karena shutdown
+		if discardErr := s.discardImport(context.WithoutCancel(ctx), project.ID); discardErr != nil {
+			return models.Project{}, errors.Join(err, discardErr)
+		}
+		return models.Project{}, err
+	}
+	return s.projects.FindByID(ctx, project.ID)
+}
+
+// discardImport menghapus proyek hasil import yang tidak selesai beserta tugasnya, jika ada. Proyeknya
+// sudah terlihat oleh pemiliknya selama import berjalan, jadi penghapusannya dicatat sebagai project.deleted
+// (yang juga membuang webhook yang sempat didaftarkan) dan cache aksesnya di-invalidate.
+func (s *ProjectService) discardImport(ctx context.Context, projectID uuid.UUID) error {
+	discarded := false
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		project, err := s.projects.FindByID(ctx, projectID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		discarded = true
+		return s.deleteProject(ctx, project, project.CreatedByID)
+	})
+	if err != nil {
+		return err
+	}
+	if discarded {
+		s.access.invalidate(ctx, projectID)
+	}
+	return nil
+}
+
+// importTask memvalidasi satu baris dan mengubahnya menjadi tugas (tanpa ProjectID). Tugas nil jika baris
+// tidak valid.
+func importTask(row ImportRow, statusMap map[string]models.TaskStatus) (*models.Task, ImportRowResult) {
+	title := strings.TrimSpace(row.Title)
+	result := ImportRowResult{Row: row.Row, Title: title, Warnings: row.Warnings}
+	if title == "" {
+		result.Errors = append(result.Errors, "title is required")
+	} else if len(title) > 255 {
+		result.Errors = append(result.Errors, "title must be at most 255 characters")
+	}
+
+	status, warning := mapStatus(row.Status, statusMap)
+	if warning != "" && row.FallbackStatus != "" {
+		if fallback, unknown := mapStatus(row.FallbackStatus, statusMap); unknown == "" {
+			status = fallback
+			warning = fmt.Sprintf("Unknown status %q was mapped to %s from %q", strings.TrimSpace(row.Status), fallback, strings.TrimSpace(row.FallbackStatus))
+		}
+	}
+	if warning != "" {
+		result.Warnings = append(result.Warnings, warning)
+	}
+
+	deadline, err := parseImportDeadline(row.Deadline)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	if len(result.Errors) > 0 {
+		result.Result = "error"
+		return nil, result
+	}
+	result.Result = "ok"
+	result.Status = status
+	return &models.Task{
+		ID:          uuid.New(),
+		Title:       title,
+		Description: row.Description,
+		Status:      status,
+		Deadline:    deadline,
+		Version:     1,
+	}, result
+}
+
+// parseImportDeadline menerima tanggal (YYYY-MM-DD) atau waktu RFC 3339, yang diambil tanggalnya menurut
+// zona waktu yang tertulis. String kosong berarti tanpa deadline.
+func parseImportDeadline(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return &t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		day := dateOnly(t)
+		return &day, nil
+	}
+	return nil, fmt.Errorf("deadline %q must use the YYYY-MM-DD format", value)
+}
+
+// statusAliases memetakan nama status yang umum dipakai aplikasi lain (setelah dinormalisasi dengan
+// statusKey) ke status Taskify.
+var statusAliases = map[string]models.TaskStatus{
+	"todo":        models.Todo,
+	"to do":       models.Todo,
+	"open":        models.Todo,
+	"new":         models.Todo,
+	"backlog":     models.Todo,
+	"pending":     models.Todo,
+	"planned":     models.Todo,
+	"not started": models.Todo,
+	"in progress": models.InProgress,
+	"doing":       models.InProgress,
+	"started":     models.InProgress,
+	"active":      models.InProgress,
+	"wip":         models.InProgress,
+	"review":      models.InProgress,
+	"in review":   models.InProgress,
+	"testing":     models.InProgress,
+	"done":        models.Done,
+	"complete":    models.Done,
+	"completed":   models.Done,
+	"closed":      models.Done,
+	"resolved":    models.Done,
+	"finished":    models.Done,
+}
+
+// statusKey menormalkan nama status: huruf kecil, dan spasi, garis bawah, atau tanda hubung berturut-turut
+// menjadi satu spasi, sehingga "In-Progress" dan "in_progress" dianggap sama.
+func statusKey(status string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(status), func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-' || r == '\t'
+	}), " ")
+}
+
+// mapStatus memetakan status dari file ke status Taskify, memakai custom lebih dulu lalu statusAliases.
+// Status kosong berarti todo. Status yang tidak dikenal juga menjadi todo, dengan peringatan.
+func mapStatus(raw string, custom map[string]models.TaskStatus) (models.TaskStatus, string) {
+	key := statusKey(raw)
+	if key == "" {
+		return models.Todo, ""
+	}
+	if status, ok := custom[key]; ok {
+		return status, ""
+	}
+	if status, ok := statusAliases[key]; ok {
+		return status, ""
+	}
+	return models.Todo, fmt.Sprintf("Unknown status %q was mapped to todo", strings.TrimSpace(raw))
+}
+
+// ParseImport mem-parse file import berformat json (file export Taskify), csv (header dengan kolom title,
+// dan opsional description, status, deadline), trello (export JSON board Trello), atau jira (export CSV
+// issue Jira). File yang tidak bisa dibaca sama sekali ditolak; kesalahan per baris baru diperiksa oleh
+// Import.
+func ParseImport(format string, data []byte) (ImportFile, error) {
+	switch format {
+	case FormatJSON:
+		return parseJSONImport(data)
+	case FormatCSV:
+		return parseCSVImport(data)
+	case FormatTrello:
+		return parseTrelloImport(data)
+	case FormatJira:
+		return parseJiraImport(data)
+	}
+	return ImportFile{}, Invalidf("format must be one of csv, json, trello or jira")
+}
+
+func parseJSONImport(data []byte) (ImportFile, error) {
+	var export ProjectExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return ImportFile{}, Invalidf("Invalid JSON file: %v", err)
+	}
+	if export.FormatVersion > exportFormatVersion {
+		return ImportFile{}, Invalidf("Unsupported format_version %d; this server reads up to %d", export.FormatVersion, exportFormatVersion)
+	}
+
+	file := ImportFile{
+		ProjectName:        export.Project.Name,
+		ProjectDescription: export.Project.Description,
+		Rows:               make([]ImportRow, 0, len(export.Tasks)),
+	}
+	for i, task := range export.Tasks {
+		row := ImportRow{Row: i + 1, Title: task.Title, Description: task.Description, Status: task.Status}
+		if task.Deadline != nil {
+			row.Deadline = *task.Deadline
+		}
+		file.Rows = append(file.Rows, row)
+	}
+	return file, nil
+}
+
+func parseCSVImport(data []byte) (ImportFile, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	reader.FieldsPerRecord = -1 // Kolom yang kurang di akhir baris dianggap kosong
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return ImportFile{}, Invalidf("CSV file is empty; expected a header row with a title column")
+	}
+	if err != nil {
+		return ImportFile{}, Invalidf("Invalid CSV file: %v", err)
+	}
+
+	var file ImportFile
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if slices.Contains(csvColumns, key) {
+			columns[key] = i
+		} else if key != "" {
+			file.Warnings = append(file.Warnings, fmt.Sprintf("Column %q is not recognised and was ignored", strings.TrimSpace(name)))
+		}
+	}
+	if _, ok := columns["title"]; !ok {
+		return ImportFile{}, Invalidf("CSV header must contain a title column")
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return ImportFile{}, Invalidf("Invalid CSV file: %v", err)
+		}
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return record[i]
+		}
+		line, _ := reader.FieldPos(0)
+		file.Rows = append(file.Rows, ImportRow{
+			Row:         line,
+			Title:       field("title"),
+			Description: field("description"),
+			Status:      field("status"),
+			Deadline:    field("deadline"),
+		})
+	}
+	return file, nil
+}
+
+// importChecklist adalah checklist dari aplikasi lain. Taskify tidak punya checklist, jadi isinya ditulis
+// ke deskripsi tugas sebagai daftar Markdown.
+type importChecklist struct {
+	Name  string
+	Items []importChecklistItem
+}
+
+type importChecklistItem struct {
+	Name string
+	Done bool
+}
+
+// importDescription menyusun deskripsi tugas dari deskripsi asli, ditambah label, checklist, dan source
+// (asal tugas, misalnya URL kartu Trello) yang tidak punya padanan di Taskify. Bagian kosong dilewati.
+func importDescription(description string, labels []string, checklists []importChecklist, source string) string {
+	var parts []string
+	if description = strings.TrimSpace(description); description != "" {
+		parts = append(parts, description)
+	}
+	if len(labels) > 0 {
+		parts = append(parts, "Labels: "+strings.Join(labels, ", "))
+	}
+	for _, checklist := range checklists {
+		lines := []string{"Checklist: " + checklist.Name}
+		for _, item := range checklist.Items {
+			mark := " "
+			if item.Done {
+				mark = "x"
+			}
+			lines = append(lines, fmt.Sprintf("- [%s] %s", mark, item.Name))
+		}
+		parts = append(parts, strings.Join(lines, "\n"))
+	}
+	if source != "" {
+		parts = append(parts, source)
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/models"
+	"taskify/repository"
+)
+
+// Members mengembalikan anggota proyek. Pemilik maupun anggota boleh melihatnya.
+func (s *ProjectService) Members(ctx context.Context, userID, projectID uuid.UUID) ([]models.ProjectMember, error) {
+	if _, err := s.access.member(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+	return s.projects.ListMembers(ctx, projectID)
+}
+
+// AddMember menjadikan user dengan email tersebut anggota proyek milik userID, sehingga bisa mengelola dan
+// memantau tugas-tugasnya.
+func (s *ProjectService) AddMember(ctx context.Context, userID, projectID uuid.UUID, email string) (models.ProjectMember, error) {
+	project, err := s.access.find(ctx, projectID, userID)
+	if err != nil {
+		return models.ProjectMember{}, err
+	}
+
+	user, err := s.users.FindByEmail(ctx, strings.TrimSpace(email))
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.ProjectMember{}, ErrMemberUserNotFound
+	}
+	if err != nil {
+		return models.ProjectMember{}, err
+	}
+	if user.ID == project.CreatedByID {
+		return models.ProjectMember{}, ErrMemberIsOwner
+	}
+
+	member := models.ProjectMember{ProjectID: project.ID, UserID: user.ID, CreatedAt: time.Now()}
+	if err := s.projects.AddMember(ctx, &member); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return models.ProjectMember{}, ErrMemberExists
+		}
+		return models.ProjectMember{}, err
+	}
+	member.User = user
+	return member, nil
+}
+
+// RemoveMember mengeluarkan memberID dari proyek. Pemilik boleh mengeluarkan siapa pun, sedangkan anggota
+// hanya boleh keluar sendiri. Watcher tugas milik anggota yang keluar tidak lagi diberi notifikasi.
+func (s *ProjectService) RemoveMember(ctx context.Context, userID, projectID, memberID uuid.UUID) error {
+	var err error
+	if memberID == userID {
+		_, err = s.access.member(ctx, projectID, userID)
+	} else {
+		_, err = s.access.find(ctx, projectID, userID)
+	}
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.projects.RemoveMember(ctx, projectID, memberID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrMemberNotFound
+	}
+	return nil
+}
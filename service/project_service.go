@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/cache"
+	"taskify/models"
+	"taskify/repository"
+)
+
+// ProjectChanges berisi field proyek yang ingin diubah; field nil tidak berubah.
+type ProjectChanges struct {
+	Name        *string
+	Description *string
+}
+
+// CopyOptions adalah opsi untuk menduplikasi proyek atau membuat proyek dari template.
+type CopyOptions struct {
+	Name        string     // Kosong berarti memakai nama bawaan
+	Description *string    // Nil berarti memakai deskripsi sumber
+	StartDate   *time.Time // Deadline digeser relatif terhadap tanggal mulai ini
+	ResetStatus bool       // Jika true, semua tugas baru berstatus todo
+}
+
+// ProjectService berisi aturan bisnis proyek: kepemilikan, arsip, dan optimistic concurrency. Perubahan
+// dan penghapusan proyek dicatat ke outbox di transaksi yang sama.
+type ProjectService struct {
+	users    repository.UserRepository
+	projects repository.ProjectRepository
+	tasks    repository.TaskRepository
+	tx       repository.Transactor
+	access   projectAccess
+	events   eventRecorder
+
+	importMaxRows int
+}
+
+// NewProjectService membuat ProjectService. projectCache boleh nil jika cache akses tidak dipakai.
+// importMaxRows (IMPORT_MAX_ROWS) membatasi jumlah tugas per import.
+func NewProjectService(users repository.UserRepository, projects repository.ProjectRepository, tasks repository.TaskRepository, outbox repository.OutboxRepository, tx repository.Transactor, projectCache cache.Store, importMaxRows int) *ProjectService {
+	return &ProjectService{
+		users:         users,
+		projects:      projects,
+		tasks:         tasks,
+		tx:            tx,
+		access:        projectAccess{projects: projects, cache: projectCache},
+		events:        eventRecorder{outbox: outbox},
+		importMaxRows: importMaxRows,
+	}
+}
+
+// Create membuat proyek baru milik createdByID, yang harus user yang valid.
+func (s *ProjectService) Create(ctx context.Context, name, description string, createdByID uuid.UUID) (models.Project, error) {
+	if _, err := s.users.FindByID(ctx, createdByID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return models.Project{}, ErrInvalidCreator
+		}
+		return models.Project{}, err
+	}
+
+	project := models.Project{
+		ID:          uuid.New(),
+		Name:        name,
+		Description: description,
+		CreatedByID: createdByID,
+		Version:     1,
+	}
+	if err := s.projects.Create(ctx, &project); err != nil {
+		return models.Project{}, err
+	}
+	return s.projects.FindByID(ctx, project.ID)
+}
+
+// List mengambil proyek milik userID dan proyek tempat userID menjadi anggota; proyek arsip hanya disertakan jika includeArchived bernilai true.
+func (s *ProjectService) List(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]models.Project, error) {
+	return s.projects.ListByUser(ctx, userID, includeArchived)
+}
+
+// Get mengambil satu proyek milik userID atau yang diikutinya sebagai anggota.
+func (s *ProjectService) Get(ctx context.Context, userID, projectID uuid.UUID) (models.Project, error) {
+	return s.access.findMember(ctx, projectID, userID)
+}
+
+// Update mengubah nama dan/atau deskripsi proyek yang tidak diarsipkan.
+func (s *ProjectService) Update(ctx context.Context, userID, projectID uuid.UUID, changes ProjectChanges, check Precondition) (models.Project, error) {
+	project, err := s.access.find(ctx, projectID, userID)
+	if err != nil {
+		return models.Project{}, err
+	}
+	// Proyek yang diarsipkan tidak boleh diubah sampai di-unarchive
+	if project.Archived {
+		return models.Project{}, ErrProjectArchived
+	}
+	if err := checkPrecondition(check, project.Version); err != nil {
+		return models.Project{}, err
+	}
+
+	updates := repository.Updates{}
+	if changes.Name != nil {
+		updates["name"] = *changes.Name
+	}
+	if changes.Description != nil {
+		updates["description"] = *changes.Description
+	}
+	if len(updates) == 0 {
+		return project, nil
+	}
+	return s.applyUpdates(ctx, project, userID, models.EventProjectUpdated, updates)
+}
+
+// SetArchived mengarsipkan (read-only dan tersembunyi dari daftar default) atau mengembalikan proyek.
+func (s *ProjectService) SetArchived(ctx context.Context, userID, projectID uuid.UUID, archived bool, check Precondition) (models.Project, error) {
+	project, err := s.access.find(ctx, projectID, userID)
+	if err != nil {
+		return models.Project{}, err
+	}
+	if project.Archived == archived {
+		if archived {
+			return models.Project{}, ErrProjectAlreadyArchived
+		}
+		return models.Project{}, ErrProjectNotArchived
+	}
+	if err := checkPrecondition(check, project.Version); err != nil {
+		return models.Project{}, err
+	}
+
+	updates := repository.Updates{"archived": archived, "archived_at": nil}
+	eventType := models.EventProjectUnarchived
+	if archived {
+		now := time.Now()
+		updates["archived_at"] = &now
+		eventType = models.EventProjectArchived
+	}
+	return s.applyUpdates(ctx, project, userID, eventType, updates)
+}
+
+// Delete menghapus proyek beserta tugas dan riwayat tugasnya dalam satu transaksi, supaya tugas tidak ikut
+// terhapus jika proyek ternyata sudah berubah.
+func (s *ProjectService) Delete(ctx context.Context, userID, projectID uuid.UUID, check Precondition) error {
+	project, err := s.access.find(ctx, projectID, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkPrecondition(check, project.Version); err != nil {
+		return err
+	}
+
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		return s.deleteProject(ctx, project, userID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.access.invalidate(ctx, project.ID)
+	return nil
+}
+
+// deleteProject menghapus proyek beserta tugas, riwayat, dan anggotanya lalu mencatat event project.deleted. Harus
+// dipanggil di dalam transaksi; cache akses di-invalidate pemanggil setelah commit.
+func (s *ProjectService) deleteProject(ctx context.Context, project models.Project, actorID uuid.UUID) error {
+	tasks, err := s.tasks.ListByProject(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+	taskIDs := make([]uuid.UUID, 0, len(tasks))
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+	if err := s.tasks.DeleteHistory(ctx, taskIDs...); err != nil {
+		return err
+	}
+	if err := s.tasks.DeleteByProject(ctx, project.ID); err != nil {
+		return err
+	}
+	if err := s.projects.DeleteMembers(ctx, project.ID); err != nil {
+		return err
+	}
+	applied, err := s.projects.Delete(ctx, project.ID, project.Version)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return ErrPreconditionFailed
+	}
+	// Webhook proyek dihapus oleh dispatcher setelah event ini dibagikan, supaya tetap menerimanya
+	return s.events.project(ctx, models.EventProjectDeleted, project, actorID)
+}
+
+// Duplicate menyalin proyek beserta seluruh tugasnya menjadi proyek baru milik userID. Proyek arsip boleh
+// diduplikasi karena hanya dibaca. Jika StartDate diberikan, semua deadline digeser sehingga deadline
+// paling awal jatuh pada StartDate. Mengembalikan proyek baru dan jumlah tugas yang disalin.
+func (s *ProjectService) Duplicate(ctx context.Context, userID, projectID uuid.UUID, opts CopyOptions) (models.Project, int, error) {
+	source, err := s.access.findMember(ctx, projectID, userID)
+	if err != nil {
+		return models.Project{}, 0, err
+	}
+	tasks, err := s.tasks.ListByProject(ctx, source.ID)
+	if err != nil {
+		return models.Project{}, 0, err
+	}
+
+	project := models.Project{
+		ID:          uuid.New(),
+		Name:        source.Name + " (copy)",
+		Description: source.Description,
+		CreatedByID: userID,
+		Version:     1,
+	}
+	if opts.Name != "" {
+		project.Name = opts.Name
+	}
+	if opts.Description != nil {
+		project.Description = *opts.Description
+	}
+
+	// Hitung pergeseran deadline (dalam hari) dari deadline paling awal ke tanggal mulai
+	shiftDays := 0
+	if anchor := earliestDeadline(tasks); anchor != nil && opts.StartDate != nil {
+		shiftDays = daysBetween(*anchor, *opts.StartDate)
+	}
+
+	copies := make([]*models.Task, 0, len(tasks))
+	for _, task := range tasks {
+		copied := &models.Task{
+			ID:          uuid.New(),
+			ProjectID:   project.ID,
+			Title:       task.Title,
+			Description: task.Description,
+			Status:      task.Status,
+			Version:     1,
+		}
+		if opts.ResetStatus {
+			copied.Status = models.Todo
+		}
+		if task.Deadline != nil {
+			deadline := dateOnly(*task.Deadline).AddDate(0, 0, shiftDays)
+			copied.Deadline = &deadline
+		}
+		copies = append(copies, copied)
+	}
+
+	created, err := createProjectWithTasks(ctx, s.tx, s.projects, s.tasks, project, copies)
+	return created, len(copies), err
+}
+
+// applyUpdates menerapkan perubahan dengan pengecekan versi, mencatat event eventType di transaksi yang
+// sama, lalu mengembalikan proyek terbaru.
+func (s *ProjectService) applyUpdates(ctx context.Context, project models.Project, actorID uuid.UUID, eventType string, updates repository.Updates) (models.Project, error) {
+	var updated models.Project
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		applied, err := s.projects.Update(ctx, project.ID, project.Version, updates)
+		if err != nil {
+			return err
+		}
+		if !applied {
+			return ErrPreconditionFailed
+		}
+		if updated, err = s.projects.FindByID(ctx, project.ID); err != nil {
+			return err
+		}
+		return s.events.project(ctx, eventType, updated, actorID)
+	})
+	if err != nil {
+		return models.Project{}, err
+	}
+
+	// Data proyek di cache akses sudah basi
+	s.access.invalidate(ctx, project.ID)
+	return updated, nil
+}
+
+// createProjectWithTasks menyimpan proyek dan semua tugasnya dalam satu transaksi.
+func createProjectWithTasks(ctx context.Context, tx repository.Transactor, projects repository.ProjectRepository, tasks repository.TaskRepository, project models.Project, projectTasks []*models.Task) (models.Project, error) {
+	err := tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := projects.Create(ctx, &project); err != nil {
+			return err
+		}
+		return tasks.Create(ctx, projectTasks...)
+	})
+	if err != nil {
+		return models.Project{}, err
+	}
+	return projects.FindByID(ctx, project.ID)
+}
+
+// earliestDeadline mengembalikan deadline paling awal dari daftar tugas, atau nil jika tidak ada deadline.
+func earliestDeadline(tasks []models.Task) *time.Time {
+	var earliest *time.Time
+	for _, task := range tasks {
+		if task.Deadline == nil {
+			continue
+		}
+		day := dateOnly(*task.Deadline)
+		if earliest == nil || day.Before(*earliest) {
+			earliest = &day
+		}
+	}
+	return earliest
+}
+
+// dateOnly membuang komponen jam dan zona waktu supaya perhitungan selisih hari tidak terpengaruh DST.
+func dateOnly(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// daysBetween menghitung selisih hari kalender dari from ke to.
+func daysBetween(from, to time.Time) int {
+	return int(dateOnly(to).Sub(dateOnly(from)).Hours() / 24)
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"taskify/models"
+)
+
+func TestProjectOwnership(t *testing.T) {
+	env := newTestEnv(t)
+	owner := env.user("owner")
+	stranger := env.user("stranger")
+	project := env.project(owner, "Mine")
+
+	_, err := env.projects.Create(env.ctx, "Ghost", "", uuid.New())
+	expectError(t, err, ErrInvalidCreator)
+
+	_, err = env.projects.Get(env.ctx, stranger.ID, project.ID)
+	expectError(t, err, ErrProjectNotFound)
+	_, err = env.projects.Update(env.ctx, stranger.ID, project.ID, ProjectChanges{Name: ptr("Stolen")}, nil)
+	expectError(t, err, ErrProjectNotFound)
+	expectError(t, env.projects.Delete(env.ctx, stranger.ID, project.ID, nil), ErrProjectNotFound)
+
+	_, err = env.projects.Update(env.ctx, owner.ID, project.ID, ProjectChanges{Name: ptr("Stale")}, staleVersion)
+	expectError(t, err, ErrPreconditionFailed)
+	updated, err := env.projects.Update(env.ctx, owner.ID, project.ID, ProjectChanges{Name: ptr("Renamed")}, nil)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.Name != "Renamed" || updated.Version != project.Version+1 {
+		t.Fatalf("unexpected project after update: %+v", updated)
+	}
+
+	archived, err := env.projects.SetArchived(env.ctx, owner.ID, project.ID, true, nil)
+	if err != nil || !archived.Archived || archived.ArchivedAt == nil {
+		t.Fatalf("expected the project to be archived, got %+v, %v", archived, err)
+	}
+	_, err = env.projects.SetArchived(env.ctx, owner.ID, project.ID, true, nil)
+	expectError(t, err, ErrProjectAlreadyArchived)
+	_, err = env.projects.Update(env.ctx, owner.ID, project.ID, ProjectChanges{Name: ptr("Frozen")}, nil)
+	expectError(t, err, ErrProjectArchived)
+	if list, _ := env.projects.List(env.ctx, owner.ID, false); len(list) != 0 {
+		t.Fatalf("expected archived projects to be hidden by default, got %v", list)
+	}
+
+	want := []string{models.EventProjectUpdated, models.EventProjectArchived}
+	if got := env.pendingTypes(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected events %v, got %v", want, got)
+	}
+}
+
+func TestProjectDelete(t *testing.T) {
+	env := newTestEnv(t)
+	owner := env.user("owner")
+	member := env.user("member")
+	project := env.project(owner, "Doomed")
+	env.addMember(owner, project.ID, member)
+	task := env.task(owner, project.ID, "Gone")
+	if _, err := env.webhooks.Create(env.ctx, owner.ID, project.ID, WebhookInput{URL: "https://example.com/hook", Events: []string{"*"}}); err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	env.relay()
+
+	// Proyek masuk cache akses sebelum dihapus
+	if _, err := env.webhooks.List(env.ctx, owner.ID, project.ID); err != nil {
+		t.Fatalf("list webhooks: %v", err)
+	}
+	expectError(t, env.projects.Delete(env.ctx, owner.ID, project.ID, staleVersion), ErrPreconditionFailed)
+	if err := env.projects.Delete(env.ctx, owner.ID, project.ID, nil); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	_, err := env.webhooks.List(env.ctx, owner.ID, project.ID)
+	expectError(t, err, ErrProjectNotFound)
+	if _, err := env.repo.Tasks().FindByID(env.ctx, task.ID); err == nil {
+		t.Fatal("expected the project's tasks to be deleted")
+	}
+	if members, _ := env.repo.Projects().ListMembers(env.ctx, project.ID); len(members) != 0 {
+		t.Fatalf("expected the project's members to be deleted, got %v", members)
+	}
+
+	if got := env.pendingTypes(); !reflect.DeepEqual(got, []string{models.EventProjectDeleted}) {
+		t.Fatalf("expected only project.deleted, got %v", got)
+	}
+	// Webhook baru dibuang setelah project.deleted dibagikan
+	if webhooks, _ := env.repo.Webhooks().ListByProject(env.ctx, project.ID); len(webhooks) != 1 {
+		t.Fatalf("expected the webhook to outlive the delete until relay, got %v", webhooks)
+	}
+	env.relay()
+	if webhooks, _ := env.repo.Webhooks().ListByProject(env.ctx, project.ID); len(webhooks) != 0 {
+		t.Fatalf("expected the webhooks to be removed after relay, got %v", webhooks)
+	}
+	if watchers, _ := env.repo.Notifications().ListWatchers(env.ctx, task.ID); len(watchers) != 0 {
+		t.Fatalf("expected watchers of deleted tasks to be removed, got %v", watchers)
+	}
+}
+
+func TestProjectMembers(t *testing.T) {
+	env := newTestEnv(t)
+	owner := env.user("owner")
+	member := env.user("member")
+	other := env.user("other")
+	project := env.project(owner, "Team")
+
+	_, err := env.projects.AddMember(env.ctx, owner.ID, project.ID, "nobody@example.com")
+	expectError(t, err, ErrMemberUserNotFound)
+	_, err = env.projects.AddMember(env.ctx, owner.ID, project.ID, owner.Email)
+	expectError(t, err, ErrMemberIsOwner)
+
+	added, err := env.projects.AddMember(env.ctx, owner.ID, project.ID, " "+member.Email+" ")
+	if err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+	if added.UserID != member.ID || added.User.Email != member.Email {
+		t.Fatalf("unexpected member: %+v", added)
+	}
+	_, err = env.projects.AddMember(env.ctx, owner.ID, project.ID, member.Email)
+	expectError(t, err, ErrMemberExists)
+
+	// Anggota bisa melihat anggota lain tetapi tidak bisa menambah atau mengeluarkan orang lain
+	members, err := env.projects.Members(env.ctx, member.ID, project.ID)
+	if err != nil || len(members) != 1 || members[0].UserID != member.ID {
+		t.Fatalf("expected the member in the list, got %v, %v", members, err)
+	}
+	_, err = env.projects.AddMember(env.ctx, member.ID, project.ID, other.Email)
+	expectError(t, err, ErrProjectNotFound)
+	env.addMember(owner, project.ID, other)
+	expectError(t, env.projects.RemoveMember(env.ctx, member.ID, project.ID, other.ID), ErrProjectNotFound)
+	_, err = env.projects.Update(env.ctx, member.ID, project.ID, ProjectChanges{Name: ptr("Mine now")}, nil)
+	expectError(t, err, ErrProjectNotFound)
+
+	// Anggota membaca proyek seperti pemiliknya
+	if got, err := env.projects.Get(env.ctx, member.ID, project.ID); err != nil || got.ID != project.ID {
+		t.Fatalf("expected the member to read the project, got %+v, %v", got, err)
+	}
+	if list, err := env.projects.List(env.ctx, member.ID, false); err != nil || len(list) != 1 {
+		t.Fatalf("expected the shared project in the member's list, got %v, %v", list, err)
+	}
+
+	// Anggota boleh keluar sendiri, sekali saja
+	if err := env.projects.RemoveMember(env.ctx, member.ID, project.ID, member.ID); err != nil {
+		t.Fatalf("leave: %v", err)
+	}
+	expectError(t, env.projects.RemoveMember(env.ctx, owner.ID, project.ID, member.ID), ErrMemberNotFound)
+	_, err = env.projects.Members(env.ctx, member.ID, project.ID)
+	expectError(t, err, ErrProjectNotFound)
+	_, err = env.projects.Get(env.ctx, member.ID, project.ID)
+	expectError(t, err, ErrProjectNotFound)
+}
@@ -0,0 +1,374 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+	_ "time/tzdata" // Zona waktu user tetap bisa dimuat di image tanpa /usr/share/zoneinfo
+
+	"github.com/google/uuid"
+
+	"taskify/config"
+	"taskify/logging"
+	"taskify/mail"
+	"taskify/models"
+	"taskify/repository"
+)
+
+const (
+	maxReminderOffsets      = 5
+	maxReminderOffsetMinute = 7 * 24 * 60
+
+	// overdueAlertWindow membatasi peringatan overdue ke deadline yang lewat dalam jendela ini, supaya
+	// menyalakan scheduler pertama kali tidak mengirim peringatan untuk semua tugas lama.
+	overdueAlertWindow = 24 * time.Hour
+	// sentReminderRetention harus lebih panjang dari offset terjauh ditambah overdueAlertWindow, karena
+	// catatan yang sudah dihapus tidak lagi mencegah pengingat yang sama terkirim ulang.
+	sentReminderRetention = 30 * 24 * time.Hour
+	// deadlineZoneSlack melebarkan rentang query karena deadline (tanggal UTC) jatuh tempo di akhir tanggal
+	// menurut zona waktu masing-masing user.
+	deadlineZoneSlack = 48 * time.Hour
+)
+
+// ReminderSettingChanges berisi pengaturan pengingat yang ingin diubah; field nil tidak berubah. Offsets
+// kosong (bukan nil) mematikan pengingat sebelum deadline.
+type ReminderSettingChanges struct {
+	Offsets       []int
+	OverdueAlerts *bool
+	DailyDigest   *bool
+	DigestHour    *int
+	Timezone      *string
+}
+
+// ReminderService mengelola pengaturan pengingat deadline user dan menjalankan scheduler yang mengirim
+// pengingat sebelum deadline, peringatan overdue, dan digest harian lewat email. Setiap pengingat dicatat
+// dengan key unik sebelum dikirim, jadi scheduler aman dijalankan di beberapa instance sekaligus.
+type ReminderService struct {
+	users     repository.UserRepository
+	tasks     repository.TaskRepository
+	reminders repository.ReminderRepository
+	mailer    mail.Mailer
+	cfg       config.ReminderConfig
+}
+
+// NewReminderService membuat ReminderService yang mengirim email lewat mailer.
+func NewReminderService(users repository.UserRepository, tasks repository.TaskRepository, reminders repository.ReminderRepository, mailer mail.Mailer, cfg config.ReminderConfig) *ReminderService {
+	return &ReminderService{users: users, tasks: tasks, reminders: reminders, mailer: mailer, cfg: cfg}
+}
+
+// Settings mengembalikan pengaturan pengingat userID, atau pengaturan bawaan jika belum pernah diubah.
+func (s *ReminderService) Settings(ctx context.Context, userID uuid.UUID) (models.ReminderSetting, error) {
+	setting, err := s.reminders.FindSetting(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.DefaultReminderSetting(userID), nil
+	}
+	return setting, err
+}
+
+// UpdateSettings memvalidasi dan menyimpan perubahan pengaturan pengingat userID.
+func (s *ReminderService) UpdateSettings(ctx context.Context, userID uuid.UUID, changes ReminderSettingChanges) (models.ReminderSetting, error) {
+	setting, err := s.Settings(ctx, userID)
+	if err != nil {
+		return models.ReminderSetting{}, err
+	}
+
+	if changes.Offsets != nil {
+		offsets, err := validateReminderOffsets(changes.Offsets)
+		if err != nil {
+			return models.ReminderSetting{}, err
+		}
+		setting.Offsets = offsets
+	}
+	if changes.OverdueAlerts != nil {
+		setting.OverdueAlerts = *changes.OverdueAlerts
+	}
+	if changes.DailyDigest != nil {
+		setting.DailyDigest = *changes.DailyDigest
+	}
+	if changes.DigestHour != nil {
+		if *changes.DigestHour < 0 || *changes.DigestHour > 23 {
+			return models.ReminderSetting{}, Invalidf("digest_hour must be between 0 and 23")
+		}
+		setting.DigestHour = *changes.DigestHour
+	}
+	if changes.Timezone != nil {
+		if _, err := time.LoadLocation(*changes.Timezone); err != nil || *changes.Timezone == "" || *changes.Timezone == "Local" {
+			return models.ReminderSetting{}, Invalidf("Unknown timezone %q; use an IANA name such as Asia/Jakarta", *changes.Timezone)
+		}
+		setting.Timezone = *changes.Timezone
+	}
+
+	setting.UpdatedAt = time.Now()
+	if err := s.reminders.SaveSetting(ctx, &setting); err != nil {
+		return models.ReminderSetting{}, err
+	}
+	return setting, nil
+}
+
+// validateReminderOffsets memastikan offset berada di 1 menit..7 hari, lalu membuang duplikat dan
+// mengurutkannya dari yang terjauh.
+func validateReminderOffsets(offsets []int) (models.ReminderOffsets, error) {
+	if len(offsets) > maxReminderOffsets {
+		return nil, Invalidf("At most %d reminder offsets are allowed", maxReminderOffsets)
+	}
+	seen := map[int]bool{}
+	result := models.ReminderOffsets{}
+	for _, minutes := range offsets {
+		if minutes < 1 || minutes > maxReminderOffsetMinute {
+			return nil, Invalidf("Reminder offsets must be between 1 and %d minutes, got %d", maxReminderOffsetMinute, minutes)
+		}
+		if !seen[minutes] {
+			seen[minutes] = true
+			result = append(result, minutes)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(result)))
+	return result, nil
+}
+
+// Run menjalankan SendDue setiap PollInterval sampai ctx dibatalkan.
+func (s *ReminderService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.SendDue(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			slog.Error("failed to send deadline reminders", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue mengirim semua pengingat yang jatuh tempo pada now dan mengembalikan jumlah email yang terkirim.
+// Kegagalan satu email dicatat ke log dan dicoba lagi di putaran berikutnya tanpa menghentikan yang lain.
+func (s *ReminderService) SendDue(ctx context.Context, now time.Time) (int, error) {
+	sent, err := s.sendTaskReminders(ctx, now)
+	if err != nil {
+		return sent, err
+	}
+	digests, err := s.sendDigests(ctx, now)
+	sent += digests
+	if err != nil {
+		return sent, err
+	}
+	return sent, s.reminders.DeleteSentBefore(ctx, now.Add(-sentReminderRetention))
+}
+
+// sendTaskReminders mengirim pengingat sebelum deadline dan peringatan overdue untuk tugas yang belum done.
+// Untuk setiap tugas hanya offset terdekat yang sudah terlewati yang dikirim, jadi tugas yang dibuat mepet
+// deadline tidak menerima semua pengingatnya sekaligus.
+func (s *ReminderService) sendTaskReminders(ctx context.Context, now time.Time) (int, error) {
+	tasks, err := s.tasks.ListByDeadline(ctx, repository.DeadlineFilter{
+		From:     now.Add(-overdueAlertWindow - deadlineZoneSlack),
+		To:       now.Add(maxReminderOffsetMinute*time.Minute + deadlineZoneSlack),
+		OpenOnly: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	settings := map[uuid.UUID]models.ReminderSetting{}
+	sent := 0
+	for _, task := range tasks {
+		owner := task.Project.User
+		setting, ok := settings[owner.ID]
+		if !ok {
+			if setting, err = s.Settings(ctx, owner.ID); err != nil {
+				return sent, err
+			}
+			settings[owner.ID] = setting
+		}
+
+		date := deadlineDate(task)
+		dueAt := dueTime(task, userLocation(setting))
+		taskID := task.ID
+		reminder := models.SentReminder{UserID: owner.ID, TaskID: &taskID, SentAt: now}
+		var message mail.Message
+
+		if now.Before(dueAt) {
+			offset, ok := passedOffset(setting.Offsets, dueAt, now)
+			if !ok {
+				continue
+			}
+			reminder.Kind = models.ReminderBeforeDeadline
+			reminder.Key = fmt.Sprintf("%s:%s:%s:%d", reminder.Kind, task.ID, date, offset)
+			message = mail.Message{
+				To:      owner.Email,
+				Subject: fmt.Sprintf("Reminder: %q is due %s", task.Title, date),
+				Body: fmt.Sprintf("Hi %s,\n\nThe task %q in project %q is due %s and is still %s.\n",
+					owner.Name, task.Title, task.Project.Name, date, task.Status),
+			}
+		} else {
+			if !setting.OverdueAlerts || now.Sub(dueAt) >= overdueAlertWindow {
+				continue
+			}
+			reminder.Kind = models.ReminderOverdue
+			reminder.Key = fmt.Sprintf("%s:%s:%s", reminder.Kind, task.ID, date)
+			message = mail.Message{
+				To:      owner.Email,
+				Subject: fmt.Sprintf("Overdue: %q was due %s", task.Title, date),
+				Body: fmt.Sprintf("Hi %s,\n\nThe task %q in project %q was due %s and is still %s.\n",
+					owner.Name, task.Title, task.Project.Name, date, task.Status),
+			}
+		}
+
+		delivered, err := s.deliver(ctx, &reminder, message)
+		if err != nil {
+			return sent, err
+		}
+		if delivered {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// sendDigests mengirim ringkasan tugas yang jatuh tempo hari ini dan yang sudah overdue ke user yang
+// mengaktifkan digest harian, sekali per tanggal lokal setelah DigestHour. Digest tanpa isi tidak dikirim.
+func (s *ReminderService) sendDigests(ctx context.Context, now time.Time) (int, error) {
+	settings, err := s.reminders.ListDigestSettings(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, setting := range settings {
+		local := now.In(userLocation(setting))
+		if local.Hour() < setting.DigestHour {
+			continue
+		}
+		today := local.Format(dateLayout)
+		reminder := models.SentReminder{
+			Key:    fmt.Sprintf("%s:%s:%s", models.ReminderDigest, setting.UserID, today),
+			Kind:   models.ReminderDigest,
+			UserID: setting.UserID,
+			SentAt: now,
+		}
+		claimed, err := s.reminders.Claim(ctx, &reminder)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+
+		message, ok, err := s.digest(ctx, setting.UserID, today)
+		if err != nil {
+			s.release(ctx, reminder.Key)
+			return sent, err
+		}
+		if !ok {
+			continue
+		}
+		if err := s.mailer.Send(ctx, message); err != nil {
+			logging.FromContext(ctx).Error("failed to send daily digest", "user_id", setting.UserID.String(), "error", err)
+			s.release(ctx, reminder.Key)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// digest menyusun email digest userID untuk tanggal lokal today. Hasil false berarti tidak ada tugas yang
+// perlu diringkas.
+func (s *ReminderService) digest(ctx context.Context, userID uuid.UUID, today string) (mail.Message, bool, error) {
+	date, err := time.Parse(dateLayout, today)
+	if err != nil {
+		return mail.Message{}, false, err
+	}
+	tasks, err := s.tasks.ListByDeadline(ctx, repository.DeadlineFilter{OwnerID: userID, To: date.AddDate(0, 0, 1), OpenOnly: true})
+	if err != nil || len(tasks) == 0 {
+		return mail.Message{}, false, err
+	}
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return mail.Message{}, false, err
+	}
+
+	var dueToday, overdue []string
+	for _, task := range tasks {
+		line := fmt.Sprintf("- %s (%s, %s)", task.Title, task.Project.Name, task.Status)
+		if deadline := deadlineDate(task); deadline == today {
+			dueToday = append(dueToday, line)
+		} else {
+			overdue = append(overdue, line+" due "+deadline)
+		}
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi %s,\n\nHere is your Taskify summary for %s.\n", user.Name, today)
+	if len(dueToday) > 0 {
+		fmt.Fprintf(&body, "\nDue today (%d):\n%s\n", len(dueToday), strings.Join(dueToday, "\n"))
+	}
+	if len(overdue) > 0 {
+		fmt.Fprintf(&body, "\nOverdue (%d):\n%s\n", len(overdue), strings.Join(overdue, "\n"))
+	}
+	return mail.Message{
+		To:      user.Email,
+		Subject: fmt.Sprintf("Taskify digest for %s: %d due today, %d overdue", today, len(dueToday), len(overdue)),
+		Body:    body.String(),
+	}, true, nil
+}
+
+// deliver mencatat reminder lalu mengirim message. Hasil false tanpa error berarti reminder sudah dikirim
+// sebelumnya atau pengirimannya gagal dan akan dicoba lagi.
+func (s *ReminderService) deliver(ctx context.Context, reminder *models.SentReminder, message mail.Message) (bool, error) {
+	claimed, err := s.reminders.Claim(ctx, reminder)
+	if err != nil || !claimed {
+		return false, err
+	}
+	if err := s.mailer.Send(ctx, message); err != nil {
+		logging.FromContext(ctx).Error("failed to send deadline reminder", "key", reminder.Key, "error", err)
+		s.release(ctx, reminder.Key)
+		return false, nil
+	}
+	return true, nil
+}
+
+// release melepas catatan reminder yang gagal dikirim supaya dicoba lagi di putaran berikutnya.
+func (s *ReminderService) release(ctx context.Context, key string) {
+	if err := s.reminders.Release(ctx, key); err != nil {
+		logging.FromContext(ctx).Error("failed to release reminder claim", "key", key, "error", err)
+	}
+}
+
+// passedOffset mengembalikan offset terkecil (terdekat ke deadline) yang waktu kirimnya sudah terlewati.
+func passedOffset(offsets models.ReminderOffsets, dueAt, now time.Time) (int, bool) {
+	best, found := 0, false
+	for _, minutes := range offsets {
+		if !now.Before(dueAt.Add(-time.Duration(minutes)*time.Minute)) && (!found || minutes < best) {
+			best, found = minutes, true
+		}
+	}
+	return best, found
+}
+
+// deadlineDate mengembalikan tanggal deadline tugas (YYYY-MM-DD).
+func deadlineDate(task models.Task) string {
+	return task.Deadline.UTC().Format(dateLayout)
+}
+
+// dueTime mengembalikan saat tugas jatuh tempo: akhir tanggal deadline di zona waktu loc.
+func dueTime(task models.Task, loc *time.Location) time.Time {
+	year, month, day := task.Deadline.UTC().Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+}
+
+// userLocation memuat zona waktu pengaturan, atau UTC jika tidak dikenal.
+func userLocation(setting models.ReminderSetting) *time.Location {
+	loc, err := time.LoadLocation(setting.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
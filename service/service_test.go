@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/cache"
+	"taskify/config"
+	"taskify/models"
+	"taskify/repository"
+)
+
+// testEnv merangkai service seperti di newApp, tetapi di atas repository.NewMemory() dan cache in-memory.
+type testEnv struct {
+	t     *testing.T
+	ctx   context.Context
+	repo  *repository.Memory
+	cache *cache.MemoryStore
+
+	projects      *ProjectService
+	tasks         *TaskService
+	webhooks      *WebhookService
+	notifications *NotificationService
+	dispatcher    *WebhookDispatcher
+}
+
+// newTestEnv membuat testEnv. configure mengubah konfigurasi webhook bawaan, yang memakai backoff 1ms dan
+// mengizinkan alamat internal karena penerima test berjalan di 127.0.0.1.
+func newTestEnv(t *testing.T, configure ...func(cfg *config.WebhookConfig)) *testEnv {
+	t.Helper()
+
+	cfg := config.Default().Webhook
+	cfg.Timeout = 5 * time.Second
+	cfg.MaxAttempts = 3
+	cfg.BackoffBase = time.Millisecond
+	cfg.BackoffMax = time.Millisecond
+	cfg.AllowPrivateTargets = true
+	for _, fn := range configure {
+		fn(&cfg)
+	}
+
+	repo := repository.NewMemory()
+	store := cache.NewMemoryStore()
+	env := &testEnv{t: t, ctx: context.Background(), repo: repo, cache: store}
+	env.projects = NewProjectService(repo.Users(), repo.Projects(), repo.Tasks(), repo.Outbox(), repo, store, 1000)
+	env.tasks = NewTaskService(repo.Projects(), repo.Tasks(), repo.Outbox(), repo, store, 100)
+	env.webhooks = NewWebhookService(repo.Projects(), repo.Webhooks(), store, cfg.AllowPrivateTargets)
+	env.notifications = NewNotificationService(repo.Projects(), repo.Tasks(), repo.Notifications(), store)
+	env.dispatcher = NewWebhookDispatcher(repo.Outbox(), repo.Webhooks(), repo, nil, cfg, env.notifications)
+	return env
+}
+
+// user menyimpan user baru langsung di repository.
+func (e *testEnv) user(name string) models.User {
+	e.t.Helper()
+
+	user := models.User{ID: uuid.New(), Name: name, Email: name + "-" + uuid.NewString()[:8] + "@example.com", Password: "hash"}
+	if err := e.repo.Users().Create(e.ctx, &user); err != nil {
+		e.t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+// project membuat proyek milik owner lewat ProjectService.
+func (e *testEnv) project(owner models.User, name string) models.Project {
+	e.t.Helper()
+
+	project, err := e.projects.Create(e.ctx, name, name+" description", owner.ID)
+	if err != nil {
+		e.t.Fatalf("create project: %v", err)
+	}
+	return project
+}
+
+// task membuat tugas todo di proyek atas nama user lewat TaskService.
+func (e *testEnv) task(user models.User, projectID uuid.UUID, title string) models.Task {
+	e.t.Helper()
+
+	task, err := e.tasks.Create(e.ctx, user.ID, projectID, TaskInput{Title: title, Status: models.Todo})
+	if err != nil {
+		e.t.Fatalf("create task: %v", err)
+	}
+	return task
+}
+
+// addMember menjadikan member anggota proyek milik owner.
+func (e *testEnv) addMember(owner models.User, projectID uuid.UUID, member models.User) {
+	e.t.Helper()
+
+	if _, err := e.projects.AddMember(e.ctx, owner.ID, projectID, member.Email); err != nil {
+		e.t.Fatalf("add member: %v", err)
+	}
+}
+
+// pendingEvents mengembalikan event outbox yang belum dibagikan dispatcher, terlama lebih dulu.
+func (e *testEnv) pendingEvents() []models.OutboxEvent {
+	e.t.Helper()
+
+	events, err := e.repo.Outbox().ListPending(e.ctx, 1000)
+	if err != nil {
+		e.t.Fatalf("list outbox: %v", err)
+	}
+	return events
+}
+
+// pendingTypes mengembalikan jenis event outbox yang belum dibagikan.
+func (e *testEnv) pendingTypes() []string {
+	var types []string
+	for _, event := range e.pendingEvents() {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+// relay membagikan semua event outbox ke webhook dan consumer-nya.
+func (e *testEnv) relay() {
+	e.t.Helper()
+
+	if _, err := e.dispatcher.RelayOutbox(e.ctx); err != nil {
+		e.t.Fatalf("relay outbox: %v", err)
+	}
+}
+
+// eventData mem-parse data event tugas.
+func eventData(t *testing.T, event models.OutboxEvent) taskEventData {
+	t.Helper()
+
+	var data taskEventData
+	if err := json.Unmarshal([]byte(event.Payload), &data); err != nil {
+		t.Fatalf("decode %s payload: %v", event.Type, err)
+	}
+	return data
+}
+
+// expectError menghentikan test jika err bukan want.
+func expectError(t *testing.T, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("expected %v, got %v", want, err)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/models"
+	"taskify/repository"
+)
+
+const (
+	BulkModeAtomic     = "atomic"      // Semua operasi berhasil, atau tidak ada yang disimpan
+	BulkModeBestEffort = "best_effort" // Operasi yang gagal dilewati, sisanya tetap disimpan
+
+	dateLayout = "2006-01-02"
+)
+
+// BulkTaskOperation: Satu operasi di dalam bulk request. Field yang dipakai tergantung nilai Op.
+type BulkTaskOperation struct {
+	Op              string            `json:"op"` // create, update_status, set_deadline, delete, move
+	TaskID          *uuid.UUID        `json:"task_id"`
+	Title           string            `json:"title"`
+	Description     string            `json:"description"`
+	Status          models.TaskStatus `json:"status"`
+	Deadline        json.RawMessage   `json:"deadline"` // YYYY-MM-DD atau null untuk menghapus deadline
+	TargetProjectID *uuid.UUID        `json:"target_project_id"`
+}
+
+// BulkTaskResult: Hasil per operasi, urutannya sama dengan urutan operasi di request.
+type BulkTaskResult struct {
+	Index  int        `json:"index"`
+	Op     string     `json:"op"`
+	Status string     `json:"status"` // ok, error, rolled_back, skipped
+	TaskID *uuid.UUID `json:"task_id,omitempty"`
+	Error  string     `json:"error,omitempty"`
+}
+
+// BulkOutcome adalah hasil keseluruhan bulk request. Aborted bernilai true jika mode atomic dibatalkan
+// karena salah satu operasi gagal; dalam hal itu tidak ada perubahan yang disimpan.
+type BulkOutcome struct {
+	Mode    string
+	Aborted bool
+	Results []BulkTaskResult
+}
+
+// Succeeded menghitung operasi yang berhasil disimpan.
+func (o BulkOutcome) Succeeded() int {
+	succeeded := 0
+	for _, result := range o.Results {
+		if result.Status == "ok" {
+			succeeded++
+		}
+	}
+	return succeeded
+}
+
+var errBulkAborted = errors.New("bulk operation aborted")
+
+// Bulk menjalankan banyak operasi tugas (create, update status, set deadline, delete, move) dalam satu
+// transaksi. Mode atomic membatalkan semuanya jika satu operasi gagal, sedangkan mode best_effort hanya
+// membatalkan operasi yang gagal (memakai savepoint).
+func (s *TaskService) Bulk(ctx context.Context, userID, projectID uuid.UUID, mode string, operations []BulkTaskOperation) (BulkOutcome, error) {
+	if mode == "" {
+		mode = BulkModeAtomic
+	}
+	if mode != BulkModeAtomic && mode != BulkModeBestEffort {
+		return BulkOutcome{}, Invalidf("mode must be either atomic or best_effort")
+	}
+	if len(operations) > s.bulkMaxOperations {
+		return BulkOutcome{}, Invalidf("A bulk request may contain at most %d operations", s.bulkMaxOperations)
+	}
+
+	project, err := s.writableProject(ctx, projectID, userID)
+	if err != nil {
+		return BulkOutcome{}, err
+	}
+
+	outcome := BulkOutcome{Mode: mode, Results: make([]BulkTaskResult, len(operations))}
+	for i, op := range operations {
+		outcome.Results[i] = BulkTaskResult{Index: i, Op: op.Op, Status: "skipped"}
+	}
+
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		for i, op := range operations {
+			var taskID uuid.UUID
+			var opErr error
+			if mode == BulkModeBestEffort {
+				// Transaksi bersarang = SAVEPOINT, jadi hanya operasi ini yang dibatalkan jika gagal
+				opErr = s.tx.Transaction(ctx, func(ctx context.Context) error {
+					var err error
+					taskID, err = s.applyBulkOperation(ctx, project, userID, op)
+					return err
+				})
+			} else {
+				taskID, opErr = s.applyBulkOperation(ctx, project, userID, op)
+			}
+
+			if opErr != nil {
+				var clientErr *Error
+				if !errors.As(opErr, &clientErr) {
+					return opErr // Error database: batalkan seluruh request
+				}
+				outcome.Results[i].Status = "error"
+				outcome.Results[i].Error = clientErr.Message
+				if mode == BulkModeAtomic {
+					return errBulkAborted
+				}
+				continue
+			}
+
+			outcome.Results[i].Status = "ok"
+			outcome.Results[i].TaskID = &taskID
+		}
+		return nil
+	})
+
+	if errors.Is(err, errBulkAborted) {
+		// Operasi yang sudah berhasil sebelum kegagalan ikut dibatalkan
+		outcome.Aborted = true
+		for i := range outcome.Results {
+			if outcome.Results[i].Status == "ok" {
+				outcome.Results[i].Status = "rolled_back"
+				outcome.Results[i].TaskID = nil
+			}
+		}
+		return outcome, nil
+	}
+	if err != nil {
+		return BulkOutcome{}, err
+	}
+	return outcome, nil
+}
+
+// applyBulkOperation menjalankan satu operasi dan mengembalikan ID tugas yang terkena.
+func (s *TaskService) applyBulkOperation(ctx context.Context, project models.Project, userID uuid.UUID, op BulkTaskOperation) (uuid.UUID, error) {
+	switch op.Op {
+	case "create":
+		return s.bulkCreateTask(ctx, project, userID, op)
+	case "update_status", "set_deadline", "delete", "move":
+	default:
+		return uuid.Nil, Invalidf("Unknown op %q; expected create, update_status, set_deadline, delete or move", op.Op)
+	}
+
+	if op.TaskID == nil {
+		return uuid.Nil, Invalidf("task_id is required for %s", op.Op)
+	}
+
+	task, err := s.findInProject(ctx, project.ID, *op.TaskID)
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			return uuid.Nil, Invalidf("Task not found in this project")
+		}
+		return uuid.Nil, err
+	}
+
+	switch op.Op {
+	case "update_status":
+		if !op.Status.IsValid() {
+			return uuid.Nil, Invalidf("status must be one of todo, in_progress, done")
+		}
+		return task.ID, s.updateBulkTask(ctx, task, userID, repository.Updates{"status": op.Status})
+
+	case "set_deadline":
+		deadline, err := parseBulkDeadline(op.Deadline, true)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return task.ID, s.updateBulkTask(ctx, task, userID, repository.Updates{"deadline": deadline})
+
+	case "delete":
+		if err := s.deleteTask(ctx, task, userID); err != nil {
+			if errors.Is(err, ErrPreconditionFailed) {
+				return uuid.Nil, Invalidf("Task was modified by another request")
+			}
+			return uuid.Nil, err
+		}
+		return task.ID, nil
+
+	case "move":
+		if op.TargetProjectID == nil {
+			return uuid.Nil, Invalidf("target_project_id is required for move")
+		}
+		if *op.TargetProjectID == project.ID {
+			return uuid.Nil, Invalidf("Task is already in the target project")
+		}
+		target, err := s.targetProject(ctx, *op.TargetProjectID, userID)
+		if err != nil {
+			if errors.Is(err, ErrTargetProjectNotFound) {
+				return uuid.Nil, Invalidf("Target project not found or you don't have access to it")
+			}
+			if errors.Is(err, ErrTargetProjectArchived) {
+				return uuid.Nil, Invalidf("Target project is archived and read-only")
+			}
+			return uuid.Nil, err
+		}
+		applied, err := s.moveTask(ctx, task, target.ID, userID)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if !applied {
+			return uuid.Nil, Invalidf("Task was modified by another request")
+		}
+		return task.ID, nil
+	}
+
+	return uuid.Nil, nil
+}
+
+func (s *TaskService) bulkCreateTask(ctx context.Context, project models.Project, actorID uuid.UUID, op BulkTaskOperation) (uuid.UUID, error) {
+	title := strings.TrimSpace(op.Title)
+	if title == "" {
+		return uuid.Nil, Invalidf("title is required for create")
+	}
+	if len(title) > 255 {
+		return uuid.Nil, Invalidf("title must be at most 255 characters")
+	}
+	if op.Status == "" {
+		op.Status = models.Todo
+	}
+	if !op.Status.IsValid() {
+		return uuid.Nil, Invalidf("status must be one of todo, in_progress, done")
+	}
+	deadline, err := parseBulkDeadline(op.Deadline, false)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	task := models.Task{
+		ID:          uuid.New(),
+		ProjectID:   project.ID,
+		Title:       title,
+		Description: op.Description,
+		Status:      op.Status,
+		Deadline:    deadline,
+		Version:     1,
+	}
+	if err := s.tasks.Create(ctx, &task); err != nil {
+		return uuid.Nil, err
+	}
+	return task.ID, s.events.taskCreated(ctx, task, actorID)
+}
+
+// updateBulkTask menerapkan perubahan dengan pengecekan versi yang sama seperti PUT/PATCH.
+func (s *TaskService) updateBulkTask(ctx context.Context, task models.Task, actorID uuid.UUID, updates repository.Updates) error {
+	applied, err := s.tasks.Update(ctx, task.ID, task.Version, updates)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return Invalidf("Task was modified by another request")
+	}
+	_, err = s.recordTaskUpdate(ctx, task, actorID)
+	return err
+}
+
+// parseBulkDeadline mem-parse deadline (YYYY-MM-DD); null menghapus deadline.
+// Jika required bernilai true, field deadline wajib ada (boleh null).
+func parseBulkDeadline(raw json.RawMessage, required bool) (*time.Time, error) {
+	if len(raw) == 0 {
+		if required {
+			return nil, Invalidf("deadline is required for set_deadline (use null to clear it)")
+		}
+		return nil, nil
+	}
+	if string(bytes.TrimSpace(raw)) == "null" {
+		return nil, nil
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, Invalidf("deadline must be a date string (YYYY-MM-DD) or null")
+	}
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return nil, Invalidf("deadline must use the YYYY-MM-DD format")
+	}
+	return &t, nil
+}
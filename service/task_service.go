@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/cache"
+	"taskify/models"
+	"taskify/repository"
+)
+
+// TaskInput berisi data untuk membuat tugas baru.
+type TaskInput struct {
+	Title       string
+	Description string
+	Status      models.TaskStatus
+	Deadline    *time.Time
+}
+
+// TaskChanges berisi field tugas yang ingin diubah; field nil tidak berubah. Deadline hanya diubah jika
+// SetDeadline bernilai true, sehingga deadline bisa dihapus dengan Deadline nil.
+type TaskChanges struct {
+	Title       *string
+	Description *string
+	Status      *models.TaskStatus
+	Deadline    *time.Time
+	SetDeadline bool
+}
+
+// TaskService berisi aturan bisnis tugas: akses untuk pemilik dan anggota proyek, proyek arsip yang
+// read-only, optimistic concurrency, serta perpindahan tugas antar proyek. Setiap perubahan dicatat ke
+// outbox di transaksi yang sama.
+type TaskService struct {
+	tasks             repository.TaskRepository
+	tx                repository.Transactor
+	access            projectAccess
+	events            eventRecorder
+	bulkMaxOperations int
+}
+
+// NewTaskService membuat TaskService. projectCache boleh nil jika cache akses tidak dipakai;
+// bulkMaxOperations (BULK_MAX_OPERATIONS) membatasi jumlah operasi per bulk request.
+func NewTaskService(projects repository.ProjectRepository, tasks repository.TaskRepository, outbox repository.OutboxRepository, tx repository.Transactor, projectCache cache.Store, bulkMaxOperations int) *TaskService {
+	return &TaskService{
+		tasks:             tasks,
+		tx:                tx,
+		access:            projectAccess{projects: projects, cache: projectCache},
+		events:            eventRecorder{outbox: outbox},
+		bulkMaxOperations: bulkMaxOperations,
+	}
+}
+
+// Create membuat tugas baru di proyek yang bisa diakses userID (pemilik atau anggota) dan tidak diarsipkan.
+func (s *TaskService) Create(ctx context.Context, userID, projectID uuid.UUID, input TaskInput) (models.Task, error) {
+	if _, err := s.writableProject(ctx, projectID, userID); err != nil {
+		return models.Task{}, err
+	}
+
+	task := models.Task{
+		ID:          uuid.New(),
+		ProjectID:   projectID,
+		Title:       input.Title,
+		Description: input.Description,
+		Status:      input.Status,
+		Deadline:    input.Deadline,
+		Version:     1,
+	}
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.tasks.Create(ctx, &task); err != nil {
+			return err
+		}
+		return s.events.taskCreated(ctx, task, userID)
+	})
+	if err != nil {
+		return models.Task{}, err
+	}
+	return s.tasks.FindByID(ctx, task.ID)
+}
+
+// List mengambil semua tugas di proyek milik userID atau yang userID menjadi anggotanya.
+func (s *TaskService) List(ctx context.Context, userID, projectID uuid.UUID) ([]models.Task, error) {
+	if _, err := s.access.member(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+	return s.tasks.ListByProject(ctx, projectID)
+}
+
+// Get mengambil satu tugas di proyek milik userID atau yang userID menjadi anggotanya.
+func (s *TaskService) Get(ctx context.Context, userID, projectID, taskID uuid.UUID) (models.Task, error) {
+	if _, err := s.access.member(ctx, projectID, userID); err != nil {
+		return models.Task{}, err
+	}
+	return s.findInProject(ctx, projectID, taskID)
+}
+
+// Update mengubah field tugas yang diberikan di changes.
+func (s *TaskService) Update(ctx context.Context, userID, projectID, taskID uuid.UUID, changes TaskChanges, check Precondition) (models.Task, error) {
+	if _, err := s.writableProject(ctx, projectID, userID); err != nil {
+		return models.Task{}, err
+	}
+	task, err := s.findInProject(ctx, projectID, taskID)
+	if err != nil {
+		return models.Task{}, err
+	}
+	if err := checkPrecondition(check, task.Version); err != nil {
+		return models.Task{}, err
+	}
+
+	updates := repository.Updates{}
+	if changes.Title != nil {
+		updates["title"] = *changes.Title
+	}
+	if changes.Description != nil {
+		updates["description"] = *changes.Description
+	}
+	if changes.Status != nil {
+		updates["status"] = *changes.Status
+	}
+	if changes.SetDeadline {
+		updates["deadline"] = changes.Deadline
+	}
+	if len(updates) == 0 {
+		return task, nil
+	}
+
+	var updated models.Task
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		applied, err := s.tasks.Update(ctx, task.ID, task.Version, updates)
+		if err != nil {
+			return err
+		}
+		if !applied {
+			return ErrPreconditionFailed
+		}
+		updated, err = s.recordTaskUpdate(ctx, task, userID)
+		return err
+	})
+	if err != nil {
+		return models.Task{}, err
+	}
+	return updated, nil
+}
+
+// Delete menghapus tugas beserta riwayatnya dalam satu transaksi.
+func (s *TaskService) Delete(ctx context.Context, userID, projectID, taskID uuid.UUID, check Precondition) error {
+	if _, err := s.writableProject(ctx, projectID, userID); err != nil {
+		return err
+	}
+	task, err := s.findInProject(ctx, projectID, taskID)
+	if err != nil {
+		return err
+	}
+	if err := checkPrecondition(check, task.Version); err != nil {
+		return err
+	}
+
+	return s.tx.Transaction(ctx, func(ctx context.Context) error {
+		return s.deleteTask(ctx, task, userID)
+	})
+}
+
+// Move memindahkan tugas ke proyek lain yang juga bisa diakses user dan mencatatnya di riwayat tugas.
+func (s *TaskService) Move(ctx context.Context, userID, projectID, taskID, targetProjectID uuid.UUID, check Precondition) (models.Task, error) {
+	task, target, err := s.loadTransfer(ctx, userID, projectID, taskID, targetProjectID, true)
+	if err != nil {
+		return models.Task{}, err
+	}
+	if target.ID == task.ProjectID {
+		return models.Task{}, ErrTaskInTargetProject
+	}
+	if err := checkPrecondition(check, task.Version); err != nil {
+		return models.Task{}, err
+	}
+
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		applied, err := s.moveTask(ctx, task, target.ID, userID)
+		if err != nil {
+			return err
+		}
+		if !applied {
+			return ErrPreconditionFailed
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Task{}, err
+	}
+	return s.tasks.FindByID(ctx, task.ID)
+}
+
+// Copy menyalin tugas ke proyek lain (atau proyek yang sama) yang bisa diakses user. Proyek asal boleh
+// diarsipkan karena hanya dibaca, tetapi proyek tujuan harus bisa ditulis.
+func (s *TaskService) Copy(ctx context.Context, userID, projectID, taskID, targetProjectID uuid.UUID) (models.Task, error) {
+	task, target, err := s.loadTransfer(ctx, userID, projectID, taskID, targetProjectID, false)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	var copied models.Task
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		copied, err = s.copyTask(ctx, task, target.ID, userID)
+		return err
+	})
+	if err != nil {
+		return models.Task{}, err
+	}
+	return s.tasks.FindByID(ctx, copied.ID)
+}
+
+// History mengambil riwayat perpindahan/penyalinan sebuah tugas, terbaru lebih dulu.
+func (s *TaskService) History(ctx context.Context, userID, projectID, taskID uuid.UUID) ([]models.TaskHistory, error) {
+	task, err := s.Get(ctx, userID, projectID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return s.tasks.ListHistory(ctx, task.ID)
+}
+
+// writableProject memastikan userID pemilik atau anggota proyek dan proyeknya tidak diarsipkan (read-only).
+func (s *TaskService) writableProject(ctx context.Context, projectID, userID uuid.UUID) (models.Project, error) {
+	project, err := s.access.member(ctx, projectID, userID)
+	if err != nil {
+		return models.Project{}, err
+	}
+	if project.Archived {
+		return models.Project{}, ErrProjectArchived
+	}
+	return project, nil
+}
+
+func (s *TaskService) findInProject(ctx context.Context, projectID, taskID uuid.UUID) (models.Task, error) {
+	task, err := s.tasks.FindInProject(ctx, projectID, taskID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.Task{}, ErrTaskNotFound
+	}
+	return task, err
+}
+
+// targetProject memastikan proyek tujuan perpindahan bisa diakses userID dan bisa ditulis.
+func (s *TaskService) targetProject(ctx context.Context, projectID, userID uuid.UUID) (models.Project, error) {
+	target, err := s.access.member(ctx, projectID, userID)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			return models.Project{}, ErrTargetProjectNotFound
+		}
+		return models.Project{}, err
+	}
+	if target.Archived {
+		return models.Project{}, ErrTargetProjectArchived
+	}
+	return target, nil
+}
+
+// loadTransfer memverifikasi bahwa user memiliki akses ke proyek asal maupun proyek tujuan untuk move/copy.
+func (s *TaskService) loadTransfer(ctx context.Context, userID, projectID, taskID, targetProjectID uuid.UUID, writableSource bool) (models.Task, models.Project, error) {
+	source, err := s.access.member(ctx, projectID, userID)
+	if err != nil {
+		return models.Task{}, models.Project{}, err
+	}
+	if writableSource && source.Archived {
+		return models.Task{}, models.Project{}, ErrProjectArchived
+	}
+
+	task, err := s.findInProject(ctx, projectID, taskID)
+	if err != nil {
+		return models.Task{}, models.Project{}, err
+	}
+
+	target, err := s.targetProject(ctx, targetProjectID, userID)
+	if err != nil {
+		return models.Task{}, models.Project{}, err
+	}
+	return task, target, nil
+}
+
+// recordTaskUpdate membaca ulang tugas yang baru diubah dan mencatat event perubahannya relatif terhadap
+// before. Panggil di dalam transaksi yang sama dengan perubahannya.
+func (s *TaskService) recordTaskUpdate(ctx context.Context, before models.Task, actorID uuid.UUID) (models.Task, error) {
+	after, err := s.tasks.FindByID(ctx, before.ID)
+	if err != nil {
+		return models.Task{}, err
+	}
+	return after, s.events.taskUpdated(ctx, before, after, actorID)
+}
+
+// deleteTask menghapus tugas dengan pengecekan versi beserta riwayatnya. Panggil di dalam transaksi.
+func (s *TaskService) deleteTask(ctx context.Context, task models.Task, actorID uuid.UUID) error {
+	applied, err := s.tasks.Delete(ctx, task.ID, task.Version)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return ErrPreconditionFailed
+	}
+	if err := s.tasks.DeleteHistory(ctx, task.ID); err != nil {
+		return err
+	}
+	return s.events.taskDeleted(ctx, task, actorID)
+}
+
+// moveTask memindahkan tugas ke proyek target dan mencatatnya di riwayat tugas.
+// Hasil false berarti tugas sudah diubah request lain sejak dibaca.
+func (s *TaskService) moveTask(ctx context.Context, task models.Task, targetProjectID, actorID uuid.UUID) (bool, error) {
+	applied, err := s.tasks.Update(ctx, task.ID, task.Version, repository.Updates{"project_id": targetProjectID})
+	if err != nil || !applied {
+		return applied, err
+	}
+
+	history := models.TaskHistory{
+		ID:            uuid.New(),
+		TaskID:        task.ID,
+		Action:        models.TaskMoved,
+		FromProjectID: task.ProjectID,
+		ToProjectID:   targetProjectID,
+		ActorID:       actorID,
+	}
+	if err := s.tasks.AddHistory(ctx, &history); err != nil {
+		return false, err
+	}
+
+	moved, err := s.tasks.FindByID(ctx, task.ID)
+	if err != nil {
+		return false, err
+	}
+	return true, s.events.taskMoved(ctx, moved, task.ProjectID, actorID)
+}
+
+// copyTask membuat salinan tugas di proyek target dan mencatat asal salinan di riwayat tugas baru.
+func (s *TaskService) copyTask(ctx context.Context, source models.Task, targetProjectID, actorID uuid.UUID) (models.Task, error) {
+	copied := models.Task{
+		ID:          uuid.New(),
+		ProjectID:   targetProjectID,
+		Title:       source.Title,
+		Description: source.Description,
+		Status:      source.Status,
+		Deadline:    source.Deadline,
+		Version:     1,
+	}
+	if err := s.tasks.Create(ctx, &copied); err != nil {
+		return copied, err
+	}
+
+	sourceTaskID := source.ID
+	history := models.TaskHistory{
+		ID:            uuid.New(),
+		TaskID:        copied.ID,
+		Action:        models.TaskCopied,
+		FromProjectID: source.ProjectID,
+		ToProjectID:   targetProjectID,
+		SourceTaskID:  &sourceTaskID,
+		ActorID:       actorID,
+	}
+	if err := s.tasks.AddHistory(ctx, &history); err != nil {
+		return copied, err
+	}
+	return copied, s.events.taskCreated(ctx, copied, actorID)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"taskify/models"
+)
+
+// staleVersion adalah Precondition yang selalu gagal, seperti If-Match dengan versi lama.
+func staleVersion(uint) error { return ErrPreconditionFailed }
+
+func TestTaskUpdateRecordsEvents(t *testing.T) {
+	env := newTestEnv(t)
+	owner := env.user("owner")
+	project := env.project(owner, "Events")
+	task := env.task(owner, project.ID, "Write")
+
+	updated, err := env.tasks.Update(env.ctx, owner.ID, project.ID, task.ID, TaskChanges{Status: ptr(models.Done)}, nil)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.Status != models.Done || updated.Version != task.Version+1 {
+		t.Fatalf("unexpected task after update: %+v", updated)
+	}
+
+	want := []string{models.EventTaskCreated, models.EventTaskUpdated, models.EventTaskStatusChanged}
+	if got := env.pendingTypes(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected events %v, got %v", want, got)
+	}
+	for _, event := range env.pendingEvents()[1:] {
+		if data := eventData(t, event); data.PreviousStatus != models.Todo || data.Status != models.Done || event.ActorID != owner.ID {
+			t.Fatalf("unexpected %s event: %+v", event.Type, data)
+		}
+	}
+
+	// Perubahan tanpa field tidak menaikkan versi maupun mencatat event
+	if same, err := env.tasks.Update(env.ctx, owner.ID, project.ID, task.ID, TaskChanges{}, nil); err != nil || same.Version != updated.Version {
+		t.Fatalf("expected an empty update to be a no-op, got %+v, %v", same, err)
+	}
+	// Precondition yang gagal tidak mengubah apa pun
+	_, err = env.tasks.Update(env.ctx, owner.ID, project.ID, task.ID, TaskChanges{Title: ptr("Stale")}, staleVersion)
+	expectError(t, err, ErrPreconditionFailed)
+	if current, _ := env.tasks.Get(env.ctx, owner.ID, project.ID, task.ID); current.Title != "Write" || current.Version != updated.Version {
+		t.Fatalf("expected the task to be unchanged, got %+v", current)
+	}
+	if got := env.pendingTypes(); len(got) != len(want) {
+		t.Fatalf("expected no new events, got %v", got)
+	}
+}
+
+func TestTaskWritesOnArchivedProject(t *testing.T) {
+	env := newTestEnv(t)
+	owner := env.user("owner")
+	project := env.project(owner, "Frozen")
+	task := env.task(owner, project.ID, "Keep")
+
+	if _, err := env.projects.SetArchived(env.ctx, owner.ID, project.ID, true, nil); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+
+	_, err := env.tasks.Create(env.ctx, owner.ID, project.ID, TaskInput{Title: "New", Status: models.Todo})
+	expectError(t, err, ErrProjectArchived)
+	_, err = env.tasks.Update(env.ctx, owner.ID, project.ID, task.ID, TaskChanges{Title: ptr("Changed")}, nil)
+	expectError(t, err, ErrProjectArchived)
+	expectError(t, env.tasks.Delete(env.ctx, owner.ID, project.ID, task.ID, nil), ErrProjectArchived)
+
+	// Proyek arsip tetap bisa dibaca
+	if tasks, err := env.tasks.List(env.ctx, owner.ID, project.ID); err != nil || len(tasks) != 1 {
+		t.Fatalf("expected the archived project to stay readable, got %v, %v", tasks, err)
+	}
+}
+
+func TestTaskAccessForMembers(t *testing.T) {
+	env := newTestEnv(t)
+	owner := env.user("owner")
+	member := env.user("member")
+	project := env.project(owner, "Shared")
+	task := env.task(owner, project.ID, "Shared task")
+
+	// Orang lain tidak bisa membedakan proyek yang ada dari yang tidak ada
+	_, err := env.tasks.List(env.ctx, member.ID, project.ID)
+	expectError(t, err, ErrProjectNotFound)
+	_, err = env.tasks.Get(env.ctx, member.ID, project.ID, task.ID)
+	expectError(t, err, ErrProjectNotFound)
+
+	env.addMember(owner, project.ID, member)
+	if _, err := env.tasks.Update(env.ctx, member.ID, project.ID, task.ID, TaskChanges{Title: ptr("Edited by member")}, nil); err != nil {
+		t.Fatalf("member update: %v", err)
+	}
+	created := env.task(member, project.ID, "Member task")
+	if created.ProjectID != project.ID {
+		t.Fatalf("expected the member's task in the shared project, got %+v", created)
+	}
+
+	// Proyek yang sudah ada di cache tidak memperpanjang akses anggota yang dikeluarkan
+	if _, err := env.projects.Get(env.ctx, owner.ID, project.ID); err != nil {
+		t.Fatalf("get project: %v", err)
+	}
+	if err := env.projects.RemoveMember(env.ctx, owner.ID, project.ID, member.ID); err != nil {
+		t.Fatalf("remove member: %v", err)
+	}
+	_, err = env.tasks.Update(env.ctx, member.ID, project.ID, task.ID, TaskChanges{Title: ptr("Too late")}, nil)
+	expectError(t, err, ErrProjectNotFound)
+	_, err = env.tasks.Get(env.ctx, member.ID, project.ID, task.ID)
+	expectError(t, err, ErrProjectNotFound)
+}
+
+func TestTaskMove(t *testing.T) {
+	env := newTestEnv(t)
+	owner := env.user("owner")
+	stranger := env.user("stranger")
+	source := env.project(owner, "Source")
+	target := env.project(owner, "Target")
+	foreign := env.project(stranger, "Foreign")
+	task := env.task(owner, source.ID, "Travel")
+
+	_, err := env.tasks.Move(env.ctx, owner.ID, source.ID, task.ID, foreign.ID, nil)
+	expectError(t, err, ErrTargetProjectNotFound)
+	_, err = env.tasks.Move(env.ctx, owner.ID, source.ID, task.ID, source.ID, nil)
+	expectError(t, err, ErrTaskInTargetProject)
+	_, err = env.tasks.Move(env.ctx, owner.ID, source.ID, task.ID, target.ID, staleVersion)
+	expectError(t, err, ErrPreconditionFailed)
+
+	moved, err := env.tasks.Move(env.ctx, owner.ID, source.ID, task.ID, target.ID, nil)
+	if err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if moved.ProjectID != target.ID {
+		t.Fatalf("expected the task in the target project, got %+v", moved)
+	}
+	_, err = env.tasks.Get(env.ctx, owner.ID, source.ID, task.ID)
+	expectError(t, err, ErrTaskNotFound)
+
+	// task.moved dicatat di kedua proyek dengan asal dan tujuan yang sama
+	var projects []uuid.UUID
+	for _, event := range env.pendingEvents() {
+		if event.Type != models.EventTaskMoved {
+			continue
+		}
+		data := eventData(t, event)
+		if *data.FromProjectID != source.ID || *data.ToProjectID != target.ID {
+			t.Fatalf("unexpected task.moved data: %+v", data)
+		}
+		projects = append(projects, event.ProjectID)
+	}
+	if want := []uuid.UUID{source.ID, target.ID}; !reflect.DeepEqual(projects, want) {
+		t.Fatalf("expected task.moved in %v, got %v", want, projects)
+	}
+
+	history, err := env.tasks.History(env.ctx, owner.ID, target.ID, task.ID)
+	if err != nil || len(history) == 0 {
+		t.Fatalf("expected the move in the task history, got %v, %v", history, err)
+	}
+}
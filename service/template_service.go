@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/models"
+	"taskify/repository"
+)
+
+// TemplateService menyimpan proyek sebagai template dan membuat proyek baru dari template.
+type TemplateService struct {
+	templates repository.TemplateRepository
+	projects  repository.ProjectRepository
+	tasks     repository.TaskRepository
+	tx        repository.Transactor
+	access    projectAccess
+}
+
+// NewTemplateService membuat TemplateService.
+func NewTemplateService(templates repository.TemplateRepository, projects repository.ProjectRepository, tasks repository.TaskRepository, tx repository.Transactor) *TemplateService {
+	return &TemplateService{
+		templates: templates,
+		projects:  projects,
+		tasks:     tasks,
+		tx:        tx,
+		access:    projectAccess{projects: projects},
+	}
+}
+
+// SaveProject menyimpan proyek beserta tugas-tugasnya sebagai template. Deadline disimpan sebagai selisih
+// hari dari deadline paling awal di proyek. Nama kosong dan deskripsi nil berarti diambil dari proyek.
+func (s *TemplateService) SaveProject(ctx context.Context, userID, projectID uuid.UUID, name string, description *string) (models.ProjectTemplate, error) {
+	project, err := s.access.findMember(ctx, projectID, userID)
+	if err != nil {
+		return models.ProjectTemplate{}, err
+	}
+	tasks, err := s.tasks.ListByProject(ctx, project.ID)
+	if err != nil {
+		return models.ProjectTemplate{}, err
+	}
+
+	template := models.ProjectTemplate{
+		ID:          uuid.New(),
+		Name:        project.Name,
+		Description: project.Description,
+		CreatedByID: userID,
+	}
+	if name = strings.TrimSpace(name); name != "" {
+		template.Name = name
+	}
+	if description != nil {
+		template.Description = *description
+	}
+
+	anchor := earliestDeadline(tasks)
+	for i, task := range tasks {
+		templateTask := models.TemplateTask{
+			ID:          uuid.New(),
+			TemplateID:  template.ID,
+			Position:    i,
+			Title:       task.Title,
+			Description: task.Description,
+			Status:      task.Status,
+		}
+		if task.Deadline != nil {
+			offset := daysBetween(*anchor, *task.Deadline)
+			templateTask.DeadlineOffsetDays = &offset
+		}
+		template.Tasks = append(template.Tasks, templateTask)
+	}
+
+	if err := s.templates.Create(ctx, &template); err != nil {
+		return models.ProjectTemplate{}, err
+	}
+	return template, nil
+}
+
+// List mengambil semua template milik userID.
+func (s *TemplateService) List(ctx context.Context, userID uuid.UUID) ([]models.ProjectTemplate, error) {
+	return s.templates.ListByOwner(ctx, userID)
+}
+
+// Get mengambil satu template beserta tugas-tugasnya dan memastikan userID adalah pemiliknya.
+func (s *TemplateService) Get(ctx context.Context, userID, templateID uuid.UUID) (models.ProjectTemplate, error) {
+	template, err := s.templates.FindByID(ctx, templateID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return models.ProjectTemplate{}, ErrTemplateNotFound
+		}
+		return models.ProjectTemplate{}, err
+	}
+	if template.CreatedByID != userID {
+		return models.ProjectTemplate{}, ErrTemplateNotFound
+	}
+	return template, nil
+}
+
+// Delete menghapus template beserta tugas-tugasnya. Proyek yang pernah dibuat dari template tidak terpengaruh.
+func (s *TemplateService) Delete(ctx context.Context, userID, templateID uuid.UUID) error {
+	template, err := s.Get(ctx, userID, templateID)
+	if err != nil {
+		return err
+	}
+	return s.templates.Delete(ctx, template.ID)
+}
+
+// Instantiate membuat proyek baru dari template. Deadline dihitung dari StartDate (default hari ini)
+// ditambah selisih hari yang tersimpan di template. Mengembalikan proyek baru dan jumlah tugas yang dibuat.
+func (s *TemplateService) Instantiate(ctx context.Context, userID, templateID uuid.UUID, opts CopyOptions) (models.Project, int, error) {
+	template, err := s.Get(ctx, userID, templateID)
+	if err != nil {
+		return models.Project{}, 0, err
+	}
+
+	project := models.Project{
+		ID:          uuid.New(),
+		Name:        template.Name,
+		Description: template.Description,
+		CreatedByID: template.CreatedByID,
+		Version:     1,
+	}
+	if opts.Name != "" {
+		project.Name = opts.Name
+	}
+	if opts.Description != nil {
+		project.Description = *opts.Description
+	}
+
+	startDate := dateOnly(time.Now())
+	if opts.StartDate != nil {
+		startDate = dateOnly(*opts.StartDate)
+	}
+
+	tasks := make([]*models.Task, 0, len(template.Tasks))
+	for _, templateTask := range template.Tasks {
+		task := &models.Task{
+			ID:          uuid.New(),
+			ProjectID:   project.ID,
+			Title:       templateTask.Title,
+			Description: templateTask.Description,
+			Status:      templateTask.Status,
+			Version:     1,
+		}
+		if opts.ResetStatus {
+			task.Status = models.Todo
+		}
+		if templateTask.DeadlineOffsetDays != nil {
+			deadline := startDate.AddDate(0, 0, *templateTask.DeadlineOffsetDays)
+			task.Deadline = &deadline
+		}
+		tasks = append(tasks, task)
+	}
+
+	created, err := createProjectWithTasks(ctx, s.tx, s.projects, s.tasks, project, tasks)
+	return created, len(tasks), err
+}
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/config"
+	"taskify/models"
+	"taskify/repository"
+)
+
+const (
+	dispatchBatchSize     = 100
+	deliveryConcurrency   = 8
+	maxLoggedResponseBody = 1024
+
+	// Header yang dikirim bersama setiap pengiriman webhook.
+	HeaderWebhookEvent     = "X-Taskify-Event"
+	HeaderWebhookDelivery  = "X-Taskify-Delivery"
+	HeaderWebhookTimestamp = "X-Taskify-Timestamp"
+	HeaderWebhookSignature = "X-Taskify-Signature"
+)
+
+// errDeliveryLeaseLost membatalkan transaksi penyimpanan hasil percobaan yang lease-nya sudah tidak dipegang.
+var errDeliveryLeaseLost = errors.New("webhook delivery lease lost")
+
+// SignWebhook menghitung nilai header X-Taskify-Signature: "sha256=" diikuti HMAC-SHA256 heksadesimal dari
+// "<timestamp>.<body>" dengan secret webhook. Timestamp ikut ditandatangani agar penerima bisa menolak replay.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// EventPublisher menerima event outbox yang sudah dibagikan, misalnya untuk disiarkan ke client real-time.
+type EventPublisher interface {
+	PublishEvent(ctx context.Context, event models.OutboxEvent) error
+}
+
+// EventConsumer memproses event outbox di dalam transaksi yang sama dengan klaim event-nya, sehingga
+// perubahan yang dibuatnya tersimpan tepat sekali per event. Error membatalkan klaim dan event dicoba lagi.
+type EventConsumer interface {
+	ConsumeEvent(ctx context.Context, event models.OutboxEvent) error
+}
+
+// WebhookDispatcher meneruskan event dari outbox ke webhook dalam dua langkah: RelayOutbox membagikan
+// setiap event menjadi pengiriman per webhook yang berlangganan, lalu DeliverDue mengirim pengiriman yang
+// jatuh tempo dan menjadwalkan retry. Keduanya aman dijalankan dari beberapa instance sekaligus karena
+// setiap event dan pengiriman diklaim dulu sebelum diproses.
+type WebhookDispatcher struct {
+	outbox    repository.OutboxRepository
+	webhooks  repository.WebhookRepository
+	tx        repository.Transactor
+	publisher EventPublisher
+	consumers []EventConsumer
+	client    *http.Client
+	cfg       config.WebhookConfig
+}
+
+// NewWebhookDispatcher membuat WebhookDispatcher dengan pengaturan retry dan timeout dari cfg. publisher boleh
+// nil; jika diisi, setiap event juga diteruskan ke publisher tepat sekali setelah dibagikan. consumers
+// dijalankan untuk setiap event di dalam transaksi pembagiannya.
+func NewWebhookDispatcher(outbox repository.OutboxRepository, webhooks repository.WebhookRepository, tx repository.Transactor, publisher EventPublisher, cfg config.WebhookConfig, consumers ...EventConsumer) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		outbox:    outbox,
+		webhooks:  webhooks,
+		tx:        tx,
+		publisher: publisher,
+		consumers: consumers,
+		client:    newWebhookClient(cfg),
+		cfg:       cfg,
+	}
+}
+
+// Run menjalankan RelayOutbox dan DeliverDue setiap PollInterval sampai ctx dibatalkan.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.RelayOutbox(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("failed to relay outbox events", "error", err)
+		}
+		if _, err := d.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("failed to deliver webhooks", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOutbox membagikan event outbox yang belum diproses menjadi pengiriman untuk setiap webhook aktif
+// yang berlangganan, dan mengembalikan jumlah pengiriman yang dibuat. Penandaan event dan pembuatan
+// pengirimannya ada di satu transaksi, jadi event tidak pernah hilang maupun dibagikan dua kali. Event yang
+// gagal dibagikan dicatat lalu dilewati supaya tidak menahan event lain, dan disisihkan sebagai dead setelah
+// RelayMaxAttempts percobaan.
+func (d *WebhookDispatcher) RelayOutbox(ctx context.Context) (int, error) {
+	events, err := d.outbox.ListPending(ctx, dispatchBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, event := range events {
+		claimed := false
+		err := d.tx.Transaction(ctx, func(ctx context.Context) error {
+			now := time.Now()
+			var err error
+			claimed, err = d.outbox.MarkProcessed(ctx, event.ID, now)
+			if err != nil || !claimed {
+				return err
+			}
+
+			webhooks, err := d.webhooks.ListByProject(ctx, event.ProjectID)
+			if err != nil {
+				return err
+			}
+			var deliveries []*models.WebhookDelivery
+			for _, webhook := range webhooks {
+				if webhook.Active && webhook.Events.Includes(event.Type) {
+					delivery := newDelivery(webhook, event.ID, event.Type, now)
+					deliveries = append(deliveries, &delivery)
+				}
+			}
+			if len(deliveries) > 0 {
+				if err := d.webhooks.CreateDeliveries(ctx, deliveries...); err != nil {
+					return err
+				}
+			}
+			for _, consumer := range d.consumers {
+				if err := consumer.ConsumeEvent(ctx, event); err != nil {
+					return err
+				}
+			}
+
+			// Langganan proyek yang dihapus baru dibuang setelah event penghapusannya dibagikan
+			if event.Type == models.EventProjectDeleted {
+				if err := d.webhooks.DeleteByProject(ctx, event.ProjectID); err != nil {
+					return err
+				}
+			}
+			created += len(deliveries)
+			return nil
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return created, ctx.Err()
+			}
+			d.recordRelayFailure(ctx, event, err)
+			continue
+		}
+
+		// Disiarkan setelah commit dan hanya oleh instance yang mengklaim event, jadi tidak pernah ganda.
+		// Jika gagal, event hanya terlewat di stream real-time; pengiriman webhook-nya tetap tersimpan
+		if claimed && d.publisher != nil {
+			if err := d.publisher.PublishEvent(ctx, event); err != nil && ctx.Err() == nil {
+				slog.Warn("failed to publish event to realtime stream", "event_id", event.ID.String(), "error", err)
+			}
+		}
+	}
+	return created, nil
+}
+
+// recordRelayFailure mencatat event yang gagal dibagikan di barisnya sendiri. Jika pencatatannya juga gagal,
+// event tetap pending dan dicoba lagi pada giliran berikutnya tanpa menambah hitungan percobaannya.
+func (d *WebhookDispatcher) recordRelayFailure(ctx context.Context, event models.OutboxEvent, cause error) {
+	logger := slog.With("event_id", event.ID.String(), "type", event.Type)
+	dead, err := d.outbox.RecordFailure(ctx, event.ID, cause.Error(), d.cfg.RelayMaxAttempts, time.Now())
+	switch {
+	case err != nil:
+		logger.Error("failed to relay outbox event", "error", cause, "record_error", err)
+	case dead:
+		logger.Error("outbox event moved to dead letter", "attempts", d.cfg.RelayMaxAttempts, "error", cause)
+	default:
+		logger.Warn("failed to relay outbox event, will retry", "error", cause)
+	}
+}
+
+// DeliverDue mengirim pengiriman pending yang sudah jatuh tempo (paralel, dibatasi deliveryConcurrency)
+// dan mengembalikan jumlah percobaan yang dilakukan.
+func (d *WebhookDispatcher) DeliverDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	due, err := d.webhooks.ListDueDeliveries(ctx, now, dispatchBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		attempted int
+		firstErr  error
+	)
+	slots := make(chan struct{}, deliveryConcurrency)
+	for _, delivery := range due {
+		// Slot diambil sebelum klaim, supaya lease tidak berjalan selama pengiriman masih mengantre
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return attempted, firstErr
+		}
+
+		// Lease lebih lama dari timeout request, supaya instance lain tidak mengirim ulang selama masih
+		// berjalan. Dibulatkan ke mikrodetik agar sama persis dengan nilai yang tersimpan di database
+		now := time.Now()
+		leaseUntil := now.Add(d.cfg.Timeout + time.Minute).Truncate(time.Microsecond)
+		claimed, err := d.webhooks.ClaimDelivery(ctx, delivery.ID, now, leaseUntil)
+		if err != nil || !claimed {
+			<-slots
+			if err != nil {
+				wg.Wait()
+				return attempted, err
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(delivery models.WebhookDelivery) {
+			defer func() { <-slots; wg.Done() }()
+			err := d.attempt(ctx, delivery, leaseUntil)
+
+			mu.Lock()
+			defer mu.Unlock()
+			attempted++
+			if err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("deliver %s: %w", delivery.ID, err)
+			}
+		}(delivery)
+	}
+	wg.Wait()
+	return attempted, firstErr
+}
+
+// attempt mengirim satu pengiriman, mencatat hasilnya di log percobaan, lalu menandainya succeeded,
+// menjadwalkan retry dengan backoff eksponensial, atau menjadikannya dead setelah MaxAttempts. Hasilnya
+// hanya disimpan jika lease leaseUntil masih dipegang.
+func (d *WebhookDispatcher) attempt(ctx context.Context, delivery models.WebhookDelivery, leaseUntil time.Time) error {
+	event, err := d.outbox.FindByID(ctx, delivery.EventID)
+	if errors.Is(err, repository.ErrNotFound) {
+		_, err := d.webhooks.UpdateDelivery(ctx, delivery.ID, leaseUntil, repository.Updates{
+			"status":          models.DeliveryDead,
+			"next_attempt_at": (*time.Time)(nil),
+			"last_error":      "event no longer exists",
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	body, err := encodeEnvelope(event)
+	if err != nil {
+		return err
+	}
+
+	number := delivery.Attempts + 1
+	started := time.Now()
+	statusCode, responseBody, sendErr := d.send(ctx, delivery, body)
+	if ctx.Err() != nil {
+		// Shutdown di tengah pengiriman tidak dihitung sebagai percobaan; lease habis lalu dicoba lagi
+		return nil
+	}
+
+	attempt := models.WebhookDeliveryAttempt{
+		ID:           uuid.New(),
+		DeliveryID:   delivery.ID,
+		Attempt:      number,
+		StatusCode:   statusCode,
+		ResponseBody: responseBody,
+		DurationMs:   time.Since(started).Milliseconds(),
+		CreatedAt:    time.Now(),
+	}
+	if sendErr != nil {
+		attempt.Error = sendErr.Error()
+	} else if statusCode < 200 || statusCode > 299 {
+		attempt.Error = fmt.Sprintf("receiver responded with status %d", statusCode)
+	}
+
+	updates := repository.Updates{
+		"attempts":         number,
+		"last_status_code": statusCode,
+		"last_error":       attempt.Error,
+	}
+	switch {
+	case attempt.Error == "":
+		updates["status"] = models.DeliverySucceeded
+		updates["next_attempt_at"] = (*time.Time)(nil)
+		updates["delivered_at"] = &attempt.CreatedAt
+	case number >= d.cfg.MaxAttempts:
+		updates["status"] = models.DeliveryDead
+		updates["next_attempt_at"] = (*time.Time)(nil)
+	default:
+		next := attempt.CreatedAt.Add(d.backoff(number))
+		updates["next_attempt_at"] = &next
+	}
+
+	err = d.tx.Transaction(ctx, func(ctx context.Context) error {
+		held, err := d.webhooks.UpdateDelivery(ctx, delivery.ID, leaseUntil, updates)
+		if err != nil {
+			return err
+		}
+		if !held {
+			return errDeliveryLeaseLost
+		}
+		return d.webhooks.AddAttempt(ctx, &attempt)
+	})
+	if errors.Is(err, errDeliveryLeaseLost) {
+		// Lease habis sebelum hasilnya tersimpan dan pengiriman sudah diklaim instance lain, jadi hasil
+		// percobaan ini dibuang agar jumlah percobaan dan statusnya tidak tertimpa
+		slog.Warn("webhook delivery lease expired before the attempt was recorded", "delivery_id", delivery.ID.String())
+		return nil
+	}
+	return err
+}
+
+// send mengirim body bertanda tangan ke URL pengiriman dan mengembalikan status code serta awal body response.
+func (d *WebhookDispatcher) send(ctx context.Context, delivery models.WebhookDelivery, body []byte) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Taskify-Webhooks/1.0")
+	req.Header.Set(HeaderWebhookEvent, delivery.EventType)
+	req.Header.Set(HeaderWebhookDelivery, delivery.ID.String())
+	req.Header.Set(HeaderWebhookTimestamp, timestamp)
+	req.Header.Set(HeaderWebhookSignature, SignWebhook(delivery.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, maxLoggedResponseBody))
+	// Sisa body dibuang supaya koneksi bisa dipakai ulang
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	return resp.StatusCode, string(excerpt), nil
+}
+
+// backoff menghitung jeda sebelum percobaan berikutnya: BackoffBase * 2^(attempt-1), dibatasi BackoffMax,
+// dengan jitter acak di paruh atas agar retry dari banyak pengiriman tidak serentak.
+func (d *WebhookDispatcher) backoff(attempt int) time.Duration {
+	delay := d.cfg.BackoffBase
+	for i := 1; i < attempt && delay < d.cfg.BackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > d.cfg.BackoffMax {
+		delay = d.cfg.BackoffMax
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
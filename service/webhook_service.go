@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/cache"
+	"taskify/models"
+	"taskify/repository"
+)
+
+// deliveryListLimit membatasi jumlah pengiriman yang ditampilkan per webhook.
+const deliveryListLimit = 50
+
+// WebhookInput berisi data untuk membuat webhook baru. Secret kosong berarti dibuatkan secara acak.
+type WebhookInput struct {
+	URL    string
+	Secret string
+	Events []string
+	Active *bool // Nil berarti aktif
+}
+
+// WebhookChanges berisi field webhook yang ingin diubah; field nil tidak berubah.
+type WebhookChanges struct {
+	URL    *string
+	Secret *string
+	Events []string
+	Active *bool
+}
+
+// WebhookService mengelola langganan webhook per proyek dan riwayat pengirimannya. Pengirimannya sendiri
+// dilakukan oleh WebhookDispatcher.
+type WebhookService struct {
+	webhooks     repository.WebhookRepository
+	access       projectAccess
+	allowPrivate bool
+}
+
+// NewWebhookService membuat WebhookService. projectCache boleh nil jika cache akses tidak dipakai.
+// allowPrivateTargets (WEBHOOK_ALLOW_PRIVATE_TARGETS) mengizinkan URL ke alamat loopback, privat, dan
+// link-local.
+func NewWebhookService(projects repository.ProjectRepository, webhooks repository.WebhookRepository, projectCache cache.Store, allowPrivateTargets bool) *WebhookService {
+	return &WebhookService{
+		webhooks:     webhooks,
+		access:       projectAccess{projects: projects, cache: projectCache},
+		allowPrivate: allowPrivateTargets,
+	}
+}
+
+// Create mendaftarkan webhook baru di proyek milik userID yang tidak diarsipkan. Webhook yang dikembalikan
+// berisi Secret; hanya di sini secret bisa dibaca oleh client.
+func (s *WebhookService) Create(ctx context.Context, userID, projectID uuid.UUID, input WebhookInput) (models.Webhook, error) {
+	if _, err := s.writableProject(ctx, projectID, userID); err != nil {
+		return models.Webhook{}, err
+	}
+
+	webhookURL, err := validateWebhookURL(input.URL, s.allowPrivate)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	events, err := validateWebhookEvents(input.Events)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	secret, err := webhookSecret(input.Secret)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	webhook := models.Webhook{
+		ID:          uuid.New(),
+		ProjectID:   projectID,
+		URL:         webhookURL,
+		Secret:      secret,
+		Events:      events,
+		Active:      input.Active == nil || *input.Active,
+		CreatedByID: userID,
+	}
+	if err := s.webhooks.Create(ctx, &webhook); err != nil {
+		return models.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// List mengambil semua webhook di proyek milik userID.
+func (s *WebhookService) List(ctx context.Context, userID, projectID uuid.UUID) ([]models.Webhook, error) {
+	if _, err := s.access.cached(ctx, projectID, userID); err != nil {
+		return nil, err
+	}
+	return s.webhooks.ListByProject(ctx, projectID)
+}
+
+// Get mengambil satu webhook di proyek milik userID.
+func (s *WebhookService) Get(ctx context.Context, userID, projectID, webhookID uuid.UUID) (models.Webhook, error) {
+	if _, err := s.access.cached(ctx, projectID, userID); err != nil {
+		return models.Webhook{}, err
+	}
+	return s.findInProject(ctx, projectID, webhookID)
+}
+
+// Update mengubah URL, secret, event, atau status aktif webhook. Pengiriman yang sudah dibuat tetap memakai
+// URL dan secret lama; redeliver memakai nilai terbaru.
+func (s *WebhookService) Update(ctx context.Context, userID, projectID, webhookID uuid.UUID, changes WebhookChanges) (models.Webhook, error) {
+	if _, err := s.writableProject(ctx, projectID, userID); err != nil {
+		return models.Webhook{}, err
+	}
+	webhook, err := s.findInProject(ctx, projectID, webhookID)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	updates := repository.Updates{}
+	if changes.URL != nil {
+		webhookURL, err := validateWebhookURL(*changes.URL, s.allowPrivate)
+		if err != nil {
+			return models.Webhook{}, err
+		}
+		updates["url"] = webhookURL
+	}
+	if changes.Events != nil {
+		events, err := validateWebhookEvents(changes.Events)
+		if err != nil {
+			return models.Webhook{}, err
+		}
+		updates["events"] = events
+	}
+	if changes.Secret != nil {
+		if strings.TrimSpace(*changes.Secret) == "" {
+			return models.Webhook{}, Invalidf("secret must not be empty")
+		}
+		secret, err := webhookSecret(*changes.Secret)
+		if err != nil {
+			return models.Webhook{}, err
+		}
+		updates["secret"] = secret
+	}
+	if changes.Active != nil {
+		updates["active"] = *changes.Active
+	}
+	if len(updates) == 0 {
+		return webhook, nil
+	}
+
+	if err := s.webhooks.Update(ctx, webhook.ID, updates); err != nil {
+		return models.Webhook{}, err
+	}
+	return s.webhooks.FindByID(ctx, webhook.ID)
+}
+
+// Delete menghapus webhook beserta riwayat pengirimannya; pengiriman yang belum terkirim dibatalkan.
+func (s *WebhookService) Delete(ctx context.Context, userID, projectID, webhookID uuid.UUID) error {
+	if _, err := s.writableProject(ctx, projectID, userID); err != nil {
+		return err
+	}
+	webhook, err := s.findInProject(ctx, projectID, webhookID)
+	if err != nil {
+		return err
+	}
+	return s.webhooks.Delete(ctx, webhook.ID)
+}
+
+// Deliveries mengambil pengiriman terbaru webhook beserta log setiap percobaannya.
+func (s *WebhookService) Deliveries(ctx context.Context, userID, projectID, webhookID uuid.UUID) ([]models.WebhookDelivery, error) {
+	webhook, err := s.Get(ctx, userID, projectID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	return s.webhooks.ListDeliveries(ctx, webhook.ID, deliveryListLimit)
+}
+
+// Redeliver menjadwalkan ulang event dari sebuah pengiriman (misalnya yang sudah dead) sebagai pengiriman
+// baru ke URL dan secret webhook saat ini. Pengiriman lama beserta lognya tidak diubah.
+func (s *WebhookService) Redeliver(ctx context.Context, userID, projectID, webhookID, deliveryID uuid.UUID) (models.WebhookDelivery, error) {
+	webhook, err := s.Get(ctx, userID, projectID, webhookID)
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	if !webhook.Active {
+		return models.WebhookDelivery{}, ErrWebhookInactive
+	}
+
+	previous, err := s.webhooks.FindDelivery(ctx, deliveryID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && previous.WebhookID != webhook.ID) {
+		return models.WebhookDelivery{}, ErrDeliveryNotFound
+	}
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+
+	delivery := newDelivery(webhook, previous.EventID, previous.EventType, time.Now())
+	if err := s.webhooks.CreateDeliveries(ctx, &delivery); err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	return s.webhooks.FindDelivery(ctx, delivery.ID)
+}
+
+// writableProject memastikan proyek milik userID dan tidak diarsipkan (read-only).
+func (s *WebhookService) writableProject(ctx context.Context, projectID, userID uuid.UUID) (models.Project, error) {
+	project, err := s.access.cached(ctx, projectID, userID)
+	if err != nil {
+		return models.Project{}, err
+	}
+	if project.Archived {
+		return models.Project{}, ErrProjectArchived
+	}
+	return project, nil
+}
+
+func (s *WebhookService) findInProject(ctx context.Context, projectID, webhookID uuid.UUID) (models.Webhook, error) {
+	webhook, err := s.webhooks.FindByID(ctx, webhookID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && webhook.ProjectID != projectID) {
+		return models.Webhook{}, ErrWebhookNotFound
+	}
+	return webhook, err
+}
+
+// newDelivery membuat pengiriman pending untuk event yang langsung jatuh tempo pada now, dengan URL dan
+// secret webhook saat ini.
+func newDelivery(webhook models.Webhook, eventID uuid.UUID, eventType string, now time.Time) models.WebhookDelivery {
+	return models.WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     webhook.ID,
+		EventID:       eventID,
+		EventType:     eventType,
+		URL:           webhook.URL,
+		Secret:        webhook.Secret,
+		Status:        models.DeliveryPending,
+		NextAttemptAt: &now,
+	}
+}
+
+// validateWebhookURL memastikan URL absolut http(s) dengan host. Kecuali allowPrivate, host berupa alamat IP
+// internal atau localhost langsung ditolak; nama host lain baru diperiksa saat dihubungi (lihat
+// newWebhookClient) karena hasil DNS-nya bisa berubah.
+func validateWebhookURL(raw string, allowPrivate bool) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", Invalidf("url is required")
+	}
+	if len(raw) > 2048 {
+		return "", Invalidf("url must be at most 2048 characters")
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", Invalidf("url must be an absolute http or https URL")
+	}
+	if !allowPrivate && internalWebhookHost(parsed.Hostname()) {
+		return "", Invalidf("url must not point to a loopback, private or link-local address")
+	}
+	return raw, nil
+}
+
+// validateWebhookEvents memastikan setidaknya satu jenis event yang dikenal (atau "*") dan membuang duplikat.
+func validateWebhookEvents(events []string) (models.WebhookEvents, error) {
+	if len(events) == 0 {
+		return nil, Invalidf("events must contain at least one event type (or \"*\" for all)")
+	}
+	known := map[string]bool{"*": true}
+	for _, eventType := range models.EventTypes {
+		known[eventType] = true
+	}
+
+	seen := map[string]bool{}
+	result := models.WebhookEvents{}
+	for _, eventType := range events {
+		eventType = strings.TrimSpace(eventType)
+		if !known[eventType] {
+			return nil, Invalidf("Unknown event type %q; expected one of %s or \"*\"", eventType, strings.Join(models.EventTypes, ", "))
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			result = append(result, eventType)
+		}
+	}
+	return result, nil
+}
+
+// webhookSecret memvalidasi secret dari client, atau membuat secret acak 32 byte jika kosong.
+func webhookSecret(secret string) (string, error) {
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		raw := make([]byte, 32)
+		if _, err := rand.Read(raw); err != nil {
+			return "", err
+		}
+		return "whsec_" + hex.EncodeToString(raw), nil
+	}
+	if len(secret) < 16 || len(secret) > 255 {
+		return "", Invalidf("secret must be between 16 and 255 characters")
+	}
+	return secret, nil
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"taskify/config"
+	"taskify/models"
+)
+
+// deliveries mengembalikan pengiriman webhook beserta log percobaannya, terbaru lebih dulu.
+func (e *testEnv) deliveries(webhookID uuid.UUID) []models.WebhookDelivery {
+	e.t.Helper()
+
+	deliveries, err := e.repo.Webhooks().ListDeliveries(e.ctx, webhookID, 100)
+	if err != nil {
+		e.t.Fatalf("list deliveries: %v", err)
+	}
+	return deliveries
+}
+
+// deliverDue menjalankan DeliverDue dan memastikan jumlah percobaannya want.
+func (e *testEnv) deliverDue(want int) {
+	e.t.Helper()
+
+	attempted, err := e.dispatcher.DeliverDue(e.ctx)
+	if err != nil {
+		e.t.Fatalf("deliver due: %v", err)
+	}
+	if attempted != want {
+		e.t.Fatalf("expected %d attempts, got %d", want, attempted)
+	}
+}
+
+func TestWebhookServiceValidation(t *testing.T) {
+	env := newTestEnv(t, func(cfg *config.WebhookConfig) { cfg.AllowPrivateTargets = false })
+	owner := env.user("owner")
+	stranger := env.user("stranger")
+	project := env.project(owner, "Hooks")
+
+	for _, url := range []string{"ftp://example.com/hook", "/relative", "http://127.0.0.1/hook", "http://localhost:8080/hook", "http://10.0.0.5/hook", "http://[::1]/hook"} {
+		_, err := env.webhooks.Create(env.ctx, owner.ID, project.ID, WebhookInput{URL: url, Events: []string{"*"}})
+		if serviceErr, ok := err.(*Error); !ok || serviceErr.Kind != KindInvalid {
+			t.Fatalf("expected %q to be rejected as invalid, got %v", url, err)
+		}
+	}
+	_, err := env.webhooks.Create(env.ctx, owner.ID, project.ID, WebhookInput{URL: "https://example.com/hook", Events: []string{"task.exploded"}})
+	if serviceErr, ok := err.(*Error); !ok || serviceErr.Kind != KindInvalid {
+		t.Fatalf("expected an unknown event to be rejected, got %v", err)
+	}
+	_, err = env.webhooks.Create(env.ctx, stranger.ID, project.ID, WebhookInput{URL: "https://example.com/hook", Events: []string{"*"}})
+	expectError(t, err, ErrProjectNotFound)
+
+	webhook, err := env.webhooks.Create(env.ctx, owner.ID, project.ID, WebhookInput{URL: "https://example.com/hook", Events: []string{models.EventTaskCreated}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if webhook.Secret == "" || !webhook.Active {
+		t.Fatalf("expected an active webhook with a generated secret, got %+v", webhook)
+	}
+
+	// Anggota proyek tidak mengelola webhook
+	member := env.user("member")
+	env.addMember(owner, project.ID, member)
+	_, err = env.webhooks.List(env.ctx, member.ID, project.ID)
+	expectError(t, err, ErrProjectNotFound)
+
+	// Alamat internal boleh dipakai jika diizinkan konfigurasi
+	allowing := NewWebhookService(env.repo.Projects(), env.repo.Webhooks(), env.cache, true)
+	if _, err := allowing.Create(env.ctx, owner.ID, project.ID, WebhookInput{URL: "http://127.0.0.1:9000/hook", Events: []string{"*"}}); err != nil {
+		t.Fatalf("expected internal targets to be allowed, got %v", err)
+	}
+}
+
+func TestWebhookDispatcherRetriesUntilSuccess(t *testing.T) {
+	env := newTestEnv(t)
+	owner := env.user("owner")
+	project := env.project(owner, "Deliveries")
+
+	var calls atomic.Int32
+	var received EventEnvelope
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(HeaderWebhookSignature) != SignWebhook("receiver-shared-secret", r.Header.Get(HeaderWebhookTimestamp), body) {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	webhook, err := env.webhooks.Create(env.ctx, owner.ID, project.ID, WebhookInput{URL: receiver.URL, Secret: "receiver-shared-secret", Events: []string{models.EventTaskCreated}})
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	task := env.task(owner, project.ID, "Ping")
+	// Event lain tidak berlangganan sehingga tidak menjadi pengiriman
+	if _, err := env.tasks.Update(env.ctx, owner.ID, project.ID, task.ID, TaskChanges{Title: ptr("Pong")}, nil); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if created, err := env.dispatcher.RelayOutbox(env.ctx); err != nil || created != 1 {
+		t.Fatalf("expected one delivery, got %d, %v", created, err)
+	}
+	// Relay ulang tidak membuat pengiriman ganda
+	if created, err := env.dispatcher.RelayOutbox(env.ctx); err != nil || created != 0 {
+		t.Fatalf("expected no new deliveries, got %d, %v", created, err)
+	}
+
+	env.deliverDue(1)
+	delivery := env.deliveries(webhook.ID)[0]
+	if delivery.Status != models.DeliveryPending || delivery.Attempts != 1 || delivery.LastStatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected a scheduled retry, got %+v", delivery)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	env.deliverDue(1)
+	delivery = env.deliveries(webhook.ID)[0]
+	if delivery.Status != models.DeliverySucceeded || delivery.Attempts != 2 || delivery.DeliveredAt == nil || len(delivery.Log) != 2 {
+		t.Fatalf("expected the retry to succeed, got %+v", delivery)
+	}
+	if received.Type != models.EventTaskCreated || received.ProjectID != project.ID || received.ActorID != owner.ID {
+		t.Fatalf("unexpected envelope: %+v", received)
+	}
+	env.deliverDue(0)
+}
+
+func TestWebhookDispatcherGivesUpAfterMaxAttempts(t *testing.T) {
+	env := newTestEnv(t)
+	owner := env.user("owner")
+	project := env.project(owner, "Dead letters")
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "broken", http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	webhook, err := env.webhooks.Create(env.ctx, owner.ID, project.ID, WebhookInput{URL: receiver.URL, Events: []string{"*"}})
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	env.task(owner, project.ID, "Doomed")
+	env.relay()
+
+	for i := 0; i < 3; i++ {
+		time.Sleep(5 * time.Millisecond)
+		env.deliverDue(1)
+	}
+	delivery := env.deliveries(webhook.ID)[0]
+	if delivery.Status != models.DeliveryDead || delivery.Attempts != 3 || delivery.NextAttemptAt != nil {
+		t.Fatalf("expected a dead delivery after 3 attempts, got %+v", delivery)
+	}
+	time.Sleep(5 * time.Millisecond)
+	env.deliverDue(0)
+}
+
+// consumerFunc adalah EventConsumer dari sebuah fungsi.
+type consumerFunc func(ctx context.Context, event models.OutboxEvent) error
+
+func (f consumerFunc) ConsumeEvent(ctx context.Context, event models.OutboxEvent) error {
+	return f(ctx, event)
+}
+
+func TestRelayOutboxIsolatesFailingEvents(t *testing.T) {
+	env := newTestEnv(t)
+	owner := env.user("owner")
+	project := env.project(owner, "Poison")
+	webhook, err := env.webhooks.Create(env.ctx, owner.ID, project.ID, WebhookInput{URL: "https://example.com/hook", Events: []string{"*"}})
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	poison := env.task(owner, project.ID, "Poison")
+	env.task(owner, project.ID, "Healthy")
+
+	cfg := config.Default().Webhook
+	cfg.RelayMaxAttempts = 2
+	failing := consumerFunc(func(_ context.Context, event models.OutboxEvent) error {
+		if eventData(t, event).ID == poison.ID {
+			return errors.New("consumer exploded")
+		}
+		return nil
+	})
+	dispatcher := NewWebhookDispatcher(env.repo.Outbox(), env.repo.Webhooks(), env.repo, nil, cfg, failing)
+
+	// Event yang gagal tidak menahan event sesudahnya
+	created, err := dispatcher.RelayOutbox(env.ctx)
+	if err != nil || created != 1 {
+		t.Fatalf("expected the healthy event to be relayed, got %d, %v", created, err)
+	}
+	if deliveries := env.deliveries(webhook.ID); len(deliveries) != 1 {
+		t.Fatalf("expected one delivery, got %v", deliveries)
+	}
+	pending := env.pendingEvents()
+	if len(pending) != 1 || pending[0].Attempts != 1 || pending[0].LastError != "consumer exploded" || pending[0].DeadAt != nil {
+		t.Fatalf("expected the failure to be recorded on the poison event, got %+v", pending)
+	}
+
+	// Setelah RelayMaxAttempts percobaan event disisihkan dan tidak dicoba lagi
+	if _, err := dispatcher.RelayOutbox(env.ctx); err != nil {
+		t.Fatalf("relay outbox: %v", err)
+	}
+	if pending := env.pendingEvents(); len(pending) != 0 {
+		t.Fatalf("expected the poison event to be dead, got %+v", pending)
+	}
+	dead, err := env.repo.Outbox().FindByID(env.ctx, pending[0].ID)
+	if err != nil || dead.Attempts != 2 || dead.DeadAt == nil || dead.ProcessedAt != nil {
+		t.Fatalf("expected a dead event after 2 attempts, got %+v, %v", dead, err)
+	}
+}
+
+func TestWebhookAttemptDiscardedWhenLeaseIsLost(t *testing.T) {
+	env := newTestEnv(t)
+	owner := env.user("owner")
+	project := env.project(owner, "Leases")
+
+	// Instance lain mengklaim ulang pengiriman selagi percobaan ini masih berjalan
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := uuid.Parse(r.Header.Get(HeaderWebhookDelivery))
+		later := time.Now().Add(time.Hour)
+		if claimed, err := env.repo.Webhooks().ClaimDelivery(r.Context(), id, later, later.Add(time.Minute)); err != nil || !claimed {
+			t.Errorf("expected to reclaim the delivery, got %v, %v", claimed, err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	webhook, err := env.webhooks.Create(env.ctx, owner.ID, project.ID, WebhookInput{URL: receiver.URL, Events: []string{"*"}})
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	env.task(owner, project.ID, "Contended")
+	env.relay()
+	env.deliverDue(1)
+
+	delivery := env.deliveries(webhook.ID)[0]
+	if delivery.Status != models.DeliveryPending || delivery.Attempts != 0 || len(delivery.Log) != 0 {
+		t.Fatalf("expected the attempt to be discarded, got %+v", delivery)
+	}
+}
+
+func TestWebhookDispatcherBlocksInternalTargets(t *testing.T) {
+	env := newTestEnv(t, func(cfg *config.WebhookConfig) { cfg.AllowPrivateTargets = false })
+	owner := env.user("owner")
+	project := env.project(owner, "Internal")
+
+	var calls atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer receiver.Close()
+
+	// Webhook tersimpan sebelum alamat internal dilarang, atau nama host-nya baru mengarah ke alamat internal
+	allowing := NewWebhookService(env.repo.Projects(), env.repo.Webhooks(), env.cache, true)
+	webhook, err := allowing.Create(env.ctx, owner.ID, project.ID, WebhookInput{URL: receiver.URL, Events: []string{"*"}})
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	env.task(owner, project.ID, "Probe")
+	env.relay()
+	env.deliverDue(1)
+
+	delivery := env.deliveries(webhook.ID)[0]
+	if calls.Load() != 0 || delivery.Attempts != 1 || delivery.LastStatusCode != 0 || delivery.LastError == "" {
+		t.Fatalf("expected the connection to be refused before reaching the receiver, got %d calls and %+v", calls.Load(), delivery)
+	}
+}
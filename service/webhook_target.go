@@ -0,0 +1,84 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+
+	"taskify/config"
+)
+
+// internalWebhookPrefixes adalah rentang alamat yang bukan tujuan webhook publik, di luar yang sudah dikenali
+// netip (loopback, privat, link-local, multicast).
+var internalWebhookPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "Jaringan ini"
+	netip.MustParsePrefix("100.64.0.0/10"), // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // Benchmarking
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64, bisa menjangkau alamat IPv4 internal
+}
+
+// internalWebhookAddr melaporkan apakah ip adalah alamat loopback, privat, link-local, atau alamat lain yang
+// bukan tujuan publik.
+func internalWebhookAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return true
+	}
+	for _, prefix := range internalWebhookPrefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// internalWebhookHost melaporkan apakah host URL webhook jelas internal tanpa perlu resolusi DNS: localhost
+// atau alamat IP internal.
+func internalWebhookHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip, err := netip.ParseAddr(host)
+	return err == nil && internalWebhookAddr(ip)
+}
+
+// newWebhookClient membuat HTTP client untuk pengiriman webhook. Redirect tidak diikuti (response 3xx
+// dicatat sebagai percobaan gagal), dan kecuali cfg.AllowPrivateTargets, koneksi ke alamat internal ditolak.
+// Pemeriksaan dilakukan pada IP yang benar-benar dihubungi setelah resolusi DNS, jadi nama host yang
+// di-resolve ulang ke alamat internal (DNS rebinding) tetap tertolak. Proxy dari environment tidak dipakai
+// karena alamat tujuan sebenarnya tidak terlihat di koneksi ke proxy.
+func newWebhookClient(cfg config.WebhookConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivateTargets {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			if internalWebhookAddr(ip) {
+				return fmt.Errorf("webhook target %s is a loopback, private or link-local address", ip)
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
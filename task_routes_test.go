@@ -0,0 +1,292 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestTaskRoutes(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Backend")
+	targetID := s.createProject(owner, "Frontend")
+	taskID := s.createTask(owner, projectID, "Write API", gin.H{"deadline": "2030-03-01"})
+	tasks := "/api/projects/" + projectID + "/tasks"
+	task := tasks + "/" + taskID
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		body    interface{}
+		headers []header
+		status  int
+		check   func(t *testing.T, body map[string]interface{})
+	}{
+		{
+			name: "create", method: http.MethodPost, path: tasks,
+			body: gin.H{"title": "Write docs", "status": "in_progress"}, status: http.StatusCreated,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if str(body, "task.status") != "in_progress" || str(body, "task.project_id") != projectID {
+					t.Fatalf("unexpected task: %v", body["task"])
+				}
+			},
+		},
+		{name: "create rejects invalid status", method: http.MethodPost, path: tasks, body: gin.H{"title": "X", "status": "blocked"}, status: http.StatusBadRequest},
+		{name: "create rejects invalid deadline", method: http.MethodPost, path: tasks, body: gin.H{"title": "X", "status": "todo", "deadline": "01/03/2030"}, status: http.StatusBadRequest},
+		{name: "create in unknown project", method: http.MethodPost, path: "/api/projects/" + uuid.NewString() + "/tasks", body: gin.H{"title": "X", "status": "todo"}, status: http.StatusNotFound},
+		{
+			name: "list", method: http.MethodGet, path: tasks, status: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if count(body, "tasks") != 2 {
+					t.Fatalf("expected 2 tasks, got %v", body["tasks"])
+				}
+			},
+		},
+		{
+			name: "get", method: http.MethodGet, path: task, status: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if !strings.HasPrefix(str(body, "task.deadline"), "2030-03-01") {
+					t.Fatalf("unexpected deadline in %v", body["task"])
+				}
+			},
+		},
+		{name: "get returns 304 for current etag", method: http.MethodGet, path: task, headers: []header{{"If-None-Match", `W/"v1"`}}, status: http.StatusNotModified},
+		{name: "get rejects invalid task id", method: http.MethodGet, path: tasks + "/not-a-uuid", status: http.StatusBadRequest},
+		{name: "get unknown task", method: http.MethodGet, path: tasks + "/" + uuid.NewString(), status: http.StatusNotFound},
+		{name: "get task through another project", method: http.MethodGet, path: "/api/projects/" + targetID + "/tasks/" + taskID, status: http.StatusNotFound},
+		{
+			name: "put with stale if-match", method: http.MethodPut, path: task,
+			body: gin.H{"title": "Stale", "status": "todo"}, headers: []header{{"If-Match", `"v7"`}},
+			status: http.StatusPreconditionFailed,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if str(body, "task.title") != "Write API" {
+					t.Fatalf("412 should include the current task, got %v", body)
+				}
+			},
+		},
+		{
+			name: "put", method: http.MethodPut, path: task,
+			body: gin.H{"title": "Write REST API", "status": "in_progress"}, headers: []header{{"If-Match", `"v1"`}},
+			status: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if str(body, "task.title") != "Write REST API" || lookup(body, "task.version") != float64(2) {
+					t.Fatalf("unexpected task: %v", body["task"])
+				}
+			},
+		},
+		{
+			name: "patch clears deadline", method: http.MethodPatch, path: task,
+			body: `{"deadline": null, "status": "done"}`, headers: []header{{"Content-Type", "application/merge-patch+json"}},
+			status: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if lookup(body, "task.deadline") != nil || str(body, "task.status") != "done" {
+					t.Fatalf("unexpected task: %v", body["task"])
+				}
+			},
+		},
+		{name: "patch rejects invalid status", method: http.MethodPatch, path: task, body: gin.H{"status": "blocked"}, status: http.StatusBadRequest},
+		{name: "move requires target", method: http.MethodPost, path: task + "/move", body: gin.H{}, status: http.StatusBadRequest},
+		{name: "move into same project", method: http.MethodPost, path: task + "/move", body: gin.H{"target_project_id": projectID}, status: http.StatusBadRequest},
+		{name: "move into unknown project", method: http.MethodPost, path: task + "/move", body: gin.H{"target_project_id": uuid.NewString()}, status: http.StatusNotFound},
+		{
+			name: "copy", method: http.MethodPost, path: task + "/copy",
+			body: gin.H{"target_project_id": targetID}, status: http.StatusCreated,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if str(body, "task.project_id") != targetID || str(body, "task.id") == taskID {
+					t.Fatalf("unexpected copy: %v", body["task"])
+				}
+			},
+		},
+		{
+			name: "move", method: http.MethodPost, path: task + "/move",
+			body: gin.H{"target_project_id": targetID}, status: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if str(body, "task.project_id") != targetID {
+					t.Fatalf("unexpected task: %v", body["task"])
+				}
+			},
+		},
+		{name: "moved task is gone from source", method: http.MethodGet, path: task, status: http.StatusNotFound},
+		{
+			name: "history", method: http.MethodGet, path: "/api/projects/" + targetID + "/tasks/" + taskID + "/history", status: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if count(body, "history") != 1 || str(body, "history.0.action") != "moved" || str(body, "history.0.from_project_id") != projectID {
+					t.Fatalf("unexpected history: %v", body["history"])
+				}
+			},
+		},
+		{name: "delete", method: http.MethodDelete, path: "/api/projects/" + targetID + "/tasks/" + taskID, status: http.StatusOK},
+		{name: "deleted task is gone", method: http.MethodGet, path: "/api/projects/" + targetID + "/tasks/" + taskID, status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.do(tt.method, tt.path, owner.Token, tt.body, tt.headers...)
+			expectStatus(t, rec, tt.status)
+			if tt.check != nil {
+				tt.check(t, decode(t, rec))
+			}
+		})
+	}
+}
+
+func TestBulkTaskRoutes(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Ops")
+	taskID := s.createTask(owner, projectID, "Patch servers", nil)
+	bulk := "/api/projects/" + projectID + "/tasks/bulk"
+
+	tests := []struct {
+		name   string
+		body   interface{}
+		status int
+		check  func(t *testing.T, body map[string]interface{})
+	}{
+		{
+			name: "atomic rolls back everything on failure",
+			body: gin.H{"operations": []gin.H{
+				{"op": "create", "title": "Rotate keys"},
+				{"op": "update_status", "task_id": uuid.NewString(), "status": "done"},
+			}},
+			status: http.StatusUnprocessableEntity,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if str(body, "results.0.status") != "rolled_back" || str(body, "results.1.status") != "error" {
+					t.Fatalf("unexpected results: %v", body["results"])
+				}
+			},
+		},
+		{
+			name: "best effort keeps successful operations",
+			body: gin.H{"mode": "best_effort", "operations": []gin.H{
+				{"op": "create", "title": "Rotate keys"},
+				{"op": "update_status", "task_id": taskID, "status": "blocked"},
+				{"op": "set_deadline", "task_id": taskID, "deadline": "2030-07-01"},
+			}},
+			status: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if lookup(body, "succeeded") != float64(2) || lookup(body, "failed") != float64(1) {
+					t.Fatalf("unexpected outcome: %v", body)
+				}
+			},
+		},
+		{name: "rejects unknown mode", body: gin.H{"mode": "yolo", "operations": []gin.H{{"op": "create", "title": "X"}}}, status: http.StatusBadRequest},
+		{name: "requires operations", body: gin.H{"operations": []gin.H{}}, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.do(http.MethodPost, bulk, owner.Token, tt.body)
+			expectStatus(t, rec, tt.status)
+			if tt.check != nil {
+				tt.check(t, decode(t, rec))
+			}
+		})
+	}
+
+	// Hanya operasi create dari mode best_effort yang tersimpan
+	rec := s.do(http.MethodGet, "/api/projects/"+projectID+"/tasks", owner.Token, nil)
+	expectStatus(t, rec, http.StatusOK)
+	if n := count(decode(t, rec), "tasks"); n != 2 {
+		t.Fatalf("expected 2 tasks after bulk requests, got %d", n)
+	}
+}
+
+func TestArchivedProjectTasksAreReadOnly(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Old")
+	activeID := s.createProject(owner, "Active")
+	taskID := s.createTask(owner, projectID, "Legacy", nil)
+	expectStatus(t, s.do(http.MethodPost, "/api/projects/detail/"+projectID+"/archive", owner.Token, nil), http.StatusOK)
+
+	task := "/api/projects/" + projectID + "/tasks/" + taskID
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+		status int
+	}{
+		{"list", http.MethodGet, "/api/projects/" + projectID + "/tasks", nil, http.StatusOK},
+		{"get", http.MethodGet, task, nil, http.StatusOK},
+		{"create", http.MethodPost, "/api/projects/" + projectID + "/tasks", gin.H{"title": "New", "status": "todo"}, http.StatusConflict},
+		{"put", http.MethodPut, task, gin.H{"title": "Changed", "status": "todo"}, http.StatusConflict},
+		{"patch", http.MethodPatch, task, gin.H{"title": "Changed"}, http.StatusConflict},
+		{"delete", http.MethodDelete, task, nil, http.StatusConflict},
+		{"move out", http.MethodPost, task + "/move", gin.H{"target_project_id": activeID}, http.StatusConflict},
+		{"copy out", http.MethodPost, task + "/copy", gin.H{"target_project_id": activeID}, http.StatusCreated},
+		{"copy in", http.MethodPost, task + "/copy", gin.H{"target_project_id": projectID}, http.StatusConflict},
+		{"bulk", http.MethodPost, "/api/projects/" + projectID + "/tasks/bulk", gin.H{"operations": []gin.H{{"op": "create", "title": "X"}}}, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectStatus(t, s.do(tt.method, tt.path, owner.Token, tt.body), tt.status)
+		})
+	}
+}
+
+// Setiap rute tugas harus menolak user lain dengan 404, baik lewat proyek milik owner maupun dengan
+// menyelipkan ID tugas/proyek milik owner ke proyek milik user itu sendiri.
+func TestTaskRoutesDenyOtherUsers(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	intruder := s.register("intruder")
+	projectID := s.createProject(owner, "Private")
+	taskID := s.createTask(owner, projectID, "Secret", nil)
+	ownProjectID := s.createProject(intruder, "Mine")
+	ownTaskID := s.createTask(intruder, ownProjectID, "Mine", nil)
+
+	tasks := "/api/projects/" + projectID + "/tasks"
+	task := tasks + "/" + taskID
+	ownTask := "/api/projects/" + ownProjectID + "/tasks/" + ownTaskID
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+	}{
+		{"create", http.MethodPost, tasks, gin.H{"title": "Injected", "status": "todo"}},
+		{"list", http.MethodGet, tasks, nil},
+		{"bulk", http.MethodPost, tasks + "/bulk", gin.H{"operations": []gin.H{{"op": "create", "title": "Injected"}}}},
+		{"get", http.MethodGet, task, nil},
+		{"put", http.MethodPut, task, gin.H{"title": "Hijacked", "status": "done"}},
+		{"patch", http.MethodPatch, task, gin.H{"title": "Hijacked"}},
+		{"delete", http.MethodDelete, task, nil},
+		{"move", http.MethodPost, task + "/move", gin.H{"target_project_id": ownProjectID}},
+		{"copy", http.MethodPost, task + "/copy", gin.H{"target_project_id": ownProjectID}},
+		{"history", http.MethodGet, task + "/history", nil},
+		{"get through own project", http.MethodGet, "/api/projects/" + ownProjectID + "/tasks/" + taskID, nil},
+		{"move own task into owner project", http.MethodPost, ownTask + "/move", gin.H{"target_project_id": projectID}},
+		{"copy own task into owner project", http.MethodPost, ownTask + "/copy", gin.H{"target_project_id": projectID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectStatus(t, s.do(tt.method, tt.path, intruder.Token, tt.body), http.StatusNotFound)
+		})
+	}
+
+	t.Run("bulk cannot touch owner task", func(t *testing.T) {
+		rec := s.do(http.MethodPost, "/api/projects/"+ownProjectID+"/tasks/bulk", intruder.Token, gin.H{
+			"operations": []gin.H{{"op": "delete", "task_id": taskID}},
+		})
+		expectStatus(t, rec, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("owner data is untouched", func(t *testing.T) {
+		rec := s.do(http.MethodGet, tasks, owner.Token, nil)
+		expectStatus(t, rec, http.StatusOK)
+		body := decode(t, rec)
+		if count(body, "tasks") != 1 || str(body, "tasks.0.title") != "Secret" || lookup(body, "tasks.0.version") != float64(1) {
+			t.Fatalf("owner tasks were modified: %v", body["tasks"])
+		}
+	})
+}
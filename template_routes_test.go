@@ -0,0 +1,119 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestTemplateRoutes(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Launch")
+	s.createTask(owner, projectID, "Announce", gin.H{"deadline": "2030-01-01"})
+	s.createTask(owner, projectID, "Ship", gin.H{"deadline": "2030-01-08", "status": "done"})
+
+	rec := s.do(http.MethodPost, "/api/projects/"+projectID+"/template", owner.Token, gin.H{"name": "Launch plan"})
+	expectStatus(t, rec, http.StatusCreated)
+	templatePath := "/api/templates/" + str(decode(t, rec), "template.id")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+		status int
+		check  func(t *testing.T, body map[string]interface{})
+	}{
+		{
+			name: "list", method: http.MethodGet, path: "/api/templates", status: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if count(body, "templates") != 1 || str(body, "templates.0.name") != "Launch plan" {
+					t.Fatalf("unexpected templates: %v", body["templates"])
+				}
+			},
+		},
+		{
+			name: "get", method: http.MethodGet, path: templatePath, status: http.StatusOK,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if count(body, "template.tasks") != 2 || lookup(body, "template.tasks.1.deadline_offset_days") != float64(7) {
+					t.Fatalf("unexpected template: %v", body["template"])
+				}
+			},
+		},
+		{name: "get rejects invalid id", method: http.MethodGet, path: "/api/templates/not-a-uuid", status: http.StatusBadRequest},
+		{name: "get unknown template", method: http.MethodGet, path: "/api/templates/" + uuid.NewString(), status: http.StatusNotFound},
+		{
+			name: "instantiate", method: http.MethodPost, path: templatePath + "/instantiate",
+			body: gin.H{"name": "Launch Q3", "start_date": "2031-07-01", "reset_status": true}, status: http.StatusCreated,
+			check: func(t *testing.T, body map[string]interface{}) {
+				if lookup(body, "tasks_created") != float64(2) || str(body, "project.name") != "Launch Q3" {
+					t.Fatalf("unexpected response: %v", body)
+				}
+				tasks := s.do(http.MethodGet, "/api/projects/"+str(body, "project.id")+"/tasks", owner.Token, nil)
+				expectStatus(t, tasks, http.StatusOK)
+				list := decode(t, tasks)
+				// Tugas diurutkan berdasarkan judul: Announce lalu Ship
+				if !strings.HasPrefix(str(list, "tasks.1.deadline"), "2031-07-08") || str(list, "tasks.1.status") != "todo" {
+					t.Fatalf("unexpected instantiated tasks: %v", list["tasks"])
+				}
+			},
+		},
+		{name: "instantiate rejects invalid start date", method: http.MethodPost, path: templatePath + "/instantiate", body: gin.H{"start_date": "July 1st"}, status: http.StatusBadRequest},
+		{name: "delete", method: http.MethodDelete, path: templatePath, status: http.StatusOK},
+		{name: "deleted template is gone", method: http.MethodGet, path: templatePath, status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.do(tt.method, tt.path, owner.Token, tt.body)
+			expectStatus(t, rec, tt.status)
+			if tt.check != nil {
+				tt.check(t, decode(t, rec))
+			}
+		})
+	}
+}
+
+func TestTemplateRoutesDenyOtherUsers(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	intruder := s.register("intruder")
+	projectID := s.createProject(owner, "Private")
+
+	rec := s.do(http.MethodPost, "/api/projects/"+projectID+"/template", owner.Token, gin.H{})
+	expectStatus(t, rec, http.StatusCreated)
+	templatePath := "/api/templates/" + str(decode(t, rec), "template.id")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+	}{
+		{"get", http.MethodGet, templatePath, nil},
+		{"delete", http.MethodDelete, templatePath, nil},
+		{"instantiate", http.MethodPost, templatePath + "/instantiate", gin.H{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectStatus(t, s.do(tt.method, tt.path, intruder.Token, tt.body), http.StatusNotFound)
+		})
+	}
+
+	t.Run("list only shows own templates", func(t *testing.T) {
+		rec := s.do(http.MethodGet, "/api/templates", intruder.Token, nil)
+		expectStatus(t, rec, http.StatusOK)
+		if n := count(decode(t, rec), "templates"); n != 0 {
+			t.Fatalf("expected no templates for the intruder, got %d", n)
+		}
+	})
+
+	t.Run("owner template is untouched", func(t *testing.T) {
+		expectStatus(t, s.do(http.MethodGet, templatePath, owner.Token, nil), http.StatusOK)
+	})
+}
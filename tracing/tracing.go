@@ -0,0 +1,79 @@
+// Package tracing memasang OpenTelemetry: tracer provider dengan exporter yang bisa dipilih lewat env,
+// middleware Gin untuk span server (dengan W3C trace context), dan plugin GORM untuk span per query.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"taskify/config"
+)
+
+// instrumentationName adalah nama tracer untuk semua span yang dibuat package ini.
+const instrumentationName = "taskify"
+
+// Setup memasang tracer provider dengan exporter dari cfg.Exporter (OTEL_TRACES_EXPORTER: none, stdout,
+// atau otlp) dan nama service cfg.ServiceName, serta propagator W3C global. Exporter OTLP memakai
+// variabel standar OTEL_EXPORTER_OTLP_* (misalnya OTEL_EXPORTER_OTLP_ENDPOINT) yang dibaca langsung
+// oleh SDK. Fungsi yang dikembalikan mengirim span yang tersisa dan harus dipanggil saat shutdown.
+func Setup(ctx context.Context, cfg config.TracingConfig) (func(context.Context) error, error) {
+	// Propagator tetap dipasang walaupun tracing mati, supaya trace context dari upstream tidak hilang
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	exporterName := cfg.Exporter
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch exporterName {
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "otlp":
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unsupported OTEL_TRACES_EXPORTER %q (expected none, stdout or otlp)", exporterName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create %s trace exporter: %w", exporterName, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	// Sampler mengikuti OTEL_TRACES_SAMPLER / OTEL_TRACES_SAMPLER_ARG yang dibaca langsung oleh SDK
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// tracer mengambil tracer dari provider global saat dipakai, sehingga provider yang dipasang belakangan
+// (misalnya oleh test) tetap dipakai.
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// SetUser menandai span request yang sedang berjalan dengan ID user yang sudah terautentikasi.
+func SetUser(ctx context.Context, userID string) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("enduser.id", userID))
+}
+
+// SetResourceID menandai span request dengan ID resource dari path, misalnya taskify.project_id.
+func SetResourceID(ctx context.Context, kind, id string) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("taskify."+kind+"_id", id))
+}
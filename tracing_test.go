@@ -0,0 +1,130 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"taskify/logging"
+)
+
+// recordSpans memasang tracer provider yang menyimpan span di memori selama test berjalan.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return recorder
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestTracingCreatesServerAndQuerySpans(t *testing.T) {
+	recorder := recordSpans(t)
+	s := newTestServer(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Traced")
+
+	const (
+		traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID = "00f067aa0ba902b7"
+	)
+	before := len(recorder.Ended())
+	rec := s.do(http.MethodGet, "/api/projects/detail/"+projectID, owner.Token, nil,
+		header{"traceparent", "00-" + traceID + "-" + parentSpanID + "-01"})
+	expectStatus(t, rec, http.StatusOK)
+	spans := recorder.Ended()[before:]
+
+	var server sdktrace.ReadOnlySpan
+	for _, span := range spans {
+		if span.SpanKind() == trace.SpanKindServer {
+			server = span
+		}
+	}
+	if server == nil {
+		t.Fatal("expected a server span")
+	}
+
+	t.Run("server span continues the incoming trace", func(t *testing.T) {
+		if server.Name() != "GET /api/projects/detail/:id" {
+			t.Fatalf("unexpected span name %q", server.Name())
+		}
+		if server.SpanContext().TraceID().String() != traceID || server.Parent().SpanID().String() != parentSpanID {
+			t.Fatalf("span is not a child of the traceparent header: %v / %v", server.SpanContext(), server.Parent())
+		}
+	})
+
+	t.Run("server span carries request attributes", func(t *testing.T) {
+		want := map[attribute.Key]string{
+			"enduser.id":                owner.ID,
+			"taskify.project_id":        projectID,
+			"http.route":                "/api/projects/detail/:id",
+			"http.response.status_code": "200",
+		}
+		for key, value := range want {
+			if got := spanAttribute(server, key); got != value {
+				t.Fatalf("%s: expected %q, got %q", key, value, got)
+			}
+		}
+	})
+
+	t.Run("queries are child spans", func(t *testing.T) {
+		found := false
+		for _, span := range spans {
+			if span.SpanKind() != trace.SpanKindClient {
+				continue
+			}
+			if span.Parent().SpanID() != server.SpanContext().SpanID() {
+				t.Fatalf("query span %q is not a child of the server span", span.Name())
+			}
+			if span.Name() == "gorm.query projects" && strings.Contains(spanAttribute(span, "db.query.text"), "projects") {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatal("expected a gorm.query projects span")
+		}
+	})
+}
+
+func TestTracingRedactsCalendarFeedToken(t *testing.T) {
+	recorder := recordSpans(t)
+	s := newTestServer(t)
+	owner := s.register("owner")
+	path := s.createCalendarFeed(owner, "/api/me/calendar-feed")
+
+	before := len(recorder.Ended())
+	expectStatus(t, s.do(http.MethodGet, path, "", nil), http.StatusOK)
+
+	for _, span := range recorder.Ended()[before:] {
+		if span.SpanKind() != trace.SpanKindServer {
+			continue
+		}
+		if got := spanAttribute(span, "url.path"); got != "/api/calendar/"+logging.Redacted {
+			t.Fatalf("expected a redacted url.path, got %q", got)
+		}
+		return
+	}
+	t.Fatal("expected a server span")
+}
@@ -1,14 +1,13 @@
 package usecase
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
-	"gorm.io/gorm"
 
-	"taskify/config"
-	"taskify/models"
+	"taskify/metrics"
+	"taskify/service"
 	"taskify/utils"
 )
 
@@ -23,71 +22,68 @@ type InputLogin struct {
 	Password string `json:"password" binding:"required"`
 }
 
-func Register(c *gin.Context) {
+// AuthHandler menangani endpoint autentikasi.
+type AuthHandler struct {
+	auth *service.AuthService
+}
+
+// NewAuthHandler membuat AuthHandler di atas AuthService.
+func NewAuthHandler(auth *service.AuthService) *AuthHandler {
+	return &AuthHandler{auth: auth}
+}
+
+func (h *AuthHandler) Register(c *gin.Context) {
 	var input InputRegister
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	var existingUser models.User
-	if err := config.DB.Where("email = ?", input.Email).First(&existingUser).Error; err == nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "Email already registered"})
-		return
-	} else if err != gorm.ErrRecordNotFound {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
-		return
-	}
-
-	hashedPassword, err := utils.HashPassword(input.Password)
+	user, err := h.auth.Register(c.Request.Context(), input.Name, input.Email, input.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
-		return
-	}
-
-	user := models.User{
-		ID:       uuid.New(),
-		Name:     input.Name,
-		Email:    input.Email,
-		Password: hashedPassword,
-	}
-
-	if err := config.DB.Create(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register user"})
+		respondError(c, err, "Failed to register user")
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{"message": "User registered successfully", "user_id": user.ID})
 }
 
-func Login(c *gin.Context) {
+func (h *AuthHandler) Login(c *gin.Context) {
 	var input InputLogin
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	var user models.User
-	if err := config.DB.Where("email = ?", input.Email).First(&user).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+	token, user, err := h.auth.Login(c.Request.Context(), input.Email, input.Password)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			metrics.LoginAttempts.WithLabelValues("invalid_credentials").Inc()
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			metrics.LoginAttempts.WithLabelValues("error").Inc()
 		}
+		respondError(c, err, "Failed to log in")
 		return
 	}
+	metrics.LoginAttempts.WithLabelValues("success").Inc()
+
+	// Mengembalikan user_id di response login
+	c.JSON(http.StatusOK, gin.H{"message": "Login successful", "token": token, "user_id": user.ID})
+}
 
-	if !utils.CheckPasswordHash(input.Password, user.Password) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+// Logout mencabut token yang sedang dipakai sehingga tidak bisa digunakan lagi walaupun belum kedaluwarsa.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	value, exists := c.Get("tokenClaims")
+	claims, ok := value.(*utils.JWTClaims)
+	if !exists || !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
 	}
 
-	token, err := utils.GenerateToken(user.ID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+	if err := h.auth.Logout(c.Request.Context(), claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke token"})
 		return
 	}
 
-	// Mengembalikan user_id di response login
-	c.JSON(http.StatusOK, gin.H{"message": "Login successful", "token": token, "user_id": user.ID})
+	c.JSON(http.StatusOK, gin.H{"message": "Logout successful"})
 }
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"taskify/ical"
+	"taskify/models"
+	"taskify/service"
+	"taskify/utils"
+)
+
+// calendarProductID adalah PRODID feed iCalendar Taskify.
+const calendarProductID = "-//Taskify//Taskify API//EN"
+
+// CalendarHandler menangani pengelolaan URL feed kalender dan feed iCalendar-nya sendiri.
+type CalendarHandler struct {
+	calendar *service.CalendarService
+}
+
+// NewCalendarHandler membuat CalendarHandler di atas CalendarService.
+func NewCalendarHandler(calendar *service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{calendar: calendar}
+}
+
+// GetCalendarFeed: Mengambil info feed kalender (tanpa URL-nya). Dipakai untuk feed semua proyek
+// (/me/calendar-feed) maupun feed satu proyek (/projects/:project_id/calendar-feed).
+func (h *CalendarHandler) GetCalendarFeed(c *gin.Context) {
+	userID, projectID, ok := calendarScope(c)
+	if !ok {
+		return
+	}
+
+	feed, err := h.calendar.Feed(c.Request.Context(), userID, projectID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve calendar feed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Calendar feed retrieved successfully", "feed": feed})
+}
+
+// RegenerateCalendarFeed: Membuat URL feed kalender baru. URL lama (jika ada) langsung berhenti berfungsi.
+// URL hanya dikembalikan di response ini.
+func (h *CalendarHandler) RegenerateCalendarFeed(c *gin.Context) {
+	userID, projectID, ok := calendarScope(c)
+	if !ok {
+		return
+	}
+
+	feed, token, err := h.calendar.Regenerate(c.Request.Context(), userID, projectID)
+	if err != nil {
+		respondError(c, err, "Failed to generate calendar feed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Calendar feed URL generated successfully", "feed": feed, "url": calendarFeedURL(c, token)})
+}
+
+// RevokeCalendarFeed: Mencabut URL feed kalender.
+func (h *CalendarHandler) RevokeCalendarFeed(c *gin.Context) {
+	userID, projectID, ok := calendarScope(c)
+	if !ok {
+		return
+	}
+
+	if err := h.calendar.Revoke(c.Request.Context(), userID, projectID); err != nil {
+		respondError(c, err, "Failed to revoke calendar feed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Calendar feed revoked successfully"})
+}
+
+// ServeCalendarFeed: Mengirim feed iCalendar untuk token di URL, tanpa header Authorization karena aplikasi
+// kalender tidak bisa mengirimnya. Query component=vtodo mengirim tugas sebagai VTODO alih-alih VEVENT
+// seharian.
+func (h *CalendarHandler) ServeCalendarFeed(c *gin.Context) {
+	component := strings.ToLower(c.DefaultQuery("component", "vevent"))
+	if component != "vevent" && component != "vtodo" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "component must be vevent or vtodo"})
+		return
+	}
+
+	data, err := h.calendar.Resolve(c.Request.Context(), strings.TrimSuffix(c.Param("token"), ".ics"))
+	if err != nil {
+		respondError(c, err, "Failed to build calendar feed")
+		return
+	}
+
+	now := time.Now()
+	calendar := ical.Calendar{ProductID: calendarProductID, Name: data.Name}
+	for _, task := range data.Tasks {
+		calendar.Components = append(calendar.Components, taskComponent(task, component, now))
+	}
+
+	c.Header("Content-Disposition", `inline; filename="taskify.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", calendar.Encode())
+}
+
+// taskComponent memetakan tugas berdeadline ke VEVENT seharian di tanggal deadline atau ke VTODO dengan DUE
+// tanggal deadline. UID diturunkan dari ID tugas supaya aplikasi kalender memperbarui entri yang sama.
+func taskComponent(task models.Task, component string, now time.Time) ical.Component {
+	entry := ical.Component{Name: strings.ToUpper(component)}
+	entry.Add("UID", task.ID.String()+"@taskify")
+	entry.Add("DTSTAMP", ical.DateTime(now))
+	entry.Add("SEQUENCE", strconv.FormatUint(uint64(task.Version-1), 10))
+
+	summary := task.Title
+	if component == "vevent" {
+		// VEVENT tidak punya status selesai, jadi tugas done ditandai di judulnya
+		if task.Status == models.Done {
+			summary = "✓ " + summary
+		}
+		entry.Add("DTSTART", ical.Date(*task.Deadline), "VALUE=DATE")
+		entry.Add("DTEND", ical.Date(task.Deadline.AddDate(0, 0, 1)), "VALUE=DATE")
+		entry.Add("TRANSP", "TRANSPARENT")
+		entry.Add("STATUS", "CONFIRMED")
+	} else {
+		entry.Add("DUE", ical.Date(*task.Deadline), "VALUE=DATE")
+		switch task.Status {
+		case models.Done:
+			entry.Add("STATUS", "COMPLETED")
+			entry.Add("PERCENT-COMPLETE", "100")
+		case models.InProgress:
+			entry.Add("STATUS", "IN-PROCESS")
+		default:
+			entry.Add("STATUS", "NEEDS-ACTION")
+		}
+	}
+	entry.Add("SUMMARY", ical.Text(summary))
+	if task.Description != "" {
+		entry.Add("DESCRIPTION", ical.Text(task.Description))
+	}
+	if task.Project.Name != "" {
+		entry.Add("CATEGORIES", ical.Text(task.Project.Name))
+	}
+	return entry
+}
+
+// calendarScope mengambil user yang login dan proyek feed dari URL; rute tanpa :project_id berarti feed
+// semua proyek (uuid.Nil).
+func calendarScope(c *gin.Context) (userID, projectID uuid.UUID, ok bool) {
+	if c.Param("project_id") != "" {
+		if projectID, ok = parseIDParam(c, "project_id", "project"); !ok {
+			return
+		}
+	}
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok = utils.GetUserIDFromContext(c)
+	return
+}
+
+// calendarFeedURL menyusun URL feed lengkap dari host request, mengikuti X-Forwarded-Proto di belakang proxy.
+func calendarFeedURL(c *gin.Context, token string) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host + "/api/calendar/" + token + ".ics"
+}
@@ -0,0 +1,387 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+
+	"taskify/logging"
+	"taskify/models"
+	"taskify/realtime"
+	"taskify/service"
+	"taskify/utils"
+)
+
+const (
+	// collabSendBuffer adalah jumlah pesan yang boleh menumpuk untuk satu koneksi sebelum client dianggap
+	// terlalu lambat dan diputus dengan kode 1013 (try again later).
+	collabSendBuffer = 64
+	// collabWriteWait adalah batas waktu menulis satu pesan ke client.
+	collabWriteWait = 10 * time.Second
+	// collabMaxMessage adalah ukuran maksimum pesan dari client.
+	collabMaxMessage = 4096
+)
+
+// Kode close WebSocket aplikasi (rentang 4000-4999) untuk koneksi yang diputus karena otorisasi.
+const (
+	CloseTokenInvalid  = 4401 // Token kedaluwarsa atau dicabut; login ulang lalu sambungkan kembali
+	CloseAccessRevoked = 4403 // User tidak lagi punya akses ke proyek
+)
+
+var (
+	errTaskIDRequired = errors.New("task_id is required")
+	errUnknownMessage = errors.New("unknown message type")
+)
+
+// collabRequest adalah pesan dari client: view, edit_start, edit_stop, atau ping.
+type collabRequest struct {
+	Type   string     `json:"type"`
+	TaskID *uuid.UUID `json:"task_id"`
+}
+
+// CollabHandler menangani channel kolaborasi WebSocket per proyek: event perubahan tugas, kehadiran, dan
+// soft lock pengeditan.
+type CollabHandler struct {
+	collab       *service.CollabService
+	events       *service.EventStreamService
+	tokens       *utils.TokenManager
+	pingInterval time.Duration
+	recheck      time.Duration
+	upgrader     websocket.Upgrader
+}
+
+// NewCollabHandler membuat CollabHandler. pingInterval (WS_PING_INTERVAL) adalah jeda ping ke client, dan
+// recheck (WS_AUTH_RECHECK_INTERVAL) adalah jeda pengecekan ulang token dan akses proyek setiap koneksi.
+func NewCollabHandler(collab *service.CollabService, events *service.EventStreamService, tokens *utils.TokenManager, pingInterval, recheck time.Duration) *CollabHandler {
+	return &CollabHandler{
+		collab:       collab,
+		events:       events,
+		tokens:       tokens,
+		pingInterval: pingInterval,
+		recheck:      recheck,
+		upgrader: websocket.Upgrader{
+			Subprotocols: []string{utils.WebSocketTokenProtocol},
+			// Autentikasi memakai Bearer token, bukan cookie, sehingga halaman dari origin lain tidak bisa
+			// menumpang sesi user; origin mana pun boleh tersambung
+			CheckOrigin: func(*http.Request) bool { return true },
+		},
+	}
+}
+
+// ProjectSocket: Membuka channel kolaborasi WebSocket untuk proyek. Server mengirim event perubahan tugas
+// dan proyek, daftar kehadiran, serta hasil permintaan lock; client mengirim tugas yang sedang dilihat dan
+// diedit. Koneksi diputus saat proyek dihapus, akses user dicabut, atau tokennya tidak berlaku lagi.
+func (h *CollabHandler) ProjectSocket(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "project_id", "project")
+	if !ok {
+		return
+	}
+
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+	value, _ := c.Get("tokenClaims")
+	claims, _ := value.(*utils.JWTClaims)
+
+	// Akses diperiksa sebelum upgrade supaya client menerima status HTTP biasa (404) jika ditolak
+	ctx := c.Request.Context()
+	session, err := h.collab.Join(ctx, userID, projectID)
+	if err != nil {
+		respondError(c, err, "Failed to join project collaboration")
+		return
+	}
+	defer session.Leave(ctx)
+
+	stream, err := h.events.Subscribe(ctx, userID, projectID, 0)
+	if err != nil {
+		respondError(c, err, "Failed to join project collaboration")
+		return
+	}
+	defer stream.Close()
+
+	ws, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrader sudah mengirim response error
+		return
+	}
+	conn := newCollabConn(ws)
+	defer conn.close(websocket.CloseNormalClosure, "")
+
+	logger := logging.FromContext(ctx)
+	go conn.writePump(h.pingInterval)
+	go conn.readPump(h.pingInterval*2, func(request collabRequest) {
+		h.handleRequest(ctx, conn, session, request)
+	})
+
+	conn.enqueue(gin.H{"type": "welcome", "connection_id": session.ID, "user_id": userID})
+
+	recheck := time.NewTicker(h.recheck)
+	defer recheck.Stop()
+
+	for {
+		select {
+		case <-conn.done:
+			return
+
+		case event, ok := <-stream.Events:
+			if !ok {
+				conn.close(closeCodeFor(stream.Err()))
+				logger.Info("collaboration channel closed by server", "reason", stream.Err())
+				return
+			}
+			message := gin.H{"type": "event", "event": json.RawMessage(event.Data)}
+			if event.ID > 0 {
+				message["id"] = event.ID
+			}
+			conn.enqueue(message)
+			if event.Type == models.EventProjectDeleted {
+				conn.finish(websocket.CloseNormalClosure, "project deleted")
+				return
+			}
+			// Perubahan proyek bisa mengubah siapa yang berhak mengaksesnya; periksa tanpa menunggu ticker
+			if strings.HasPrefix(event.Type, "project.") && !h.authorize(ctx, conn, claims, userID, projectID) {
+				return
+			}
+
+		case roster, ok := <-session.Presence:
+			if !ok {
+				conn.close(closeCodeFor(session.Err()))
+				return
+			}
+			conn.enqueue(gin.H{"type": "presence", "members": roster})
+
+		case <-recheck.C:
+			if !h.authorize(ctx, conn, claims, userID, projectID) {
+				return
+			}
+		}
+	}
+}
+
+// handleRequest menjalankan satu pesan client dan mengirim hasilnya ke koneksi.
+func (h *CollabHandler) handleRequest(ctx context.Context, conn *collabConn, session *service.CollabSession, request collabRequest) {
+	var err error
+	switch request.Type {
+	case "ping":
+		conn.enqueue(gin.H{"type": "pong"})
+	case "view":
+		err = session.View(ctx, request.TaskID)
+	case "edit_start":
+		if request.TaskID == nil {
+			err = errTaskIDRequired
+			break
+		}
+		lock, lockErr := session.StartEditing(ctx, *request.TaskID)
+		switch {
+		case errors.Is(lockErr, service.ErrTaskLocked):
+			conn.enqueue(gin.H{"type": "lock_denied", "lock": lock})
+		case lockErr == nil:
+			conn.enqueue(gin.H{"type": "lock_granted", "lock": lock})
+		default:
+			err = lockErr
+		}
+	case "edit_stop":
+		if request.TaskID == nil {
+			err = errTaskIDRequired
+			break
+		}
+		session.StopEditing(ctx, *request.TaskID)
+	default:
+		err = errUnknownMessage
+	}
+	if err == nil {
+		return
+	}
+
+	message := "Failed to process message"
+	var serviceErr *service.Error
+	switch {
+	case errors.As(err, &serviceErr):
+		message = serviceErr.Message
+	case errors.Is(err, errTaskIDRequired) || errors.Is(err, errUnknownMessage):
+		message = err.Error()
+	default:
+		logging.FromContext(ctx).Error("failed to process collaboration message", "type", request.Type, "error", err)
+	}
+	conn.enqueue(gin.H{"type": "error", "request": request.Type, "error": message})
+}
+
+// authorize memeriksa ulang token dan akses proyek koneksi. Jika tidak berlaku lagi, koneksi diputus dengan
+// kode yang sesuai dan hasilnya false. Proyek yang ternyata sudah dihapus diperlakukan sama dengan event
+// project.deleted: event-nya dikirim lalu koneksi ditutup normal, walaupun worker outbox belum
+// menyiarkannya. Kegagalan sementara (cache atau database tidak bisa dihubungi) tidak memutus koneksi;
+// pengecekan diulang pada giliran berikutnya.
+func (h *CollabHandler) authorize(ctx context.Context, conn *collabConn, claims *utils.JWTClaims, userID, projectID uuid.UUID) bool {
+	if claims != nil {
+		if claims.ExpiresAt != nil && time.Now().After(claims.ExpiresAt.Time) {
+			conn.close(CloseTokenInvalid, "token expired")
+			return false
+		}
+		revoked, err := h.tokens.IsRevoked(ctx, claims.ID)
+		if err == nil && revoked {
+			conn.close(CloseTokenInvalid, "token revoked")
+			return false
+		}
+	}
+
+	err := h.events.CheckAccess(ctx, userID, projectID)
+	var serviceErr *service.Error
+	if errors.As(err, &serviceErr) && serviceErr.Kind == service.KindNotFound {
+		deleted, gone, err := h.events.Deletion(ctx, projectID)
+		if err != nil {
+			logging.FromContext(ctx).Warn("failed to recheck collaboration access", "error", err)
+			return true
+		}
+		if gone {
+			if deleted.Data != nil {
+				conn.enqueue(gin.H{"type": "event", "event": json.RawMessage(deleted.Data)})
+			}
+			conn.finish(websocket.CloseNormalClosure, "project deleted")
+			return false
+		}
+		conn.close(CloseAccessRevoked, "access to the project was revoked")
+		return false
+	}
+	if err != nil {
+		logging.FromContext(ctx).Warn("failed to recheck collaboration access", "error", err)
+	}
+	return true
+}
+
+// closeCodeFor memilih kode close untuk langganan yang diakhiri server.
+func closeCodeFor(err error) (int, string) {
+	switch {
+	case errors.Is(err, realtime.ErrSlowConsumer):
+		return websocket.CloseTryAgainLater, "client too slow"
+	case errors.Is(err, realtime.ErrClosed):
+		return websocket.CloseGoingAway, "server shutting down"
+	default:
+		return websocket.CloseInternalServerErr, "realtime backend unavailable"
+	}
+}
+
+// collabFrame adalah satu entri antrean kirim: pesan JSON, atau permintaan menutup koneksi jika data nil.
+type collabFrame struct {
+	data   []byte
+	code   int
+	reason string
+}
+
+// collabConn membungkus satu koneksi WebSocket. Semua pesan keluar lewat antrean send yang dibatasi
+// collabSendBuffer dan ditulis oleh writePump, sehingga client yang lambat tidak menahan pengirimnya.
+type collabConn struct {
+	ws   *websocket.Conn
+	send chan collabFrame
+	done chan struct{}
+	once sync.Once
+}
+
+func newCollabConn(ws *websocket.Conn) *collabConn {
+	return &collabConn{ws: ws, send: make(chan collabFrame, collabSendBuffer), done: make(chan struct{})}
+}
+
+// enqueue mengantrekan pesan untuk client. Jika antrean penuh, client diputus dengan kode 1013 supaya
+// tersambung ulang dan memuat ulang datanya, alih-alih pesan dibuang diam-diam.
+func (cc *collabConn) enqueue(message any) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	cc.push(collabFrame{data: data})
+}
+
+// finish menutup koneksi setelah semua pesan yang sudah diantrekan terkirim, lalu menunggu penutupan
+// selesai paling lama collabWriteWait.
+func (cc *collabConn) finish(code int, reason string) {
+	cc.push(collabFrame{code: code, reason: reason})
+	select {
+	case <-cc.done:
+	case <-time.After(collabWriteWait):
+		cc.close(code, reason)
+	}
+}
+
+func (cc *collabConn) push(frame collabFrame) {
+	select {
+	case <-cc.done:
+	case cc.send <- frame:
+	default:
+		cc.close(websocket.CloseTryAgainLater, "client too slow")
+	}
+}
+
+// close mengirim frame close dengan code dan reason lalu menutup koneksi. Aman dipanggil lebih dari sekali
+// dan dari goroutine mana pun.
+func (cc *collabConn) close(code int, reason string) {
+	cc.once.Do(func() {
+		close(cc.done)
+		message := websocket.FormatCloseMessage(code, reason)
+		cc.ws.WriteControl(websocket.CloseMessage, message, time.Now().Add(collabWriteWait))
+		cc.ws.Close()
+	})
+}
+
+// writePump menulis pesan antrean dan ping berkala ke client.
+func (cc *collabConn) writePump(pingInterval time.Duration) {
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-cc.done:
+			return
+		case frame := <-cc.send:
+			if frame.data == nil {
+				cc.close(frame.code, frame.reason)
+				return
+			}
+			cc.ws.SetWriteDeadline(time.Now().Add(collabWriteWait))
+			if err := cc.ws.WriteMessage(websocket.TextMessage, frame.data); err != nil {
+				cc.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ping.C:
+			if err := cc.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(collabWriteWait)); err != nil {
+				cc.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		}
+	}
+}
+
+// readPump membaca pesan client sampai koneksi ditutup. Client yang tidak membalas ping dalam pongWait
+// dianggap hilang.
+func (cc *collabConn) readPump(pongWait time.Duration, handle func(collabRequest)) {
+	defer cc.close(websocket.CloseNormalClosure, "")
+
+	cc.ws.SetReadLimit(collabMaxMessage)
+	cc.ws.SetReadDeadline(time.Now().Add(pongWait))
+	cc.ws.SetPongHandler(func(string) error {
+		return cc.ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := cc.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		cc.ws.SetReadDeadline(time.Now().Add(pongWait))
+
+		var request collabRequest
+		if err := json.Unmarshal(data, &request); err != nil {
+			cc.enqueue(gin.H{"type": "error", "error": "Messages must be JSON objects with a type"})
+			continue
+		}
+		handle(request)
+	}
+}
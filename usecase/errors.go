@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"taskify/logging"
+	"taskify/service"
+	"taskify/tracing"
+)
+
+var statusByKind = map[service.Kind]int{
+	service.KindInvalid:              http.StatusBadRequest,
+	service.KindUnauthorized:         http.StatusUnauthorized,
+	service.KindNotFound:             http.StatusNotFound,
+	service.KindConflict:             http.StatusConflict,
+	service.KindPreconditionFailed:   http.StatusPreconditionFailed,
+	service.KindPreconditionRequired: http.StatusPreconditionRequired,
+}
+
+// respondError mengirim error dari service sebagai response JSON. Error service memakai status sesuai
+// Kind-nya; error lain dianggap error server, dicatat di log, dan hanya pesan fallback yang dikirim ke client.
+func respondError(c *gin.Context, err error, fallback string) {
+	var serviceErr *service.Error
+	if errors.As(err, &serviceErr) {
+		if status, ok := statusByKind[serviceErr.Kind]; ok {
+			c.JSON(status, gin.H{"error": serviceErr.Message})
+			return
+		}
+	}
+	logging.FromContext(c.Request.Context()).Error(fallback, "error", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+}
+
+// parseIDParam mem-parse parameter URL berupa UUID. Jika gagal, 400 sudah dikirim dan hasil kedua false.
+func parseIDParam(c *gin.Context, param, label string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + label + " ID format"})
+		return uuid.Nil, false
+	}
+	tracing.SetResourceID(c.Request.Context(), label, id.String())
+	return id, true
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"taskify/service"
+)
+
+// etagFor membentuk strong ETag dari kolom version sebuah resource.
+func etagFor(version uint) string {
+	return fmt.Sprintf(`"v%d"`, version)
+}
+
+// evaluateIfMatch memeriksa header If-Match terhadap versi resource saat ini.
+// Jika required (REQUIRE_IF_MATCH), request tanpa If-Match ditolak dengan 428.
+// Mengembalikan http.StatusOK jika request boleh lanjut, atau 428/412 jika harus ditolak.
+func evaluateIfMatch(c *gin.Context, version uint, required bool) int {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		if required {
+			return http.StatusPreconditionRequired
+		}
+		return http.StatusOK
+	}
+
+	current := etagFor(version)
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		// If-Match memakai strong comparison: weak ETag (W/...) tidak pernah cocok
+		if candidate == "*" || candidate == current {
+			return http.StatusOK
+		}
+	}
+	return http.StatusPreconditionFailed
+}
+
+// notModified menangani If-None-Match pada GET. Jika ETag cocok, 304 dikirim dan hasilnya true.
+func notModified(c *gin.Context, version uint) bool {
+	current := etagFor(version)
+	c.Header("ETag", current)
+
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		// If-None-Match memakai weak comparison, jadi prefix W/ diabaikan
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == current {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatch membuat service.Precondition dari header If-Match request ini.
+func ifMatch(c *gin.Context, required bool) service.Precondition {
+	return func(version uint) error {
+		switch evaluateIfMatch(c, version, required) {
+		case http.StatusPreconditionRequired:
+			return service.ErrPreconditionRequired
+		case http.StatusPreconditionFailed:
+			return service.ErrPreconditionFailed
+		}
+		return nil
+	}
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"taskify/logging"
+	"taskify/models"
+	"taskify/service"
+	"taskify/utils"
+)
+
+// sseRetry adalah jeda (milidetik) yang disarankan ke EventSource sebelum tersambung ulang.
+const sseRetry = 3000
+
+// EventStreamHandler menangani stream Server-Sent Events perubahan tugas dan proyek.
+type EventStreamHandler struct {
+	events    *service.EventStreamService
+	heartbeat time.Duration
+}
+
+// NewEventStreamHandler membuat EventStreamHandler di atas EventStreamService. heartbeat
+// (SSE_HEARTBEAT_INTERVAL) adalah jeda komentar heartbeat sekaligus pengecekan ulang akses.
+func NewEventStreamHandler(events *service.EventStreamService, heartbeat time.Duration) *EventStreamHandler {
+	return &EventStreamHandler{events: events, heartbeat: heartbeat}
+}
+
+// StreamProjectEvents: Mengirim perubahan tugas dan proyek secara real-time sebagai text/event-stream.
+// Client yang tersambung ulang mengirim Last-Event-ID (atau query last_event_id) untuk menerima event
+// yang terlewat. Stream berakhir saat proyek dihapus atau user tidak lagi punya akses.
+func (h *EventStreamHandler) StreamProjectEvents(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "project_id", "project")
+	if !ok {
+		return
+	}
+
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var after uint64
+	if lastEventID != "" {
+		var err error
+		if after, err = strconv.ParseUint(lastEventID, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Last-Event-ID must be an event id from this stream"})
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	stream, err := h.events.Subscribe(ctx, userID, projectID, after)
+	if err != nil {
+		respondError(c, err, "Failed to open event stream")
+		return
+	}
+	defer stream.Close()
+
+	// Stream berumur panjang, jadi batas HTTP_WRITE_TIMEOUT tidak berlaku untuk request ini
+	http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Matikan buffering nginx
+	c.Status(http.StatusOK)
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", sseRetry)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case event, ok := <-stream.Events:
+			if !ok {
+				// Server shutdown atau client terlalu lambat: EventSource tersambung ulang dengan Last-Event-ID
+				logging.FromContext(ctx).Info("event stream closed by server", "reason", stream.Err())
+				return
+			}
+			if event.ID > 0 {
+				fmt.Fprintf(c.Writer, "id: %d\n", event.ID)
+			}
+			if _, err := fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event.Type, event.Data); err != nil {
+				return
+			}
+			c.Writer.Flush()
+			if event.Type == models.EventProjectDeleted {
+				return
+			}
+
+		case <-heartbeat.C:
+			if err := h.events.CheckAccess(ctx, userID, projectID); err != nil {
+				// Proyek yang dihapus sebelum worker outbox menyiarkannya tetap diakhiri dengan project.deleted
+				if deleted, gone, _ := h.events.Deletion(ctx, projectID); gone && deleted.Data != nil {
+					fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", deleted.Type, deleted.Data)
+					c.Writer.Flush()
+				}
+				return
+			}
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
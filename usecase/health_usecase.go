@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"taskify/logging"
+)
+
+// readinessTimeout membatasi lama setiap pengecekan dependency di /readyz.
+const readinessTimeout = 2 * time.Second
+
+// HealthCheck memeriksa satu dependency (database, cache, ...); error berarti belum siap melayani request.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// HealthHandler menangani probe liveness dan readiness untuk orchestrator.
+type HealthHandler struct {
+	checks []HealthCheck
+}
+
+// NewHealthHandler membuat HealthHandler dengan daftar dependency yang diperiksa oleh /readyz.
+func NewHealthHandler(checks ...HealthCheck) *HealthHandler {
+	return &HealthHandler{checks: checks}
+}
+
+// Liveness: Proses masih hidup dan bisa melayani HTTP. Tidak memeriksa dependency supaya
+// gangguan database tidak membuat orchestrator me-restart semua instance.
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readiness: Memeriksa semua dependency. 503 membuat instance dikeluarkan dari load balancer sementara.
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	results := gin.H{}
+	ready := true
+	for _, check := range h.checks {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+		err := check.Check(ctx)
+		cancel()
+
+		if err != nil {
+			logging.FromContext(ctx).Warn("readiness check failed", "check", check.Name, "error", err)
+			results[check.Name] = "unavailable"
+			ready = false
+			continue
+		}
+		results[check.Name] = "ok"
+	}
+
+	if !ready {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "not_ready", "checks": results})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ready", "checks": results})
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"taskify/service"
+	"taskify/utils"
+)
+
+// ImportJobHandler menangani import proyek di latar belakang untuk file besar, termasuk export Trello dan
+// Jira.
+type ImportJobHandler struct {
+	jobs     *service.ImportJobService
+	maxBytes int
+}
+
+// NewImportJobHandler membuat ImportJobHandler. maxBytes (IMPORT_MAX_BYTES) membatasi ukuran file.
+func NewImportJobHandler(jobs *service.ImportJobService, maxBytes int) *ImportJobHandler {
+	return &ImportJobHandler{jobs: jobs, maxBytes: maxBytes}
+}
+
+// CreateImportJob: Mengantrekan import proyek dari file (multipart field "file" atau body) dan langsung
+// mengembalikan 202 dengan job-nya; progres dan laporannya di-poll lewat GetImportJob. Query sama seperti
+// ImportProject, dengan format tambahan trello (export JSON board) dan jira (export CSV issue).
+func (h *ImportJobHandler) CreateImportJob(c *gin.Context) {
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	opts, ok := bindImportOptions(c)
+	if !ok {
+		return
+	}
+	data, filename, contentType, ok := readImportFile(c, h.maxBytes)
+	if !ok {
+		return
+	}
+
+	job, err := h.jobs.Create(c.Request.Context(), userID, importFormat(c, filename, contentType), filename, data, opts)
+	if err != nil {
+		respondError(c, err, "Failed to queue import")
+		return
+	}
+
+	c.Header("Location", "/api/imports/"+job.ID.String())
+	c.JSON(http.StatusAccepted, gin.H{"message": "Import queued", "job": job})
+}
+
+// GetImportJob: Mengambil status dan progres import job, beserta laporannya setelah selesai.
+func (h *ImportJobHandler) GetImportJob(c *gin.Context) {
+	jobID, ok := parseIDParam(c, "import_id", "import job")
+	if !ok {
+		return
+	}
+
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.jobs.Get(c.Request.Context(), userID, jobID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve import job")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Import job retrieved successfully", "job": job})
+}
+
+// ListImportJobs: Mengambil import job terbaru milik user, tanpa laporannya.
+func (h *ImportJobHandler) ListImportJobs(c *gin.Context) {
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	jobs, err := h.jobs.List(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve import jobs")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Import jobs retrieved successfully", "jobs": jobs})
+}
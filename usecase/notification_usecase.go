@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"taskify/models"
+	"taskify/service"
+	"taskify/utils"
+)
+
+// InputNotificationPreferences: Jenis notifikasi yang ingin diaktifkan (true) atau dimatikan (false).
+type InputNotificationPreferences struct {
+	Preferences map[string]bool `json:"preferences" binding:"required"`
+}
+
+// notificationResponse adalah notifikasi beserta data event pemicunya.
+type notificationResponse struct {
+	models.Notification
+	Data json.RawMessage `json:"data"`
+}
+
+func newNotificationResponse(notification models.Notification) notificationResponse {
+	data := json.RawMessage(notification.Payload)
+	if !json.Valid(data) {
+		data = json.RawMessage("null")
+	}
+	return notificationResponse{Notification: notification, Data: data}
+}
+
+// NotificationHandler menangani inbox notifikasi, preferensi notifikasi, dan watcher tugas.
+type NotificationHandler struct {
+	notifications *service.NotificationService
+}
+
+// NewNotificationHandler membuat NotificationHandler di atas NotificationService.
+func NewNotificationHandler(notifications *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notifications: notifications}
+}
+
+// GetNotifications: Mengambil inbox user yang login, terbaru lebih dulu, beserta jumlah yang belum dibaca.
+// Query: unread=true (hanya yang belum dibaca), limit (default 20, maksimum 100), cursor (next_cursor
+// dari halaman sebelumnya).
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	unreadOnly, err := strconv.ParseBool(c.DefaultQuery("unread", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unread must be true or false"})
+		return
+	}
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return
+		}
+	}
+
+	page, err := h.notifications.List(c.Request.Context(), userID, unreadOnly, c.Query("cursor"), limit)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve notifications")
+		return
+	}
+
+	notifications := make([]notificationResponse, 0, len(page.Notifications))
+	for _, notification := range page.Notifications {
+		notifications = append(notifications, newNotificationResponse(notification))
+	}
+	var nextCursor interface{}
+	if page.NextCursor != "" {
+		nextCursor = page.NextCursor
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Notifications retrieved successfully",
+		"notifications": notifications,
+		"unread_count":  page.UnreadCount,
+		"next_cursor":   nextCursor,
+	})
+}
+
+// MarkNotificationRead: Menandai satu notifikasi sebagai dibaca.
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	notificationID, ok := parseIDParam(c, "notification_id", "notification")
+	if !ok {
+		return
+	}
+
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	notification, err := h.notifications.MarkRead(c.Request.Context(), userID, notificationID)
+	if err != nil {
+		respondError(c, err, "Failed to mark notification as read")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read", "notification": newNotificationResponse(notification)})
+}
+
+// MarkAllNotificationsRead: Menandai semua notifikasi user sebagai dibaca.
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	updated, err := h.notifications.MarkAllRead(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err, "Failed to mark notifications as read")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "All notifications marked as read", "updated": updated})
+}
+
+// GetNotificationPreferences: Mengambil preferensi user untuk setiap jenis notifikasi.
+func (h *NotificationHandler) GetNotificationPreferences(c *gin.Context) {
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	preferences, err := h.notifications.Preferences(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve notification preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification preferences retrieved successfully", "preferences": preferences})
+}
+
+// UpdateNotificationPreferences: Mengaktifkan atau mematikan jenis notifikasi; jenis yang tidak dikirim
+// tidak berubah.
+func (h *NotificationHandler) UpdateNotificationPreferences(c *gin.Context) {
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var input InputNotificationPreferences
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preferences, err := h.notifications.UpdatePreferences(c.Request.Context(), userID, input.Preferences)
+	if err != nil {
+		respondError(c, err, "Failed to update notification preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification preferences updated successfully", "preferences": preferences})
+}
+
+// WatchTask: Menjadikan user yang login watcher tugas, sehingga menerima notifikasi saat tugas berubah.
+func (h *NotificationHandler) WatchTask(c *gin.Context) {
+	projectID, taskID, userID, ok := taskParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.notifications.Watch(c.Request.Context(), userID, projectID, taskID); err != nil {
+		respondError(c, err, "Failed to watch task")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task watched successfully"})
+}
+
+// UnwatchTask: Berhenti menerima notifikasi perubahan tugas.
+func (h *NotificationHandler) UnwatchTask(c *gin.Context) {
+	projectID, taskID, userID, ok := taskParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.notifications.Unwatch(c.Request.Context(), userID, projectID, taskID); err != nil {
+		respondError(c, err, "Failed to unwatch task")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task unwatched successfully"})
+}
+
+// GetTaskWatchers: Mengambil daftar watcher tugas.
+func (h *NotificationHandler) GetTaskWatchers(c *gin.Context) {
+	projectID, taskID, userID, ok := taskParams(c)
+	if !ok {
+		return
+	}
+
+	watchers, err := h.notifications.Watchers(c.Request.Context(), userID, projectID, taskID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve task watchers")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task watchers retrieved successfully", "watchers": watchers})
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mergePatch adalah dokumen JSON Merge Patch (RFC 7396) yang sudah di-decode per field.
+// Nilai RawMessage disimpan mentah supaya null bisa dibedakan dari field yang tidak dikirim.
+type mergePatch map[string]json.RawMessage
+
+// fieldErrors mengumpulkan pesan validasi per field agar client tahu field mana yang salah.
+type fieldErrors map[string]string
+
+// decodeMergePatch membaca body request sebagai merge patch dan menolak field yang tidak dikenal.
+// Jika gagal, response error sudah dikirim dan nilai kedua bernilai false.
+func decodeMergePatch(c *gin.Context, allowed ...string) (mergePatch, bool) {
+	if ct := c.GetHeader("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mediaType != "application/merge-patch+json" && mediaType != "application/json") {
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type must be application/merge-patch+json or application/json"})
+			return nil, false
+		}
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return nil, false
+	}
+
+	// RFC 7396 mengizinkan patch non-objek (mengganti seluruh resource), tapi itu tidak masuk akal di sini
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Patch document must be a JSON object"})
+		return nil, false
+	}
+
+	var patch mergePatch
+	if err := json.Unmarshal(trimmed, &patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON: " + err.Error()})
+		return nil, false
+	}
+
+	errs := fieldErrors{}
+	for field := range patch {
+		known := false
+		for _, name := range allowed {
+			if field == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			errs[field] = "unknown field"
+		}
+	}
+	if len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid patch document", "fields": errs})
+		return nil, false
+	}
+
+	return patch, true
+}
+
+// isNull melaporkan apakah nilai patch adalah literal JSON null.
+func isNull(raw json.RawMessage) bool {
+	return string(bytes.TrimSpace(raw)) == "null"
+}
+
+// requiredString mengambil field string yang tidak boleh null maupun kosong.
+func (p mergePatch) requiredString(field string, maxLen int, errs fieldErrors) (string, bool) {
+	raw, ok := p[field]
+	if !ok {
+		return "", false
+	}
+	if isNull(raw) {
+		errs[field] = "cannot be null"
+		return "", false
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		errs[field] = "must be a string"
+		return "", false
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		errs[field] = "must not be empty"
+		return "", false
+	}
+	if len(value) > maxLen {
+		errs[field] = fmt.Sprintf("must be at most %d characters", maxLen)
+		return "", false
+	}
+	return value, true
+}
+
+// optionalString mengambil field string yang boleh dikosongkan; null diperlakukan sebagai string kosong.
+func (p mergePatch) optionalString(field string, errs fieldErrors) (string, bool) {
+	raw, ok := p[field]
+	if !ok {
+		return "", false
+	}
+	if isNull(raw) {
+		return "", true
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		errs[field] = "must be a string or null"
+		return "", false
+	}
+	return value, true
+}
+
+// optionalDate mengambil field tanggal (YYYY-MM-DD); null berarti tanggal dihapus.
+// Nilai kedua bernilai true jika field ada di patch dan valid.
+func (p mergePatch) optionalDate(field string, errs fieldErrors) (*time.Time, bool) {
+	raw, ok := p[field]
+	if !ok {
+		return nil, false
+	}
+	if isNull(raw) {
+		return nil, true
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		errs[field] = "must be a date string (YYYY-MM-DD) or null"
+		return nil, false
+	}
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		errs[field] = "must use the YYYY-MM-DD format"
+		return nil, false
+	}
+	return &t, true
+}
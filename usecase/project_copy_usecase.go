@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"taskify/service"
+	"taskify/utils"
+)
+
+// InputProjectCopy: Opsi untuk menduplikasi proyek atau membuat proyek dari template. Semua field opsional.
+type InputProjectCopy struct {
+	Name        string      `json:"name"`
+	Description *string     `json:"description"`
+	StartDate   *CustomDate `json:"start_date"`   // Deadline digeser relatif terhadap tanggal mulai ini (YYYY-MM-DD)
+	ResetStatus bool        `json:"reset_status"` // Jika true, semua tugas baru berstatus todo
+}
+
+// bindProjectCopy membaca InputProjectCopy; body boleh kosong karena semua opsi memiliki nilai default.
+// Jika gagal, 400 sudah dikirim.
+func bindProjectCopy(c *gin.Context) (service.CopyOptions, bool) {
+	var input InputProjectCopy
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return service.CopyOptions{}, false
+	}
+
+	opts := service.CopyOptions{Name: input.Name, Description: input.Description, ResetStatus: input.ResetStatus}
+	if input.StartDate != nil {
+		startDate := input.StartDate.Time
+		opts.StartDate = &startDate
+	}
+	return opts, true
+}
+
+// DuplicateProject: Menyalin proyek beserta seluruh tugasnya menjadi proyek baru milik user yang login.
+// Jika start_date diberikan, semua deadline digeser sehingga deadline paling awal jatuh pada start_date.
+func (h *ProjectHandler) DuplicateProject(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "project_id", "project")
+	if !ok {
+		return
+	}
+
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	opts, ok := bindProjectCopy(c)
+	if !ok {
+		return
+	}
+
+	project, copied, err := h.projects.Duplicate(c.Request.Context(), userID, projectID, opts)
+	if err != nil {
+		respondError(c, err, "Failed to duplicate project")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Project duplicated successfully", "project": project, "tasks_copied": copied})
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"taskify/service"
+	"taskify/utils"
+)
+
+// ExportProject: Mengunduh proyek beserta semua tugasnya sebagai file. Query format=json (default) memuat
+// proyek dan tugas; format=csv hanya memuat tugas, satu baris per tugas. Keduanya bisa diimport kembali.
+func (h *ProjectHandler) ExportProject(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "project_id", "project")
+	if !ok {
+		return
+	}
+
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", service.FormatJSON))
+	if format != service.FormatJSON && format != service.FormatCSV {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be either csv or json"})
+		return
+	}
+
+	export, err := h.projects.Export(c.Request.Context(), userID, projectID)
+	if err != nil {
+		respondError(c, err, "Failed to export project")
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+exportFilename(export.Project.Name)+"."+format+`"`)
+	if format == service.FormatJSON {
+		c.JSON(http.StatusOK, export)
+		return
+	}
+	data, err := export.CSV()
+	if err != nil {
+		respondError(c, err, "Failed to export project")
+		return
+	}
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+}
+
+// exportFilename membuat nama file yang aman dari nama proyek: huruf kecil, dan karakter selain huruf dan
+// angka ASCII menjadi tanda hubung.
+func exportFilename(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	if filename := strings.TrimSuffix(b.String(), "-"); filename != "" {
+		return filename
+	}
+	return "project"
+}
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"taskify/models"
+	"taskify/service"
+	"taskify/utils"
+)
+
+// ImportProject: Membuat proyek baru dari file CSV atau JSON (lihat ExportProject), export board Trello,
+// atau export CSV Jira, dikirim sebagai field multipart "file" atau langsung sebagai body. Setiap baris
+// divalidasi dan dilaporkan. Untuk file besar gunakan import job (lihat CreateImportJob). Query:
+//   - format: csv, json, trello, atau jira; default csv atau json dari ekstensi nama file atau Content-Type
+//   - name: nama proyek; default dari file JSON atau nama file CSV
+//   - mode: atomic (default, tidak menyimpan apa pun jika ada baris yang tidak valid) atau best_effort
+//   - dry_run=true: hanya validasi dan laporan
+//   - status_map=<status>:<todo|in_progress|done>, boleh berulang, untuk status yang tidak dikenali
+func (h *ProjectHandler) ImportProject(c *gin.Context) {
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	opts, ok := bindImportOptions(c)
+	if !ok {
+		return
+	}
+	data, filename, contentType, ok := readImportFile(c, h.importMaxBytes)
+	if !ok {
+		return
+	}
+
+	file, err := service.ParseImport(importFormat(c, filename, contentType), data)
+	if err != nil {
+		respondError(c, err, "Failed to import project")
+		return
+	}
+	if file.ProjectName == "" && filename != "" {
+		file.ProjectName = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+
+	project, report, err := h.projects.Import(c.Request.Context(), userID, file, opts)
+	if err != nil {
+		respondError(c, err, "Failed to import project")
+		return
+	}
+
+	switch {
+	case report.DryRun:
+		c.JSON(http.StatusOK, gin.H{"message": "Dry run completed; no changes were applied", "report": report})
+	case report.Aborted:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Import failed; no changes were applied", "report": report})
+	default:
+		c.JSON(http.StatusCreated, gin.H{"message": "Project imported successfully", "project": project, "report": report})
+	}
+}
+
+// bindImportOptions membaca opsi import dari query. Jika gagal, 400 sudah dikirim.
+func bindImportOptions(c *gin.Context) (service.ImportOptions, bool) {
+	opts := service.ImportOptions{Name: c.Query("name"), Mode: c.Query("mode")}
+
+	if raw := c.Query("dry_run"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run must be a boolean"})
+			return service.ImportOptions{}, false
+		}
+		opts.DryRun = dryRun
+	}
+
+	for _, mapping := range c.QueryArray("status_map") {
+		from, to, found := strings.Cut(mapping, ":")
+		if !found || strings.TrimSpace(from) == "" || !models.TaskStatus(to).IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "status_map must look like <status>:<todo|in_progress|done>"})
+			return service.ImportOptions{}, false
+		}
+		if opts.StatusMap == nil {
+			opts.StatusMap = map[string]models.TaskStatus{}
+		}
+		opts.StatusMap[from] = models.TaskStatus(to)
+	}
+	return opts, true
+}
+
+// readImportFile membaca file import dari field multipart "file", atau dari body jika request bukan
+// multipart, dengan batas maxBytes. Mengembalikan isi file beserta nama file dan Content-Type-nya (nama
+// kosong jika dari body). Jika gagal, error sudah dikirim.
+func readImportFile(c *gin.Context, maxBytes int) (data []byte, filename, contentType string, ok bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(maxBytes))
+
+	var reader io.Reader = c.Request.Body
+	contentType = c.GetHeader("Content-Type")
+	if c.ContentType() == "multipart/form-data" {
+		header, err := c.FormFile("file")
+		if err != nil {
+			respondReadError(c, err, maxBytes, `Upload the file in the multipart field "file"`)
+			return nil, "", "", false
+		}
+		upload, err := header.Open()
+		if err != nil {
+			respondError(c, err, "Failed to read import file")
+			return nil, "", "", false
+		}
+		defer upload.Close()
+		reader, filename, contentType = upload, header.Filename, header.Header.Get("Content-Type")
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		respondReadError(c, err, maxBytes, "Failed to read import file")
+		return nil, "", "", false
+	}
+	if len(data) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Import file is empty"})
+		return nil, "", "", false
+	}
+	return data, filename, contentType, true
+}
+
+// respondReadError mengirim 413 jika body melebihi batas ukuran, atau 400 dengan message untuk error lain.
+func respondReadError(c *gin.Context, err error, maxBytes int, message string) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Import file must be at most %d bytes", maxBytes)})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": message})
+}
+
+// importFormat menentukan format file import dari query format, lalu ekstensi nama file, lalu Content-Type.
+// String kosong berarti tidak diketahui dan ditolak oleh service.ParseImport.
+func importFormat(c *gin.Context, filename, contentType string) string {
+	if format := c.Query("format"); format != "" {
+		return strings.ToLower(format)
+	}
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return service.FormatCSV
+	case ".json":
+		return service.FormatJSON
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv", "application/csv":
+		return service.FormatCSV
+	case "application/json":
+		return service.FormatJSON
+	}
+	return ""
+}
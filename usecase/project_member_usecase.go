@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"taskify/utils"
+)
+
+// InputProjectMember: User terdaftar yang akan dijadikan anggota proyek.
+type InputProjectMember struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// GetProjectMembers: Mengambil anggota proyek. Bisa dipanggil pemilik maupun anggota.
+func (h *ProjectHandler) GetProjectMembers(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "project_id", "project")
+	if !ok {
+		return
+	}
+
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.projects.Members(c.Request.Context(), userID, projectID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve project members")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Project members retrieved successfully", "members": members})
+}
+
+// AddProjectMember: Menjadikan user dengan email tertentu anggota proyek, sehingga bisa mengelola dan
+// memantau tugas-tugasnya. Hanya pemilik proyek.
+func (h *ProjectHandler) AddProjectMember(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "project_id", "project")
+	if !ok {
+		return
+	}
+
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var input InputProjectMember
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := h.projects.AddMember(c.Request.Context(), userID, projectID, input.Email)
+	if err != nil {
+		respondError(c, err, "Failed to add project member")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Project member added successfully", "member": member})
+}
+
+// RemoveProjectMember: Mengeluarkan anggota dari proyek. Pemilik boleh mengeluarkan siapa pun; anggota
+// hanya boleh mengeluarkan dirinya sendiri.
+func (h *ProjectHandler) RemoveProjectMember(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "project_id", "project")
+	if !ok {
+		return
+	}
+	memberID, ok := parseIDParam(c, "user_id", "user")
+	if !ok {
+		return
+	}
+
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	if err := h.projects.RemoveMember(c.Request.Context(), userID, projectID, memberID); err != nil {
+		respondError(c, err, "Failed to remove project member")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Project member removed successfully"})
+}
@@ -1,14 +1,14 @@
 package usecase
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 
-	"taskify/config"
-	"taskify/models"
+	"taskify/service"
 	"taskify/utils"
 )
 
@@ -19,8 +19,22 @@ type InputProject struct {
 	CreatedByID uuid.UUID `json:"created_by" binding:"required"` // Field ini wajib diisi di body saat Create Project
 }
 
+// ProjectHandler menangani endpoint proyek.
+type ProjectHandler struct {
+	projects       *service.ProjectService
+	requireIfMatch bool
+	importMaxBytes int
+}
+
+// NewProjectHandler membuat ProjectHandler di atas ProjectService.
+// requireIfMatch (REQUIRE_IF_MATCH) mewajibkan header If-Match pada PUT/PATCH/DELETE;
+// importMaxBytes (IMPORT_MAX_BYTES) membatasi ukuran file import.
+func NewProjectHandler(projects *service.ProjectService, requireIfMatch bool, importMaxBytes int) *ProjectHandler {
+	return &ProjectHandler{projects: projects, requireIfMatch: requireIfMatch, importMaxBytes: importMaxBytes}
+}
+
 // CreateProject: Membuat proyek baru. Memerlukan otentikasi (JWT) dan CreatedByID manual.
-func CreateProject(c *gin.Context) {
+func (h *ProjectHandler) CreateProject(c *gin.Context) {
 	var input InputProject
 	// Melakukan binding JSON dari request body ke struct InputProject
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -28,36 +42,9 @@ func CreateProject(c *gin.Context) {
 		return
 	}
 
-	// Verifikasi apakah CreatedByID yang diberikan di body request adalah user yang valid di database.
-	var user models.User
-	if err := config.DB.Where("id = ?", input.CreatedByID).First(&user).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			// Jika user dengan ID tersebut tidak ditemukan
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Provided created_by ID does not correspond to a valid user"})
-		} else {
-			// Jika ada error database lain saat mencari user
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error validating created_by ID"})
-		}
-		return
-	}
-
-	// Buat objek Project baru
-	project := models.Project{
-		ID:          uuid.New(), // Generate UUID baru untuk ID proyek
-		Name:        input.Name,
-		Description: input.Description,
-		CreatedByID: input.CreatedByID, // Set CreatedByID dari input request
-	}
-
-	// Simpan proyek ke database
-	if err := config.DB.Create(&project).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create project"})
-		return
-	}
-
-	// PRELOAD USER UNTUK RESPONSE: Agar detail User muncul di JSON respons
-	if err := config.DB.Preload("User").First(&project, "id = ?", project.ID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to preload user for project response"})
+	project, err := h.projects.Create(c.Request.Context(), input.Name, input.Description, input.CreatedByID)
+	if err != nil {
+		respondError(c, err, "Failed to create project")
 		return
 	}
 
@@ -65,7 +52,8 @@ func CreateProject(c *gin.Context) {
 }
 
 // GetProjects: Mengambil semua proyek yang dimiliki oleh user yang sedang login.
-func GetProjects(c *gin.Context) {
+// Proyek yang diarsipkan disembunyikan kecuali query ?include_archived=true diberikan.
+func (h *ProjectHandler) GetProjects(c *gin.Context) {
 	// AMBIL USER ID DARI JWT TOKEN: Ini adalah kunci otorisasi!
 	userID, ok := utils.GetUserIDFromContext(c)
 	if !ok {
@@ -73,11 +61,19 @@ func GetProjects(c *gin.Context) {
 		return
 	}
 
-	var projects []models.Project
-	// Ambil proyek di mana created_by_id sama dengan userID dari token
-	// PRELOAD USER UNTUK RESPONSE: Agar detail User muncul di JSON respons
-	if err := config.DB.Preload("User").Where("created_by_id = ?", userID).Find(&projects).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve projects"})
+	includeArchived := false
+	if raw := c.Query("include_archived"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "include_archived must be a boolean"})
+			return
+		}
+		includeArchived = parsed
+	}
+
+	projects, err := h.projects.List(c.Request.Context(), userID, includeArchived)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve projects")
 		return
 	}
 
@@ -85,11 +81,9 @@ func GetProjects(c *gin.Context) {
 }
 
 // GetProjectByID: Mengambil satu proyek berdasarkan ID, memastikan user yang login adalah pemiliknya.
-func GetProjectByID(c *gin.Context) {
-	projectIDStr := c.Param("id")              // Ambil projectID dari parameter URL
-	projectID, err := uuid.Parse(projectIDStr) // Parse string ke UUID
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID format"})
+func (h *ProjectHandler) GetProjectByID(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "id", "project")
+	if !ok {
 		return
 	}
 
@@ -99,16 +93,14 @@ func GetProjectByID(c *gin.Context) {
 		return
 	}
 
-	var project models.Project
-	// Cari proyek berdasarkan ID DAN pastikan created_by_id cocok dengan userID dari token.
-	// PRELOAD USER UNTUK RESPONSE: Agar detail User muncul di JSON respons
-	if err := config.DB.Preload("User").Where("id = ? AND created_by_id = ?", projectID, userID).First(&project).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			// Jika proyek tidak ditemukan ATAU user yang login bukan pemiliknya
-			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or you don't have access"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve project"})
-		}
+	project, err := h.projects.Get(c.Request.Context(), userID, projectID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve project")
+		return
+	}
+
+	// CONDITIONAL GET: Kirim 304 jika client sudah memegang versi terbaru
+	if notModified(c, project.Version) {
 		return
 	}
 
@@ -116,11 +108,9 @@ func GetProjectByID(c *gin.Context) {
 }
 
 // UpdateProject: Mengupdate proyek yang sudah ada, memastikan user yang login adalah pemiliknya.
-func UpdateProject(c *gin.Context) {
-	projectIDStr := c.Param("id") // Ambil projectID dari parameter URL
-	projectID, err := uuid.Parse(projectIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID format"})
+func (h *ProjectHandler) UpdateProject(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "id", "project")
+	if !ok {
 		return
 	}
 
@@ -139,39 +129,72 @@ func UpdateProject(c *gin.Context) {
 		return
 	}
 
-	var project models.Project
-	// Cari proyek untuk diupdate berdasarkan ID DAN pastikan user yang login adalah pemiliknya.
-	if err := config.DB.Where("id = ? AND created_by_id = ?", projectID, userID).First(&project).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or you don't have access"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve project for update"})
-		}
+	// Field kosong tetap diabaikan seperti sebelumnya
+	var changes service.ProjectChanges
+	if input.Name != "" {
+		changes.Name = &input.Name
+	}
+	if input.Description != "" {
+		changes.Description = &input.Description
+	}
+
+	project, err := h.projects.Update(c.Request.Context(), userID, projectID, changes, ifMatch(c, h.requireIfMatch))
+	if err != nil {
+		h.respondProjectError(c, err, userID, projectID, "Failed to update project")
 		return
 	}
 
-	// Lakukan update pada field yang diberikan
-	updates := models.Project{Name: input.Name, Description: input.Description}
-	if err := config.DB.Model(&project).Updates(updates).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update project"})
+	c.Header("ETag", etagFor(project.Version))
+	c.JSON(http.StatusOK, gin.H{"message": "Project updated successfully", "project": project})
+}
+
+// PatchProject: Mengupdate sebagian field proyek dengan semantik JSON Merge Patch (RFC 7396).
+// Field yang tidak dikirim tidak berubah, dan description bisa dikosongkan dengan null atau "".
+func (h *ProjectHandler) PatchProject(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "id", "project")
+	if !ok {
 		return
 	}
 
-	// PRELOAD USER UNTUK RESPONSE: Agar detail User muncul di JSON respons setelah update
-	if err := config.DB.Preload("User").First(&project, "id = ?", project.ID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to preload user after project update"})
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
 		return
 	}
 
+	patch, ok := decodeMergePatch(c, "name", "description")
+	if !ok {
+		return
+	}
+
+	// Validasi per field; hanya field yang dikirim yang diubah
+	errs := fieldErrors{}
+	var changes service.ProjectChanges
+	if name, ok := patch.requiredString("name", 255, errs); ok {
+		changes.Name = &name
+	}
+	if description, ok := patch.optionalString("description", errs); ok {
+		changes.Description = &description
+	}
+	if len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid patch document", "fields": errs})
+		return
+	}
+
+	project, err := h.projects.Update(c.Request.Context(), userID, projectID, changes, ifMatch(c, h.requireIfMatch))
+	if err != nil {
+		h.respondProjectError(c, err, userID, projectID, "Failed to update project")
+		return
+	}
+
+	c.Header("ETag", etagFor(project.Version))
 	c.JSON(http.StatusOK, gin.H{"message": "Project updated successfully", "project": project})
 }
 
-// DeleteProject: Menghapus proyek, memastikan user yang login adalah pemiliknya.
-func DeleteProject(c *gin.Context) {
-	projectIDStr := c.Param("id") // Ambil projectID dari parameter URL
-	projectID, err := uuid.Parse(projectIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID format"})
+// DeleteProject: Menghapus proyek beserta tugas-tugasnya, memastikan user yang login adalah pemiliknya.
+func (h *ProjectHandler) DeleteProject(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "id", "project")
+	if !ok {
 		return
 	}
 
@@ -181,28 +204,64 @@ func DeleteProject(c *gin.Context) {
 		return
 	}
 
-	var project models.Project
-	// Cari proyek untuk dihapus berdasarkan ID DAN pastikan user yang login adalah pemiliknya.
-	if err := config.DB.Where("id = ? AND created_by_id = ?", projectID, userID).First(&project).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or you don't have access"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve project for deletion"})
-		}
+	if err := h.projects.Delete(c.Request.Context(), userID, projectID, ifMatch(c, h.requireIfMatch)); err != nil {
+		h.respondProjectError(c, err, userID, projectID, "Failed to delete project")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Project deleted successfully"})
+}
+
+// ArchiveProject: Mengarsipkan proyek sehingga menjadi read-only dan tersembunyi dari daftar default.
+func (h *ProjectHandler) ArchiveProject(c *gin.Context) {
+	h.setProjectArchived(c, true)
+}
+
+// UnarchiveProject: Mengembalikan proyek yang diarsipkan agar bisa diubah lagi.
+func (h *ProjectHandler) UnarchiveProject(c *gin.Context) {
+	h.setProjectArchived(c, false)
+}
+
+func (h *ProjectHandler) setProjectArchived(c *gin.Context, archived bool) {
+	projectID, ok := parseIDParam(c, "id", "project")
+	if !ok {
 		return
 	}
 
-	// Hapus tugas-tugas terkait. (Secara teori, ON DELETE CASCADE di DB sudah cukup, tapi ini sebagai fallback)
-	if err := config.DB.Where("project_id = ?", projectID).Delete(&models.Task{}).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete associated tasks"})
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
 		return
 	}
 
-	// Hapus proyek
-	if err := config.DB.Delete(&project).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete project"})
+	project, err := h.projects.SetArchived(c.Request.Context(), userID, projectID, archived, ifMatch(c, h.requireIfMatch))
+	if err != nil {
+		h.respondProjectError(c, err, userID, projectID, "Failed to update project archive state")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Project deleted successfully"})
+	message := "Project unarchived successfully"
+	if archived {
+		message = "Project archived successfully"
+	}
+	c.Header("ETag", etagFor(project.Version))
+	c.JSON(http.StatusOK, gin.H{"message": message, "project": project})
+}
+
+// respondProjectError mengirim error dari ProjectService. Untuk 412, representasi proyek terbaru ikut
+// dikirim supaya client bisa menggabungkan perubahannya lalu mencoba lagi.
+func (h *ProjectHandler) respondProjectError(c *gin.Context, err error, userID, projectID uuid.UUID, fallback string) {
+	if !errors.Is(err, service.ErrPreconditionFailed) {
+		respondError(c, err, fallback)
+		return
+	}
+
+	current, err := h.projects.Get(c.Request.Context(), userID, projectID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve project")
+		return
+	}
+
+	c.Header("ETag", etagFor(current.Version))
+	c.JSON(http.StatusPreconditionFailed, gin.H{"error": service.ErrPreconditionFailed.Message, "project": current})
 }
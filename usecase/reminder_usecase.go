@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"taskify/service"
+	"taskify/utils"
+)
+
+// InputReminderSettings: Pengaturan pengingat deadline; field yang tidak dikirim tidak berubah.
+type InputReminderSettings struct {
+	OffsetsMinutes []int   `json:"offsets_minutes"` // [] mematikan pengingat sebelum deadline
+	OverdueAlerts  *bool   `json:"overdue_alerts"`
+	DailyDigest    *bool   `json:"daily_digest"`
+	DigestHour     *int    `json:"digest_hour"`
+	Timezone       *string `json:"timezone"`
+}
+
+// ReminderHandler menangani pengaturan pengingat deadline user.
+type ReminderHandler struct {
+	reminders *service.ReminderService
+}
+
+// NewReminderHandler membuat ReminderHandler di atas ReminderService.
+func NewReminderHandler(reminders *service.ReminderService) *ReminderHandler {
+	return &ReminderHandler{reminders: reminders}
+}
+
+// GetReminderSettings: Mengambil pengaturan pengingat deadline user yang login.
+func (h *ReminderHandler) GetReminderSettings(c *gin.Context) {
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.reminders.Settings(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve reminder settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reminder settings retrieved successfully", "settings": settings})
+}
+
+// UpdateReminderSettings: Mengubah offset pengingat, peringatan overdue, digest harian, atau zona waktu.
+func (h *ReminderHandler) UpdateReminderSettings(c *gin.Context) {
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var input InputReminderSettings
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := h.reminders.UpdateSettings(c.Request.Context(), userID, service.ReminderSettingChanges{
+		Offsets:       input.OffsetsMinutes,
+		OverdueAlerts: input.OverdueAlerts,
+		DailyDigest:   input.DailyDigest,
+		DigestHour:    input.DigestHour,
+		Timezone:      input.Timezone,
+	})
+	if err != nil {
+		respondError(c, err, "Failed to update reminder settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reminder settings updated successfully", "settings": settings})
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"taskify/service"
+	"taskify/utils"
+)
+
+// InputBulkTasks: Struktur input untuk menjalankan banyak operasi tugas dalam satu request.
+type InputBulkTasks struct {
+	Mode       string                      `json:"mode"` // atomic (default) atau best_effort
+	Operations []service.BulkTaskOperation `json:"operations" binding:"required,min=1"`
+}
+
+// BulkTasks: Menjalankan banyak operasi tugas (create, update status, set deadline, delete, move)
+// dalam satu transaksi. Mode atomic membatalkan semuanya jika satu operasi gagal, sedangkan
+// mode best_effort hanya membatalkan operasi yang gagal (memakai savepoint).
+func (h *TaskHandler) BulkTasks(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "project_id", "project")
+	if !ok {
+		return
+	}
+
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var input InputBulkTasks
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	outcome, err := h.tasks.Bulk(c.Request.Context(), userID, projectID, input.Mode, input.Operations)
+	if err != nil {
+		respondError(c, err, "Failed to execute bulk operation")
+		return
+	}
+
+	if outcome.Aborted {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Bulk operation failed; no changes were applied", "mode": outcome.Mode, "results": outcome.Results})
+		return
+	}
+
+	succeeded := outcome.Succeeded()
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Bulk operation completed",
+		"mode":      outcome.Mode,
+		"succeeded": succeeded,
+		"failed":    len(outcome.Results) - succeeded,
+		"results":   outcome.Results,
+	})
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// InputTaskTransfer: Struktur input untuk memindahkan atau menyalin tugas ke proyek lain.
+type InputTaskTransfer struct {
+	TargetProjectID uuid.UUID `json:"target_project_id" binding:"required"`
+}
+
+// MoveTask: Memindahkan tugas ke proyek lain milik user yang sama dan mencatatnya di riwayat tugas.
+func (h *TaskHandler) MoveTask(c *gin.Context) {
+	projectID, taskID, userID, ok := taskParams(c)
+	if !ok {
+		return
+	}
+
+	var input InputTaskTransfer
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, err := h.tasks.Move(c.Request.Context(), userID, projectID, taskID, input.TargetProjectID, ifMatch(c, h.requireIfMatch))
+	if err != nil {
+		// Pada 412 tugas masih berada di proyek asal
+		h.respondTaskError(c, err, userID, projectID, taskID, "Failed to move task")
+		return
+	}
+
+	c.Header("ETag", etagFor(task.Version))
+	c.JSON(http.StatusOK, gin.H{"message": "Task moved successfully", "task": task})
+}
+
+// CopyTask: Menyalin tugas ke proyek lain (atau proyek yang sama) milik user. Proyek asal boleh diarsipkan
+// karena hanya dibaca, tetapi proyek tujuan harus bisa ditulis.
+func (h *TaskHandler) CopyTask(c *gin.Context) {
+	projectID, taskID, userID, ok := taskParams(c)
+	if !ok {
+		return
+	}
+
+	var input InputTaskTransfer
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	copied, err := h.tasks.Copy(c.Request.Context(), userID, projectID, taskID, input.TargetProjectID)
+	if err != nil {
+		respondError(c, err, "Failed to copy task")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Task copied successfully", "task": copied})
+}
+
+// GetTaskHistory: Mengambil riwayat perpindahan/penyalinan sebuah tugas, terbaru lebih dulu.
+func (h *TaskHandler) GetTaskHistory(c *gin.Context) {
+	projectID, taskID, userID, ok := taskParams(c)
+	if !ok {
+		return
+	}
+
+	history, err := h.tasks.History(c.Request.Context(), userID, projectID, taskID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve task history")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task history retrieved successfully", "history": history})
+}
@@ -2,15 +2,15 @@ package usecase
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 
-	"taskify/config"
 	"taskify/models"
+	"taskify/service"
 	"taskify/utils"
 )
 
@@ -42,27 +42,27 @@ func (cd CustomDate) MarshalJSON() ([]byte, error) {
 	return json.Marshal(cd.Time.Format(dateLayout))
 }
 
-func CreateTask(c *gin.Context) {
-	projectIDStr := c.Param("project_id")
-	projectID, err := uuid.Parse(projectIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID format"}) // Jika format ID salah
-		return
-	}
+// TaskHandler menangani endpoint tugas di dalam proyek.
+type TaskHandler struct {
+	tasks          *service.TaskService
+	requireIfMatch bool
+}
 
-	userID, ok := utils.GetUserIDFromContext(c)
+// NewTaskHandler membuat TaskHandler di atas TaskService.
+// requireIfMatch (REQUIRE_IF_MATCH) mewajibkan header If-Match pada PUT/PATCH/DELETE dan move.
+func NewTaskHandler(tasks *service.TaskService, requireIfMatch bool) *TaskHandler {
+	return &TaskHandler{tasks: tasks, requireIfMatch: requireIfMatch}
+}
+
+// CreateTask: Membuat tugas baru di proyek milik user yang login.
+func (h *TaskHandler) CreateTask(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "project_id", "project")
 	if !ok {
 		return
 	}
 
-	var project models.Project
-
-	if err := config.DB.Where("id = ? AND created_by_id = ?", projectID, userID).First(&project).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or you don't have access to this project"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve project for task creation"})
-		}
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
 		return
 	}
 
@@ -74,29 +74,19 @@ func CreateTask(c *gin.Context) {
 		return
 	}
 
-	// Buat objek Task baru
-	task := models.Task{
-		ID:          uuid.New(),
-		ProjectID:   projectID,
+	taskInput := service.TaskInput{
 		Title:       input.Title,
 		Description: input.Description,
 		Status:      input.Status,
 	}
-
 	if input.Deadline != nil {
 		t := input.Deadline.Time
-		task.Deadline = &t
-	}
-
-	// Simpan tugas ke database
-	if err := config.DB.Create(&task).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create task"})
-		return
+		taskInput.Deadline = &t
 	}
 
-	// PRELOAD PROJECT DAN USER UNTUK RESPONSE: Agar detail Project dan User muncul di JSON respons
-	if err := config.DB.Preload("Project.User").First(&task, "id = ?", task.ID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to preload project/user for task response"})
+	task, err := h.tasks.Create(c.Request.Context(), userID, projectID, taskInput)
+	if err != nil {
+		respondError(c, err, "Failed to create task")
 		return
 	}
 
@@ -104,11 +94,9 @@ func CreateTask(c *gin.Context) {
 }
 
 // GetTasksByProject: Mengambil semua tugas untuk proyek spesifik, memastikan user yang login punya akses.
-func GetTasksByProject(c *gin.Context) {
-	projectIDStr := c.Param("project_id") // Ambil projectID dari parameter URL
-	projectID, err := uuid.Parse(projectIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID format"})
+func (h *TaskHandler) GetTasksByProject(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "project_id", "project")
+	if !ok {
 		return
 	}
 
@@ -118,22 +106,9 @@ func GetTasksByProject(c *gin.Context) {
 		return
 	}
 
-	var project models.Project
-	// VERIFIKASI KEPEMILIKAN PROYEK: Pastikan projectID di URL dimiliki oleh userID yang login.
-	if err := config.DB.Where("id = ? AND created_by_id = ?", projectID, userID).First(&project).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or you don't have access to this project"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve project"})
-		}
-		return
-	}
-
-	var tasks []models.Task
-	// Ambil semua tugas yang ProjectID-nya cocok dengan projectID dari URL.
-	// PRELOAD PROJECT DAN USER UNTUK RESPONSE: Agar detail Project dan User muncul di JSON respons
-	if err := config.DB.Preload("Project.User").Where("project_id = ?", projectID).Find(&tasks).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tasks"})
+	tasks, err := h.tasks.List(c.Request.Context(), userID, projectID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve tasks")
 		return
 	}
 
@@ -141,180 +116,152 @@ func GetTasksByProject(c *gin.Context) {
 }
 
 // GetTaskByID: Mengambil satu tugas berdasarkan ID dalam proyek spesifik, memastikan user punya akses.
-func GetTaskByID(c *gin.Context) {
-	projectIDStr := c.Param("project_id") // Ambil projectID dari parameter URL
-	projectID, err := uuid.Parse(projectIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID format"})
+func (h *TaskHandler) GetTaskByID(c *gin.Context) {
+	projectID, taskID, userID, ok := taskParams(c)
+	if !ok {
 		return
 	}
 
-	taskIDStr := c.Param("task_id") // Ambil taskID dari parameter URL
-	taskID, err := uuid.Parse(taskIDStr)
+	task, err := h.tasks.Get(c.Request.Context(), userID, projectID, taskID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID format"})
+		respondError(c, err, "Failed to retrieve task")
 		return
 	}
 
-	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
-	userID, ok := utils.GetUserIDFromContext(c)
-	if !ok {
+	// CONDITIONAL GET: Kirim 304 jika client sudah memegang versi terbaru
+	if notModified(c, task.Version) {
 		return
 	}
 
-	var project models.Project
-	// VERIFIKASI KEPEMILIKAN PROYEK: Penting sebelum mencari task
-	if err := config.DB.Where("id = ? AND created_by_id = ?", projectID, userID).First(&project).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or you don't have access to this project"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve project"})
-		}
+	c.JSON(http.StatusOK, gin.H{"message": "Task retrieved successfully", "task": task})
+}
+
+// UpdateTask: Mengupdate tugas dalam proyek spesifik, memastikan user punya akses.
+func (h *TaskHandler) UpdateTask(c *gin.Context) {
+	projectID, taskID, userID, ok := taskParams(c)
+	if !ok {
 		return
 	}
 
-	var task models.Task
-	// Cari tugas berdasarkan taskID DAN pastikan projectID cocok DAN project tersebut dimiliki oleh userID yang login
-	// PRELOAD PROJECT DAN USER UNTUK RESPONSE: Agar detail Project dan User muncul di JSON respons
-	if err := config.DB.Preload("Project.User").Where("id = ? AND project_id = ?", taskID, projectID).First(&task).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found in this project or you don't have access"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve task"})
-		}
+	var input InputTask
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Task retrieved successfully", "task": task})
-}
-
-// UpdateTask: Mengupdate tugas dalam proyek spesifik, memastikan user punya akses.
-func UpdateTask(c *gin.Context) {
-	projectIDStr := c.Param("project_id") // Ambil projectID dari parameter URL
-	projectID, err := uuid.Parse(projectIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID format"})
-		return
+	// Title, description dan status selalu ditulis; deadline hanya jika dikirim
+	changes := service.TaskChanges{
+		Title:       &input.Title,
+		Description: &input.Description,
+		Status:      &input.Status,
+	}
+	if input.Deadline != nil {
+		t := input.Deadline.Time
+		changes.Deadline = &t
+		changes.SetDeadline = true
 	}
 
-	taskIDStr := c.Param("task_id") // Ambil taskID dari parameter URL
-	taskID, err := uuid.Parse(taskIDStr)
+	task, err := h.tasks.Update(c.Request.Context(), userID, projectID, taskID, changes, ifMatch(c, h.requireIfMatch))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID format"})
+		h.respondTaskError(c, err, userID, projectID, taskID, "Failed to update task")
 		return
 	}
 
-	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
-	userID, ok := utils.GetUserIDFromContext(c)
+	c.Header("ETag", etagFor(task.Version))
+	c.JSON(http.StatusOK, gin.H{"message": "Task updated successfully", "task": task})
+}
+
+// PatchTask: Mengupdate sebagian field tugas dengan semantik JSON Merge Patch (RFC 7396).
+// Field yang tidak dikirim tidak berubah; deadline bisa dihapus dengan mengirim null.
+func (h *TaskHandler) PatchTask(c *gin.Context) {
+	projectID, taskID, userID, ok := taskParams(c)
 	if !ok {
 		return
 	}
 
-	var project models.Project
-	// VERIFIKASI KEPEMILIKAN PROYEK: Penting sebelum mengupdate task
-	if err := config.DB.Where("id = ? AND created_by_id = ?", projectID, userID).First(&project).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or you don't have access to this project"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve project for task update"})
-		}
+	patch, ok := decodeMergePatch(c, "title", "description", "status", "deadline")
+	if !ok {
 		return
 	}
 
-	var input InputTask
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+	// Validasi per field; hanya field yang dikirim yang diubah
+	errs := fieldErrors{}
+	var changes service.TaskChanges
+	if title, ok := patch.requiredString("title", 255, errs); ok {
+		changes.Title = &title
 	}
-
-	var task models.Task
-	// Cari tugas untuk diupdate berdasarkan taskID DAN pastikan projectID cocok
-	if err := config.DB.Where("id = ? AND project_id = ?", taskID, projectID).First(&task).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found in this project or you don't have access"})
+	if description, ok := patch.optionalString("description", errs); ok {
+		changes.Description = &description
+	}
+	if status, ok := patch.requiredString("status", 20, errs); ok {
+		if taskStatus := models.TaskStatus(status); taskStatus.IsValid() {
+			changes.Status = &taskStatus
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve task for update"})
+			errs["status"] = "must be one of todo, in_progress, done"
 		}
-		return
 	}
-
-	// Lakukan update pada field yang diberikan
-	updates := models.Task{
-		ID:          uuid.New(),
-		ProjectID:   projectID,
-		Title:       input.Title,
-		Description: input.Description,
-		Status:      input.Status,
-	}
-
-	if input.Deadline != nil {
-		t := input.Deadline.Time
-		task.Deadline = &t
+	if deadline, ok := patch.optionalDate("deadline", errs); ok {
+		changes.Deadline = deadline
+		changes.SetDeadline = true
 	}
-
-	if err := config.DB.Model(&task).Updates(updates).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update task"})
+	if len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid patch document", "fields": errs})
 		return
 	}
 
-	// PRELOAD PROJECT DAN USER UNTUK RESPONSE: Agar detail Project dan User muncul di JSON respons setelah update
-	if err := config.DB.Preload("Project.User").First(&task, "id = ?", task.ID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to preload project/user after task update"})
+	task, err := h.tasks.Update(c.Request.Context(), userID, projectID, taskID, changes, ifMatch(c, h.requireIfMatch))
+	if err != nil {
+		h.respondTaskError(c, err, userID, projectID, taskID, "Failed to update task")
 		return
 	}
 
+	c.Header("ETag", etagFor(task.Version))
 	c.JSON(http.StatusOK, gin.H{"message": "Task updated successfully", "task": task})
 }
 
 // DeleteTask: Menghapus tugas dalam proyek spesifik, memastikan user punya akses.
-func DeleteTask(c *gin.Context) {
-	projectIDStr := c.Param("project_id") // Ambil projectID dari parameter URL
-	projectID, err := uuid.Parse(projectIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID format"})
+func (h *TaskHandler) DeleteTask(c *gin.Context) {
+	projectID, taskID, userID, ok := taskParams(c)
+	if !ok {
 		return
 	}
 
-	taskIDStr := c.Param("task_id") // Ambil taskID dari parameter URL
-	taskID, err := uuid.Parse(taskIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID format"})
+	if err := h.tasks.Delete(c.Request.Context(), userID, projectID, taskID, ifMatch(c, h.requireIfMatch)); err != nil {
+		h.respondTaskError(c, err, userID, projectID, taskID, "Failed to delete task")
 		return
 	}
 
-	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
-	userID, ok := utils.GetUserIDFromContext(c)
-	if !ok {
+	c.JSON(http.StatusOK, gin.H{"message": "Task deleted successfully"})
+}
+
+// respondTaskError mengirim error dari TaskService. Untuk 412, representasi tugas terbaru ikut dikirim
+// supaya client bisa menggabungkan perubahannya lalu mencoba lagi.
+func (h *TaskHandler) respondTaskError(c *gin.Context, err error, userID, projectID, taskID uuid.UUID, fallback string) {
+	if !errors.Is(err, service.ErrPreconditionFailed) {
+		respondError(c, err, fallback)
 		return
 	}
 
-	var project models.Project
-	// VERIFIKASI KEPEMILIKAN PROYEK: Penting sebelum menghapus task
-	if err := config.DB.Where("id = ? AND created_by_id = ?", projectID, userID).First(&project).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or you don't have access to this project"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve project for task deletion"})
-		}
+	current, err := h.tasks.Get(c.Request.Context(), userID, projectID, taskID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve task")
 		return
 	}
 
-	var task models.Task
-	// Cari tugas untuk dihapus berdasarkan taskID DAN pastikan projectID cocok
-	if err := config.DB.Where("id = ? AND project_id = ?", taskID, projectID).First(&task).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found in this project or you don't have access"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve task for deletion"})
-		}
+	c.Header("ETag", etagFor(current.Version))
+	c.JSON(http.StatusPreconditionFailed, gin.H{"error": service.ErrPreconditionFailed.Message, "task": current})
+}
+
+// taskParams mengambil projectID dan taskID dari parameter URL serta userID dari JWT.
+// Jika gagal, response error sudah dikirim.
+func taskParams(c *gin.Context) (projectID, taskID, userID uuid.UUID, ok bool) {
+	if projectID, ok = parseIDParam(c, "project_id", "project"); !ok {
 		return
 	}
-
-	// Hapus tugas
-	if err := config.DB.Delete(&task).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete task"})
+	if taskID, ok = parseIDParam(c, "task_id", "task"); !ok {
 		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Task deleted successfully"})
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok = utils.GetUserIDFromContext(c)
+	return
 }
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"taskify/service"
+	"taskify/utils"
+)
+
+// InputTemplate: Nama dan deskripsi template saat menyimpan proyek sebagai template (opsional,
+// default diambil dari proyek sumber).
+type InputTemplate struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+}
+
+// TemplateHandler menangani endpoint template proyek.
+type TemplateHandler struct {
+	templates *service.TemplateService
+}
+
+// NewTemplateHandler membuat TemplateHandler di atas TemplateService.
+func NewTemplateHandler(templates *service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{templates: templates}
+}
+
+// SaveProjectAsTemplate: Menyimpan proyek beserta tugas-tugasnya sebagai template yang bisa dipakai ulang.
+// Deadline disimpan sebagai selisih hari dari deadline paling awal di proyek.
+func (h *TemplateHandler) SaveProjectAsTemplate(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "project_id", "project")
+	if !ok {
+		return
+	}
+
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var input InputTemplate
+	// Body boleh kosong; nama dan deskripsi diambil dari proyek
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.templates.SaveProject(c.Request.Context(), userID, projectID, input.Name, input.Description)
+	if err != nil {
+		respondError(c, err, "Failed to save project as template")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Template created successfully", "template": template})
+}
+
+// GetTemplates: Mengambil semua template milik user yang sedang login.
+func (h *TemplateHandler) GetTemplates(c *gin.Context) {
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	templates, err := h.templates.List(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve templates")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Templates retrieved successfully", "templates": templates})
+}
+
+// GetTemplateByID: Mengambil satu template beserta tugas-tugasnya.
+func (h *TemplateHandler) GetTemplateByID(c *gin.Context) {
+	templateID, userID, ok := templateParams(c)
+	if !ok {
+		return
+	}
+
+	template, err := h.templates.Get(c.Request.Context(), userID, templateID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve template")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Template retrieved successfully", "template": template})
+}
+
+// DeleteTemplate: Menghapus template beserta tugas-tugasnya. Proyek yang pernah dibuat dari template tidak terpengaruh.
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	templateID, userID, ok := templateParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.templates.Delete(c.Request.Context(), userID, templateID); err != nil {
+		respondError(c, err, "Failed to delete template")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted successfully"})
+}
+
+// InstantiateTemplate: Membuat proyek baru dari template. Deadline dihitung dari start_date
+// (default hari ini) ditambah selisih hari yang tersimpan di template.
+func (h *TemplateHandler) InstantiateTemplate(c *gin.Context) {
+	templateID, userID, ok := templateParams(c)
+	if !ok {
+		return
+	}
+
+	opts, ok := bindProjectCopy(c)
+	if !ok {
+		return
+	}
+
+	project, created, err := h.templates.Instantiate(c.Request.Context(), userID, templateID, opts)
+	if err != nil {
+		respondError(c, err, "Failed to create project from template")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Project created from template successfully", "project": project, "tasks_created": created})
+}
+
+// templateParams mengambil templateID dari parameter URL dan userID dari JWT. Jika gagal, response error sudah dikirim.
+func templateParams(c *gin.Context) (templateID, userID uuid.UUID, ok bool) {
+	if templateID, ok = parseIDParam(c, "template_id", "template"); !ok {
+		return
+	}
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok = utils.GetUserIDFromContext(c)
+	return
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"taskify/models"
+	"taskify/service"
+	"taskify/utils"
+)
+
+// InputWebhook: Struktur input untuk mendaftarkan webhook. Secret kosong berarti dibuatkan server.
+type InputWebhook struct {
+	URL    string   `json:"url" binding:"required"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events" binding:"required"`
+	Active *bool    `json:"active"`
+}
+
+// InputWebhookPatch: Field webhook yang ingin diubah; field yang tidak dikirim tidak berubah.
+type InputWebhookPatch struct {
+	URL    *string  `json:"url"`
+	Secret *string  `json:"secret"`
+	Events []string `json:"events"`
+	Active *bool    `json:"active"`
+}
+
+// createdWebhook adalah response pembuatan webhook, satu-satunya response yang memuat secret.
+type createdWebhook struct {
+	models.Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhookHandler menangani endpoint webhook proyek.
+type WebhookHandler struct {
+	webhooks *service.WebhookService
+}
+
+// NewWebhookHandler membuat WebhookHandler di atas WebhookService.
+func NewWebhookHandler(webhooks *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhooks: webhooks}
+}
+
+// CreateWebhook: Mendaftarkan webhook untuk proyek milik user yang login. Secret hanya dikembalikan di
+// response ini, jadi client harus menyimpannya untuk memverifikasi X-Taskify-Signature.
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "project_id", "project")
+	if !ok {
+		return
+	}
+
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var input InputWebhook
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	webhook, err := h.webhooks.Create(c.Request.Context(), userID, projectID, service.WebhookInput{
+		URL:    input.URL,
+		Secret: input.Secret,
+		Events: input.Events,
+		Active: input.Active,
+	})
+	if err != nil {
+		respondError(c, err, "Failed to create webhook")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Webhook created successfully", "webhook": createdWebhook{Webhook: webhook, Secret: webhook.Secret}})
+}
+
+// GetWebhooks: Mengambil semua webhook proyek.
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	projectID, ok := parseIDParam(c, "project_id", "project")
+	if !ok {
+		return
+	}
+
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	webhooks, err := h.webhooks.List(c.Request.Context(), userID, projectID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve webhooks")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhooks retrieved successfully", "webhooks": webhooks})
+}
+
+// GetWebhookByID: Mengambil satu webhook proyek.
+func (h *WebhookHandler) GetWebhookByID(c *gin.Context) {
+	projectID, webhookID, userID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhooks.Get(c.Request.Context(), userID, projectID, webhookID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook retrieved successfully", "webhook": webhook})
+}
+
+// PatchWebhook: Mengubah URL, secret, daftar event, atau status aktif webhook.
+func (h *WebhookHandler) PatchWebhook(c *gin.Context) {
+	projectID, webhookID, userID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	var input InputWebhookPatch
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	webhook, err := h.webhooks.Update(c.Request.Context(), userID, projectID, webhookID, service.WebhookChanges{
+		URL:    input.URL,
+		Secret: input.Secret,
+		Events: input.Events,
+		Active: input.Active,
+	})
+	if err != nil {
+		respondError(c, err, "Failed to update webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook updated successfully", "webhook": webhook})
+}
+
+// DeleteWebhook: Menghapus webhook beserta riwayat pengirimannya.
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	projectID, webhookID, userID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhooks.Delete(c.Request.Context(), userID, projectID, webhookID); err != nil {
+		respondError(c, err, "Failed to delete webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// GetWebhookDeliveries: Mengambil pengiriman terbaru webhook beserta log setiap percobaannya.
+func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
+	projectID, webhookID, userID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.webhooks.Deliveries(c.Request.Context(), userID, projectID, webhookID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve webhook deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deliveries retrieved successfully", "deliveries": deliveries})
+}
+
+// RedeliverWebhook: Mengantrekan ulang event dari sebuah pengiriman (misalnya yang sudah dead) ke URL
+// webhook saat ini. Pengiriman berjalan asinkron, jadi response-nya 202.
+func (h *WebhookHandler) RedeliverWebhook(c *gin.Context) {
+	projectID, webhookID, userID, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseIDParam(c, "delivery_id", "delivery")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhooks.Redeliver(c.Request.Context(), userID, projectID, webhookID, deliveryID)
+	if err != nil {
+		respondError(c, err, "Failed to redeliver webhook")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Webhook delivery queued", "delivery": delivery})
+}
+
+// webhookParams mengambil projectID dan webhookID dari parameter URL dan userID dari JWT. Jika gagal,
+// response error sudah dikirim.
+func webhookParams(c *gin.Context) (projectID, webhookID, userID uuid.UUID, ok bool) {
+	if projectID, ok = parseIDParam(c, "project_id", "project"); !ok {
+		return
+	}
+	if webhookID, ok = parseIDParam(c, "webhook_id", "webhook"); !ok {
+		return
+	}
+	// AMBIL USER ID DARI JWT TOKEN: Kunci otorisasi!
+	userID, ok = utils.GetUserIDFromContext(c)
+	return
+}
@@ -1,11 +1,12 @@
 package utils
 
 import (
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+
+	"taskify/cache"
 )
 
 type JWTClaims struct {
@@ -13,38 +14,48 @@ type JWTClaims struct {
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID uuid.UUID) (string, error) {
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
+// TokenManager menerbitkan, memvalidasi, dan mencabut JWT. Secret dan masa berlaku berasal dari
+// config.AuthConfig; daftar token yang dicabut disimpan di store.
+type TokenManager struct {
+	secret []byte
+	ttl    time.Duration
+	store  cache.Store
+}
+
+// NewTokenManager membuat TokenManager. Secret sudah divalidasi oleh config.Load.
+func NewTokenManager(secret string, ttl time.Duration, store cache.Store) *TokenManager {
+	return &TokenManager{secret: []byte(secret), ttl: ttl, store: store}
+}
+
+// Generate menerbitkan token untuk userID yang berlaku selama ttl.
+func (m *TokenManager) Generate(userID uuid.UUID) (string, error) {
+	if len(m.secret) == 0 {
 		return "", jwt.ErrInvalidKey
 	}
 
+	now := time.Now()
 	claims := &JWTClaims{
 		UserID: userID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        uuid.NewString(), // jti, dipakai untuk mencabut token saat logout
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(jwtSecret))
-	if err != nil {
-		return "", err
-	}
-	return tokenString, nil
+	return token.SignedString(m.secret)
 }
 
-func ValidateToken(tokenString string) (*JWTClaims, error) {
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
+// Validate memeriksa tanda tangan dan masa berlaku token lalu mengembalikan claims-nya.
+func (m *TokenManager) Validate(tokenString string) (*JWTClaims, error) {
+	if len(m.secret) == 0 {
 		return nil, jwt.ErrInvalidKey
 	}
 
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(jwtSecret), nil
+		return m.secret, nil
 	})
-
 	if err != nil {
 		return nil, err
 	}
@@ -56,3 +67,7 @@ func ValidateToken(tokenString string) (*JWTClaims, error) {
 
 	return claims, nil
 }
+
+// WebSocketTokenProtocol adalah subprotocol WebSocket penanda token. Browser tidak bisa mengirim header
+// Authorization saat membuka WebSocket, jadi token dikirim sebagai Sec-WebSocket-Protocol: bearer, <token>.
+const WebSocketTokenProtocol = "bearer"
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"taskify/cache"
+)
+
+func revokedTokenKey(tokenID string) string {
+	return "revoked_token:" + tokenID
+}
+
+// Revoke memasukkan token ke daftar pencabutan sampai token tersebut kedaluwarsa.
+func (m *TokenManager) Revoke(ctx context.Context, claims *JWTClaims) error {
+	if claims.ID == "" {
+		return errors.New("token has no ID and cannot be revoked")
+	}
+	ttl := m.ttl
+	if claims.ExpiresAt != nil {
+		ttl = time.Until(claims.ExpiresAt.Time)
+	}
+	if ttl <= 0 {
+		return nil // Token sudah kedaluwarsa, tidak perlu dicatat
+	}
+	return m.store.Set(ctx, revokedTokenKey(claims.ID), []byte("1"), ttl)
+}
+
+// IsRevoked melaporkan apakah token dengan ID tersebut sudah dicabut.
+func (m *TokenManager) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	if tokenID == "" {
+		return false, nil
+	}
+	_, err := m.store.Get(ctx, revokedTokenKey(tokenID))
+	if errors.Is(err, cache.ErrMiss) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,487 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"taskify/config"
+	"taskify/models"
+	"taskify/service"
+)
+
+// webhookReceiver adalah penerima webhook palsu yang mencatat setiap request dan membalas dengan status
+// yang bisa diubah di tengah test.
+type webhookReceiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	status   int
+	requests []receivedWebhook
+}
+
+type receivedWebhook struct {
+	Header http.Header
+	Body   []byte
+}
+
+func newWebhookReceiver(t *testing.T) *webhookReceiver {
+	t.Helper()
+
+	r := &webhookReceiver{status: http.StatusOK}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.requests = append(r.requests, receivedWebhook{Header: req.Header.Clone(), Body: body})
+		w.WriteHeader(r.status)
+		w.Write([]byte("receiver says " + http.StatusText(r.status)))
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *webhookReceiver) respondWith(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *webhookReceiver) received() []receivedWebhook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedWebhook(nil), r.requests...)
+}
+
+// fastWebhookRetries memperpendek jeda retry supaya test dead letter tidak perlu menunggu lama.
+func fastWebhookRetries(cfg *config.Config) {
+	cfg.Webhook.MaxAttempts = 3
+	cfg.Webhook.BackoffBase = time.Millisecond
+	cfg.Webhook.BackoffMax = time.Millisecond
+}
+
+// createWebhook mendaftarkan webhook dan mengembalikan ID serta secret-nya.
+func (s *testServer) createWebhook(user testUser, projectID, url string, events ...string) (string, string) {
+	s.t.Helper()
+
+	rec := s.do(http.MethodPost, "/api/projects/"+projectID+"/webhooks", user.Token, gin.H{"url": url, "events": events})
+	expectStatus(s.t, rec, http.StatusCreated)
+	body := decode(s.t, rec)
+	return str(body, "webhook.id"), str(body, "webhook.secret")
+}
+
+// dispatch menjalankan satu putaran dispatcher: membagikan event outbox lalu mengirim yang jatuh tempo.
+func (s *testServer) dispatch() {
+	s.t.Helper()
+
+	ctx := context.Background()
+	if _, err := s.app.dispatcher.RelayOutbox(ctx); err != nil {
+		s.t.Fatalf("relay outbox: %v", err)
+	}
+	if _, err := s.app.dispatcher.DeliverDue(ctx); err != nil {
+		s.t.Fatalf("deliver webhooks: %v", err)
+	}
+}
+
+// outboxTypes mengembalikan jenis event di outbox sesuai urutan penulisan.
+func (s *testServer) outboxTypes() []string {
+	s.t.Helper()
+
+	var types []string
+	if err := s.db.Model(&models.OutboxEvent{}).Order("created_at, id").Pluck("type", &types).Error; err != nil {
+		s.t.Fatalf("read outbox: %v", err)
+	}
+	return types
+}
+
+// decodeEnvelope memverifikasi tanda tangan request lalu mem-parse body-nya.
+func decodeEnvelope(t *testing.T, request receivedWebhook, secret string) map[string]interface{} {
+	t.Helper()
+
+	timestamp := request.Header.Get(service.HeaderWebhookTimestamp)
+	if want := service.SignWebhook(secret, timestamp, request.Body); request.Header.Get(service.HeaderWebhookSignature) != want {
+		t.Fatalf("invalid signature %q, expected %q", request.Header.Get(service.HeaderWebhookSignature), want)
+	}
+	var envelope map[string]interface{}
+	if err := json.Unmarshal(request.Body, &envelope); err != nil {
+		t.Fatalf("decode webhook body %q: %v", request.Body, err)
+	}
+	if envelope["type"] != request.Header.Get(service.HeaderWebhookEvent) {
+		t.Fatalf("event header %q does not match body type %v", request.Header.Get(service.HeaderWebhookEvent), envelope["type"])
+	}
+	return envelope
+}
+
+func TestWebhookManagement(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	stranger := s.register("stranger")
+	projectID := s.createProject(owner, "Hooked")
+	base := "/api/projects/" + projectID + "/webhooks"
+
+	t.Run("validates input", func(t *testing.T) {
+		tests := []struct {
+			name string
+			body gin.H
+			want string
+		}{
+			{"relative url", gin.H{"url": "/hooks", "events": []string{"*"}}, "absolute http or https URL"},
+			{"unsupported scheme", gin.H{"url": "ftp://example.com", "events": []string{"*"}}, "absolute http or https URL"},
+			{"unknown event", gin.H{"url": "https://example.com", "events": []string{"task.exploded"}}, "Unknown event type"},
+			{"no events", gin.H{"url": "https://example.com", "events": []string{}}, "at least one event type"},
+			{"short secret", gin.H{"url": "https://example.com", "events": []string{"*"}, "secret": "short"}, "between 16 and 255"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rec := s.do(http.MethodPost, base, owner.Token, tt.body)
+				expectStatus(t, rec, http.StatusBadRequest)
+				if !strings.Contains(str(decode(t, rec), "error"), tt.want) {
+					t.Fatalf("expected %q in %s", tt.want, rec.Body.String())
+				}
+			})
+		}
+	})
+
+	webhookID, secret := s.createWebhook(owner, projectID, "https://example.com/hooks", "task.created", "task.created", "project.deleted")
+
+	t.Run("secret is only returned on create", func(t *testing.T) {
+		if !strings.HasPrefix(secret, "whsec_") {
+			t.Fatalf("expected a generated secret, got %q", secret)
+		}
+		rec := s.do(http.MethodGet, base+"/"+webhookID, owner.Token, nil)
+		expectStatus(t, rec, http.StatusOK)
+		body := decode(t, rec)
+		if _, ok := lookup(body, "webhook.secret").(string); ok {
+			t.Fatalf("secret leaked: %s", rec.Body.String())
+		}
+		if count(body, "webhook.events") != 2 || lookup(body, "webhook.active") != true {
+			t.Fatalf("unexpected webhook: %s", rec.Body.String())
+		}
+
+		rec = s.do(http.MethodGet, base, owner.Token, nil)
+		expectStatus(t, rec, http.StatusOK)
+		if count(decode(t, rec), "webhooks") != 1 || strings.Contains(rec.Body.String(), secret) {
+			t.Fatalf("unexpected list: %s", rec.Body.String())
+		}
+	})
+
+	t.Run("patch changes only the given fields", func(t *testing.T) {
+		rec := s.do(http.MethodPatch, base+"/"+webhookID, owner.Token, gin.H{"events": []string{"*"}, "active": false})
+		expectStatus(t, rec, http.StatusOK)
+		body := decode(t, rec)
+		if str(body, "webhook.events.0") != "*" || lookup(body, "webhook.active") != false || str(body, "webhook.url") != "https://example.com/hooks" {
+			t.Fatalf("unexpected webhook: %s", rec.Body.String())
+		}
+
+		rec = s.do(http.MethodPatch, base+"/"+webhookID, owner.Token, gin.H{"url": "not a url"})
+		expectStatus(t, rec, http.StatusBadRequest)
+	})
+
+	t.Run("other users cannot see or change webhooks", func(t *testing.T) {
+		expectStatus(t, s.do(http.MethodGet, base, stranger.Token, nil), http.StatusNotFound)
+		expectStatus(t, s.do(http.MethodGet, base+"/"+webhookID, stranger.Token, nil), http.StatusNotFound)
+		expectStatus(t, s.do(http.MethodDelete, base+"/"+webhookID, stranger.Token, nil), http.StatusNotFound)
+
+		// Webhook proyek lain tidak bisa diakses lewat proyek milik sendiri
+		otherProject := s.createProject(stranger, "Elsewhere")
+		expectStatus(t, s.do(http.MethodGet, "/api/projects/"+otherProject+"/webhooks/"+webhookID, stranger.Token, nil), http.StatusNotFound)
+	})
+
+	t.Run("archived projects are read-only", func(t *testing.T) {
+		expectStatus(t, s.do(http.MethodPost, "/api/projects/detail/"+projectID+"/archive", owner.Token, nil), http.StatusOK)
+		rec := s.do(http.MethodPost, base, owner.Token, gin.H{"url": "https://example.com", "events": []string{"*"}})
+		expectStatus(t, rec, http.StatusConflict)
+		expectStatus(t, s.do(http.MethodGet, base, owner.Token, nil), http.StatusOK)
+		expectStatus(t, s.do(http.MethodPost, "/api/projects/detail/"+projectID+"/unarchive", owner.Token, nil), http.StatusOK)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		expectStatus(t, s.do(http.MethodDelete, base+"/"+webhookID, owner.Token, nil), http.StatusOK)
+		expectStatus(t, s.do(http.MethodGet, base+"/"+webhookID, owner.Token, nil), http.StatusNotFound)
+	})
+}
+
+func TestWebhookDeliversSignedEvents(t *testing.T) {
+	s := newTestServer(t)
+	receiver := newWebhookReceiver(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Hooked")
+	webhookID, secret := s.createWebhook(owner, projectID, receiver.URL, models.EventTaskCreated, models.EventTaskStatusChanged)
+
+	taskID := s.createTask(owner, projectID, "Ship it", nil)
+	rec := s.do(http.MethodPatch, "/api/projects/"+projectID+"/tasks/"+taskID, owner.Token, gin.H{"title": "Ship it now"})
+	expectStatus(t, rec, http.StatusOK)
+	rec = s.do(http.MethodPatch, "/api/projects/"+projectID+"/tasks/"+taskID, owner.Token, gin.H{"status": "done"})
+	expectStatus(t, rec, http.StatusOK)
+
+	// Belum ada yang dikirim sebelum dispatcher berjalan: pengiriman selalu asinkron
+	if got := len(receiver.received()); got != 0 {
+		t.Fatalf("expected no synchronous deliveries, got %d", got)
+	}
+	s.dispatch()
+
+	requests := receiver.received()
+	if len(requests) != 2 {
+		t.Fatalf("expected task.created and task.status_changed only, got %d requests", len(requests))
+	}
+	types := map[string]map[string]interface{}{}
+	for _, request := range requests {
+		envelope := decodeEnvelope(t, request, secret)
+		types[str(envelope, "type")] = envelope
+		if str(envelope, "project_id") != projectID || str(envelope, "actor_id") != owner.ID || str(envelope, "data.id") != taskID {
+			t.Fatalf("unexpected envelope: %s", request.Body)
+		}
+	}
+	changed := types[models.EventTaskStatusChanged]
+	if changed == nil || str(changed, "data.status") != "done" || str(changed, "data.previous_status") != "todo" || str(changed, "data.title") != "Ship it now" {
+		t.Fatalf("unexpected status change event: %v", changed)
+	}
+	if types[models.EventTaskCreated] == nil {
+		t.Fatalf("missing task.created in %v", types)
+	}
+
+	rec = s.do(http.MethodGet, "/api/projects/"+projectID+"/webhooks/"+webhookID+"/deliveries", owner.Token, nil)
+	expectStatus(t, rec, http.StatusOK)
+	body := decode(t, rec)
+	if count(body, "deliveries") != 2 {
+		t.Fatalf("expected 2 deliveries, got %s", rec.Body.String())
+	}
+	for i := 0; i < 2; i++ {
+		delivery := lookup(body, "deliveries").([]interface{})[i]
+		if str(delivery, "status") != models.DeliverySucceeded || count(delivery, "log") != 1 || lookup(delivery, "log.0.status_code") != float64(200) {
+			t.Fatalf("unexpected delivery: %v", delivery)
+		}
+	}
+
+	// Event yang sudah dibagikan tidak dikirim dua kali
+	s.dispatch()
+	if got := len(receiver.received()); got != 2 {
+		t.Fatalf("expected no further deliveries, got %d requests", got)
+	}
+}
+
+func TestWebhookRetriesDeadLetterAndRedeliver(t *testing.T) {
+	s := newTestServer(t, fastWebhookRetries)
+	receiver := newWebhookReceiver(t)
+	receiver.respondWith(http.StatusServiceUnavailable)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Flaky")
+	webhookID, secret := s.createWebhook(owner, projectID, receiver.URL, "*")
+	deliveriesPath := "/api/projects/" + projectID + "/webhooks/" + webhookID + "/deliveries"
+
+	s.createTask(owner, projectID, "Retry me", nil)
+	for i := 0; i < 20 && len(receiver.received()) < 3; i++ {
+		s.dispatch()
+		time.Sleep(5 * time.Millisecond)
+	}
+	s.dispatch()
+
+	rec := s.do(http.MethodGet, deliveriesPath, owner.Token, nil)
+	expectStatus(t, rec, http.StatusOK)
+	delivery := lookup(decode(t, rec), "deliveries.0")
+	if str(delivery, "status") != models.DeliveryDead || lookup(delivery, "attempts") != float64(3) || count(delivery, "log") != 3 {
+		t.Fatalf("expected a dead delivery after 3 attempts, got %v", delivery)
+	}
+	if lookup(delivery, "log.2.status_code") != float64(503) || !strings.Contains(str(delivery, "log.2.response_body"), "Service Unavailable") {
+		t.Fatalf("unexpected attempt log: %v", lookup(delivery, "log"))
+	}
+	if got := len(receiver.received()); got != 3 {
+		t.Fatalf("dead deliveries must not be retried, got %d requests", got)
+	}
+
+	receiver.respondWith(http.StatusNoContent)
+	rec = s.do(http.MethodPost, deliveriesPath+"/"+str(delivery, "id")+"/redeliver", owner.Token, nil)
+	expectStatus(t, rec, http.StatusAccepted)
+	redelivery := decode(t, rec)
+	if str(redelivery, "delivery.status") != models.DeliveryPending || str(redelivery, "delivery.event_id") != str(delivery, "event_id") {
+		t.Fatalf("unexpected redelivery: %s", rec.Body.String())
+	}
+	s.dispatch()
+
+	requests := receiver.received()
+	if len(requests) != 4 {
+		t.Fatalf("expected the redelivery to be sent, got %d requests", len(requests))
+	}
+	first, last := decodeEnvelope(t, requests[0], secret), decodeEnvelope(t, requests[3], secret)
+	if str(first, "id") != str(last, "id") {
+		t.Fatal("redelivery must carry the original event id so receivers can deduplicate")
+	}
+	if requests[0].Header.Get(service.HeaderWebhookDelivery) == requests[3].Header.Get(service.HeaderWebhookDelivery) {
+		t.Fatal("redelivery must be a new delivery")
+	}
+
+	rec = s.do(http.MethodGet, deliveriesPath, owner.Token, nil)
+	expectStatus(t, rec, http.StatusOK)
+	if str(decode(t, rec), "deliveries.0.status") != models.DeliverySucceeded {
+		t.Fatalf("expected the newest delivery to succeed: %s", rec.Body.String())
+	}
+
+	t.Run("unknown delivery", func(t *testing.T) {
+		rec := s.do(http.MethodPost, deliveriesPath+"/"+webhookID+"/redeliver", owner.Token, nil)
+		expectStatus(t, rec, http.StatusNotFound)
+	})
+}
+
+func TestOutboxOnlyRecordsCommittedChanges(t *testing.T) {
+	s := newTestServer(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Outbox")
+	taskID := s.createTask(owner, projectID, "Original", nil)
+	otherID := s.createTask(owner, projectID, "Other", nil)
+
+	// Perubahan yang gagal karena versi lama tidak menulis event
+	rec := s.do(http.MethodPatch, "/api/projects/"+projectID+"/tasks/"+taskID, owner.Token, gin.H{"title": "Stale"}, header{"If-Match", `"99"`})
+	expectStatus(t, rec, http.StatusPreconditionFailed)
+
+	// Bulk atomic yang dibatalkan membuang event dari operasi yang sempat berhasil
+	rec = s.do(http.MethodPost, "/api/projects/"+projectID+"/tasks/bulk", owner.Token, gin.H{"operations": []gin.H{
+		{"op": "update_status", "task_id": otherID, "status": "done"},
+		{"op": "delete", "task_id": projectID},
+	}})
+	expectStatus(t, rec, http.StatusUnprocessableEntity)
+	if str(decode(t, rec), "results.0.status") != "rolled_back" {
+		t.Fatalf("expected the first operation to be rolled back: %s", rec.Body.String())
+	}
+
+	want := []string{models.EventTaskCreated, models.EventTaskCreated}
+	if got := s.outboxTypes(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected outbox %v, got %v", want, got)
+	}
+
+	target := s.createProject(owner, "Target")
+	rec = s.do(http.MethodPost, "/api/projects/"+projectID+"/tasks/"+taskID+"/move", owner.Token, gin.H{"target_project_id": target})
+	expectStatus(t, rec, http.StatusOK)
+	expectStatus(t, s.do(http.MethodPost, "/api/projects/detail/"+projectID+"/archive", owner.Token, nil), http.StatusOK)
+
+	want = append(want, models.EventTaskMoved, models.EventTaskMoved, models.EventProjectArchived)
+	if got := s.outboxTypes(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected outbox %v, got %v", want, got)
+	}
+}
+
+func TestProjectDeletedEventReachesWebhooksBeforeTheyAreRemoved(t *testing.T) {
+	s := newTestServer(t)
+	receiver := newWebhookReceiver(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Doomed")
+	_, secret := s.createWebhook(owner, projectID, receiver.URL, models.EventProjectDeleted)
+
+	expectStatus(t, s.do(http.MethodDelete, "/api/projects/detail/"+projectID, owner.Token, nil), http.StatusOK)
+	s.dispatch()
+
+	requests := receiver.received()
+	if len(requests) != 1 {
+		t.Fatalf("expected one project.deleted delivery, got %d", len(requests))
+	}
+	if envelope := decodeEnvelope(t, requests[0], secret); str(envelope, "data.name") != "Doomed" {
+		t.Fatalf("unexpected envelope: %v", envelope)
+	}
+
+	var remaining int64
+	if err := s.db.Model(&models.Webhook{}).Where("project_id = ?", projectID).Count(&remaining).Error; err != nil {
+		t.Fatal(err)
+	}
+	if remaining != 0 {
+		t.Fatalf("expected webhooks of the deleted project to be removed, %d left", remaining)
+	}
+}
+
+func TestWebhookRejectsInternalTargets(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) { cfg.Webhook.AllowPrivateTargets = false })
+	receiver := newWebhookReceiver(t)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Internal")
+	base := "/api/projects/" + projectID + "/webhooks"
+
+	for _, url := range []string{"http://169.254.169.254/latest/meta-data", "http://127.0.0.1:8080/", "http://10.0.0.5/hook", "http://[::1]/hook", "http://localhost/hook", receiver.URL} {
+		rec := s.do(http.MethodPost, base, owner.Token, gin.H{"url": url, "events": []string{"*"}})
+		expectStatus(t, rec, http.StatusBadRequest)
+		if !strings.Contains(str(decode(t, rec), "error"), "loopback, private or link-local") {
+			t.Fatalf("unexpected error for %s: %s", url, rec.Body.String())
+		}
+	}
+
+	// Nama host yang lolos validasi tetapi di-resolve ke alamat internal (DNS rebinding) tetap tertolak saat
+	// dihubungi; di sini URL-nya diubah langsung di database
+	webhookID, _ := s.createWebhook(owner, projectID, "https://hooks.example.com/taskify", "*")
+	if err := s.db.Model(&models.Webhook{}).Where("id = ?", webhookID).Update("url", receiver.URL).Error; err != nil {
+		t.Fatalf("point webhook at receiver: %v", err)
+	}
+	s.createTask(owner, projectID, "Secret", nil)
+	s.dispatch()
+
+	if got := len(receiver.received()); got != 0 {
+		t.Fatalf("internal receiver must not be contacted, got %d requests", got)
+	}
+	rec := s.do(http.MethodGet, base+"/"+webhookID+"/deliveries", owner.Token, nil)
+	expectStatus(t, rec, http.StatusOK)
+	if attempt := lookup(decode(t, rec), "deliveries.0.log.0"); !strings.Contains(str(attempt, "error"), "loopback, private or link-local") {
+		t.Fatalf("expected the attempt to be blocked, got %v", attempt)
+	}
+}
+
+func TestWebhookDoesNotFollowRedirects(t *testing.T) {
+	s := newTestServer(t)
+	target := newWebhookReceiver(t)
+	redirector := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+	t.Cleanup(redirector.Close)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Redirected")
+	webhookID, _ := s.createWebhook(owner, projectID, redirector.URL, "*")
+
+	s.createTask(owner, projectID, "Moved", nil)
+	s.dispatch()
+
+	if got := len(target.received()); got != 0 {
+		t.Fatalf("redirect target must not be contacted, got %d requests", got)
+	}
+	rec := s.do(http.MethodGet, "/api/projects/"+projectID+"/webhooks/"+webhookID+"/deliveries", owner.Token, nil)
+	expectStatus(t, rec, http.StatusOK)
+	delivery := lookup(decode(t, rec), "deliveries.0")
+	if str(delivery, "status") != models.DeliveryPending || lookup(delivery, "log.0.status_code") != float64(http.StatusFound) {
+		t.Fatalf("expected the redirect to count as a failed attempt, got %v", delivery)
+	}
+}
+
+func TestWebhookAttemptIsDiscardedWhenLeaseIsLost(t *testing.T) {
+	s := newTestServer(t)
+	reclaimed := time.Now().Add(time.Hour).UTC()
+	// Penerima lambat: selama request berjalan lease dianggap habis dan pengiriman diklaim ulang instance lain
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := s.db.Model(&models.WebhookDelivery{}).Where("status = ?", models.DeliveryPending).
+			Update("next_attempt_at", reclaimed).Error; err != nil {
+			t.Errorf("reclaim delivery: %v", err)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(receiver.Close)
+	owner := s.register("owner")
+	projectID := s.createProject(owner, "Slow")
+	webhookID, _ := s.createWebhook(owner, projectID, receiver.URL, "*")
+
+	s.createTask(owner, projectID, "Late", nil)
+	s.dispatch()
+
+	rec := s.do(http.MethodGet, "/api/projects/"+projectID+"/webhooks/"+webhookID+"/deliveries", owner.Token, nil)
+	expectStatus(t, rec, http.StatusOK)
+	delivery := lookup(decode(t, rec), "deliveries.0")
+	if lookup(delivery, "attempts") != float64(0) || count(delivery, "log") != 0 {
+		t.Fatalf("an attempt without the lease must not be recorded, got %v", delivery)
+	}
+	var stored models.WebhookDelivery
+	if err := s.db.First(&stored, "id = ?", str(delivery, "id")).Error; err != nil {
+		t.Fatalf("load delivery: %v", err)
+	}
+	if stored.NextAttemptAt == nil || !stored.NextAttemptAt.Equal(reclaimed) {
+		t.Fatalf("the new lease must be kept, got %v", stored.NextAttemptAt)
+	}
+}